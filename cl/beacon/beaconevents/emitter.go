@@ -0,0 +1,89 @@
+package beaconevents
+
+import (
+	"sync"
+)
+
+// subscriptionBufferSize is how many events a subscriber can lag behind before events are dropped for it.
+const subscriptionBufferSize = 256
+
+// Event is an event published to the subscribers of its topic.
+type Event struct {
+	Topic string
+	Data  any
+}
+
+type subscription struct {
+	topics map[string]struct{}
+	ch     chan Event
+}
+
+// Emitters dispatches the events published by forkchoice and the gossip manager to the subscribers of the event stream.
+type Emitters struct {
+	mu        sync.RWMutex
+	subs      map[int]*subscription
+	totalSubs int
+}
+
+func NewEmitters() *Emitters {
+	return &Emitters{
+		subs: make(map[int]*subscription),
+	}
+}
+
+// Subscribe returns a channel receiving the events of the given topics, and a function cancelling the subscription.
+func (e *Emitters) Subscribe(topics []string) (<-chan Event, func()) {
+	sub := &subscription{
+		topics: make(map[string]struct{}, len(topics)),
+		ch:     make(chan Event, subscriptionBufferSize),
+	}
+	for _, topic := range topics {
+		sub.topics[topic] = struct{}{}
+	}
+	e.mu.Lock()
+	e.totalSubs++
+	idx := e.totalSubs
+	e.subs[idx] = sub
+	e.mu.Unlock()
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() {
+			e.mu.Lock()
+			delete(e.subs, idx)
+			e.mu.Unlock()
+		})
+	}
+}
+
+// Publish sends the event to the subscribers of the topic. It never blocks: subscribers too slow to keep up miss the event.
+func (e *Emitters) Publish(topic string, data any) {
+	if e == nil {
+		return
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	for _, sub := range e.subs {
+		if _, ok := sub.topics[topic]; !ok {
+			continue
+		}
+		select {
+		case sub.ch <- Event{Topic: topic, Data: data}:
+		default:
+		}
+	}
+}
+
+// HasSubscribers returns true if anyone is subscribed to the topic, so that publishers can skip building costly events.
+func (e *Emitters) HasSubscribers(topic string) bool {
+	if e == nil {
+		return false
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	for _, sub := range e.subs {
+		if _, ok := sub.topics[topic]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package beaconevents
+
+import (
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+)
+
+// Topics of the /eth/v1/events stream.
+const (
+	TopicHead                 = "head"
+	TopicBlock                = "block"
+	TopicAttestation          = "attestation"
+	TopicVoluntaryExit        = "voluntary_exit"
+	TopicFinalizedCheckpoint  = "finalized_checkpoint"
+	TopicChainReorg           = "chain_reorg"
+	TopicBlobSidecar          = "blob_sidecar"
+	TopicContributionAndProof = "contribution_and_proof"
+)
+
+var topics = map[string]struct{}{
+	TopicHead:                 {},
+	TopicBlock:                {},
+	TopicAttestation:          {},
+	TopicVoluntaryExit:        {},
+	TopicFinalizedCheckpoint:  {},
+	TopicChainReorg:           {},
+	TopicBlobSidecar:          {},
+	TopicContributionAndProof: {},
+}
+
+// IsValidTopic returns true if the topic can be subscribed to.
+func IsValidTopic(topic string) bool {
+	_, ok := topics[topic]
+	return ok
+}
+
+// HeadData is the data of the head event, published when the head of the chain changes.
+type HeadData struct {
+	Slot                      uint64         `json:"slot,string"`
+	Block                     libcommon.Hash `json:"block"`
+	State                     libcommon.Hash `json:"state"`
+	EpochTransition           bool           `json:"epoch_transition"`
+	PreviousDutyDependentRoot libcommon.Hash `json:"previous_duty_dependent_root"`
+	CurrentDutyDependentRoot  libcommon.Hash `json:"current_duty_dependent_root"`
+	ExecutionOptimistic       bool           `json:"execution_optimistic"`
+}
+
+// BlockData is the data of the block event, published when a block is imported by forkchoice.
+type BlockData struct {
+	Slot                uint64         `json:"slot,string"`
+	Block               libcommon.Hash `json:"block"`
+	ExecutionOptimistic bool           `json:"execution_optimistic"`
+}
+
+// FinalizedCheckpointData is the data of the finalized_checkpoint event.
+type FinalizedCheckpointData struct {
+	Block               libcommon.Hash `json:"block"`
+	State               libcommon.Hash `json:"state"`
+	Epoch               uint64         `json:"epoch,string"`
+	ExecutionOptimistic bool           `json:"execution_optimistic"`
+}
+
+// ChainReorgData is the data of the chain_reorg event, published when the new head is not a descendant of the old one.
+type ChainReorgData struct {
+	Slot                uint64         `json:"slot,string"`
+	Depth               uint64         `json:"depth,string"`
+	OldHeadBlock        libcommon.Hash `json:"old_head_block"`
+	NewHeadBlock        libcommon.Hash `json:"new_head_block"`
+	OldHeadState        libcommon.Hash `json:"old_head_state"`
+	NewHeadState        libcommon.Hash `json:"new_head_state"`
+	Epoch               uint64         `json:"epoch,string"`
+	ExecutionOptimistic bool           `json:"execution_optimistic"`
+}
+
+// BlobSidecarData is the data of the blob_sidecar event, published when a blob sidecar is received over gossip.
+type BlobSidecarData struct {
+	BlockRoot     libcommon.Hash    `json:"block_root"`
+	Index         uint64            `json:"index,string"`
+	Slot          uint64            `json:"slot,string"`
+	KzgCommitment libcommon.Bytes48 `json:"kzg_commitment"`
+	VersionedHash libcommon.Hash    `json:"versioned_hash"`
+}
@@ -6,8 +6,11 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"strconv"
+	"strings"
 
 	"github.com/ledgerwatch/erigon-lib/types/ssz"
+	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/phase1/forkchoice/fork_graph"
 	"github.com/ledgerwatch/log/v3"
 )
@@ -50,6 +53,24 @@ func (e *EndpointError) WriteTo(w http.ResponseWriter) {
 	}
 }
 
+const (
+	jsonContentType = "application/json"
+	sszContentType  = "application/octet-stream"
+
+	// ConsensusVersionHeader carries the fork of versioned responses.
+	ConsensusVersionHeader = "Eth-Consensus-Version"
+)
+
+// SSZSupporter is implemented by responses which can only be encoded in SSZ depending on their content.
+type SSZSupporter interface {
+	SupportsSSZ() bool
+}
+
+// Versioned is implemented by responses bound to a fork, which is then given in the Eth-Consensus-Version header.
+type Versioned interface {
+	ConsensusVersion() (clparams.StateVersion, bool)
+}
+
 type EndpointHandler[T any] interface {
 	Handle(r *http.Request) (T, error)
 }
@@ -73,33 +94,78 @@ func HandleEndpoint[T any](h EndpointHandler[T]) http.HandlerFunc {
 			endpointError.WriteTo(w)
 			return
 		}
-		// TODO: ssz handler
-		// TODO: potentially add a context option to buffer these
-		contentType := r.Header.Get("Accept")
-		switch contentType {
-		case "application/octet-stream":
-			sszMarshaler, ok := any(ans).(ssz.Marshaler)
-			if !ok {
-				NewEndpointError(http.StatusBadRequest, "This endpoint does not support SSZ response").WriteTo(w)
-				return
+		sszMarshaler, supportsSSZ := any(ans).(ssz.Marshaler)
+		if supporter, ok := any(ans).(SSZSupporter); ok {
+			supportsSSZ = supportsSSZ && supporter.SupportsSSZ()
+		}
+		contentType, ok := negotiateContentType(r.Header.Get("Accept"), supportsSSZ)
+		if !ok {
+			NewEndpointError(http.StatusNotAcceptable, "content type must be application/json or application/octet-stream, the latter only where SSZ is supported").WriteTo(w)
+			return
+		}
+		if versioned, ok := any(ans).(Versioned); ok {
+			if version, ok := versioned.ConsensusVersion(); ok {
+				w.Header().Set(ConsensusVersionHeader, clparams.ClVersionToString(version))
 			}
+		}
+		switch contentType {
+		case sszContentType:
 			// TODO: we should probably figure out some way to stream this in the future :)
 			encoded, err := sszMarshaler.EncodeSSZ(nil)
 			if err != nil {
 				WrapEndpointError(err).WriteTo(w)
 				return
 			}
+			w.Header().Set("Content-Type", sszContentType)
 			w.Write(encoded)
-		case "application/json", "":
-			w.Header().Add("content-type", "application/json")
+		default:
+			w.Header().Set("Content-Type", jsonContentType)
 			err := json.NewEncoder(w).Encode(ans)
 			if err != nil {
 				// this error is fatal, log to console
 				log.Error("beaconapi failed to encode json", "type", reflect.TypeOf(ans), "err", err)
 			}
-		default:
-			http.Error(w, "content type must be application/json or application/octet-stream", http.StatusBadRequest)
-
 		}
 	})
 }
+
+// negotiateContentType picks the media type of the response from the Accept header, honouring quality values and
+// preferring the first listed type on ties. SSZ is only picked for responses supporting it, and an empty header or a
+// wildcard means JSON. It returns false if none of the accepted types can be served.
+func negotiateContentType(accept string, supportsSSZ bool) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return jsonContentType, true
+	}
+	best, bestQuality := "", 0.0
+	for _, mediaRange := range strings.Split(accept, ",") {
+		params := strings.Split(mediaRange, ";")
+		quality := 1.0
+		for _, param := range params[1:] {
+			key, value, found := strings.Cut(param, "=")
+			if !found || strings.TrimSpace(key) != "q" {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				parsed = 0
+			}
+			quality = parsed
+		}
+		var candidate string
+		switch strings.ToLower(strings.TrimSpace(params[0])) {
+		case sszContentType:
+			if !supportsSSZ {
+				continue
+			}
+			candidate = sszContentType
+		case jsonContentType, "application/*", "*/*":
+			candidate = jsonContentType
+		default:
+			continue
+		}
+		if quality > bestQuality {
+			best, bestQuality = candidate, quality
+		}
+	}
+	return best, best != ""
+}
@@ -0,0 +1,80 @@
+package beaconhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		accept      string
+		supportsSSZ bool
+		expected    string
+		acceptable  bool
+	}{
+		{name: "empty", accept: "", expected: jsonContentType, acceptable: true},
+		{name: "blank", accept: "  ", supportsSSZ: true, expected: jsonContentType, acceptable: true},
+		{name: "json", accept: "application/json", supportsSSZ: true, expected: jsonContentType, acceptable: true},
+		{name: "json case insensitive", accept: "Application/JSON", expected: jsonContentType, acceptable: true},
+		{name: "ssz", accept: "application/octet-stream", supportsSSZ: true, expected: sszContentType, acceptable: true},
+		{name: "ssz unsupported", accept: "application/octet-stream", acceptable: false},
+		{name: "ssz unsupported with json fallback", accept: "application/octet-stream, application/json;q=0.5", expected: jsonContentType, acceptable: true},
+		{name: "ssz unsupported with wildcard fallback", accept: "application/octet-stream, */*;q=0.2", expected: jsonContentType, acceptable: true},
+		{name: "first listed wins ties ssz", accept: "application/octet-stream, application/json", supportsSSZ: true, expected: sszContentType, acceptable: true},
+		{name: "first listed wins ties json", accept: "application/json, application/octet-stream", supportsSSZ: true, expected: jsonContentType, acceptable: true},
+		{name: "higher quality json", accept: "application/octet-stream;q=0.5, application/json", supportsSSZ: true, expected: jsonContentType, acceptable: true},
+		{name: "higher quality ssz", accept: "application/json;q=0.9, application/octet-stream;q=1.0", supportsSSZ: true, expected: sszContentType, acceptable: true},
+		{name: "spaces around parameters", accept: "application/json ; q=0.1 , application/octet-stream ; q=0.2", supportsSSZ: true, expected: sszContentType, acceptable: true},
+		{name: "other parameters", accept: "application/octet-stream;charset=utf-8;q=0.3, application/json;q=0.2", supportsSSZ: true, expected: sszContentType, acceptable: true},
+		{name: "any type", accept: "*/*", supportsSSZ: true, expected: jsonContentType, acceptable: true},
+		{name: "any application subtype", accept: "application/*", supportsSSZ: true, expected: jsonContentType, acceptable: true},
+		{name: "ssz over wildcard", accept: "*/*;q=0.8, application/octet-stream", supportsSSZ: true, expected: sszContentType, acceptable: true},
+		{name: "json refused", accept: "application/json;q=0", acceptable: false},
+		{name: "ssz refused", accept: "application/octet-stream;q=0", supportsSSZ: true, acceptable: false},
+		{name: "json refused with wildcard", accept: "application/json;q=0, */*", expected: jsonContentType, acceptable: true},
+		{name: "ssz refused with json", accept: "application/octet-stream;q=0, application/json;q=0.1", supportsSSZ: true, expected: jsonContentType, acceptable: true},
+		{name: "invalid quality", accept: "application/octet-stream;q=high, application/json;q=0.1", supportsSSZ: true, expected: jsonContentType, acceptable: true},
+		{name: "unknown type", accept: "text/html", supportsSSZ: true, acceptable: false},
+		{name: "unknown type with wildcard", accept: "text/html, */*;q=0.1", expected: jsonContentType, acceptable: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contentType, ok := negotiateContentType(tt.accept, tt.supportsSSZ)
+			require.Equal(t, tt.acceptable, ok)
+			require.Equal(t, tt.expected, contentType)
+		})
+	}
+}
+
+func TestHandleEndpointNotAcceptable(t *testing.T) {
+	handler := HandleEndpointFunc(func(r *http.Request) (map[string]string, error) {
+		return map[string]string{"hello": "world"}, nil
+	})
+	tests := []struct {
+		name        string
+		accept      string
+		status      int
+		contentType string
+	}{
+		{name: "json", accept: "application/json", status: http.StatusOK, contentType: jsonContentType},
+		{name: "ssz unsupported", accept: "application/octet-stream", status: http.StatusNotAcceptable},
+		{name: "unknown type", accept: "text/html", status: http.StatusNotAcceptable},
+		{name: "json refused", accept: "application/json;q=0", status: http.StatusNotAcceptable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			require.Equal(t, tt.status, w.Code)
+			if tt.contentType != "" {
+				require.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
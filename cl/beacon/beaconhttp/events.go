@@ -0,0 +1,121 @@
+package beaconhttp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/sse"
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconevents"
+	"github.com/ledgerwatch/log/v3"
+)
+
+const eventStreamContentType = "text/event-stream"
+
+// HandleEventStream serves the /eth/v1/events stream of the topics given in the query, either as
+// repeated `topics` parameters or as a comma separated list.
+func HandleEventStream(emitters *beaconevents.Emitters) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var topics []string
+		for _, param := range r.URL.Query()["topics"] {
+			for _, topic := range strings.Split(param, ",") {
+				topic = strings.TrimSpace(topic)
+				if !beaconevents.IsValidTopic(topic) {
+					NewEndpointError(http.StatusBadRequest, fmt.Sprintf("invalid topic: %q", topic)).WriteTo(w)
+					return
+				}
+				topics = append(topics, topic)
+			}
+		}
+		if len(topics) == 0 {
+			NewEndpointError(http.StatusBadRequest, "at least one topic is required").WriteTo(w)
+			return
+		}
+		if !acceptsEventStream(r.Header.Get("Accept")) {
+			NewEndpointError(http.StatusNotAcceptable, "the events endpoint only serves text/event-stream").WriteTo(w)
+			return
+		}
+		// subscribe before opening the stream, so that no event is missed once the client sees it open
+		events, unsubscribe := emitters.Subscribe(topics)
+		defer unsubscribe()
+
+		rc := http.NewResponseController(w)
+		w.Header().Set("Content-Type", eventStreamContentType)
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		// the stream is long-lived, not every ResponseWriter supports lifting the write deadline though
+		_ = rc.SetWriteDeadline(time.Time{})
+		if err := rc.Flush(); err != nil {
+			log.Debug("beaconapi event stream closed", "err", err)
+			return
+		}
+		bw := bufio.NewWriter(w)
+		sw := sse.NewWriter(bw)
+		for {
+			select {
+			case event := <-events:
+				data, err := json.Marshal(event.Data)
+				if err != nil {
+					log.Error("beaconapi failed to encode event", "topic", event.Topic, "err", err)
+					continue
+				}
+				if err := writeEvent(sw, bw, rc, event.Topic, data); err != nil {
+					log.Debug("beaconapi event stream closed", "err", err)
+					return
+				}
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}
+}
+
+// writeEvent writes an event terminated by an empty line, so that the client dispatches it right away, and
+// flushes it to the client.
+func writeEvent(sw *sse.Writer, bw *bufio.Writer, rc *http.ResponseController, topic string, data []byte) error {
+	if err := sw.Header("event", topic); err != nil {
+		return err
+	}
+	if err := sw.WriteData(bytes.NewReader(data)); err != nil {
+		return err
+	}
+	if err := sw.Next(); err != nil {
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	return rc.Flush()
+}
+
+// acceptsEventStream tells whether the Accept header allows a text/event-stream response, an empty header
+// accepting anything.
+func acceptsEventStream(accept string) bool {
+	if strings.TrimSpace(accept) == "" {
+		return true
+	}
+	for _, mediaRange := range strings.Split(accept, ",") {
+		params := strings.Split(mediaRange, ";")
+		refused := false
+		for _, param := range params[1:] {
+			key, value, found := strings.Cut(param, "=")
+			if found && strings.TrimSpace(key) == "q" {
+				q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+				refused = err != nil || q == 0
+			}
+		}
+		if refused {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(params[0])) {
+		case eventStreamContentType, "text/*", "*/*":
+			return true
+		}
+	}
+	return false
+}
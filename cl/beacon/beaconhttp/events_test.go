@@ -0,0 +1,67 @@
+package beaconhttp
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconevents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcceptsEventStream(t *testing.T) {
+	for accept, ok := range map[string]bool{
+		"":                                    true,
+		"text/event-stream":                   true,
+		"Text/Event-Stream":                   true,
+		"application/json, text/event-stream": true,
+		"text/*":                              true,
+		"*/*":                                 true,
+		"text/event-stream;q=0, */*;q=0.1":    true,
+		"application/json":                    false,
+		"text/event-stream;q=0":               false,
+	} {
+		assert.Equal(t, ok, acceptsEventStream(accept), accept)
+	}
+}
+
+func TestHandleEventStream(t *testing.T) {
+	emitters := beaconevents.NewEmitters()
+	server := httptest.NewServer(HandleEventStream(emitters))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"?topics=head", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	// the stream is open, so the subscription is in place
+	emitters.Publish(beaconevents.TopicHead, map[string]string{"slot": "1"})
+	reader := bufio.NewReader(resp.Body)
+	var lines []string
+	for len(lines) < 3 {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		lines = append(lines, line)
+	}
+	// every event is terminated, so that it is dispatched before the next one is sent
+	assert.Equal(t, []string{"event: head\n", "data: {\"slot\":\"1\"}\n", "\n"}, lines)
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"?topics=head", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "application/json")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusNotAcceptable, resp.StatusCode)
+}
@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/bits"
+	"net/http"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconhttp"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
+	"github.com/ledgerwatch/erigon/cl/transition"
+	"github.com/ledgerwatch/erigon/cl/utils"
+)
+
+type beaconCommitteeSubscription struct {
+	ValidatorIndex   uint64 `json:"validator_index"`
+	CommitteeIndex   uint64 `json:"committee_index"`
+	CommitteesAtSlot uint64 `json:"committees_at_slot"`
+	Slot             uint64 `json:"slot"`
+	IsAggregator     bool   `json:"is_aggregator"`
+}
+
+type proposerPreparation struct {
+	ValidatorIndex uint64            `json:"validator_index"`
+	FeeRecipient   libcommon.Address `json:"fee_recipient"`
+}
+
+func (a *ApiHandler) getAttestationData(r *http.Request) (*beaconResponse, error) {
+	slot, err := uint64FromQueryParams(r, "slot")
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	committeeIndex, err := uint64FromQueryParams(r, "committee_index")
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	if slot == nil || committeeIndex == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, "slot and committee_index are required")
+	}
+	data, err := a.AttestationData(*slot, *committeeIndex)
+	if err != nil {
+		return nil, err
+	}
+	return newBeaconResponse(data), nil
+}
+
+// AttestationData returns the data a committee is expected to attest to at the given slot, on top of the head.
+func (a *ApiHandler) AttestationData(slot, committeeIndex uint64) (solid.AttestationData, error) {
+	if slot > a.forkchoiceStore.Slot() {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Sprintf("slot %d is in the future", slot))
+	}
+
+	headRoot, headSlot, err := a.forkchoiceStore.GetHead()
+	if err != nil {
+		return nil, err
+	}
+	s, err := a.forkchoiceStore.GetStateAtBlockRoot(headRoot, false)
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusServiceUnavailable, "beacon node is syncing")
+	}
+	epoch := slot / a.beaconChainCfg.SlotsPerEpoch
+	if epoch+1 < state.Epoch(s) {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Sprintf("slot %d is too old", slot))
+	}
+	epochStartSlot := epoch * a.beaconChainCfg.SlotsPerEpoch
+	if epoch > state.Epoch(s) {
+		// the justified checkpoint only moves at epoch boundaries, so the head state needs to be advanced to the new epoch.
+		if s, err = s.Copy(); err != nil {
+			return nil, err
+		}
+		if err := transition.DefaultMachine.ProcessSlots(s, epochStartSlot); err != nil {
+			return nil, err
+		}
+	}
+	if committeeIndex >= s.CommitteeCount(epoch) {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Sprintf("committee index %d is out of range", committeeIndex))
+	}
+
+	beaconBlockRoot := headRoot
+	if slot < headSlot {
+		if beaconBlockRoot, err = s.GetBlockRootAtSlot(slot); err != nil {
+			return nil, err
+		}
+	}
+	targetRoot := headRoot
+	if epochStartSlot < headSlot {
+		if targetRoot, err = s.GetBlockRootAtSlot(epochStartSlot); err != nil {
+			return nil, err
+		}
+	}
+	return solid.NewAttestionDataFromParameters(
+		slot,
+		committeeIndex,
+		beaconBlockRoot,
+		s.CurrentJustifiedCheckpoint(),
+		solid.NewCheckpointFromParameters(targetRoot, epoch),
+	), nil
+}
+
+func (a *ApiHandler) getAggregateAttestation(r *http.Request) (*beaconResponse, error) {
+	dataRoot, err := hashFromQueryParams(r, "attestation_data_root")
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	slot, err := uint64FromQueryParams(r, "slot")
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	if dataRoot == nil || slot == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, "attestation_data_root and slot are required")
+	}
+	aggregate, err := a.AggregateAttestation(*dataRoot, *slot)
+	if err != nil {
+		return nil, err
+	}
+	return newBeaconResponse(aggregate), nil
+}
+
+// AggregateAttestation aggregates the pooled attestations of the given slot which attest to the given data.
+func (a *ApiHandler) AggregateAttestation(dataRoot libcommon.Hash, slot uint64) (*solid.Attestation, error) {
+
+	var (
+		aggregationBits []byte
+		signatures      [][]byte
+		data            solid.AttestationData
+	)
+	for _, attestation := range a.operationsPool.AttestationsPool.Raw() {
+		attestationData := attestation.AttestantionData()
+		if attestationData.Slot() != slot {
+			continue
+		}
+		root, err := attestationData.HashSSZ()
+		if err != nil {
+			return nil, err
+		}
+		if root != dataRoot {
+			continue
+		}
+		if aggregationBits == nil {
+			aggregationBits = libcommon.CopyBytes(attestation.AggregationBits())
+			data = attestationData
+		} else if !mergeAggregationBits(aggregationBits, attestation.AggregationBits()) {
+			continue
+		}
+		signature := attestation.Signature()
+		signatures = append(signatures, signature[:])
+	}
+	if aggregationBits == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, "no matching attestations found")
+	}
+	signature, err := utils.AggregateSignatures(signatures)
+	if err != nil {
+		return nil, err
+	}
+	return solid.NewAttestionFromParameters(aggregationBits, data, signature), nil
+}
+
+// mergeAggregationBits sets the bits of other into dst, unless the two bitlists overlap: a validator counted twice
+// would make the aggregated signature invalid.
+func mergeAggregationBits(dst, other []byte) bool {
+	if len(dst) != len(other) || len(dst) == 0 || dst[len(dst)-1] == 0 {
+		return false
+	}
+	// the highest bit of the last byte is the bitlist length marker, and it is the same in both.
+	last := len(dst) - 1
+	marker := byte(1) << (bits.Len8(dst[last]) - 1)
+	if other[last]&marker == 0 {
+		return false
+	}
+	for i := range dst {
+		overlap := dst[i] & other[i]
+		if i == last {
+			overlap &^= marker
+		}
+		if overlap != 0 {
+			return false
+		}
+	}
+	for i := range dst {
+		dst[i] |= other[i]
+	}
+	return true
+}
+
+func (a *ApiHandler) postAggregateAndProofs(r *http.Request) (*beaconResponse, error) {
+	var aggregates []*cltypes.SignedAggregateAndProof
+	if err := json.NewDecoder(r.Body).Decode(&aggregates); err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	if err := a.PublishAggregateAndProofs(r.Context(), aggregates); err != nil {
+		return nil, err
+	}
+	return newBeaconResponse(nil), nil
+}
+
+// PublishAggregateAndProofs pools and gossips aggregates, once fork choice accepted them.
+func (a *ApiHandler) PublishAggregateAndProofs(ctx context.Context, aggregates []*cltypes.SignedAggregateAndProof) error {
+	for i, aggregate := range aggregates {
+		if aggregate.Message == nil || aggregate.Message.Aggregate == nil {
+			return beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Sprintf("aggregate %d: missing aggregate", i))
+		}
+		// the aggregate signature is verified by fork choice, the selection proof is left to the receiving peers.
+		if err := a.forkchoiceStore.OnAttestation(aggregate.Message.Aggregate, false); err != nil {
+			return beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Sprintf("aggregate %d: %s", i, err))
+		}
+		a.operationsPool.AttestationsPool.Insert(aggregate.Message.Aggregate.Signature(), aggregate.Message.Aggregate)
+		if err := a.gossipManager.PublishAggregateAndProof(ctx, aggregate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *ApiHandler) postBeaconCommitteeSubscriptions(r *http.Request) (*beaconResponse, error) {
+	var subscriptions []beaconCommitteeSubscription
+	if err := json.NewDecoder(r.Body).Decode(&subscriptions); err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	currentSlot := a.forkchoiceStore.Slot()
+	for i, subscription := range subscriptions {
+		if subscription.CommitteeIndex >= subscription.CommitteesAtSlot {
+			return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Sprintf("subscription %d: committee index out of range", i))
+		}
+		if subscription.Slot+a.beaconChainCfg.SlotsPerEpoch < currentSlot {
+			return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Sprintf("subscription %d: slot %d is too old", i, subscription.Slot))
+		}
+	}
+	// There is nothing to join, the sentinel is subscribed to every attestation subnet.
+	return newBeaconResponse(nil), nil
+}
+
+func (a *ApiHandler) postPrepareBeaconProposer(r *http.Request) (*beaconResponse, error) {
+	var preparations []proposerPreparation
+	if err := json.NewDecoder(r.Body).Decode(&preparations); err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	a.feeRecipientsMu.Lock()
+	defer a.feeRecipientsMu.Unlock()
+	for _, preparation := range preparations {
+		a.feeRecipients[preparation.ValidatorIndex] = preparation.FeeRecipient
+	}
+	return newBeaconResponse(nil), nil
+}
+
+// feeRecipient returns the fee recipient prepared for the proposer, the zero address if there is none.
+func (a *ApiHandler) feeRecipient(proposerIndex uint64) libcommon.Address {
+	a.feeRecipientsMu.RLock()
+	defer a.feeRecipientsMu.RUnlock()
+	return a.feeRecipients[proposerIndex]
+}
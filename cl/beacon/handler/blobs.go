@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconhttp"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/persistence/beacon_indicies"
+)
+
+// blobIndicesFromRequest parses the `indices` query parameters, either repeated or comma separated. A nil result
+// means that no filter was given.
+func blobIndicesFromRequest(r *http.Request) (map[uint64]struct{}, error) {
+	var indices map[uint64]struct{}
+	for _, param := range r.URL.Query()["indices"] {
+		for _, str := range strings.Split(param, ",") {
+			index, err := strconv.ParseUint(strings.TrimSpace(str), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid blob index: %s", str)
+			}
+			if index >= cltypes.MaxBlobsPerBlock {
+				return nil, fmt.Errorf("blob index %d out of range", index)
+			}
+			if indices == nil {
+				indices = make(map[uint64]struct{})
+			}
+			indices[index] = struct{}{}
+		}
+	}
+	return indices, nil
+}
+
+func (a *ApiHandler) getBlobSidecars(r *http.Request) (*beaconResponse, error) {
+	ctx := r.Context()
+	tx, err := a.indiciesDB.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	blockId, err := blockIdFromRequest(r)
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	indices, err := blobIndicesFromRequest(r)
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	root, err := a.rootFromBlockId(ctx, tx, blockId)
+	if err != nil {
+		return nil, err
+	}
+	slot, err := beacon_indicies.ReadBlockSlotByBlockRoot(tx, root)
+	if err != nil {
+		return nil, err
+	}
+	if slot == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Sprintf("block not found %x", root))
+	}
+
+	// blocks without blobs, or whose blobs were pruned, have no sidecars.
+	sidecars, _, err := a.blobStorage.ReadBlobSidecars(ctx, *slot, root)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*cltypes.BlobSidecar, 0, len(sidecars))
+	for _, sidecar := range sidecars {
+		if _, ok := indices[sidecar.Index]; indices != nil && !ok {
+			continue
+		}
+		out = append(out, sidecar)
+	}
+	return newBeaconResponse(out), nil
+}
@@ -0,0 +1,475 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"math/bits"
+	"net/http"
+	"strconv"
+
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+	"github.com/go-chi/chi/v5"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/crypto/kzg"
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconhttp"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
+	"github.com/ledgerwatch/erigon/cl/transition"
+	"github.com/ledgerwatch/erigon/cl/transition/machine"
+	"github.com/ledgerwatch/erigon/cl/utils"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/turbo/engineapi/engine_types"
+	"github.com/ledgerwatch/log/v3"
+)
+
+// infiniteSignature is the signature of an empty sync aggregate.
+var infiniteSignature = libcommon.Bytes96{0xc0}
+
+// BlockContents is the deneb block production and publication format, the block travels with its blobs.
+type BlockContents struct {
+	Block     *cltypes.BeaconBlock `json:"block,omitempty"`
+	KzgProofs []hexutility.Bytes   `json:"kzg_proofs"`
+	Blobs     []hexutility.Bytes   `json:"blobs"`
+}
+
+// SignedBlockContents is a signed block along with its blobs, which are left empty before deneb.
+type SignedBlockContents struct {
+	SignedBlock *cltypes.SignedBeaconBlock `json:"signed_block"`
+	KzgProofs   []hexutility.Bytes         `json:"kzg_proofs"`
+	Blobs       []hexutility.Bytes         `json:"blobs"`
+}
+
+func (a *ApiHandler) getBlockProduction(r *http.Request) (*beaconResponse, error) {
+	slot, err := strconv.ParseUint(chi.URLParam(r, "slot"), 10, 64)
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Sprintf("invalid slot: %s", err))
+	}
+	var randaoReveal libcommon.Bytes96
+	if err := randaoReveal.UnmarshalText([]byte(r.URL.Query().Get("randao_reveal"))); err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Sprintf("invalid randao_reveal: %s", err))
+	}
+	graffiti, err := hashFromQueryParams(r, "graffiti")
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	if graffiti == nil {
+		graffiti = &libcommon.Hash{}
+	}
+	contents, err := a.ProduceBlock(slot, randaoReveal, *graffiti)
+	if err != nil {
+		return nil, err
+	}
+	version := contents.Block.Version()
+	if version < clparams.DenebVersion {
+		return newBeaconResponse(contents.Block).withVersion(version), nil
+	}
+	return newBeaconResponse(contents).withVersion(version), nil
+}
+
+// ProduceBlock builds an unsigned block on top of the head for the given slot, with the randao reveal of the proposer.
+// The blobs of the execution payload are returned along with the block.
+func (a *ApiHandler) ProduceBlock(slot uint64, randaoReveal libcommon.Bytes96, graffiti libcommon.Hash) (*BlockContents, error) {
+	headRoot, headSlot, err := a.forkchoiceStore.GetHead()
+	if err != nil {
+		return nil, err
+	}
+	if slot <= headSlot {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Sprintf("slot %d is not after the head slot %d", slot, headSlot))
+	}
+	s, err := a.forkchoiceStore.GetStateAtBlockRoot(headRoot, true)
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusServiceUnavailable, "beacon node is syncing")
+	}
+	if err := transition.DefaultMachine.ProcessSlots(s, slot); err != nil {
+		return nil, err
+	}
+	proposerIndex, err := s.GetBeaconProposerIndex()
+	if err != nil {
+		return nil, err
+	}
+	if s.Eth1Data().DepositCount > s.Eth1DepositIndex() {
+		return nil, beaconhttp.NewEndpointError(http.StatusServiceUnavailable, "cannot produce a block with pending deposits")
+	}
+
+	version := s.Version()
+	block := cltypes.NewBeaconBlock(a.beaconChainCfg)
+	block.Slot = slot
+	block.ProposerIndex = proposerIndex
+	block.ParentRoot = headRoot
+	body := block.Body
+	body.Version = version
+	body.RandaoReveal = randaoReveal
+	body.Graffiti = graffiti
+	body.Eth1Data = s.Eth1Data().Copy()
+	body.Deposits = solid.NewStaticListSSZ[*cltypes.Deposit](cltypes.MaxDeposits, 1240)
+	if body.SyncAggregate, err = a.produceSyncAggregate(s, slot, headRoot); err != nil {
+		return nil, err
+	}
+	body.BlobKzgCommitments = solid.NewStaticListSSZ[*cltypes.KZGCommitment](cltypes.MaxBlobsCommittmentsPerBlock, 48)
+	if err := a.packOperations(s, body); err != nil {
+		return nil, err
+	}
+
+	var blobsBundle *engine_types.BlobsBundleV1
+	body.ExecutionPayload = cltypes.NewEth1Block(version, a.beaconChainCfg)
+	if version >= clparams.BellatrixVersion {
+		if !state.IsMergeTransitionComplete(s) {
+			return nil, beaconhttp.NewEndpointError(http.StatusServiceUnavailable, "cannot produce a block before the merge transition")
+		}
+		if body.ExecutionPayload, blobsBundle, err = a.produceExecutionPayload(s, headRoot, proposerIndex); err != nil {
+			return nil, err
+		}
+	}
+	if blobsBundle != nil {
+		for _, commitment := range blobsBundle.Commitments {
+			var c cltypes.KZGCommitment
+			copy(c[:], commitment)
+			body.BlobKzgCommitments.Append(&c)
+		}
+	}
+
+	// the randao reveal is the only part of the block signed by the validator client at this point, check it.
+	if err := machine.ProcessBlock(transition.ValidatingMachine, s, &cltypes.SignedBeaconBlock{Block: block}); err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	if block.StateRoot, err = s.HashSSZ(); err != nil {
+		return nil, err
+	}
+	contents := &BlockContents{Block: block, KzgProofs: []hexutility.Bytes{}, Blobs: []hexutility.Bytes{}}
+	if blobsBundle != nil {
+		contents.KzgProofs = blobsBundle.Proofs
+		contents.Blobs = blobsBundle.Blobs
+	}
+	return contents, nil
+}
+
+// packOperations fills the block body with the pooled operations which are still valid on top of the given state.
+// Operations are tried in block processing order on a scratch copy, so that they do not conflict with each other.
+func (a *ApiHandler) packOperations(s *state.CachingBeaconState, body *cltypes.BeaconBody) error {
+	scratch, err := s.Copy()
+	if err != nil {
+		return err
+	}
+	impl := transition.DefaultMachine
+
+	body.ProposerSlashings = solid.NewStaticListSSZ[*cltypes.ProposerSlashing](cltypes.MaxProposerSlashings, 416)
+	for _, slashing := range a.operationsPool.ProposerSlashingsPool.Raw() {
+		if body.ProposerSlashings.Len() >= cltypes.MaxProposerSlashings {
+			break
+		}
+		if impl.ProcessProposerSlashing(scratch, slashing) == nil {
+			body.ProposerSlashings.Append(slashing)
+		}
+	}
+	body.AttesterSlashings = solid.NewDynamicListSSZ[*cltypes.AttesterSlashing](cltypes.MaxAttesterSlashings)
+	for _, slashing := range a.operationsPool.AttesterSlashingsPool.Raw() {
+		if body.AttesterSlashings.Len() >= cltypes.MaxAttesterSlashings {
+			break
+		}
+		if impl.ProcessAttesterSlashing(scratch, slashing) == nil {
+			body.AttesterSlashings.Append(slashing)
+		}
+	}
+	body.Attestations = solid.NewDynamicListSSZ[*solid.Attestation](cltypes.MaxAttestations)
+	for _, attestation := range a.operationsPool.AttestationsPool.Raw() {
+		if body.Attestations.Len() >= cltypes.MaxAttestations {
+			break
+		}
+		single := solid.NewDynamicListSSZ[*solid.Attestation](1)
+		single.Append(attestation)
+		if impl.ProcessAttestations(scratch, single) == nil {
+			body.Attestations.Append(attestation)
+		}
+	}
+	body.VoluntaryExits = solid.NewStaticListSSZ[*cltypes.SignedVoluntaryExit](cltypes.MaxVoluntaryExits, 112)
+	for _, exit := range a.operationsPool.VoluntaryExistsPool.Raw() {
+		if body.VoluntaryExits.Len() >= cltypes.MaxVoluntaryExits {
+			break
+		}
+		if impl.ProcessVoluntaryExit(scratch, exit) == nil {
+			body.VoluntaryExits.Append(exit)
+		}
+	}
+	body.ExecutionChanges = solid.NewStaticListSSZ[*cltypes.SignedBLSToExecutionChange](cltypes.MaxExecutionChanges, 172)
+	if body.Version < clparams.CapellaVersion {
+		return nil
+	}
+	for _, change := range a.operationsPool.BLSToExecutionChangesPool.Raw() {
+		if body.ExecutionChanges.Len() >= cltypes.MaxExecutionChanges {
+			break
+		}
+		if impl.ProcessBlsToExecutionChange(scratch, change) == nil {
+			body.ExecutionChanges.Append(change)
+		}
+	}
+	return nil
+}
+
+// produceSyncAggregate merges the pooled contributions of the previous slot for the parent block, keeping the best
+// contribution of each subcommittee since overlapping contributions cannot be aggregated. The aggregate is checked on
+// a scratch copy of the state, and an empty one is used if it does not verify.
+func (a *ApiHandler) produceSyncAggregate(s *state.CachingBeaconState, slot uint64, parentRoot libcommon.Hash) (*cltypes.SyncAggregate, error) {
+	empty := &cltypes.SyncAggregate{SyncCommiteeSignature: infiniteSignature}
+	if s.Version() < clparams.AltairVersion || slot == 0 {
+		return empty, nil
+	}
+	best := make(map[uint64]*cltypes.SyncCommitteeContribution)
+	for _, contribution := range a.operationsPool.SyncContributionsPool.Raw() {
+		if contribution.Slot != slot-1 || contribution.BeaconBlockRoot != parentRoot ||
+			contribution.SubcommitteeIndex >= a.beaconChainCfg.SyncCommitteeSubnetCount {
+			continue
+		}
+		if current, ok := best[contribution.SubcommitteeIndex]; !ok || bitsCount(contribution.AggregationBits) > bitsCount(current.AggregationBits) {
+			best[contribution.SubcommitteeIndex] = contribution
+		}
+	}
+	if len(best) == 0 {
+		return empty, nil
+	}
+
+	aggregate := &cltypes.SyncAggregate{}
+	subcommitteeSize := a.beaconChainCfg.SyncCommitteeSize / a.beaconChainCfg.SyncCommitteeSubnetCount
+	signatures := make([][]byte, 0, len(best))
+	for subcommitteeIndex, contribution := range best {
+		for i := uint64(0); i < subcommitteeSize; i++ {
+			if contribution.AggregationBits[i/8]&(1<<(i%8)) == 0 {
+				continue
+			}
+			position := subcommitteeIndex*subcommitteeSize + i
+			aggregate.SyncCommiteeBits[position/8] |= 1 << (position % 8)
+		}
+		signatures = append(signatures, libcommon.CopyBytes(contribution.Signature[:]))
+	}
+	signature, err := utils.AggregateSignatures(signatures)
+	if err != nil {
+		return nil, err
+	}
+	aggregate.SyncCommiteeSignature = signature
+
+	scratch, err := s.Copy()
+	if err != nil {
+		return nil, err
+	}
+	if err := transition.ValidatingMachine.ProcessSyncAggregate(scratch, aggregate); err != nil {
+		log.Warn("[Beacon API] Dropping the invalid sync aggregate of the produced block", "slot", slot, "err", err)
+		return empty, nil
+	}
+	return aggregate, nil
+}
+
+func bitsCount(b []byte) (count int) {
+	for _, v := range b {
+		count += bits.OnesCount8(v)
+	}
+	return
+}
+
+// produceExecutionPayload asks the execution engine to build a payload on top of the state latest payload.
+func (a *ApiHandler) produceExecutionPayload(s *state.CachingBeaconState, parentRoot libcommon.Hash, proposerIndex uint64) (*cltypes.Eth1Block, *engine_types.BlobsBundleV1, error) {
+	engine := a.forkchoiceStore.Engine()
+	if engine == nil {
+		return nil, nil, beaconhttp.NewEndpointError(http.StatusServiceUnavailable, "no execution engine available")
+	}
+	version := s.Version()
+	attributes := &engine_types.PayloadAttributes{
+		Timestamp:             hexutil.Uint64(state.ComputeTimestampAtSlot(s, s.Slot())),
+		PrevRandao:            s.GetRandaoMixes(state.Epoch(s)),
+		SuggestedFeeRecipient: a.feeRecipient(proposerIndex),
+	}
+	if version >= clparams.CapellaVersion {
+		attributes.Withdrawals = []*types.Withdrawal{}
+		for _, w := range state.ExpectedWithdrawals(s) {
+			attributes.Withdrawals = append(attributes.Withdrawals, &types.Withdrawal{
+				Index:     w.Index,
+				Validator: w.Validator,
+				Address:   w.Address,
+				Amount:    w.Amount,
+			})
+		}
+	}
+	if version >= clparams.DenebVersion {
+		attributes.ParentBeaconBlockRoot = &parentRoot
+	}
+	payloadId, err := engine.AssembleBlock(s.LatestExecutionPayloadHeader().BlockHash, attributes)
+	if err != nil {
+		return nil, nil, err
+	}
+	payload, blobsBundle, _, err := engine.GetAssembledBlock(payloadId, version)
+	if err != nil {
+		return nil, nil, err
+	}
+	return a.eth1BlockFromPayload(payload, version), blobsBundle, nil
+}
+
+func (a *ApiHandler) eth1BlockFromPayload(payload *engine_types.ExecutionPayload, version clparams.StateVersion) *cltypes.Eth1Block {
+	block := cltypes.NewEth1Block(version, a.beaconChainCfg)
+	block.ParentHash = payload.ParentHash
+	block.FeeRecipient = payload.FeeRecipient
+	block.StateRoot = payload.StateRoot
+	block.ReceiptsRoot = payload.ReceiptsRoot
+	block.LogsBloom = types.BytesToBloom(payload.LogsBloom)
+	block.PrevRandao = payload.PrevRandao
+	block.BlockNumber = uint64(payload.BlockNumber)
+	block.GasLimit = uint64(payload.GasLimit)
+	block.GasUsed = uint64(payload.GasUsed)
+	block.Time = uint64(payload.Timestamp)
+	block.Extra = solid.NewExtraData()
+	block.Extra.SetBytes(payload.ExtraData)
+	// the base fee is little endian on the consensus side.
+	baseFee := (*big.Int)(payload.BaseFeePerGas).Bytes()
+	for i, j := 0, len(baseFee)-1; i < j; i, j = i+1, j-1 {
+		baseFee[i], baseFee[j] = baseFee[j], baseFee[i]
+	}
+	copy(block.BaseFeePerGas[:], baseFee)
+	block.BlockHash = payload.BlockHash
+	transactions := make([][]byte, len(payload.Transactions))
+	for i, tx := range payload.Transactions {
+		transactions[i] = tx
+	}
+	block.Transactions = solid.NewTransactionsSSZFromTransactions(transactions)
+	block.Withdrawals = solid.NewStaticListSSZ[*cltypes.Withdrawal](int(a.beaconChainCfg.MaxWithdrawalsPerPayload), 44)
+	for _, w := range payload.Withdrawals {
+		block.Withdrawals.Append(&cltypes.Withdrawal{Index: w.Index, Validator: w.Validator, Address: w.Address, Amount: w.Amount})
+	}
+	if payload.BlobGasUsed != nil {
+		block.BlobGasUsed = uint64(*payload.BlobGasUsed)
+	}
+	if payload.ExcessBlobGas != nil {
+		block.ExcessBlobGas = uint64(*payload.ExcessBlobGas)
+	}
+	return block
+}
+
+func (a *ApiHandler) postBlock(r *http.Request) (*beaconResponse, error) {
+	buf, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	// the fork, and so the shape of the body, is only known from the slot.
+	var header struct {
+		Message *struct {
+			Slot uint64 `json:"slot"`
+		} `json:"message"`
+		SignedBlock *struct {
+			Message *struct {
+				Slot uint64 `json:"slot"`
+			} `json:"message"`
+		} `json:"signed_block"`
+	}
+	if err := json.Unmarshal(buf, &header); err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	var slot uint64
+	switch {
+	case header.Message != nil:
+		slot = header.Message.Slot
+	case header.SignedBlock != nil && header.SignedBlock.Message != nil:
+		slot = header.SignedBlock.Message.Slot
+	default:
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, "missing block message")
+	}
+	version := a.beaconChainCfg.GetCurrentStateVersion(slot / a.beaconChainCfg.SlotsPerEpoch)
+
+	block := cltypes.NewSignedBeaconBlock(a.beaconChainCfg)
+	block.Block.Body.Version = version
+	contents := &SignedBlockContents{SignedBlock: block}
+	if version >= clparams.DenebVersion {
+		if err := json.Unmarshal(buf, contents); err != nil {
+			return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+		}
+	} else if err := json.Unmarshal(buf, block); err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	if err := a.PublishBlock(r.Context(), contents); err != nil {
+		return nil, err
+	}
+	return newBeaconResponse(nil), nil
+}
+
+// PublishBlock imports a signed block and gossips it, along with the sidecars of its blobs.
+func (a *ApiHandler) PublishBlock(ctx context.Context, contents *SignedBlockContents) error {
+	block := contents.SignedBlock
+	var sidecars []*cltypes.BlobSidecar
+	if block.Version() >= clparams.DenebVersion {
+		var err error
+		if sidecars, err = blobSidecarsFromContents(contents); err != nil {
+			return beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	// the block is imported before being broadcast, so that an invalid block never reaches the network.
+	if err := a.forkchoiceStore.OnBlock(block, true, true); err != nil {
+		return beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Sprintf("invalid block: %s", err))
+	}
+	blockRoot, err := block.Block.HashSSZ()
+	if err != nil {
+		return err
+	}
+	if err := a.blobStorage.WriteBlobSidecars(ctx, blockRoot, sidecars); err != nil {
+		return err
+	}
+	if err := a.gossipManager.PublishBlock(ctx, block); err != nil {
+		return err
+	}
+	for _, sidecar := range sidecars {
+		if err := a.gossipManager.PublishBlobSidecar(ctx, blockRoot, sidecar); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// blobSidecarsFromContents builds the sidecars of a submitted deneb block, after checking the blobs against the
+// commitments of the block.
+func blobSidecarsFromContents(contents *SignedBlockContents) ([]*cltypes.BlobSidecar, error) {
+	body := contents.SignedBlock.Block.Body
+	commitmentsCount := body.BlobKzgCommitments.Len()
+	if len(contents.Blobs) != commitmentsCount || len(contents.KzgProofs) != commitmentsCount {
+		return nil, fmt.Errorf("expected %d blobs and proofs, got %d blobs and %d proofs", commitmentsCount, len(contents.Blobs), len(contents.KzgProofs))
+	}
+	if commitmentsCount == 0 {
+		return nil, nil
+	}
+	header := contents.SignedBlock.SignedBeaconBlockHeader()
+	blobs := make([]gokzg4844.Blob, commitmentsCount)
+	commitments := make([]gokzg4844.KZGCommitment, commitmentsCount)
+	proofs := make([]gokzg4844.KZGProof, commitmentsCount)
+	sidecars := make([]*cltypes.BlobSidecar, commitmentsCount)
+	for i := range sidecars {
+		if len(contents.Blobs[i]) != len(blobs[i]) || len(contents.KzgProofs[i]) != len(proofs[i]) {
+			return nil, fmt.Errorf("blob %d: invalid blob or proof length", i)
+		}
+		copy(blobs[i][:], contents.Blobs[i])
+		copy(proofs[i][:], contents.KzgProofs[i])
+		commitments[i] = gokzg4844.KZGCommitment(*body.BlobKzgCommitments.Get(i))
+
+		branch, err := body.KzgCommitmentMerkleProof(i)
+		if err != nil {
+			return nil, err
+		}
+		sidecar := cltypes.NewBlobSidecar()
+		sidecar.Index = uint64(i)
+		sidecar.Blob = cltypes.Blob(blobs[i])
+		sidecar.KzgCommitment = libcommon.Bytes48(commitments[i])
+		sidecar.KzgProof = libcommon.Bytes48(proofs[i])
+		sidecar.SignedBlockHeader = header
+		for j, h := range branch {
+			sidecar.CommitmentInclusionProof.Set(j, h)
+		}
+		sidecars[i] = sidecar
+	}
+	if err := kzg.Ctx().VerifyBlobKZGProofBatch(blobs, commitments, proofs); err != nil {
+		return nil, fmt.Errorf("invalid blobs: %w", err)
+	}
+	return sidecars, nil
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconhttp"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
+)
+
+type committeeResponse struct {
+	Index      uint64   `json:"index"`
+	Slot       uint64   `json:"slot"`
+	Validators []uint64 `json:"validators"`
+}
+
+type syncCommitteesResponse struct {
+	Validators          []uint64   `json:"validators"`
+	ValidatorAggregates [][]uint64 `json:"validator_aggregates"`
+}
+
+func (a *ApiHandler) getCommittees(r *http.Request) (*beaconResponse, error) {
+	ctx := r.Context()
+
+	tx, err := a.indiciesDB.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	s, finalized, err := a.stateFromRequest(ctx, tx, r)
+	if err != nil {
+		return nil, err
+	}
+	epochMaybe, err := uint64FromQueryParams(r, "epoch")
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	indexMaybe, err := uint64FromQueryParams(r, "index")
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	slotMaybe, err := uint64FromQueryParams(r, "slot")
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+
+	stateEpoch := state.Epoch(s)
+	epoch := stateEpoch
+	if epochMaybe != nil {
+		epoch = *epochMaybe
+	}
+	// the shuffling is only known from the randao mixes of the state for the previous, current and next epoch.
+	if epoch > stateEpoch+1 || epoch+1 < stateEpoch {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Sprintf("epoch %d is out of range for the state at epoch %d", epoch, stateEpoch))
+	}
+	if slotMaybe != nil && *slotMaybe/a.beaconChainCfg.SlotsPerEpoch != epoch {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Sprintf("slot %d is not in epoch %d", *slotMaybe, epoch))
+	}
+
+	committeesPerSlot := s.CommitteeCount(epoch)
+	committees := []committeeResponse{}
+	for slot := epoch * a.beaconChainCfg.SlotsPerEpoch; slot < (epoch+1)*a.beaconChainCfg.SlotsPerEpoch; slot++ {
+		if slotMaybe != nil && slot != *slotMaybe {
+			continue
+		}
+		for index := uint64(0); index < committeesPerSlot; index++ {
+			if indexMaybe != nil && index != *indexMaybe {
+				continue
+			}
+			validators, err := s.GetBeaconCommitee(slot, index)
+			if err != nil {
+				return nil, err
+			}
+			committees = append(committees, committeeResponse{Index: index, Slot: slot, Validators: validators})
+		}
+	}
+	return newBeaconResponse(committees).withFinalized(finalized), nil
+}
+
+func (a *ApiHandler) getSyncCommittees(r *http.Request) (*beaconResponse, error) {
+	ctx := r.Context()
+
+	tx, err := a.indiciesDB.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	s, finalized, err := a.stateFromRequest(ctx, tx, r)
+	if err != nil {
+		return nil, err
+	}
+	if s.Version() < clparams.AltairVersion {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, "sync committees are not available before altair")
+	}
+	epochMaybe, err := uint64FromQueryParams(r, "epoch")
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+
+	statePeriod := state.Epoch(s) / a.beaconChainCfg.EpochsPerSyncCommitteePeriod
+	period := statePeriod
+	if epochMaybe != nil {
+		period = *epochMaybe / a.beaconChainCfg.EpochsPerSyncCommitteePeriod
+	}
+	committee := s.CurrentSyncCommittee()
+	switch period {
+	case statePeriod:
+	case statePeriod + 1:
+		committee = s.NextSyncCommittee()
+	default:
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Sprintf("epoch %d is out of range for the state sync committees", *epochMaybe))
+	}
+
+	pubkeys := committee.GetCommittee()
+	validators := make([]uint64, len(pubkeys))
+	for i, pk := range pubkeys {
+		idx, ok := s.ValidatorIndexByPubkey(pk)
+		if !ok {
+			return nil, fmt.Errorf("sync committee member %x is not a validator", pk)
+		}
+		validators[i] = idx
+	}
+	subcommitteeSize := uint64(len(validators)) / a.beaconChainCfg.SyncCommitteeSubnetCount
+	aggregates := make([][]uint64, a.beaconChainCfg.SyncCommitteeSubnetCount)
+	for i := range aggregates {
+		aggregates[i] = validators[uint64(i)*subcommitteeSize : uint64(i+1)*subcommitteeSize]
+	}
+	return newBeaconResponse(&syncCommitteesResponse{Validators: validators, ValidatorAggregates: aggregates}).withFinalized(finalized), nil
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconhttp"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
+)
+
+// AttesterDuty is the committee assignment of a validator at a slot.
+type AttesterDuty struct {
+	Pubkey                  libcommon.Bytes48 `json:"pubkey"`
+	ValidatorIndex          uint64            `json:"validator_index"`
+	CommitteeIndex          uint64            `json:"committee_index"`
+	CommitteeLength         uint64            `json:"committee_length"`
+	CommitteesAtSlot        uint64            `json:"committees_at_slot"`
+	ValidatorCommitteeIndex uint64            `json:"validator_committee_index"`
+	Slot                    uint64            `json:"slot"`
+}
+
+// validatorIndicesFromBody parses the list of validator indices posted to the duties endpoints. Indices may be
+// given either as numbers or as decimal strings.
+func validatorIndicesFromBody(r *http.Request) (map[uint64]struct{}, error) {
+	var numbers []json.Number
+	if err := json.NewDecoder(r.Body).Decode(&numbers); err != nil {
+		return nil, err
+	}
+	indices := make(map[uint64]struct{}, len(numbers))
+	for _, number := range numbers {
+		idx, err := strconv.ParseUint(number.String(), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid validator index: %s", number)
+		}
+		indices[idx] = struct{}{}
+	}
+	return indices, nil
+}
+
+func (a *ApiHandler) getDutiesAttester(r *http.Request) (*beaconResponse, error) {
+	epoch, err := epochFromRequest(r)
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	indices, err := validatorIndicesFromBody(r)
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	duties, dependentRoot, err := a.AttesterDuties(epoch, indices)
+	if err != nil {
+		return nil, err
+	}
+	return newBeaconResponse(duties).withFinalized(false).withDependentRoot(dependentRoot), nil
+}
+
+// AttesterDuties returns the committee assignments of the given validators during the epoch, along with the root of
+// the block the assignments depend on.
+func (a *ApiHandler) AttesterDuties(epoch uint64, indices map[uint64]struct{}) ([]AttesterDuty, libcommon.Hash, error) {
+	s, cancel := a.syncedData.HeadState()
+	defer cancel()
+	if s == nil {
+		return nil, libcommon.Hash{}, beaconhttp.NewEndpointError(http.StatusServiceUnavailable, "beacon node is syncing")
+	}
+	stateEpoch := state.Epoch(s)
+	// the shuffling is only known from the head state for the previous, current and next epoch.
+	if epoch > stateEpoch+1 || epoch+1 < stateEpoch {
+		return nil, libcommon.Hash{}, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Sprintf("epoch %d is out of range for the head state at epoch %d", epoch, stateEpoch))
+	}
+
+	// attester duties depend on the randao mix at the end of epoch-2, which is fixed by the last block before epoch-1.
+	var dependentSlot uint64
+	if epoch > 1 {
+		dependentSlot = (epoch-1)*a.beaconChainCfg.SlotsPerEpoch - 1
+	}
+	dependentRoot, err := s.GetBlockRootAtSlot(dependentSlot)
+	if err != nil {
+		return nil, libcommon.Hash{}, err
+	}
+
+	committeesPerSlot := s.CommitteeCount(epoch)
+	duties := []AttesterDuty{}
+	for slot := epoch * a.beaconChainCfg.SlotsPerEpoch; slot < (epoch+1)*a.beaconChainCfg.SlotsPerEpoch; slot++ {
+		for committeeIndex := uint64(0); committeeIndex < committeesPerSlot; committeeIndex++ {
+			committee, err := s.GetBeaconCommitee(slot, committeeIndex)
+			if err != nil {
+				return nil, libcommon.Hash{}, err
+			}
+			for position, validatorIndex := range committee {
+				if _, ok := indices[validatorIndex]; !ok {
+					continue
+				}
+				pk, err := s.ValidatorPublicKey(int(validatorIndex))
+				if err != nil {
+					return nil, libcommon.Hash{}, err
+				}
+				duties = append(duties, AttesterDuty{
+					Pubkey:                  pk,
+					ValidatorIndex:          validatorIndex,
+					CommitteeIndex:          committeeIndex,
+					CommitteeLength:         uint64(len(committee)),
+					CommitteesAtSlot:        committeesPerSlot,
+					ValidatorCommitteeIndex: uint64(position),
+					Slot:                    slot,
+				})
+			}
+		}
+	}
+	return duties, dependentRoot, nil
+}
@@ -12,19 +12,27 @@ import (
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 )
 
-type proposerDuties struct {
+// ProposerDuty is the proposal assigned to a validator at a slot.
+type ProposerDuty struct {
 	Pubkey         libcommon.Bytes48 `json:"pubkey"`
 	ValidatorIndex uint64            `json:"validator_index"`
 	Slot           uint64            `json:"slot"`
 }
 
 func (a *ApiHandler) getDutiesProposer(r *http.Request) (*beaconResponse, error) {
-
 	epoch, err := epochFromRequest(r)
 	if err != nil {
 		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
 	}
+	duties, err := a.ProposerDuties(epoch)
+	if err != nil {
+		return nil, err
+	}
+	return newBeaconResponse(duties).withFinalized(false).withVersion(a.beaconChainCfg.GetCurrentStateVersion(epoch)), nil
+}
 
+// ProposerDuties returns the proposer of each slot of the epoch.
+func (a *ApiHandler) ProposerDuties(epoch uint64) ([]ProposerDuty, error) {
 	if epoch < a.forkchoiceStore.FinalizedCheckpoint().Epoch() {
 		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, "invalid epoch")
 	}
@@ -39,11 +47,10 @@ func (a *ApiHandler) getDutiesProposer(r *http.Request) (*beaconResponse, error)
 
 	expectedSlot := epoch * a.beaconChainCfg.SlotsPerEpoch
 
-	duties := make([]proposerDuties, a.beaconChainCfg.SlotsPerEpoch)
+	duties := make([]ProposerDuty, a.beaconChainCfg.SlotsPerEpoch)
 	wg := sync.WaitGroup{}
 
 	for slot := expectedSlot; slot < expectedSlot+a.beaconChainCfg.SlotsPerEpoch; slot++ {
-		var proposerIndex uint64
 		// Lets do proposer index computation
 		mixPosition := (epoch + a.beaconChainCfg.EpochsPerHistoricalVector - a.beaconChainCfg.MinSeedLookahead - 1) %
 			a.beaconChainCfg.EpochsPerHistoricalVector
@@ -71,16 +78,15 @@ func (a *ApiHandler) getDutiesProposer(r *http.Request) (*beaconResponse, error)
 		// Do it in parallel
 		go func(i, slot uint64, indicies []uint64, seedArray [32]byte) {
 			defer wg.Done()
-			proposerIndex, err = shuffling2.ComputeProposerIndex(state.BeaconState, indices, seedArray)
+			proposerIndex, err := shuffling2.ComputeProposerIndex(state.BeaconState, indices, seedArray)
 			if err != nil {
 				panic(err)
 			}
-			var pk libcommon.Bytes48
-			pk, err = state.ValidatorPublicKey(int(proposerIndex))
+			pk, err := state.ValidatorPublicKey(int(proposerIndex))
 			if err != nil {
 				panic(err)
 			}
-			duties[i] = proposerDuties{
+			duties[i] = ProposerDuty{
 				Pubkey:         pk,
 				ValidatorIndex: proposerIndex,
 				Slot:           slot,
@@ -88,7 +94,5 @@ func (a *ApiHandler) getDutiesProposer(r *http.Request) (*beaconResponse, error)
 		}(slot-expectedSlot, slot, indices, seedArray)
 	}
 	wg.Wait()
-
-	return newBeaconResponse(duties).withFinalized(false).withVersion(a.beaconChainCfg.GetCurrentStateVersion(epoch)), nil
-
+	return duties, nil
 }
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconhttp"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
+)
+
+// SyncDuty is the membership of a validator in a sync committee, at each of its positions in the committee.
+type SyncDuty struct {
+	Pubkey                        libcommon.Bytes48 `json:"pubkey"`
+	ValidatorIndex                uint64            `json:"validator_index"`
+	ValidatorSyncCommitteeIndices []uint64          `json:"validator_sync_committee_indices"`
+}
+
+func (a *ApiHandler) getDutiesSync(r *http.Request) (*beaconResponse, error) {
+	epoch, err := epochFromRequest(r)
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	indices, err := validatorIndicesFromBody(r)
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	duties, err := a.SyncDuties(epoch, indices)
+	if err != nil {
+		return nil, err
+	}
+	return newBeaconResponse(duties).withFinalized(false), nil
+}
+
+// SyncDuties returns the sync committee positions of the given validators in the committee of the epoch.
+func (a *ApiHandler) SyncDuties(epoch uint64, indices map[uint64]struct{}) ([]SyncDuty, error) {
+	if a.beaconChainCfg.GetCurrentStateVersion(epoch) < clparams.AltairVersion {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, "sync committees are not available before altair")
+	}
+
+	s, cancel := a.syncedData.HeadState()
+	defer cancel()
+	if s == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusServiceUnavailable, "beacon node is syncing")
+	}
+	if s.Version() < clparams.AltairVersion {
+		return nil, beaconhttp.NewEndpointError(http.StatusServiceUnavailable, "head state is not past the altair fork yet")
+	}
+
+	statePeriod := state.Epoch(s) / a.beaconChainCfg.EpochsPerSyncCommitteePeriod
+	committee := s.CurrentSyncCommittee()
+	switch epoch / a.beaconChainCfg.EpochsPerSyncCommitteePeriod {
+	case statePeriod:
+	case statePeriod + 1:
+		committee = s.NextSyncCommittee()
+	default:
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Sprintf("epoch %d is out of range for the head state sync committees", epoch))
+	}
+
+	// a validator can sit more than once in the same committee.
+	positions := make(map[uint64][]uint64)
+	for i, pk := range committee.GetCommittee() {
+		idx, ok := s.ValidatorIndexByPubkey(pk)
+		if !ok {
+			return nil, fmt.Errorf("sync committee member %x is not a validator", pk)
+		}
+		if _, requested := indices[idx]; requested {
+			positions[idx] = append(positions[idx], uint64(i))
+		}
+	}
+	duties := make([]SyncDuty, 0, len(positions))
+	for idx, committeeIndices := range positions {
+		pk, err := s.ValidatorPublicKey(int(idx))
+		if err != nil {
+			return nil, err
+		}
+		duties = append(duties, SyncDuty{
+			Pubkey:                        pk,
+			ValidatorIndex:                idx,
+			ValidatorSyncCommitteeIndices: committeeIndices,
+		})
+	}
+	// the map iteration order is random, keep the response stable.
+	sort.Slice(duties, func(i, j int) bool {
+		return duties[i].ValidatorIndex < duties[j].ValidatorIndex
+	})
+	return duties, nil
+}
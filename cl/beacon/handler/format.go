@@ -23,6 +23,7 @@ type beaconResponse struct {
 	Finalized           *bool                  `json:"finalized,omitempty"`
 	Version             *clparams.StateVersion `json:"version,omitempty"`
 	ExecutionOptimistic *bool                  `json:"execution_optimistic,omitempty"`
+	DependentRoot       *libcommon.Hash        `json:"dependent_root,omitempty"`
 }
 
 func (b *beaconResponse) EncodeSSZ(xs []byte) ([]byte, error) {
@@ -45,6 +46,20 @@ func (b *beaconResponse) EncodingSizeSSZ() int {
 	return marshaler.EncodingSizeSSZ()
 }
 
+// SupportsSSZ tells whether the wrapped data can be sent as SSZ.
+func (b *beaconResponse) SupportsSSZ() bool {
+	_, ok := b.Data.(ssz.Marshaler)
+	return ok
+}
+
+// ConsensusVersion returns the fork of the wrapped data, if any.
+func (b *beaconResponse) ConsensusVersion() (clparams.StateVersion, bool) {
+	if b.Version == nil {
+		return 0, false
+	}
+	return *b.Version, true
+}
+
 func newBeaconResponse(data any) *beaconResponse {
 	return &beaconResponse{
 		Data: data,
@@ -57,7 +72,7 @@ func (r *beaconResponse) withFinalized(finalized bool) (out *beaconResponse) {
 	out.Finalized = new(bool)
 	out.ExecutionOptimistic = new(bool)
 	out.Finalized = &finalized
-	return r
+	return out
 }
 
 func (r *beaconResponse) withVersion(version clparams.StateVersion) (out *beaconResponse) {
@@ -65,7 +80,14 @@ func (r *beaconResponse) withVersion(version clparams.StateVersion) (out *beacon
 	*out = *r
 	out.Version = new(clparams.StateVersion)
 	out.Version = &version
-	return r
+	return out
+}
+
+func (r *beaconResponse) withDependentRoot(root libcommon.Hash) (out *beaconResponse) {
+	out = new(beaconResponse)
+	*out = *r
+	out.DependentRoot = &root
+	return out
 }
 
 //// In case of it being a json we need to also expose finalization, version, etc...
@@ -5,12 +5,18 @@ import (
 	"sync"
 
 	"github.com/go-chi/chi/v5"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconevents"
 	"github.com/ledgerwatch/erigon/cl/beacon/beaconhttp"
 	"github.com/ledgerwatch/erigon/cl/beacon/synced_data"
 	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/light_client"
 	"github.com/ledgerwatch/erigon/cl/persistence"
+	"github.com/ledgerwatch/erigon/cl/persistence/blob_storage"
+	"github.com/ledgerwatch/erigon/cl/persistence/state/historical_states_reader"
 	"github.com/ledgerwatch/erigon/cl/phase1/forkchoice"
+	"github.com/ledgerwatch/erigon/cl/phase1/network"
 	"github.com/ledgerwatch/erigon/cl/pool"
 	"github.com/ledgerwatch/erigon/turbo/snapshotsync/freezeblocks"
 )
@@ -19,17 +25,25 @@ type ApiHandler struct {
 	o   sync.Once
 	mux chi.Router
 
-	blockReader     freezeblocks.BeaconSnapshotReader
-	indiciesDB      kv.RoDB
-	genesisCfg      *clparams.GenesisConfig
-	beaconChainCfg  *clparams.BeaconChainConfig
-	forkchoiceStore forkchoice.ForkChoiceStorage
-	operationsPool  pool.OperationsPool
-	syncedData      *synced_data.SyncedDataManager
+	blockReader      freezeblocks.BeaconSnapshotReader
+	indiciesDB       kv.RoDB
+	genesisCfg       *clparams.GenesisConfig
+	beaconChainCfg   *clparams.BeaconChainConfig
+	forkchoiceStore  forkchoice.ForkChoiceStorage
+	operationsPool   pool.OperationsPool
+	syncedData       *synced_data.SyncedDataManager
+	emitters         *beaconevents.Emitters
+	stateReader      *historical_states_reader.HistoricalStatesReader
+	gossipManager    *network.GossipManager
+	blobStorage      blob_storage.BlobStorage
+	lightClientStore *light_client.Store
+
+	feeRecipientsMu sync.RWMutex
+	feeRecipients   map[uint64]libcommon.Address
 }
 
-func NewApiHandler(genesisConfig *clparams.GenesisConfig, beaconChainConfig *clparams.BeaconChainConfig, source persistence.RawBeaconBlockChain, indiciesDB kv.RoDB, forkchoiceStore forkchoice.ForkChoiceStorage, operationsPool pool.OperationsPool, rcsn freezeblocks.BeaconSnapshotReader, syncedData *synced_data.SyncedDataManager) *ApiHandler {
-	return &ApiHandler{o: sync.Once{}, genesisCfg: genesisConfig, beaconChainCfg: beaconChainConfig, indiciesDB: indiciesDB, forkchoiceStore: forkchoiceStore, operationsPool: operationsPool, blockReader: rcsn, syncedData: syncedData}
+func NewApiHandler(genesisConfig *clparams.GenesisConfig, beaconChainConfig *clparams.BeaconChainConfig, source persistence.RawBeaconBlockChain, indiciesDB kv.RoDB, forkchoiceStore forkchoice.ForkChoiceStorage, operationsPool pool.OperationsPool, rcsn freezeblocks.BeaconSnapshotReader, syncedData *synced_data.SyncedDataManager, emitters *beaconevents.Emitters, stateReader *historical_states_reader.HistoricalStatesReader, gossipManager *network.GossipManager, blobStorage blob_storage.BlobStorage, lightClientStore *light_client.Store) *ApiHandler {
+	return &ApiHandler{o: sync.Once{}, genesisCfg: genesisConfig, beaconChainCfg: beaconChainConfig, indiciesDB: indiciesDB, forkchoiceStore: forkchoiceStore, operationsPool: operationsPool, blockReader: rcsn, syncedData: syncedData, emitters: emitters, stateReader: stateReader, gossipManager: gossipManager, blobStorage: blobStorage, lightClientStore: lightClientStore, feeRecipients: make(map[uint64]libcommon.Address)}
 }
 
 func (a *ApiHandler) init() {
@@ -39,7 +53,7 @@ func (a *ApiHandler) init() {
 	// otterscn specific ones are commented as such
 	r.Route("/eth", func(r chi.Router) {
 		r.Route("/v1", func(r chi.Router) {
-			r.Get("/events", http.NotFound)
+			r.Get("/events", beaconhttp.HandleEventStream(a.emitters))
 			r.Route("/config", func(r chi.Router) {
 				r.Get("/spec", beaconhttp.HandleEndpointFunc(a.getSpec))
 				r.Get("/deposit_contract", beaconhttp.HandleEndpointFunc(a.getDepositContract))
@@ -51,49 +65,64 @@ func (a *ApiHandler) init() {
 					r.Get("/{block_id}", beaconhttp.HandleEndpointFunc(a.getHeader))
 				})
 				r.Route("/blocks", func(r chi.Router) {
-					r.Post("/", http.NotFound)
+					r.Post("/", beaconhttp.HandleEndpointFunc(a.postBlock))
 					r.Get("/{block_id}", beaconhttp.HandleEndpointFunc(a.getBlock))
 					r.Get("/{block_id}/attestations", beaconhttp.HandleEndpointFunc(a.getBlockAttestations))
 					r.Get("/{block_id}/root", beaconhttp.HandleEndpointFunc(a.getBlockRoot))
 				})
+				r.Get("/blob_sidecars/{block_id}", beaconhttp.HandleEndpointFunc(a.getBlobSidecars))
+				r.Route("/light_client", func(r chi.Router) {
+					r.Get("/bootstrap/{block_root}", beaconhttp.HandleEndpointFunc(a.getLightClientBootstrap))
+					r.Get("/updates", beaconhttp.HandleEndpointFunc(a.getLightClientUpdates))
+					r.Get("/finality_update", beaconhttp.HandleEndpointFunc(a.getLightClientFinalityUpdate))
+					r.Get("/optimistic_update", beaconhttp.HandleEndpointFunc(a.getLightClientOptimisticUpdate))
+				})
 				r.Get("/genesis", beaconhttp.HandleEndpointFunc(a.getGenesis))
+				r.Route("/rewards", func(r chi.Router) {
+					r.Get("/blocks/{block_id}", beaconhttp.HandleEndpointFunc(a.getBlockRewards))
+					r.Post("/attestations/{epoch}", beaconhttp.HandleEndpointFunc(a.getAttestationsRewards))
+					r.Post("/sync_committee/{block_id}", beaconhttp.HandleEndpointFunc(a.getSyncCommitteeRewards))
+				})
 				r.Post("/binded_blocks", http.NotFound)
 				r.Route("/pool", func(r chi.Router) {
-					r.Post("/attestations", http.NotFound)
+					r.Post("/attestations", beaconhttp.HandleEndpointFunc(a.postPoolAttestations))
 					r.Get("/voluntary_exits", beaconhttp.HandleEndpointFunc(a.poolVoluntaryExits))
 					r.Get("/attester_slashings", beaconhttp.HandleEndpointFunc(a.poolAttesterSlashings))
 					r.Get("/proposer_slashings", beaconhttp.HandleEndpointFunc(a.poolProposerSlashings))
 					r.Get("/bls_to_execution_changes", beaconhttp.HandleEndpointFunc(a.poolBlsToExecutionChanges))
 					r.Get("/attestations", beaconhttp.HandleEndpointFunc(a.poolAttestations))
-					r.Post("/sync_committees", http.NotFound)
+					r.Post("/sync_committees", beaconhttp.HandleEndpointFunc(a.postPoolSyncCommittees))
 				})
 				r.Get("/node/syncing", http.NotFound)
 				r.Route("/states", func(r chi.Router) {
-					r.Get("/head/validators/{index}", http.NotFound) // otterscan
-					r.Get("/head/committees", http.NotFound)         // otterscan
 					r.Route("/{state_id}", func(r chi.Router) {
-						r.Get("/validators", http.NotFound)
+						r.Get("/validators", beaconhttp.HandleEndpointFunc(a.getAllValidators))
 						r.Get("/root", beaconhttp.HandleEndpointFunc(a.getStateRoot))
 						r.Get("/fork", beaconhttp.HandleEndpointFunc(a.getStateFork))
-						r.Get("/validators/{id}", http.NotFound)
+						r.Get("/finality_checkpoints", beaconhttp.HandleEndpointFunc(a.getStateFinalityCheckpoints))
+						r.Get("/validators/{validator_id}", beaconhttp.HandleEndpointFunc(a.getSingleValidator)) // otterscan
+						r.Get("/validator_balances", beaconhttp.HandleEndpointFunc(a.getAllValidatorsBalances))
+						r.Get("/committees", beaconhttp.HandleEndpointFunc(a.getCommittees)) // otterscan
+						r.Get("/sync_committees", beaconhttp.HandleEndpointFunc(a.getSyncCommittees))
+						r.Get("/randao", beaconhttp.HandleEndpointFunc(a.getRandao))
 					})
 				})
 			})
 			r.Route("/validator", func(r chi.Router) {
 				r.Route("/duties", func(r chi.Router) {
-					r.Post("/attester/{epoch}", http.NotFound)
+					r.Post("/attester/{epoch}", beaconhttp.HandleEndpointFunc(a.getDutiesAttester))
 					r.Get("/proposer/{epoch}", beaconhttp.HandleEndpointFunc(a.getDutiesProposer))
-					r.Post("/sync/{epoch}", http.NotFound)
+					r.Post("/sync/{epoch}", beaconhttp.HandleEndpointFunc(a.getDutiesSync))
 				})
 				r.Get("/blinded_blocks/{slot}", http.NotFound)
-				r.Get("/attestation_data", http.NotFound)
-				r.Get("/aggregate_attestation", http.NotFound)
-				r.Post("/aggregate_and_proofs", http.NotFound)
-				r.Post("/beacon_committee_subscriptions", http.NotFound)
+				r.Get("/attestation_data", beaconhttp.HandleEndpointFunc(a.getAttestationData))
+				r.Get("/aggregate_attestation", beaconhttp.HandleEndpointFunc(a.getAggregateAttestation))
+				r.Post("/aggregate_and_proofs", beaconhttp.HandleEndpointFunc(a.postAggregateAndProofs))
+				r.Post("/beacon_committee_subscriptions", beaconhttp.HandleEndpointFunc(a.postBeaconCommitteeSubscriptions))
 				r.Post("/sync_committee_subscriptions", http.NotFound)
-				r.Get("/sync_committee_contribution", http.NotFound)
-				r.Post("/contribution_and_proofs", http.NotFound)
-				r.Post("/prepare_beacon_proposer", http.NotFound)
+				r.Get("/sync_committee_contribution", beaconhttp.HandleEndpointFunc(a.getSyncCommitteeContribution))
+				r.Post("/contribution_and_proofs", beaconhttp.HandleEndpointFunc(a.postContributionAndProofs))
+				r.Post("/prepare_beacon_proposer", beaconhttp.HandleEndpointFunc(a.postPrepareBeaconProposer))
 			})
 		})
 		r.Route("/v2", func(r chi.Router) {
@@ -106,7 +135,7 @@ func (a *ApiHandler) init() {
 				r.Get("/blocks/{block_id}", beaconhttp.HandleEndpointFunc(a.getBlock)) //otterscan
 			})
 			r.Route("/validator", func(r chi.Router) {
-				r.Post("/blocks/{slot}", http.NotFound)
+				r.Get("/blocks/{slot}", beaconhttp.HandleEndpointFunc(a.getBlockProduction))
 			})
 		})
 	})
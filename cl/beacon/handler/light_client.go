@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/go-chi/chi/v5"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconhttp"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/sentinel/communication"
+)
+
+type lightClientUpdateResponse struct {
+	Version clparams.StateVersion      `json:"version"`
+	Data    *cltypes.LightClientUpdate `json:"data"`
+}
+
+func (a *ApiHandler) getLightClientBootstrap(r *http.Request) (*beaconResponse, error) {
+	blockRoot := chi.URLParam(r, "block_root")
+	if !regexp.MustCompile(`^0x[0-9a-fA-F]{64}$`).MatchString(blockRoot) {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, "invalid path variable: {block_root}")
+	}
+	if a.lightClientStore == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, "light client data is not available")
+	}
+	bootstrap, ok := a.lightClientStore.Bootstrap(libcommon.HexToHash(blockRoot))
+	if !ok {
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Sprintf("no bootstrap available for block %s", blockRoot))
+	}
+	return newBeaconResponse(bootstrap).withVersion(bootstrap.Version()), nil
+}
+
+func (a *ApiHandler) getLightClientUpdates(r *http.Request) ([]lightClientUpdateResponse, error) {
+	startPeriod, err := uint64FromQueryParams(r, "start_period")
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	count, err := uint64FromQueryParams(r, "count")
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	if startPeriod == nil || count == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, "start_period and count are required")
+	}
+	if *count > communication.MaximumRequestClientUpdates {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Sprintf("count must not exceed %d", communication.MaximumRequestClientUpdates))
+	}
+	if a.lightClientStore == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, "light client data is not available")
+	}
+	updates := a.lightClientStore.Updates(*startPeriod, *count)
+	resp := make([]lightClientUpdateResponse, 0, len(updates))
+	for _, update := range updates {
+		resp = append(resp, lightClientUpdateResponse{Version: update.Version(), Data: update})
+	}
+	return resp, nil
+}
+
+func (a *ApiHandler) getLightClientFinalityUpdate(r *http.Request) (*beaconResponse, error) {
+	if a.lightClientStore == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, "light client data is not available")
+	}
+	update := a.lightClientStore.FinalityUpdate()
+	if update == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, "no finality update available")
+	}
+	return newBeaconResponse(update).withVersion(update.Version()), nil
+}
+
+func (a *ApiHandler) getLightClientOptimisticUpdate(r *http.Request) (*beaconResponse, error) {
+	if a.lightClientStore == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, "light client data is not available")
+	}
+	update := a.lightClientStore.OptimisticUpdate()
+	if update == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, "no optimistic update available")
+	}
+	return newBeaconResponse(update).withVersion(update.Version()), nil
+}
@@ -1,7 +1,13 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
+
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconhttp"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
 )
 
 func (a *ApiHandler) poolVoluntaryExits(r *http.Request) (*beaconResponse, error) {
@@ -23,3 +29,33 @@ func (a *ApiHandler) poolBlsToExecutionChanges(r *http.Request) (*beaconResponse
 func (a *ApiHandler) poolAttestations(r *http.Request) (*beaconResponse, error) {
 	return newBeaconResponse(a.operationsPool.AttestationsPool.Raw()), nil
 }
+
+func (a *ApiHandler) postPoolAttestations(r *http.Request) (*beaconResponse, error) {
+	var attestations []*solid.Attestation
+	if err := json.NewDecoder(r.Body).Decode(&attestations); err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	if err := a.PublishAttestations(r.Context(), attestations); err != nil {
+		return nil, err
+	}
+	return newBeaconResponse(nil), nil
+}
+
+// PublishAttestations pools attestations and gossips them on their subnet, once fork choice accepted them.
+func (a *ApiHandler) PublishAttestations(ctx context.Context, attestations []*solid.Attestation) error {
+	for i, attestation := range attestations {
+		if err := a.forkchoiceStore.OnAttestation(attestation, false); err != nil {
+			return beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Sprintf("attestation %d: %s", i, err))
+		}
+		a.operationsPool.AttestationsPool.Insert(attestation.Signature(), attestation)
+		data := attestation.AttestantionData()
+		subnet, err := a.forkchoiceStore.ComputeSubnetForAttestation(data.Slot(), data.ValidatorIndex())
+		if err != nil {
+			return beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Sprintf("attestation %d: %s", i, err))
+		}
+		if err := a.gossipManager.PublishAttestation(ctx, attestation, subnet); err != nil {
+			return err
+		}
+	}
+	return nil
+}
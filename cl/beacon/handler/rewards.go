@@ -0,0 +1,345 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconhttp"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/persistence/beacon_indicies"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
+	"github.com/ledgerwatch/erigon/cl/transition"
+	"github.com/ledgerwatch/erigon/cl/transition/impl/eth2/statechange"
+)
+
+type blockRewardsResponse struct {
+	ProposerIndex     uint64 `json:"proposer_index"`
+	Total             uint64 `json:"total"`
+	Attestations      uint64 `json:"attestations"`
+	SyncAggregate     uint64 `json:"sync_aggregate"`
+	ProposerSlashings uint64 `json:"proposer_slashings"`
+	AttesterSlashings uint64 `json:"attester_slashings"`
+}
+
+type idealAttestationRewards struct {
+	EffectiveBalance uint64 `json:"effective_balance"`
+	Head             int64  `json:"head"`
+	Target           int64  `json:"target"`
+	Source           int64  `json:"source"`
+	Inactivity       int64  `json:"inactivity"`
+}
+
+type totalAttestationRewards struct {
+	ValidatorIndex uint64 `json:"validator_index"`
+	Head           int64  `json:"head"`
+	Target         int64  `json:"target"`
+	Source         int64  `json:"source"`
+	Inactivity     int64  `json:"inactivity"`
+}
+
+type attestationRewardsResponse struct {
+	IdealRewards []idealAttestationRewards `json:"ideal_rewards"`
+	TotalRewards []totalAttestationRewards `json:"total_rewards"`
+}
+
+type syncCommitteeRewards struct {
+	ValidatorIndex uint64 `json:"validator_index"`
+	Reward         int64  `json:"reward"`
+}
+
+// blockFromRequest resolves the {block_id} of the request into a block, and tells whether the block is finalized.
+func (a *ApiHandler) blockFromRequest(ctx context.Context, tx kv.Tx, r *http.Request) (*cltypes.SignedBeaconBlock, bool, error) {
+	blockId, err := blockIdFromRequest(r)
+	if err != nil {
+		return nil, false, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	root, err := a.rootFromBlockId(ctx, tx, blockId)
+	if err != nil {
+		return nil, false, err
+	}
+	blk, err := a.blockReader.ReadBlockByRoot(ctx, tx, root)
+	if err != nil {
+		return nil, false, err
+	}
+	if blk == nil {
+		return nil, false, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Sprintf("block not found %x", root))
+	}
+	canonicalRoot, err := beacon_indicies.ReadCanonicalBlockRoot(tx, blk.Block.Slot)
+	if err != nil {
+		return nil, false, err
+	}
+	return blk, root == canonicalRoot && blk.Block.Slot <= a.forkchoiceStore.FinalizedSlot(), nil
+}
+
+// blockStateAtSlot returns the post state of a block, advanced through the empty slots up to the given slot. Blocks
+// still in the fork graph are replayed by fork choice, older canonical ones are rebuilt by the historical states reader.
+func (a *ApiHandler) blockStateAtSlot(ctx context.Context, tx kv.Tx, blockRoot libcommon.Hash, slot uint64) (*state.CachingBeaconState, error) {
+	blockSlot, err := beacon_indicies.ReadBlockSlotByBlockRoot(tx, blockRoot)
+	if err != nil {
+		return nil, err
+	}
+	if blockSlot == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Sprintf("block not found %x", blockRoot))
+	}
+	s, err := a.forkchoiceStore.GetStateAtBlockRoot(blockRoot, true)
+	if err != nil || s == nil {
+		canonicalRoot, err := beacon_indicies.ReadCanonicalBlockRoot(tx, *blockSlot)
+		if err != nil {
+			return nil, err
+		}
+		if canonicalRoot != blockRoot || a.stateReader == nil {
+			return nil, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Sprintf("could not read state of block %x", blockRoot))
+		}
+		if s, err = a.stateReader.ReadHistoricalState(ctx, tx, *blockSlot); err != nil {
+			return nil, beaconhttp.NewEndpointError(http.StatusNotFound, err.Error())
+		}
+		if s == nil {
+			return nil, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Sprintf("could not read state of block %x", blockRoot))
+		}
+	}
+	if s.Slot() < slot {
+		if err := transition.DefaultMachine.ProcessSlots(s, slot); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// canonicalStateAtSlot returns the state of the canonical chain at the given slot, before any block of that slot.
+func (a *ApiHandler) canonicalStateAtSlot(ctx context.Context, tx kv.Tx, slot uint64) (*state.CachingBeaconState, error) {
+	blockSlot := slot
+	for {
+		root, err := beacon_indicies.ReadCanonicalBlockRoot(tx, blockSlot)
+		if err != nil {
+			return nil, err
+		}
+		if root != (libcommon.Hash{}) {
+			return a.blockStateAtSlot(ctx, tx, root, slot)
+		}
+		if blockSlot == 0 {
+			return nil, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Sprintf("no canonical block found up to slot %d", slot))
+		}
+		blockSlot--
+	}
+}
+
+// validatorSet turns a validator filter into a set, nil if there is no filter.
+func validatorSet(filter []uint64) map[uint64]struct{} {
+	if filter == nil {
+		return nil
+	}
+	set := make(map[uint64]struct{}, len(filter))
+	for _, idx := range filter {
+		set[idx] = struct{}{}
+	}
+	return set
+}
+
+func (a *ApiHandler) getBlockRewards(r *http.Request) (*beaconResponse, error) {
+	ctx := r.Context()
+	tx, err := a.indiciesDB.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	blk, finalized, err := a.blockFromRequest(ctx, tx, r)
+	if err != nil {
+		return nil, err
+	}
+	if blk.Version() < clparams.AltairVersion {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, "block rewards are not available before altair")
+	}
+	block := blk.Block
+	s, err := a.blockStateAtSlot(ctx, tx, block.ParentRoot, block.Slot)
+	if err != nil {
+		return nil, err
+	}
+
+	// the rewards of the operations are the proposer balance increases observed while replaying them.
+	impl := transition.DefaultMachine
+	proposerGain := func(process func() error) (uint64, error) {
+		before, err := s.ValidatorBalance(int(block.ProposerIndex))
+		if err != nil {
+			return 0, err
+		}
+		if err := process(); err != nil {
+			return 0, err
+		}
+		after, err := s.ValidatorBalance(int(block.ProposerIndex))
+		if err != nil {
+			return 0, err
+		}
+		if after < before {
+			return 0, nil
+		}
+		return after - before, nil
+	}
+	rewards := &blockRewardsResponse{ProposerIndex: block.ProposerIndex}
+	if rewards.ProposerSlashings, err = proposerGain(func() error {
+		return solid.RangeErr[*cltypes.ProposerSlashing](block.Body.ProposerSlashings, func(_ int, slashing *cltypes.ProposerSlashing, _ int) error {
+			return impl.ProcessProposerSlashing(s, slashing)
+		})
+	}); err != nil {
+		return nil, err
+	}
+	if rewards.AttesterSlashings, err = proposerGain(func() error {
+		return solid.RangeErr[*cltypes.AttesterSlashing](block.Body.AttesterSlashings, func(_ int, slashing *cltypes.AttesterSlashing, _ int) error {
+			return impl.ProcessAttesterSlashing(s, slashing)
+		})
+	}); err != nil {
+		return nil, err
+	}
+	if rewards.Attestations, err = proposerGain(func() error {
+		return impl.ProcessAttestations(s, block.Body.Attestations)
+	}); err != nil {
+		return nil, err
+	}
+	// the proposer may sit in the sync committee too, so its share of the sync aggregate is computed instead.
+	proposerReward, _, err := s.SyncRewards()
+	if err != nil {
+		return nil, err
+	}
+	rewards.SyncAggregate = proposerReward * uint64(block.Body.SyncAggregate.Sum())
+	rewards.Total = rewards.Attestations + rewards.SyncAggregate + rewards.ProposerSlashings + rewards.AttesterSlashings
+	return newBeaconResponse(rewards).withFinalized(finalized), nil
+}
+
+func (a *ApiHandler) getAttestationsRewards(r *http.Request) (*beaconResponse, error) {
+	ctx := r.Context()
+	tx, err := a.indiciesDB.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	epoch, err := epochFromRequest(r)
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	if a.beaconChainCfg.GetCurrentStateVersion(epoch) < clparams.AltairVersion {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, "attestation rewards are not available before altair")
+	}
+	// the duties of an epoch are rewarded at the end of the following one.
+	lastSlot := (epoch+2)*a.beaconChainCfg.SlotsPerEpoch - 1
+	_, headSlot, err := a.forkchoiceStore.GetHead()
+	if err != nil {
+		return nil, err
+	}
+	if lastSlot > headSlot {
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Sprintf("attestation rewards of epoch %d are not available yet", epoch))
+	}
+	s, err := a.canonicalStateAtSlot(ctx, tx, lastSlot)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := validatorIdsFromBody(r, s)
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+
+	eligibleValidators := state.EligibleValidatorsIndicies(s)
+	unslashedIndiciesSet := statechange.GetUnslashedIndiciesSet(s)
+	// finality and inactivity scores are updated before the rewards are processed, and both weigh on them.
+	if err := statechange.ProcessJustificationBitsAndFinality(s, unslashedIndiciesSet); err != nil {
+		return nil, err
+	}
+	if err := statechange.ProcessInactivityScores(s, eligibleValidators, unslashedIndiciesSet); err != nil {
+		return nil, err
+	}
+	rewards, idealRewards, err := statechange.ComputeAttestationRewards(s, eligibleValidators, unslashedIndiciesSet)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &attestationRewardsResponse{
+		IdealRewards: make([]idealAttestationRewards, 0, len(idealRewards)),
+		TotalRewards: []totalAttestationRewards{},
+	}
+	for i, ideal := range idealRewards {
+		resp.IdealRewards = append(resp.IdealRewards, idealAttestationRewards{
+			EffectiveBalance: uint64(i+1) * a.beaconChainCfg.EffectiveBalanceIncrement,
+			Head:             ideal.Head,
+			Target:           ideal.Target,
+			Source:           ideal.Source,
+			Inactivity:       ideal.Inactivity,
+		})
+	}
+	requested := validatorSet(filter)
+	for i, idx := range eligibleValidators {
+		if _, ok := requested[idx]; requested != nil && !ok {
+			continue
+		}
+		resp.TotalRewards = append(resp.TotalRewards, totalAttestationRewards{
+			ValidatorIndex: idx,
+			Head:           rewards[i].Head,
+			Target:         rewards[i].Target,
+			Source:         rewards[i].Source,
+			Inactivity:     rewards[i].Inactivity,
+		})
+	}
+	return newBeaconResponse(resp).withFinalized(lastSlot <= a.forkchoiceStore.FinalizedSlot()), nil
+}
+
+func (a *ApiHandler) getSyncCommitteeRewards(r *http.Request) (*beaconResponse, error) {
+	ctx := r.Context()
+	tx, err := a.indiciesDB.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	blk, finalized, err := a.blockFromRequest(ctx, tx, r)
+	if err != nil {
+		return nil, err
+	}
+	if blk.Version() < clparams.AltairVersion {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, "sync committee rewards are not available before altair")
+	}
+	block := blk.Block
+	s, err := a.blockStateAtSlot(ctx, tx, block.ParentRoot, block.Slot)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := validatorIdsFromBody(r, s)
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	_, participantReward, err := s.SyncRewards()
+	if err != nil {
+		return nil, err
+	}
+
+	// a validator can sit more than once in the same committee, its rewards are then summed.
+	var order []uint64
+	rewards := make(map[uint64]int64)
+	bits := block.Body.SyncAggregate.SyncCommiteeBits
+	for i, pk := range s.CurrentSyncCommittee().GetCommittee() {
+		idx, ok := s.ValidatorIndexByPubkey(pk)
+		if !ok {
+			return nil, fmt.Errorf("sync committee member %x is not a validator", pk)
+		}
+		if _, seen := rewards[idx]; !seen {
+			order = append(order, idx)
+		}
+		if bits[i/8]&(1<<(i%8)) != 0 {
+			rewards[idx] += int64(participantReward)
+		} else {
+			rewards[idx] -= int64(participantReward)
+		}
+	}
+	requested := validatorSet(filter)
+	resp := []syncCommitteeRewards{}
+	for _, idx := range order {
+		if _, ok := requested[idx]; requested != nil && !ok {
+			continue
+		}
+		resp = append(resp, syncCommitteeRewards{ValidatorIndex: idx, Reward: rewards[idx]})
+	}
+	return newBeaconResponse(resp).withFinalized(finalized), nil
+}
@@ -10,7 +10,9 @@ import (
 	"github.com/ledgerwatch/erigon/cl/beacon/beaconhttp"
 	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
 	"github.com/ledgerwatch/erigon/cl/persistence/beacon_indicies"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
 	"github.com/ledgerwatch/erigon/cl/utils"
 )
 
@@ -58,6 +60,53 @@ func (a *ApiHandler) rootFromStateId(ctx context.Context, tx kv.Tx, stateId *seg
 	return
 }
 
+// stateFromRequest resolves the {state_id} of the request into a beacon state. States still in the fork graph are
+// replayed by fork choice, older canonical ones are rebuilt by the historical states reader.
+func (a *ApiHandler) stateFromRequest(ctx context.Context, tx kv.Tx, r *http.Request) (s *state.CachingBeaconState, finalized bool, err error) {
+	stateId, err := stateIdFromRequest(r)
+	if err != nil {
+		return nil, false, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	root, httpStatus, err := a.rootFromStateId(ctx, tx, stateId)
+	if err != nil {
+		return nil, false, beaconhttp.NewEndpointError(httpStatus, err.Error())
+	}
+	blockRoot, err := beacon_indicies.ReadBlockRootByStateRoot(tx, root)
+	if err != nil {
+		return nil, false, err
+	}
+	slot, err := beacon_indicies.ReadBlockSlotByBlockRoot(tx, blockRoot)
+	if err != nil {
+		return nil, false, err
+	}
+	if slot == nil {
+		return nil, false, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Sprintf("could not find state: %x", root))
+	}
+	canonicalRoot, err := beacon_indicies.ReadCanonicalBlockRoot(tx, *slot)
+	if err != nil {
+		return nil, false, err
+	}
+	canonical := canonicalRoot == blockRoot
+	finalized = canonical && *slot <= a.forkchoiceStore.FinalizedSlot()
+
+	s, err = a.forkchoiceStore.GetStateAtBlockRoot(blockRoot, true)
+	if err == nil && s != nil {
+		return s, finalized, nil
+	}
+	// the historical states reader only knows about the canonical chain
+	if !canonical || a.stateReader == nil {
+		return nil, false, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Sprintf("could not read state: %x", root))
+	}
+	s, err = a.stateReader.ReadHistoricalState(ctx, tx, *slot)
+	if err != nil {
+		return nil, false, beaconhttp.NewEndpointError(http.StatusNotFound, err.Error())
+	}
+	if s == nil {
+		return nil, false, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Sprintf("could not read state: %x", root))
+	}
+	return s, finalized, nil
+}
+
 type rootResponse struct {
 	Root libcommon.Hash `json:"root"`
 }
@@ -158,25 +207,68 @@ func (a *ApiHandler) getFullState(r *http.Request) (*beaconResponse, error) {
 	}
 	defer tx.Rollback()
 
-	blockId, err := stateIdFromRequest(r)
+	s, finalized, err := a.stateFromRequest(ctx, tx, r)
 	if err != nil {
-		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+		return nil, err
 	}
+	return newBeaconResponse(s).withFinalized(finalized).withVersion(s.Version()), nil
+}
 
-	root, httpStatus, err := a.rootFromStateId(ctx, tx, blockId)
+type finalityCheckpointsResponse struct {
+	PreviousJustified solid.Checkpoint `json:"previous_justified"`
+	CurrentJustified  solid.Checkpoint `json:"current_justified"`
+	Finalized         solid.Checkpoint `json:"finalized"`
+}
+
+func (a *ApiHandler) getStateFinalityCheckpoints(r *http.Request) (*beaconResponse, error) {
+	ctx := r.Context()
+
+	tx, err := a.indiciesDB.BeginRo(ctx)
 	if err != nil {
-		return nil, beaconhttp.NewEndpointError(httpStatus, err.Error())
+		return nil, err
 	}
+	defer tx.Rollback()
 
-	blockRoot, err := beacon_indicies.ReadBlockRootByStateRoot(tx, root)
+	s, finalized, err := a.stateFromRequest(ctx, tx, r)
+	if err != nil {
+		return nil, err
+	}
+	return newBeaconResponse(finalityCheckpointsResponse{
+		PreviousJustified: s.PreviousJustifiedCheckpoint(),
+		CurrentJustified:  s.CurrentJustifiedCheckpoint(),
+		Finalized:         s.FinalizedCheckpoint(),
+	}).withFinalized(finalized), nil
+}
+
+type randaoResponse struct {
+	Randao libcommon.Hash `json:"randao"`
+}
+
+func (a *ApiHandler) getRandao(r *http.Request) (*beaconResponse, error) {
+	ctx := r.Context()
+
+	tx, err := a.indiciesDB.BeginRo(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 
-	state, err := a.forkchoiceStore.GetStateAtBlockRoot(blockRoot, true)
+	s, finalized, err := a.stateFromRequest(ctx, tx, r)
+	if err != nil {
+		return nil, err
+	}
+	stateEpoch := state.Epoch(s)
+	epoch := stateEpoch
+	epochMaybe, err := uint64FromQueryParams(r, "epoch")
 	if err != nil {
 		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
 	}
-
-	return newBeaconResponse(state).withFinalized(false).withVersion(state.Version()), nil
+	if epochMaybe != nil {
+		epoch = *epochMaybe
+	}
+	// the randao mixes vector only remembers the last EpochsPerHistoricalVector epochs
+	if epoch > stateEpoch || epoch+a.beaconChainCfg.EpochsPerHistoricalVector <= stateEpoch {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Sprintf("epoch %d is out of range for the state at epoch %d", epoch, stateEpoch))
+	}
+	return newBeaconResponse(&randaoResponse{Randao: s.GetRandaoMixes(epoch)}).withFinalized(finalized), nil
 }
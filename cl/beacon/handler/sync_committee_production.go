@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconhttp"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
+	"github.com/ledgerwatch/erigon/cl/utils"
+)
+
+func (a *ApiHandler) postPoolSyncCommittees(r *http.Request) (*beaconResponse, error) {
+	var messages []*cltypes.SyncCommitteeMessage
+	if err := json.NewDecoder(r.Body).Decode(&messages); err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	if err := a.PublishSyncCommitteeMessages(r.Context(), messages); err != nil {
+		return nil, err
+	}
+	return newBeaconResponse(nil), nil
+}
+
+// PublishSyncCommitteeMessages pools sync committee messages and gossips them on the subnets of their validator, once
+// fork choice accepted them.
+func (a *ApiHandler) PublishSyncCommitteeMessages(ctx context.Context, messages []*cltypes.SyncCommitteeMessage) error {
+	for i, msg := range messages {
+		positions, err := a.syncCommitteePositions(msg.Slot, msg.ValidatorIndex)
+		if err != nil {
+			return beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Sprintf("message %d: %s", i, err))
+		}
+		if len(positions) == 0 {
+			return beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Sprintf("message %d: validator %d is not part of the sync committee", i, msg.ValidatorIndex))
+		}
+		subcommitteeSize := a.beaconChainCfg.SyncCommitteeSize / a.beaconChainCfg.SyncCommitteeSubnetCount
+		published := make(map[uint64]struct{})
+		for _, position := range positions {
+			subnet := position / subcommitteeSize
+			if _, ok := published[subnet]; ok {
+				continue
+			}
+			published[subnet] = struct{}{}
+			if err := a.forkchoiceStore.OnSyncCommitteeMessage(msg, subnet, false); err != nil {
+				return beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Sprintf("message %d: %s", i, err))
+			}
+			if err := a.gossipManager.PublishSyncCommitteeMessage(ctx, msg, subnet); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (a *ApiHandler) getSyncCommitteeContribution(r *http.Request) (*beaconResponse, error) {
+	slot, err := uint64FromQueryParams(r, "slot")
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	subcommitteeIndex, err := uint64FromQueryParams(r, "subcommittee_index")
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	blockRoot, err := hashFromQueryParams(r, "beacon_block_root")
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	if slot == nil || subcommitteeIndex == nil || blockRoot == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, "slot, subcommittee_index and beacon_block_root are required")
+	}
+	contribution, err := a.SyncCommitteeContribution(*slot, *subcommitteeIndex, *blockRoot)
+	if err != nil {
+		return nil, err
+	}
+	return newBeaconResponse(contribution), nil
+}
+
+// SyncCommitteeContribution aggregates the pooled sync committee messages of a subcommittee for the given block.
+func (a *ApiHandler) SyncCommitteeContribution(slot, subcommitteeIndex uint64, blockRoot libcommon.Hash) (*cltypes.SyncCommitteeContribution, error) {
+	if subcommitteeIndex >= a.beaconChainCfg.SyncCommitteeSubnetCount {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Sprintf("subcommittee index %d is out of range", subcommitteeIndex))
+	}
+	s, cancel := a.syncedData.HeadState()
+	defer cancel()
+	if s == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusServiceUnavailable, "beacon node is syncing")
+	}
+	committee, err := a.syncCommitteeAtSlot(s, slot)
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	subcommitteeSize := a.beaconChainCfg.SyncCommitteeSize / a.beaconChainCfg.SyncCommitteeSubnetCount
+	subcommittee := committee[subcommitteeIndex*subcommitteeSize : (subcommitteeIndex+1)*subcommitteeSize]
+
+	contribution := cltypes.NewSyncCommitteeContribution()
+	contribution.Slot = slot
+	contribution.BeaconBlockRoot = blockRoot
+	contribution.SubcommitteeIndex = subcommitteeIndex
+	var signatures [][]byte
+	for _, msg := range a.operationsPool.SyncCommitteeMessagesPool.Raw() {
+		if msg.Slot != slot || msg.BeaconBlockRoot != blockRoot {
+			continue
+		}
+		pk, err := s.ValidatorPublicKey(int(msg.ValidatorIndex))
+		if err != nil {
+			continue
+		}
+		// a validator sitting more than once in the subcommittee is counted, and its signature aggregated, once per seat.
+		for i, member := range subcommittee {
+			if member != pk || contribution.AggregationBits[i/8]&(1<<(i%8)) != 0 {
+				continue
+			}
+			contribution.AggregationBits[i/8] |= 1 << (i % 8)
+			signatures = append(signatures, libcommon.CopyBytes(msg.Signature[:]))
+		}
+	}
+	if len(signatures) == 0 {
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, "no matching sync committee messages found")
+	}
+	if contribution.Signature, err = utils.AggregateSignatures(signatures); err != nil {
+		return nil, err
+	}
+	return contribution, nil
+}
+
+func (a *ApiHandler) postContributionAndProofs(r *http.Request) (*beaconResponse, error) {
+	var contributions []*cltypes.SignedContributionAndProof
+	if err := json.NewDecoder(r.Body).Decode(&contributions); err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	if err := a.PublishContributionAndProofs(r.Context(), contributions); err != nil {
+		return nil, err
+	}
+	return newBeaconResponse(nil), nil
+}
+
+// PublishContributionAndProofs pools and gossips sync committee contributions, once fork choice accepted them.
+func (a *ApiHandler) PublishContributionAndProofs(ctx context.Context, contributions []*cltypes.SignedContributionAndProof) error {
+	for i, contribution := range contributions {
+		if contribution.Message == nil || contribution.Message.Contribution == nil {
+			return beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Sprintf("contribution %d: missing contribution", i))
+		}
+		if err := a.forkchoiceStore.OnSignedContributionAndProof(contribution, false); err != nil {
+			return beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Sprintf("contribution %d: %s", i, err))
+		}
+		if err := a.gossipManager.PublishContributionAndProof(ctx, contribution); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncCommitteePositions returns the positions of a validator in the sync committee signing at the given slot.
+func (a *ApiHandler) syncCommitteePositions(slot, validatorIndex uint64) ([]uint64, error) {
+	s, cancel := a.syncedData.HeadState()
+	defer cancel()
+	if s == nil {
+		return nil, fmt.Errorf("beacon node is syncing")
+	}
+	committee, err := a.syncCommitteeAtSlot(s, slot)
+	if err != nil {
+		return nil, err
+	}
+	pk, err := s.ValidatorPublicKey(int(validatorIndex))
+	if err != nil {
+		return nil, err
+	}
+	var positions []uint64
+	for i, member := range committee {
+		if member == pk {
+			positions = append(positions, uint64(i))
+		}
+	}
+	return positions, nil
+}
+
+// syncCommitteeAtSlot returns the public keys of the sync committee expected to sign at the given slot: messages of a
+// slot are signed by the committee of the following slot, which only matters at period boundaries.
+func (a *ApiHandler) syncCommitteeAtSlot(s *state.CachingBeaconState, slot uint64) ([]libcommon.Bytes48, error) {
+	if s.Version() < clparams.AltairVersion {
+		return nil, fmt.Errorf("no sync committee before altair")
+	}
+	period := (slot + 1) / a.beaconChainCfg.SlotsPerEpoch / a.beaconChainCfg.EpochsPerSyncCommitteePeriod
+	statePeriod := state.Epoch(s) / a.beaconChainCfg.EpochsPerSyncCommitteePeriod
+	switch period {
+	case statePeriod:
+		return s.CurrentSyncCommittee().GetCommittee(), nil
+	case statePeriod + 1:
+		return s.NextSyncCommittee().GetCommittee(), nil
+	}
+	return nil, fmt.Errorf("no sync committee known for slot %d", slot)
+}
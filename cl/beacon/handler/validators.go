@@ -0,0 +1,372 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconhttp"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
+)
+
+type validatorStatus int
+
+const (
+	validatorPendingInitialized validatorStatus = 1
+	validatorPendingQueued      validatorStatus = 2
+	validatorActiveOngoing      validatorStatus = 3
+	validatorActiveExiting      validatorStatus = 4
+	validatorActiveSlashed      validatorStatus = 5
+	validatorExitedUnslashed    validatorStatus = 6
+	validatorExitedSlashed      validatorStatus = 7
+	validatorWithdrawalPossible validatorStatus = 8
+	validatorWithdrawalDone     validatorStatus = 9
+	validatorActive             validatorStatus = 10
+	validatorPending            validatorStatus = 11
+	validatorExited             validatorStatus = 12
+	validatorWithdrawal         validatorStatus = 13
+)
+
+func validatorStatusFromString(s string) (validatorStatus, error) {
+	switch s {
+	case "pending_initialized":
+		return validatorPendingInitialized, nil
+	case "pending_queued":
+		return validatorPendingQueued, nil
+	case "active_ongoing":
+		return validatorActiveOngoing, nil
+	case "active_exiting":
+		return validatorActiveExiting, nil
+	case "active_slashed":
+		return validatorActiveSlashed, nil
+	case "exited_unslashed":
+		return validatorExitedUnslashed, nil
+	case "exited_slashed":
+		return validatorExitedSlashed, nil
+	case "withdrawal_possible":
+		return validatorWithdrawalPossible, nil
+	case "withdrawal_done":
+		return validatorWithdrawalDone, nil
+	case "active":
+		return validatorActive, nil
+	case "pending":
+		return validatorPending, nil
+	case "exited":
+		return validatorExited, nil
+	case "withdrawal":
+		return validatorWithdrawal, nil
+	default:
+		return 0, fmt.Errorf("invalid validator status %s", s)
+	}
+}
+
+func (s validatorStatus) String() string {
+	switch s {
+	case validatorPendingInitialized:
+		return "pending_initialized"
+	case validatorPendingQueued:
+		return "pending_queued"
+	case validatorActiveOngoing:
+		return "active_ongoing"
+	case validatorActiveExiting:
+		return "active_exiting"
+	case validatorActiveSlashed:
+		return "active_slashed"
+	case validatorExitedUnslashed:
+		return "exited_unslashed"
+	case validatorExitedSlashed:
+		return "exited_slashed"
+	case validatorWithdrawalPossible:
+		return "withdrawal_possible"
+	case validatorWithdrawalDone:
+		return "withdrawal_done"
+	case validatorActive:
+		return "active"
+	case validatorPending:
+		return "pending"
+	case validatorExited:
+		return "exited"
+	case validatorWithdrawal:
+		return "withdrawal"
+	default:
+		panic("invalid validator status")
+	}
+}
+
+// general returns the coarse status (active, pending, exited or withdrawal) a fine grained status belongs to.
+func (s validatorStatus) general() validatorStatus {
+	switch s {
+	case validatorPendingInitialized, validatorPendingQueued:
+		return validatorPending
+	case validatorActiveOngoing, validatorActiveExiting, validatorActiveSlashed:
+		return validatorActive
+	case validatorExitedUnslashed, validatorExitedSlashed:
+		return validatorExited
+	case validatorWithdrawalPossible, validatorWithdrawalDone:
+		return validatorWithdrawal
+	default:
+		return s
+	}
+}
+
+// https://github.com/ethereum/beacon-APIs/blob/master/validator-flow.md
+func validatorStatusAtEpoch(v solid.Validator, epoch, farFutureEpoch uint64) validatorStatus {
+	switch {
+	case v.ActivationEpoch() > epoch:
+		if v.ActivationEligibilityEpoch() == farFutureEpoch {
+			return validatorPendingInitialized
+		}
+		return validatorPendingQueued
+	case epoch < v.ExitEpoch():
+		if v.ExitEpoch() == farFutureEpoch {
+			return validatorActiveOngoing
+		}
+		if v.Slashed() {
+			return validatorActiveSlashed
+		}
+		return validatorActiveExiting
+	case epoch < v.WithdrawableEpoch():
+		if v.Slashed() {
+			return validatorExitedSlashed
+		}
+		return validatorExitedUnslashed
+	case v.EffectiveBalance() == 0:
+		return validatorWithdrawalDone
+	default:
+		return validatorWithdrawalPossible
+	}
+}
+
+// validatorIdsFromRequest parses the `id` query parameters, either repeated or comma separated. An id is either a
+// validator index or a public key. A nil result means that no filter was given.
+func validatorIdsFromRequest(r *http.Request, s *state.CachingBeaconState) ([]uint64, error) {
+	var ids []uint64
+	for _, param := range r.URL.Query()["id"] {
+		for _, id := range strings.Split(param, ",") {
+			idx, found, err := validatorIndexFromString(strings.TrimSpace(id), s)
+			if err != nil {
+				return nil, err
+			}
+			// unknown validators are not an error, they are just not part of the response.
+			if found {
+				ids = append(ids, idx)
+			}
+		}
+	}
+	if ids == nil && len(r.URL.Query()["id"]) > 0 {
+		return []uint64{}, nil
+	}
+	return ids, nil
+}
+
+// validatorIdsFromBody parses a JSON array of validator ids from the request body, with the same rules as
+// validatorIdsFromRequest. A nil result means that no filter was given.
+func validatorIdsFromBody(r *http.Request, s *state.CachingBeaconState) ([]uint64, error) {
+	var ids []string
+	if err := json.NewDecoder(r.Body).Decode(&ids); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	indices := []uint64{}
+	for _, id := range ids {
+		idx, found, err := validatorIndexFromString(strings.TrimSpace(id), s)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			indices = append(indices, idx)
+		}
+	}
+	return indices, nil
+}
+
+func validatorIndexFromString(id string, s *state.CachingBeaconState) (uint64, bool, error) {
+	if strings.HasPrefix(id, "0x") {
+		if len(id) != 2+2*48 {
+			return 0, false, fmt.Errorf("invalid validator public key: %s", id)
+		}
+		var pk libcommon.Bytes48
+		if err := pk.UnmarshalText([]byte(id)); err != nil {
+			return 0, false, fmt.Errorf("invalid validator public key: %s", id)
+		}
+		idx, ok := s.ValidatorIndexByPubkey(pk)
+		return idx, ok, nil
+	}
+	idx, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid validator id: %s", id)
+	}
+	return idx, idx < uint64(s.ValidatorLength()), nil
+}
+
+func validatorStatusesFromRequest(r *http.Request) (map[validatorStatus]struct{}, error) {
+	var statuses map[validatorStatus]struct{}
+	for _, param := range r.URL.Query()["status"] {
+		for _, str := range strings.Split(param, ",") {
+			status, err := validatorStatusFromString(strings.TrimSpace(str))
+			if err != nil {
+				return nil, err
+			}
+			if statuses == nil {
+				statuses = make(map[validatorStatus]struct{})
+			}
+			statuses[status] = struct{}{}
+		}
+	}
+	return statuses, nil
+}
+
+type validatorResponse struct {
+	Index     uint64          `json:"index"`
+	Balance   uint64          `json:"balance"`
+	Status    string          `json:"status"`
+	Validator solid.Validator `json:"validator"`
+}
+
+type validatorBalanceResponse struct {
+	Index   uint64 `json:"index"`
+	Balance uint64 `json:"balance"`
+}
+
+func (a *ApiHandler) newValidatorResponse(s *state.CachingBeaconState, idx uint64) (*validatorResponse, error) {
+	v, err := s.ValidatorForValidatorIndex(int(idx))
+	if err != nil {
+		return nil, err
+	}
+	balance, err := s.ValidatorBalance(int(idx))
+	if err != nil {
+		return nil, err
+	}
+	return &validatorResponse{
+		Index:     idx,
+		Balance:   balance,
+		Status:    validatorStatusAtEpoch(v, state.Epoch(s), a.beaconChainCfg.FarFutureEpoch).String(),
+		Validator: v,
+	}, nil
+}
+
+func (a *ApiHandler) getAllValidators(r *http.Request) (*beaconResponse, error) {
+	ctx := r.Context()
+
+	tx, err := a.indiciesDB.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	s, finalized, err := a.stateFromRequest(ctx, tx, r)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := validatorIdsFromRequest(r, s)
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	statuses, err := validatorStatusesFromRequest(r)
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	if ids == nil {
+		ids = make([]uint64, s.ValidatorLength())
+		for i := range ids {
+			ids[i] = uint64(i)
+		}
+	}
+
+	epoch := state.Epoch(s)
+	validators := make([]*validatorResponse, 0, len(ids))
+	for _, idx := range ids {
+		resp, err := a.newValidatorResponse(s, idx)
+		if err != nil {
+			return nil, err
+		}
+		if statuses != nil {
+			status := validatorStatusAtEpoch(resp.Validator, epoch, a.beaconChainCfg.FarFutureEpoch)
+			_, hasStatus := statuses[status]
+			_, hasGeneralStatus := statuses[status.general()]
+			if !hasStatus && !hasGeneralStatus {
+				continue
+			}
+		}
+		validators = append(validators, resp)
+	}
+	return newBeaconResponse(validators).withFinalized(finalized), nil
+}
+
+func (a *ApiHandler) getSingleValidator(r *http.Request) (*beaconResponse, error) {
+	ctx := r.Context()
+
+	tx, err := a.indiciesDB.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	s, finalized, err := a.stateFromRequest(ctx, tx, r)
+	if err != nil {
+		return nil, err
+	}
+	id := chi.URLParam(r, "validator_id")
+	idx, found, err := validatorIndexFromString(id, s)
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+	if !found {
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Sprintf("validator not found: %s", id))
+	}
+	resp, err := a.newValidatorResponse(s, idx)
+	if err != nil {
+		return nil, err
+	}
+	return newBeaconResponse(resp).withFinalized(finalized), nil
+}
+
+func (a *ApiHandler) getAllValidatorsBalances(r *http.Request) (*beaconResponse, error) {
+	ctx := r.Context()
+
+	tx, err := a.indiciesDB.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	s, finalized, err := a.stateFromRequest(ctx, tx, r)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := validatorIdsFromRequest(r, s)
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err.Error())
+	}
+
+	var balances []validatorBalanceResponse
+	if ids == nil {
+		balances = make([]validatorBalanceResponse, 0, s.ValidatorLength())
+		s.ForEachBalance(func(balance uint64, idx, _ int) bool {
+			balances = append(balances, validatorBalanceResponse{Index: uint64(idx), Balance: balance})
+			return true
+		})
+		return newBeaconResponse(balances).withFinalized(finalized), nil
+	}
+	balances = make([]validatorBalanceResponse, 0, len(ids))
+	for _, idx := range ids {
+		balance, err := s.ValidatorBalance(int(idx))
+		if err != nil {
+			return nil, err
+		}
+		balances = append(balances, validatorBalanceResponse{Index: idx, Balance: balance})
+	}
+	return newBeaconResponse(balances).withFinalized(finalized), nil
+}
@@ -40,3 +40,8 @@ func (f *notFoundNoWriter) WriteHeader(statusCode int) {
 	// if it's a 404 and we are not at our last handler, set the target to an io.Discard
 	f.code = statusCode
 }
+
+// Unwrap exposes the underlying ResponseWriter to http.ResponseController, so that event streams can be flushed.
+func (f *notFoundNoWriter) Unwrap() http.ResponseWriter {
+	return f.rw
+}
@@ -51,6 +51,7 @@ func (v *ValidatorApiHandler) GetEthV1NodeSyncing(r *http.Request) (any, error)
 }
 
 func (v *ValidatorApiHandler) EventSourceGetV1Events(w http.ResponseWriter, r *http.Request) {
+	beaconhttp.HandleEventStream(v.Emitters).ServeHTTP(w, r)
 }
 
 func (v *ValidatorApiHandler) GetEthV1ConfigSpec(r *http.Request) (*clparams.BeaconChainConfig, error) {
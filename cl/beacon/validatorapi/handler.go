@@ -5,13 +5,15 @@ import (
 	"sync"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconevents"
 	"github.com/ledgerwatch/erigon/cl/beacon/beaconhttp"
 	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/phase1/forkchoice"
 )
 
 type ValidatorApiHandler struct {
-	FC forkchoice.ForkChoiceStorage
+	FC       forkchoice.ForkChoiceStorage
+	Emitters *beaconevents.Emitters
 
 	BeaconChainCfg *clparams.BeaconChainConfig
 	GenesisCfg     *clparams.GenesisConfig
@@ -48,7 +50,7 @@ func (v *ValidatorApiHandler) init(r chi.Router) {
 				r.Get("/node/syncing", beaconhttp.HandleEndpointFunc(v.GetEthV1NodeSyncing))
 			})
 			r.Get("/config/spec", beaconhttp.HandleEndpointFunc(v.GetEthV1ConfigSpec))
-			r.Get("/events", http.NotFound)
+			r.Get("/events", v.EventSourceGetV1Events)
 			r.Route("/validator", func(r chi.Router) {
 				r.Route("/duties", func(r chi.Router) {
 					r.Post("/attester/{epoch}", http.NotFound)
@@ -76,7 +78,7 @@ func (v *ValidatorApiHandler) init(r chi.Router) {
 				r.Post("/blocks/{block_id}", http.NotFound)
 			})
 			r.Route("/validator", func(r chi.Router) {
-				r.Post("/blocks/{slot}", http.NotFound)
+				r.Get("/blocks/{slot}", http.NotFound)
 			})
 		})
 		r.Route("/v3", func(r chi.Router) {
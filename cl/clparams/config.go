@@ -33,6 +33,14 @@ import (
 type CaplinConfig struct {
 	Backfilling bool
 	Archive     bool
+
+	// ValidatorKeystoresDir holds the EIP-2335 keystores of the validators run by the node, none if empty.
+	ValidatorKeystoresDir string
+	// ValidatorPasswordFile holds the password of the keystores.
+	ValidatorPasswordFile string
+	// ValidatorSlashingProtectionImport is an EIP-3076 interchange file imported into the slashing protection
+	// database at startup.
+	ValidatorSlashingProtectionImport string
 }
 
 type NetworkType int
@@ -482,6 +490,9 @@ type BeaconChainConfig struct {
 	// Light client
 	MinSyncCommitteeParticipants uint64 `yaml:"MIN_SYNC_COMMITTEE_PARTICIPANTS" spec:"true"` // MinSyncCommitteeParticipants defines the minimum amount of sync committee participants for which the light client acknowledges the signature.
 
+	// Deneb
+	MinEpochsForBlobSidecarsRequests uint64 `yaml:"MIN_EPOCHS_FOR_BLOB_SIDECARS_REQUESTS" spec:"true"` // MinEpochsForBlobSidecarsRequests is the minimum number of epochs for which blob sidecars are kept and served.
+
 	// Bellatrix
 	TerminalBlockHash                libcommon.Hash    `yaml:"TERMINAL_BLOCK_HASH" spec:"true"`                  // TerminalBlockHash of beacon chain.
 	TerminalBlockHashActivationEpoch uint64            `yaml:"TERMINAL_BLOCK_HASH_ACTIVATION_EPOCH" spec:"true"` // TerminalBlockHashActivationEpoch of beacon chain.
@@ -741,6 +752,9 @@ var MainnetBeaconConfig BeaconChainConfig = BeaconChainConfig{
 	// Light client
 	MinSyncCommitteeParticipants: 1,
 
+	// Deneb
+	MinEpochsForBlobSidecarsRequests: 4096,
+
 	// Bellatrix
 	TerminalBlockHashActivationEpoch: 18446744073709551615,
 	TerminalBlockHash:                [32]byte{},
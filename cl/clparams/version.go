@@ -1,5 +1,7 @@
 package clparams
 
+import "encoding/json"
+
 type StateVersion uint8
 
 const (
@@ -44,3 +46,8 @@ func ClVersionToString(s StateVersion) string {
 		panic("unsupported fork version")
 	}
 }
+
+// MarshalJSON encodes the version as its fork name, as the beacon API expects.
+func (v StateVersion) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ClVersionToString(v))
+}
@@ -1,6 +1,7 @@
 package cltypes
 
 import (
+	"encoding/json"
 	"fmt"
 
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
@@ -41,7 +42,7 @@ type BeaconBody struct {
 	// Data related to the Ethereum 1.0 chain
 	Eth1Data *Eth1Data `json:"eth1_data"`
 	// A byte array used to customize validators' behavior
-	Graffiti libcommon.Hash `json:"graffit"`
+	Graffiti libcommon.Hash `json:"graffiti"`
 	// A list of slashing events for validators who included invalid blocks in the chain
 	ProposerSlashings *solid.ListSSZ[*ProposerSlashing] `json:"proposer_slashings"`
 	// A list of slashing events for validators who included invalid attestations in the chain
@@ -57,7 +58,7 @@ type BeaconBody struct {
 	// Data related to crosslink records and executing operations on the Ethereum 2.0 chain
 	ExecutionPayload *Eth1Block `json:"execution_payload,omitempty"`
 	// Withdrawals Diffs for Execution Layer
-	ExecutionChanges *solid.ListSSZ[*SignedBLSToExecutionChange] `json:"execution_changes,omitempty"`
+	ExecutionChanges *solid.ListSSZ[*SignedBLSToExecutionChange] `json:"bls_to_execution_changes,omitempty"`
 	// The commitments for beacon chain blobs
 	// With a max of 4 per block
 	BlobKzgCommitments *solid.ListSSZ[*KZGCommitment] `json:"blob_kzg_commitments,omitempty"`
@@ -180,10 +181,80 @@ func (b *BeaconBody) DecodeSSZ(buf []byte, version int) error {
 	return err
 }
 
+// UnmarshalJSON decodes the body from JSON. The version must have been set beforehand, so that lists get their limits.
+func (b *BeaconBody) UnmarshalJSON(buf []byte) error {
+	type beaconBody BeaconBody
+	b.Eth1Data = &Eth1Data{}
+	b.SyncAggregate = &SyncAggregate{}
+	b.ExecutionPayload = NewEth1Block(b.Version, b.beaconCfg)
+	b.ProposerSlashings = solid.NewStaticListSSZ[*ProposerSlashing](MaxProposerSlashings, 416)
+	b.AttesterSlashings = solid.NewDynamicListSSZ[*AttesterSlashing](MaxAttesterSlashings)
+	b.Attestations = solid.NewDynamicListSSZ[*solid.Attestation](MaxAttestations)
+	b.Deposits = solid.NewStaticListSSZ[*Deposit](MaxDeposits, 1240)
+	b.VoluntaryExits = solid.NewStaticListSSZ[*SignedVoluntaryExit](MaxVoluntaryExits, 112)
+	b.ExecutionChanges = solid.NewStaticListSSZ[*SignedBLSToExecutionChange](MaxExecutionChanges, 172)
+	b.BlobKzgCommitments = solid.NewStaticListSSZ[*KZGCommitment](MaxBlobsCommittmentsPerBlock, 48)
+	return json.Unmarshal(buf, (*beaconBody)(b))
+}
+
 func (b *BeaconBody) HashSSZ() ([32]byte, error) {
 	return merkle_tree.HashTreeRoot(b.getSchema(false)...)
 }
 
+// KzgCommitmentMerkleProof returns the branch proving the inclusion of the blob KZG commitment at index in the body,
+// as carried by blob sidecars.
+func (b *BeaconBody) KzgCommitmentMerkleProof(index int) ([][32]byte, error) {
+	if b.Version < clparams.DenebVersion {
+		return nil, fmt.Errorf("blob kzg commitments are not available before deneb")
+	}
+	commitmentsCount := b.BlobKzgCommitments.Len()
+	if index >= commitmentsCount {
+		return nil, fmt.Errorf("blob kzg commitment %d out of range", index)
+	}
+	var err error
+	commitmentRoots := make([][32]byte, commitmentsCount)
+	for i := range commitmentRoots {
+		if commitmentRoots[i], err = b.BlobKzgCommitments.Get(i).HashSSZ(); err != nil {
+			return nil, err
+		}
+	}
+	commitmentsBranch, err := merkle_tree.MerkleProof(int(merkle_tree.GetDepth(MaxBlobsCommittmentsPerBlock)), index, commitmentRoots)
+	if err != nil {
+		return nil, err
+	}
+	// the commitments are the last field of the body.
+	bodyBranch, err := b.fieldMerkleProof(len(b.getSchema(false)) - 1)
+	if err != nil {
+		return nil, err
+	}
+	// the list length is mixed in between the commitments subtree and the body tree.
+	branch := append(commitmentsBranch, merkle_tree.Uint64Root(uint64(commitmentsCount)))
+	return append(branch, bodyBranch...), nil
+}
+
+// ExecutionPayloadMerkleProof returns the branch proving the inclusion of the execution payload in the body, as carried
+// by light client headers.
+func (b *BeaconBody) ExecutionPayloadMerkleProof() ([][32]byte, error) {
+	if b.Version < clparams.BellatrixVersion {
+		return nil, fmt.Errorf("execution payload is not available before bellatrix")
+	}
+	// the payload follows the sync aggregate.
+	return b.fieldMerkleProof(9)
+}
+
+// fieldMerkleProof returns the branch of the body field at index against the body root.
+func (b *BeaconBody) fieldMerkleProof(index int) ([][32]byte, error) {
+	var err error
+	schema := b.getSchema(false)
+	fieldRoots := make([][32]byte, len(schema))
+	for i, field := range schema {
+		if fieldRoots[i], err = merkle_tree.HashTreeRoot(field); err != nil {
+			return nil, err
+		}
+	}
+	return merkle_tree.MerkleProof(int(merkle_tree.GetDepth(merkle_tree.NextPowerOfTwo(uint64(len(schema))))), index, fieldRoots)
+}
+
 func (b *BeaconBody) getSchema(storage bool) []interface{} {
 	s := []interface{}{b.RandaoReveal[:], b.Eth1Data, b.Graffiti[:], b.ProposerSlashings, b.AttesterSlashings, b.Attestations, b.Deposits, b.VoluntaryExits}
 	if b.Version >= clparams.AltairVersion {
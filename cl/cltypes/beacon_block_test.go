@@ -1,6 +1,7 @@
 package cltypes
 
 import (
+	"encoding/json"
 	"math/big"
 	"testing"
 
@@ -71,3 +72,36 @@ func TestBeaconBody(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, b)
 }
+
+func TestBeaconBodyJSON(t *testing.T) {
+	blobGasUsed, excessBlobGas := uint64(1), uint64(2)
+	block := types.NewBlock(&types.Header{
+		BaseFee:       big.NewInt(7),
+		BlobGasUsed:   &blobGasUsed,
+		ExcessBlobGas: &excessBlobGas,
+	}, []types.Transaction{types.NewTransaction(1, [20]byte{}, uint256.NewInt(1), 5, uint256.NewInt(2), nil)}, nil, nil, types.Withdrawals{&types.Withdrawal{
+		Index:     69,
+		Validator: 3,
+	}})
+	body := NewBeaconBody(&clparams.MainnetBeaconConfig)
+	body.Version = clparams.DenebVersion
+	body.RandaoReveal = [96]byte{1, 2, 3}
+	body.Graffiti = [32]byte{4, 5, 6}
+	body.ExecutionPayload = NewEth1BlockFromHeaderAndBody(block.Header(), block.RawBody(), &clparams.MainnetBeaconConfig)
+	body.EncodingSizeSSZ() // allocates the empty lists
+	body.SyncAggregate.SyncCommiteeBits[0] = 1
+	body.BlobKzgCommitments.Append(&KZGCommitment{8})
+
+	encoded, err := json.Marshal(body)
+	assert.NoError(t, err)
+
+	decoded := NewBeaconBody(&clparams.MainnetBeaconConfig)
+	decoded.Version = clparams.DenebVersion
+	assert.NoError(t, json.Unmarshal(encoded, decoded))
+
+	expected, err := body.HashSSZ()
+	assert.NoError(t, err)
+	root, err := decoded.HashSSZ()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, root)
+}
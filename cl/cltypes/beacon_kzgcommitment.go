@@ -2,9 +2,11 @@ package cltypes
 
 import (
 	"encoding/json"
+	"fmt"
 
 	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
 	"github.com/ledgerwatch/erigon/cl/merkle_tree"
 	ssz2 "github.com/ledgerwatch/erigon/cl/ssz"
 )
@@ -12,6 +14,22 @@ import (
 type Blob gokzg4844.Blob
 type KZGProof gokzg4844.KZGProof // [48]byte
 
+func (b Blob) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hexutility.Bytes(b[:]))
+}
+
+func (b *Blob) UnmarshalJSON(data []byte) error {
+	var tmp hexutility.Bytes
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		return err
+	}
+	if len(tmp) != len(b) {
+		return fmt.Errorf("blob must be %d bytes, got %d", len(b), len(tmp))
+	}
+	copy(b[:], tmp)
+	return nil
+}
+
 // https://github.com/ethereum/consensus-specs/blob/3a2304981a3b820a22b518fe4859f4bba0ebc83b/specs/deneb/polynomial-commitments.md#custom-types
 const BYTES_PER_FIELD_ELEMENT = 32
 const FIELD_ELEMENTS_PER_BLOB = 4096
@@ -0,0 +1,82 @@
+package cltypes
+
+import (
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/types/clonable"
+
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/merkle_tree"
+	ssz2 "github.com/ledgerwatch/erigon/cl/ssz"
+	"github.com/ledgerwatch/erigon/cl/utils"
+)
+
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/p2p-interface.md#preset
+const (
+	MaxBlobsPerBlock                 = 6
+	KzgCommitmentInclusionProofDepth = 17
+
+	// kzgCommitmentsSubtreeIndex is the subtree index of the first blob KZG commitment in the block body tree:
+	// field 11 of the body, then the list data root, then the commitment vector of depth 12.
+	kzgCommitmentsSubtreeIndex = 11 << 13
+)
+
+/*
+ * BlobSidecar is a blob of a block, gossiped and served separately from the block. The signed block header and the
+ * inclusion proof tie the KZG commitment of the blob to the block.
+ */
+type BlobSidecar struct {
+	Index                    uint64                   `json:"index,string"`
+	Blob                     Blob                     `json:"blob"`
+	KzgCommitment            libcommon.Bytes48        `json:"kzg_commitment"`
+	KzgProof                 libcommon.Bytes48        `json:"kzg_proof"`
+	SignedBlockHeader        *SignedBeaconBlockHeader `json:"signed_block_header"`
+	CommitmentInclusionProof solid.HashVectorSSZ      `json:"kzg_commitment_inclusion_proof"`
+}
+
+func NewBlobSidecar() *BlobSidecar {
+	return &BlobSidecar{
+		SignedBlockHeader:        &SignedBeaconBlockHeader{Header: &BeaconBlockHeader{}},
+		CommitmentInclusionProof: solid.NewHashVector(KzgCommitmentInclusionProofDepth),
+	}
+}
+
+// VerifyCommitmentInclusionProof checks that the KZG commitment of the sidecar is part of the body of its block header.
+func (b *BlobSidecar) VerifyCommitmentInclusionProof() bool {
+	leaf, err := merkle_tree.BytesRoot(b.KzgCommitment[:])
+	if err != nil {
+		return false
+	}
+	branch := make([]libcommon.Hash, 0, KzgCommitmentInclusionProofDepth)
+	b.CommitmentInclusionProof.Range(func(_ int, h libcommon.Hash, _ int) bool {
+		branch = append(branch, h)
+		return true
+	})
+	return utils.IsValidMerkleBranch(leaf, branch, KzgCommitmentInclusionProofDepth, kzgCommitmentsSubtreeIndex+b.Index, b.SignedBlockHeader.Header.BodyRoot)
+}
+
+func (b *BlobSidecar) Clone() clonable.Clonable {
+	return NewBlobSidecar()
+}
+
+func (b *BlobSidecar) Static() bool {
+	return true
+}
+
+func (b *BlobSidecar) EncodeSSZ(dst []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(dst, b.Index, b.Blob[:], b.KzgCommitment[:], b.KzgProof[:], b.SignedBlockHeader, b.CommitmentInclusionProof)
+}
+
+func (b *BlobSidecar) DecodeSSZ(buf []byte, version int) error {
+	b.SignedBlockHeader = &SignedBeaconBlockHeader{Header: &BeaconBlockHeader{}}
+	b.CommitmentInclusionProof = solid.NewHashVector(KzgCommitmentInclusionProofDepth)
+	return ssz2.UnmarshalSSZ(buf, version, &b.Index, b.Blob[:], b.KzgCommitment[:], b.KzgProof[:], b.SignedBlockHeader, b.CommitmentInclusionProof)
+}
+
+func (b *BlobSidecar) EncodingSizeSSZ() int {
+	return 8 + int(BYTES_PER_BLOB) + 2*length.Bytes48 + b.SignedBlockHeader.EncodingSizeSSZ() + KzgCommitmentInclusionProofDepth*length.Hash
+}
+
+func (b *BlobSidecar) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(b.Index, b.Blob[:], b.KzgCommitment[:], b.KzgProof[:], b.SignedBlockHeader, b.CommitmentInclusionProof)
+}
@@ -0,0 +1,79 @@
+package cltypes_test
+
+import (
+	"math/big"
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/core/types"
+)
+
+func TestBlobSidecarEncodeDecodeSSZ(t *testing.T) {
+	sidecar := cltypes.NewBlobSidecar()
+	sidecar.Index = 3
+	sidecar.Blob[0], sidecar.Blob[len(sidecar.Blob)-1] = 1, 2
+	sidecar.KzgCommitment[0] = 3
+	sidecar.KzgProof[47] = 4
+	sidecar.SignedBlockHeader.Header.Slot = 100
+	sidecar.SignedBlockHeader.Header.ParentRoot = libcommon.HexToHash("0x5")
+	sidecar.CommitmentInclusionProof.Set(16, libcommon.HexToHash("0x6"))
+
+	encoded, err := sidecar.EncodeSSZ(nil)
+	require.NoError(t, err)
+	require.Len(t, encoded, sidecar.EncodingSizeSSZ())
+
+	decoded := cltypes.NewBlobSidecar()
+	require.NoError(t, decoded.DecodeSSZ(encoded, int(clparams.DenebVersion)))
+	require.Equal(t, sidecar, decoded)
+
+	root, err := sidecar.HashSSZ()
+	require.NoError(t, err)
+	decodedRoot, err := decoded.HashSSZ()
+	require.NoError(t, err)
+	require.Equal(t, root, decodedRoot)
+}
+
+func TestBlobSidecarCommitmentInclusionProof(t *testing.T) {
+	blobGasUsed, excessBlobGas := uint64(1), uint64(2)
+	block := types.NewBlock(&types.Header{
+		BaseFee:       big.NewInt(7),
+		BlobGasUsed:   &blobGasUsed,
+		ExcessBlobGas: &excessBlobGas,
+	}, nil, nil, nil, types.Withdrawals{})
+	body := cltypes.NewBeaconBody(&clparams.MainnetBeaconConfig)
+	body.Version = clparams.DenebVersion
+	body.Graffiti = [32]byte{4, 5, 6}
+	body.ExecutionPayload = cltypes.NewEth1BlockFromHeaderAndBody(block.Header(), block.RawBody(), &clparams.MainnetBeaconConfig)
+	body.EncodingSizeSSZ() // allocates the empty lists
+	for i := byte(0); i < 3; i++ {
+		body.BlobKzgCommitments.Append(&cltypes.KZGCommitment{i + 1})
+	}
+	bodyRoot, err := body.HashSSZ()
+	require.NoError(t, err)
+
+	sidecar := cltypes.NewBlobSidecar()
+	sidecar.Index = 1
+	sidecar.KzgCommitment = libcommon.Bytes48(*body.BlobKzgCommitments.Get(1))
+	sidecar.SignedBlockHeader.Header.BodyRoot = bodyRoot
+	branch, err := body.KzgCommitmentMerkleProof(1)
+	require.NoError(t, err)
+	require.Len(t, branch, cltypes.KzgCommitmentInclusionProofDepth)
+	for i, h := range branch {
+		sidecar.CommitmentInclusionProof.Set(i, h)
+	}
+	require.True(t, sidecar.VerifyCommitmentInclusionProof())
+
+	// the proof is bound to the position of the commitment.
+	sidecar.Index = 2
+	require.False(t, sidecar.VerifyCommitmentInclusionProof())
+	sidecar.Index = 1
+	sidecar.KzgCommitment[0]++
+	require.False(t, sidecar.VerifyCommitmentInclusionProof())
+
+	_, err = body.KzgCommitmentMerkleProof(3)
+	require.Error(t, err)
+}
@@ -107,3 +107,23 @@ func (*Eth1Header) Clone() clonable.Clonable {
 func (*Withdrawal) Clone() clonable.Clonable {
 	return &Withdrawal{}
 }
+
+func (*SyncCommitteeMessage) Clone() clonable.Clonable {
+	return &SyncCommitteeMessage{}
+}
+
+func (*SignedContributionAndProof) Clone() clonable.Clonable {
+	return &SignedContributionAndProof{}
+}
+
+func (*SyncCommitteeContribution) Clone() clonable.Clonable {
+	return NewSyncCommitteeContribution()
+}
+
+func (*ContributionAndProof) Clone() clonable.Clonable {
+	return &ContributionAndProof{}
+}
+
+func (*SyncAggregatorSelectionData) Clone() clonable.Clonable {
+	return &SyncAggregatorSelectionData{}
+}
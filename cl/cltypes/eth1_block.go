@@ -1,6 +1,7 @@
 package cltypes
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/big"
 
@@ -160,6 +161,15 @@ func (b *Eth1Block) EncodingSizeSSZ() (size int) {
 	return
 }
 
+// UnmarshalJSON decodes the block from JSON. The version and config must have been set with NewEth1Block beforehand.
+func (b *Eth1Block) UnmarshalJSON(buf []byte) error {
+	type eth1Block Eth1Block
+	b.Extra = solid.NewExtraData()
+	b.Transactions = &solid.TransactionsSSZ{}
+	b.Withdrawals = solid.NewStaticListSSZ[*Withdrawal](int(b.beaconCfg.MaxWithdrawalsPerPayload), 44)
+	return json.Unmarshal(buf, (*eth1Block)(b))
+}
+
 // DecodeSSZ decodes the block in SSZ format.
 func (b *Eth1Block) DecodeSSZ(buf []byte, version int) error {
 	b.Extra = solid.NewExtraData()
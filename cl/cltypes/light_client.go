@@ -0,0 +1,305 @@
+package cltypes
+
+import (
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/types/clonable"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/merkle_tree"
+	ssz2 "github.com/ledgerwatch/erigon/cl/ssz"
+)
+
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/altair/light-client/sync-protocol.md#constants
+const (
+	ExecutionBranchLength            = 4
+	CurrentSyncCommitteeBranchLength = 5
+	NextSyncCommitteeBranchLength    = 5
+	FinalityBranchLength             = 6
+)
+
+/*
+ * LightClientHeader is the block header light clients follow. From capella onwards it also carries the execution
+ * payload header and its inclusion proof in the block body.
+ */
+type LightClientHeader struct {
+	Beacon          *BeaconBlockHeader  `json:"beacon"`
+	Execution       *Eth1Header         `json:"execution,omitempty"`
+	ExecutionBranch solid.HashVectorSSZ `json:"execution_branch,omitempty"`
+
+	version clparams.StateVersion
+}
+
+func NewLightClientHeader(version clparams.StateVersion) *LightClientHeader {
+	h := &LightClientHeader{
+		Beacon:  &BeaconBlockHeader{},
+		version: version,
+	}
+	if version >= clparams.CapellaVersion {
+		h.Execution = NewEth1Header(version)
+		h.ExecutionBranch = solid.NewHashVector(ExecutionBranchLength)
+	}
+	return h
+}
+
+func (h *LightClientHeader) Version() clparams.StateVersion {
+	return h.version
+}
+
+func (h *LightClientHeader) Static() bool {
+	return h.version < clparams.CapellaVersion
+}
+
+func (h *LightClientHeader) EncodeSSZ(dst []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(dst, h.getSchema()...)
+}
+
+func (h *LightClientHeader) DecodeSSZ(buf []byte, version int) error {
+	*h = *NewLightClientHeader(clparams.StateVersion(version))
+	return ssz2.UnmarshalSSZ(buf, version, h.getSchema()...)
+}
+
+func (h *LightClientHeader) EncodingSizeSSZ() int {
+	size := h.Beacon.EncodingSizeSSZ()
+	if h.version >= clparams.CapellaVersion {
+		size += 4 + h.Execution.EncodingSizeSSZ() + ExecutionBranchLength*length.Hash
+	}
+	return size
+}
+
+func (h *LightClientHeader) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(h.getSchema()...)
+}
+
+func (h *LightClientHeader) Clone() clonable.Clonable {
+	return NewLightClientHeader(h.version)
+}
+
+func (h *LightClientHeader) getSchema() []interface{} {
+	s := []interface{}{h.Beacon}
+	if h.version >= clparams.CapellaVersion {
+		s = append(s, h.Execution, h.ExecutionBranch)
+	}
+	return s
+}
+
+/*
+ * LightClientBootstrap is the trusted starting point of a light client: a finalized header and the sync committee
+ * of its period.
+ */
+type LightClientBootstrap struct {
+	Header                     *LightClientHeader   `json:"header"`
+	CurrentSyncCommittee       *solid.SyncCommittee `json:"current_sync_committee"`
+	CurrentSyncCommitteeBranch solid.HashVectorSSZ  `json:"current_sync_committee_branch"`
+}
+
+func NewLightClientBootstrap(version clparams.StateVersion) *LightClientBootstrap {
+	return &LightClientBootstrap{
+		Header:                     NewLightClientHeader(version),
+		CurrentSyncCommittee:       &solid.SyncCommittee{},
+		CurrentSyncCommitteeBranch: solid.NewHashVector(CurrentSyncCommitteeBranchLength),
+	}
+}
+
+func (b *LightClientBootstrap) Version() clparams.StateVersion {
+	return b.Header.version
+}
+
+func (b *LightClientBootstrap) Static() bool {
+	return b.Header.Static()
+}
+
+func (b *LightClientBootstrap) EncodeSSZ(dst []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(dst, b.Header, b.CurrentSyncCommittee, b.CurrentSyncCommitteeBranch)
+}
+
+func (b *LightClientBootstrap) DecodeSSZ(buf []byte, version int) error {
+	*b = *NewLightClientBootstrap(clparams.StateVersion(version))
+	return ssz2.UnmarshalSSZ(buf, version, b.Header, b.CurrentSyncCommittee, b.CurrentSyncCommitteeBranch)
+}
+
+func (b *LightClientBootstrap) EncodingSizeSSZ() int {
+	size := b.Header.EncodingSizeSSZ() + b.CurrentSyncCommittee.EncodingSizeSSZ() + CurrentSyncCommitteeBranchLength*length.Hash
+	if !b.Header.Static() {
+		size += 4
+	}
+	return size
+}
+
+func (b *LightClientBootstrap) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(b.Header, b.CurrentSyncCommittee, b.CurrentSyncCommitteeBranch)
+}
+
+func (b *LightClientBootstrap) Clone() clonable.Clonable {
+	return NewLightClientBootstrap(b.Version())
+}
+
+/*
+ * LightClientUpdate proves a header signed by the sync committee, the finalized header it points to and the next
+ * sync committee, letting light clients move from one sync committee period to the next.
+ */
+type LightClientUpdate struct {
+	AttestedHeader          *LightClientHeader   `json:"attested_header"`
+	NextSyncCommittee       *solid.SyncCommittee `json:"next_sync_committee"`
+	NextSyncCommitteeBranch solid.HashVectorSSZ  `json:"next_sync_committee_branch"`
+	FinalizedHeader         *LightClientHeader   `json:"finalized_header"`
+	FinalityBranch          solid.HashVectorSSZ  `json:"finality_branch"`
+	SyncAggregate           *SyncAggregate       `json:"sync_aggregate"`
+	SignatureSlot           uint64               `json:"signature_slot,string"`
+}
+
+func NewLightClientUpdate(version clparams.StateVersion) *LightClientUpdate {
+	return &LightClientUpdate{
+		AttestedHeader:          NewLightClientHeader(version),
+		NextSyncCommittee:       &solid.SyncCommittee{},
+		NextSyncCommitteeBranch: solid.NewHashVector(NextSyncCommitteeBranchLength),
+		FinalizedHeader:         NewLightClientHeader(version),
+		FinalityBranch:          solid.NewHashVector(FinalityBranchLength),
+		SyncAggregate:           &SyncAggregate{},
+	}
+}
+
+func (u *LightClientUpdate) Version() clparams.StateVersion {
+	return u.AttestedHeader.version
+}
+
+func (u *LightClientUpdate) Static() bool {
+	return u.AttestedHeader.Static()
+}
+
+func (u *LightClientUpdate) EncodeSSZ(dst []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(dst, u.getSchema()...)
+}
+
+func (u *LightClientUpdate) DecodeSSZ(buf []byte, version int) error {
+	*u = *NewLightClientUpdate(clparams.StateVersion(version))
+	return ssz2.UnmarshalSSZ(buf, version, u.getSchema()...)
+}
+
+func (u *LightClientUpdate) EncodingSizeSSZ() int {
+	size := u.AttestedHeader.EncodingSizeSSZ() + u.NextSyncCommittee.EncodingSizeSSZ() + NextSyncCommitteeBranchLength*length.Hash +
+		u.FinalizedHeader.EncodingSizeSSZ() + FinalityBranchLength*length.Hash + u.SyncAggregate.EncodingSizeSSZ() + 8
+	if !u.Static() {
+		size += 8 // the two header offsets
+	}
+	return size
+}
+
+func (u *LightClientUpdate) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(u.getSchema()...)
+}
+
+func (u *LightClientUpdate) Clone() clonable.Clonable {
+	return NewLightClientUpdate(u.Version())
+}
+
+func (u *LightClientUpdate) getSchema() []interface{} {
+	return []interface{}{u.AttestedHeader, u.NextSyncCommittee, u.NextSyncCommitteeBranch, u.FinalizedHeader, u.FinalityBranch, u.SyncAggregate, &u.SignatureSlot}
+}
+
+/*
+ * LightClientFinalityUpdate is the part of an update that proves the latest finalized header.
+ */
+type LightClientFinalityUpdate struct {
+	AttestedHeader  *LightClientHeader  `json:"attested_header"`
+	FinalizedHeader *LightClientHeader  `json:"finalized_header"`
+	FinalityBranch  solid.HashVectorSSZ `json:"finality_branch"`
+	SyncAggregate   *SyncAggregate      `json:"sync_aggregate"`
+	SignatureSlot   uint64              `json:"signature_slot,string"`
+}
+
+func NewLightClientFinalityUpdate(version clparams.StateVersion) *LightClientFinalityUpdate {
+	return &LightClientFinalityUpdate{
+		AttestedHeader:  NewLightClientHeader(version),
+		FinalizedHeader: NewLightClientHeader(version),
+		FinalityBranch:  solid.NewHashVector(FinalityBranchLength),
+		SyncAggregate:   &SyncAggregate{},
+	}
+}
+
+func (u *LightClientFinalityUpdate) Version() clparams.StateVersion {
+	return u.AttestedHeader.version
+}
+
+func (u *LightClientFinalityUpdate) Static() bool {
+	return u.AttestedHeader.Static()
+}
+
+func (u *LightClientFinalityUpdate) EncodeSSZ(dst []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(dst, u.getSchema()...)
+}
+
+func (u *LightClientFinalityUpdate) DecodeSSZ(buf []byte, version int) error {
+	*u = *NewLightClientFinalityUpdate(clparams.StateVersion(version))
+	return ssz2.UnmarshalSSZ(buf, version, u.getSchema()...)
+}
+
+func (u *LightClientFinalityUpdate) EncodingSizeSSZ() int {
+	size := u.AttestedHeader.EncodingSizeSSZ() + u.FinalizedHeader.EncodingSizeSSZ() + FinalityBranchLength*length.Hash +
+		u.SyncAggregate.EncodingSizeSSZ() + 8
+	if !u.Static() {
+		size += 8 // the two header offsets
+	}
+	return size
+}
+
+func (u *LightClientFinalityUpdate) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(u.getSchema()...)
+}
+
+func (u *LightClientFinalityUpdate) Clone() clonable.Clonable {
+	return NewLightClientFinalityUpdate(u.Version())
+}
+
+func (u *LightClientFinalityUpdate) getSchema() []interface{} {
+	return []interface{}{u.AttestedHeader, u.FinalizedHeader, u.FinalityBranch, u.SyncAggregate, &u.SignatureSlot}
+}
+
+/*
+ * LightClientOptimisticUpdate is the part of an update that proves the latest header signed by the sync committee.
+ */
+type LightClientOptimisticUpdate struct {
+	AttestedHeader *LightClientHeader `json:"attested_header"`
+	SyncAggregate  *SyncAggregate     `json:"sync_aggregate"`
+	SignatureSlot  uint64             `json:"signature_slot,string"`
+}
+
+func NewLightClientOptimisticUpdate(version clparams.StateVersion) *LightClientOptimisticUpdate {
+	return &LightClientOptimisticUpdate{
+		AttestedHeader: NewLightClientHeader(version),
+		SyncAggregate:  &SyncAggregate{},
+	}
+}
+
+func (u *LightClientOptimisticUpdate) Version() clparams.StateVersion {
+	return u.AttestedHeader.version
+}
+
+func (u *LightClientOptimisticUpdate) Static() bool {
+	return u.AttestedHeader.Static()
+}
+
+func (u *LightClientOptimisticUpdate) EncodeSSZ(dst []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(dst, u.AttestedHeader, u.SyncAggregate, &u.SignatureSlot)
+}
+
+func (u *LightClientOptimisticUpdate) DecodeSSZ(buf []byte, version int) error {
+	*u = *NewLightClientOptimisticUpdate(clparams.StateVersion(version))
+	return ssz2.UnmarshalSSZ(buf, version, u.AttestedHeader, u.SyncAggregate, &u.SignatureSlot)
+}
+
+func (u *LightClientOptimisticUpdate) EncodingSizeSSZ() int {
+	size := u.AttestedHeader.EncodingSizeSSZ() + u.SyncAggregate.EncodingSizeSSZ() + 8
+	if !u.Static() {
+		size += 4
+	}
+	return size
+}
+
+func (u *LightClientOptimisticUpdate) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(u.AttestedHeader, u.SyncAggregate, &u.SignatureSlot)
+}
+
+func (u *LightClientOptimisticUpdate) Clone() clonable.Clonable {
+	return NewLightClientOptimisticUpdate(u.Version())
+}
@@ -0,0 +1,86 @@
+package cltypes_test
+
+import (
+	"math/big"
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/utils"
+	"github.com/ledgerwatch/erigon/core/types"
+)
+
+func TestLightClientUpdateEncodeDecodeSSZ(t *testing.T) {
+	for _, version := range []clparams.StateVersion{clparams.AltairVersion, clparams.CapellaVersion, clparams.DenebVersion} {
+		update := cltypes.NewLightClientUpdate(version)
+		update.AttestedHeader.Beacon.Slot = 100
+		update.FinalizedHeader.Beacon.Slot = 64
+		update.NextSyncCommittee[0] = 1
+		update.NextSyncCommitteeBranch.Set(4, libcommon.HexToHash("0x2"))
+		update.FinalityBranch.Set(5, libcommon.HexToHash("0x3"))
+		update.SyncAggregate.SyncCommiteeBits[0] = 0xff
+		update.SignatureSlot = 101
+		if version >= clparams.CapellaVersion {
+			update.AttestedHeader.Execution.BlockNumber = 5
+			update.AttestedHeader.Execution.Extra.SetBytes([]byte{6, 7})
+			update.AttestedHeader.ExecutionBranch.Set(3, libcommon.HexToHash("0x4"))
+		}
+
+		encoded, err := update.EncodeSSZ(nil)
+		require.NoError(t, err)
+		require.Len(t, encoded, update.EncodingSizeSSZ())
+
+		decoded := update.Clone().(*cltypes.LightClientUpdate)
+		require.NoError(t, decoded.DecodeSSZ(encoded, int(version)))
+		require.Equal(t, update, decoded)
+
+		root, err := update.HashSSZ()
+		require.NoError(t, err)
+		decodedRoot, err := decoded.HashSSZ()
+		require.NoError(t, err)
+		require.Equal(t, root, decodedRoot)
+	}
+}
+
+func TestLightClientHeaderExecutionBranch(t *testing.T) {
+	blobGasUsed, excessBlobGas := uint64(1), uint64(2)
+	block := types.NewBlock(&types.Header{
+		Number:        big.NewInt(3),
+		BaseFee:       big.NewInt(7),
+		BlobGasUsed:   &blobGasUsed,
+		ExcessBlobGas: &excessBlobGas,
+	}, nil, nil, nil, types.Withdrawals{})
+	body := cltypes.NewBeaconBody(&clparams.MainnetBeaconConfig)
+	body.Version = clparams.DenebVersion
+	body.Graffiti = [32]byte{4, 5, 6}
+	body.ExecutionPayload = cltypes.NewEth1BlockFromHeaderAndBody(block.Header(), block.RawBody(), &clparams.MainnetBeaconConfig)
+	body.EncodingSizeSSZ() // allocates the empty lists
+	bodyRoot, err := body.HashSSZ()
+	require.NoError(t, err)
+
+	header := cltypes.NewLightClientHeader(clparams.DenebVersion)
+	header.Execution, err = body.ExecutionPayload.PayloadHeader()
+	require.NoError(t, err)
+	proof, err := body.ExecutionPayloadMerkleProof()
+	require.NoError(t, err)
+	require.Len(t, proof, cltypes.ExecutionBranchLength)
+	branch := make([]libcommon.Hash, len(proof))
+	for i, h := range proof {
+		branch[i] = h
+		header.ExecutionBranch.Set(i, h)
+	}
+	payloadRoot, err := header.Execution.HashSSZ()
+	require.NoError(t, err)
+	require.True(t, utils.IsValidMerkleBranch(payloadRoot, branch, cltypes.ExecutionBranchLength, 9, bodyRoot))
+
+	// the header survives an encoding round trip with its execution part.
+	encoded, err := header.EncodeSSZ(nil)
+	require.NoError(t, err)
+	decoded := cltypes.NewLightClientHeader(clparams.AltairVersion)
+	require.NoError(t, decoded.DecodeSSZ(encoded, int(clparams.DenebVersion)))
+	require.Equal(t, header.Execution.BlockNumber, decoded.Execution.BlockNumber)
+	require.Equal(t, header.ExecutionBranch.Get(3), decoded.ExecutionBranch.Get(3))
+}
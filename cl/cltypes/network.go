@@ -1,6 +1,8 @@
 package cltypes
 
 import (
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/length"
 	"github.com/ledgerwatch/erigon-lib/types/clonable"
 	"github.com/ledgerwatch/erigon-lib/types/ssz"
 
@@ -82,6 +84,58 @@ func (*BeaconBlocksByRangeRequest) Clone() clonable.Clonable {
 	return &BeaconBlocksByRangeRequest{}
 }
 
+/*
+ * LightClientUpdatesByRangeRequest is the request for the best light client updates of a range of sync committee
+ * periods.
+ */
+type LightClientUpdatesByRangeRequest struct {
+	StartPeriod uint64
+	Count       uint64
+}
+
+func (l *LightClientUpdatesByRangeRequest) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, l.StartPeriod, l.Count)
+}
+
+func (l *LightClientUpdatesByRangeRequest) DecodeSSZ(buf []byte, v int) error {
+	return ssz2.UnmarshalSSZ(buf, v, &l.StartPeriod, &l.Count)
+}
+
+func (l *LightClientUpdatesByRangeRequest) EncodingSizeSSZ() int {
+	return 2 * 8
+}
+
+func (*LightClientUpdatesByRangeRequest) Clone() clonable.Clonable {
+	return &LightClientUpdatesByRangeRequest{}
+}
+
+/*
+ * Root is a request made of a single root, as for light client bootstraps.
+ */
+type Root struct {
+	Root libcommon.Hash
+}
+
+func (r *Root) EncodeSSZ(buf []byte) ([]byte, error) {
+	return append(buf, r.Root[:]...), nil
+}
+
+func (r *Root) DecodeSSZ(buf []byte, _ int) error {
+	if len(buf) < r.EncodingSizeSSZ() {
+		return ssz.ErrLowBufferSize
+	}
+	copy(r.Root[:], buf)
+	return nil
+}
+
+func (r *Root) EncodingSizeSSZ() int {
+	return length.Hash
+}
+
+func (*Root) Clone() clonable.Clonable {
+	return &Root{}
+}
+
 /*
  * Status is a P2P Message we exchange when connecting to a new Peer.
  * It contains network information about the other peer and if mismatching we drop it.
@@ -1,6 +1,7 @@
 package solid
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/ledgerwatch/erigon-lib/common"
@@ -91,3 +92,19 @@ func TestAttestation(t *testing.T) {
 	cloned := attestation.Clone()
 	assert.NotEqual(t, nil, cloned.(*Attestation))
 }
+
+func TestAttestationJSON(t *testing.T) {
+	source := NewCheckpointFromParameters(common.HexToHash("0x01"), 1)
+	target := NewCheckpointFromParameters(common.HexToHash("0x02"), 2)
+	data := NewAttestionDataFromParameters(64, 3, common.HexToHash("0x03"), source, target)
+	attestation := NewAttestionFromParameters([]byte{0b1101}, data, [96]byte{4})
+
+	encoded, err := json.Marshal(attestation)
+	assert.NoError(t, err)
+	decoded := &Attestation{}
+	assert.NoError(t, json.Unmarshal(encoded, decoded))
+
+	assert.Equal(t, attestation.AggregationBits(), decoded.AggregationBits())
+	assert.Equal(t, attestation.Signature(), decoded.Signature())
+	assert.True(t, data.Equal(decoded.AttestantionData()))
+}
@@ -0,0 +1,173 @@
+package cltypes
+
+import (
+	"fmt"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/types/ssz"
+	"github.com/ledgerwatch/erigon/cl/merkle_tree"
+	ssz2 "github.com/ledgerwatch/erigon/cl/ssz"
+)
+
+// SyncSubcommitteeBitsLength is the size of the aggregation bits of a contribution, SYNC_COMMITTEE_SIZE // SYNC_COMMITTEE_SUBNET_COUNT bits.
+const SyncSubcommitteeBitsLength = 16
+
+// SyncCommitteeMessage is the vote of a single sync committee member for the head block of a slot.
+type SyncCommitteeMessage struct {
+	Slot            uint64            `json:"slot,string"`
+	BeaconBlockRoot libcommon.Hash    `json:"beacon_block_root"`
+	ValidatorIndex  uint64            `json:"validator_index,string"`
+	Signature       libcommon.Bytes96 `json:"signature"`
+}
+
+func (m *SyncCommitteeMessage) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, m.Slot, m.BeaconBlockRoot[:], m.ValidatorIndex, m.Signature[:])
+}
+
+func (m *SyncCommitteeMessage) DecodeSSZ(buf []byte, version int) error {
+	return ssz2.UnmarshalSSZ(buf, version, &m.Slot, m.BeaconBlockRoot[:], &m.ValidatorIndex, m.Signature[:])
+}
+
+func (*SyncCommitteeMessage) EncodingSizeSSZ() int {
+	return 144
+}
+
+func (*SyncCommitteeMessage) Static() bool {
+	return true
+}
+
+func (m *SyncCommitteeMessage) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(m.Slot, m.BeaconBlockRoot[:], m.ValidatorIndex, m.Signature[:])
+}
+
+// SyncCommitteeContribution is the aggregate of the sync committee messages of one subcommittee.
+type SyncCommitteeContribution struct {
+	Slot              uint64            `json:"slot,string"`
+	BeaconBlockRoot   libcommon.Hash    `json:"beacon_block_root"`
+	SubcommitteeIndex uint64            `json:"subcommittee_index,string"`
+	AggregationBits   hexutility.Bytes  `json:"aggregation_bits"`
+	Signature         libcommon.Bytes96 `json:"signature"`
+}
+
+func NewSyncCommitteeContribution() *SyncCommitteeContribution {
+	return &SyncCommitteeContribution{AggregationBits: make(hexutility.Bytes, SyncSubcommitteeBitsLength)}
+}
+
+func (c *SyncCommitteeContribution) EncodeSSZ(buf []byte) ([]byte, error) {
+	if len(c.AggregationBits) != SyncSubcommitteeBitsLength {
+		return nil, fmt.Errorf("[SyncCommitteeContribution] invalid aggregation bits length %d", len(c.AggregationBits))
+	}
+	return ssz2.MarshalSSZ(buf, c.Slot, c.BeaconBlockRoot[:], c.SubcommitteeIndex, []byte(c.AggregationBits), c.Signature[:])
+}
+
+func (c *SyncCommitteeContribution) DecodeSSZ(buf []byte, version int) error {
+	if len(buf) < c.EncodingSizeSSZ() {
+		return fmt.Errorf("[SyncCommitteeContribution] err: %s", ssz.ErrLowBufferSize)
+	}
+	c.AggregationBits = make(hexutility.Bytes, SyncSubcommitteeBitsLength)
+	return ssz2.UnmarshalSSZ(buf, version, &c.Slot, c.BeaconBlockRoot[:], &c.SubcommitteeIndex, []byte(c.AggregationBits), c.Signature[:])
+}
+
+func (*SyncCommitteeContribution) EncodingSizeSSZ() int {
+	return 160
+}
+
+func (*SyncCommitteeContribution) Static() bool {
+	return true
+}
+
+func (c *SyncCommitteeContribution) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(c.Slot, c.BeaconBlockRoot[:], c.SubcommitteeIndex, []byte(c.AggregationBits), c.Signature[:])
+}
+
+// ParticipantsCount returns the amount of subcommittee members included in the contribution.
+func (c *SyncCommitteeContribution) ParticipantsCount() int {
+	count := 0
+	for _, b := range c.AggregationBits {
+		for ; b != 0; b &= b - 1 {
+			count++
+		}
+	}
+	return count
+}
+
+// ContributionAndProof contains the index of the aggregator, the contribution it aggregated and its selection proof.
+type ContributionAndProof struct {
+	AggregatorIndex uint64                     `json:"aggregator_index,string"`
+	Contribution    *SyncCommitteeContribution `json:"contribution"`
+	SelectionProof  libcommon.Bytes96          `json:"selection_proof"`
+}
+
+func (a *ContributionAndProof) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, a.AggregatorIndex, a.Contribution, a.SelectionProof[:])
+}
+
+func (a *ContributionAndProof) DecodeSSZ(buf []byte, version int) error {
+	a.Contribution = NewSyncCommitteeContribution()
+	return ssz2.UnmarshalSSZ(buf, version, &a.AggregatorIndex, a.Contribution, a.SelectionProof[:])
+}
+
+func (*ContributionAndProof) EncodingSizeSSZ() int {
+	return 264
+}
+
+func (*ContributionAndProof) Static() bool {
+	return true
+}
+
+func (a *ContributionAndProof) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(a.AggregatorIndex, a.Contribution, a.SelectionProof[:])
+}
+
+type SignedContributionAndProof struct {
+	Message   *ContributionAndProof `json:"message"`
+	Signature libcommon.Bytes96     `json:"signature"`
+}
+
+func (s *SignedContributionAndProof) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, s.Message, s.Signature[:])
+}
+
+func (s *SignedContributionAndProof) DecodeSSZ(buf []byte, version int) error {
+	s.Message = new(ContributionAndProof)
+	return ssz2.UnmarshalSSZ(buf, version, s.Message, s.Signature[:])
+}
+
+func (*SignedContributionAndProof) EncodingSizeSSZ() int {
+	return 360
+}
+
+func (*SignedContributionAndProof) Static() bool {
+	return true
+}
+
+func (s *SignedContributionAndProof) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(s.Message, s.Signature[:])
+}
+
+// SyncAggregatorSelectionData is the object signed by sync committee aggregators as selection proof.
+type SyncAggregatorSelectionData struct {
+	Slot              uint64 `json:"slot,string"`
+	SubcommitteeIndex uint64 `json:"subcommittee_index,string"`
+}
+
+func (d *SyncAggregatorSelectionData) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, d.Slot, d.SubcommitteeIndex)
+}
+
+func (d *SyncAggregatorSelectionData) DecodeSSZ(buf []byte, version int) error {
+	return ssz2.UnmarshalSSZ(buf, version, &d.Slot, &d.SubcommitteeIndex)
+}
+
+func (*SyncAggregatorSelectionData) EncodingSizeSSZ() int {
+	return 16
+}
+
+func (*SyncAggregatorSelectionData) Static() bool {
+	return true
+}
+
+func (d *SyncAggregatorSelectionData) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(d.Slot, d.SubcommitteeIndex)
+}
@@ -0,0 +1,60 @@
+package cltypes_test
+
+import (
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+)
+
+func TestSignedContributionAndProofEncodeDecodeSSZ(t *testing.T) {
+	contribution := cltypes.NewSyncCommitteeContribution()
+	contribution.Slot = 100
+	contribution.BeaconBlockRoot = libcommon.HexToHash("0x1")
+	contribution.SubcommitteeIndex = 2
+	contribution.AggregationBits[0] = 0b1011
+	contribution.AggregationBits[15] = 0x80
+	contribution.Signature[0] = 3
+	signed := &cltypes.SignedContributionAndProof{
+		Message: &cltypes.ContributionAndProof{
+			AggregatorIndex: 7,
+			Contribution:    contribution,
+			SelectionProof:  libcommon.Bytes96{4},
+		},
+		Signature: libcommon.Bytes96{5},
+	}
+	require.Equal(t, 4, contribution.ParticipantsCount())
+
+	encoded, err := signed.EncodeSSZ(nil)
+	require.NoError(t, err)
+	require.Len(t, encoded, signed.EncodingSizeSSZ())
+
+	decoded := &cltypes.SignedContributionAndProof{}
+	require.NoError(t, decoded.DecodeSSZ(encoded, int(clparams.AltairVersion)))
+	require.Equal(t, signed, decoded)
+
+	root, err := signed.HashSSZ()
+	require.NoError(t, err)
+	decodedRoot, err := decoded.HashSSZ()
+	require.NoError(t, err)
+	require.Equal(t, root, decodedRoot)
+}
+
+func TestSyncCommitteeMessageEncodeDecodeSSZ(t *testing.T) {
+	message := &cltypes.SyncCommitteeMessage{
+		Slot:            10,
+		BeaconBlockRoot: libcommon.HexToHash("0x2"),
+		ValidatorIndex:  11,
+		Signature:       libcommon.Bytes96{6},
+	}
+	encoded, err := message.EncodeSSZ(nil)
+	require.NoError(t, err)
+	require.Len(t, encoded, message.EncodingSizeSSZ())
+
+	decoded := &cltypes.SyncCommitteeMessage{}
+	require.NoError(t, decoded.DecodeSSZ(encoded, int(clparams.AltairVersion)))
+	require.Equal(t, message, decoded)
+}
@@ -0,0 +1,331 @@
+package light_client
+
+import (
+	"fmt"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/merkle_tree"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state/raw"
+	"github.com/ledgerwatch/erigon/cl/sentinel/communication"
+)
+
+const (
+	// blocksKept is how many recent blocks keep the data needed to serve bootstraps and build updates from them.
+	// It covers the finalized checkpoint with plenty of margin.
+	blocksKept = 1024
+	// committeesKept is how many sync committee periods keep their committee.
+	committeesKept = 4
+)
+
+// blockData is what a block contributes to the light client data: its header, and the proofs against its post-state.
+type blockData struct {
+	header                     *cltypes.LightClientHeader
+	currentSyncCommitteeBranch [][32]byte
+	nextSyncCommitteeBranch    [][32]byte
+	finalizedCheckpoint        solid.Checkpoint
+	finalityBranch             [][32]byte
+}
+
+/*
+ * Store keeps the light client data produced while fork choice imports blocks, so that it can be served both over
+ * req/resp and through the beacon API. The best update of each sync committee period, the latest finality update
+ * and the latest optimistic update are kept in memory only and are rebuilt as new blocks come in after a restart.
+ */
+type Store struct {
+	beaconCfg *clparams.BeaconChainConfig
+
+	blocks     *lru.Cache[libcommon.Hash, *blockData]
+	committees *lru.Cache[uint64, *solid.SyncCommittee] // sync committee period -> committee
+
+	mu               sync.RWMutex
+	bestUpdates      map[uint64]*cltypes.LightClientUpdate // sync committee period -> update
+	finalityUpdate   *cltypes.LightClientFinalityUpdate
+	optimisticUpdate *cltypes.LightClientOptimisticUpdate
+}
+
+func NewStore(beaconCfg *clparams.BeaconChainConfig) (*Store, error) {
+	blocks, err := lru.New[libcommon.Hash, *blockData](blocksKept)
+	if err != nil {
+		return nil, err
+	}
+	committees, err := lru.New[uint64, *solid.SyncCommittee](committeesKept)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{
+		beaconCfg:   beaconCfg,
+		blocks:      blocks,
+		committees:  committees,
+		bestUpdates: make(map[uint64]*cltypes.LightClientUpdate),
+	}, nil
+}
+
+func (s *Store) syncCommitteePeriod(slot uint64) uint64 {
+	return slot / (s.beaconCfg.SlotsPerEpoch * s.beaconCfg.EpochsPerSyncCommitteePeriod)
+}
+
+// OnBlock records the light client data of an imported block, postState being the state after the block. If the
+// sync aggregate of the block signs its parent, the updates are refreshed as well.
+func (s *Store) OnBlock(block *cltypes.SignedBeaconBlock, blockRoot libcommon.Hash, postState *state.CachingBeaconState) error {
+	if block.Version() < clparams.AltairVersion {
+		return nil
+	}
+	header, err := headerFromBlock(block)
+	if err != nil {
+		return err
+	}
+	data := &blockData{header: header, finalizedCheckpoint: postState.FinalizedCheckpoint().Copy()}
+	if data.currentSyncCommitteeBranch, err = postState.LeafProof(raw.CurrentSyncCommitteeLeafIndex); err != nil {
+		return err
+	}
+	if data.nextSyncCommitteeBranch, err = postState.LeafProof(raw.NextSyncCommitteeLeafIndex); err != nil {
+		return err
+	}
+	finalizedCheckpointBranch, err := postState.LeafProof(raw.FinalizedCheckpointLeafIndex)
+	if err != nil {
+		return err
+	}
+	// the finalized root is the second field of the checkpoint, its sibling is the epoch.
+	data.finalityBranch = append([][32]byte{merkle_tree.Uint64Root(data.finalizedCheckpoint.Epoch())}, finalizedCheckpointBranch...)
+	s.blocks.Add(blockRoot, data)
+
+	period := s.syncCommitteePeriod(block.Block.Slot)
+	if !s.committees.Contains(period) {
+		s.committees.Add(period, postState.CurrentSyncCommittee().Copy())
+	}
+	if !s.committees.Contains(period + 1) {
+		s.committees.Add(period+1, postState.NextSyncCommittee().Copy())
+	}
+
+	syncAggregate := block.Block.Body.SyncAggregate
+	if uint64(syncAggregate.Sum()) < s.beaconCfg.MinSyncCommitteeParticipants {
+		return nil
+	}
+	attested, ok := s.blocks.Get(block.Block.ParentRoot)
+	if !ok {
+		return nil
+	}
+	update, err := s.createUpdate(attested, syncAggregate, block.Block.Slot)
+	if err != nil {
+		return err
+	}
+	s.processUpdate(update)
+	return nil
+}
+
+// headerFromBlock builds the light client header of a block, in the format of the block fork.
+func headerFromBlock(block *cltypes.SignedBeaconBlock) (*cltypes.LightClientHeader, error) {
+	header := cltypes.NewLightClientHeader(block.Version())
+	header.Beacon = block.SignedBeaconBlockHeader().Header
+	if block.Version() < clparams.CapellaVersion {
+		return header, nil
+	}
+	var err error
+	if header.Execution, err = block.Block.Body.ExecutionPayload.PayloadHeader(); err != nil {
+		return nil, err
+	}
+	branch, err := block.Block.Body.ExecutionPayloadMerkleProof()
+	if err != nil {
+		return nil, err
+	}
+	for i, h := range branch {
+		header.ExecutionBranch.Set(i, h)
+	}
+	return header, nil
+}
+
+// upgradeHeader returns the header in the format of a later fork, as the headers of an update share the format of
+// its attested header.
+func upgradeHeader(header *cltypes.LightClientHeader, version clparams.StateVersion) *cltypes.LightClientHeader {
+	if header.Version() == version {
+		return header
+	}
+	upgraded := cltypes.NewLightClientHeader(version)
+	upgraded.Beacon = header.Beacon.Copy()
+	if header.Version() < clparams.CapellaVersion {
+		// the execution payload is left empty for headers from before capella.
+		return upgraded
+	}
+	upgraded.Execution = header.Execution.Copy()
+	if version >= clparams.DenebVersion {
+		upgraded.Execution.Deneb()
+	}
+	header.ExecutionBranch.CopyTo(upgraded.ExecutionBranch)
+	return upgraded
+}
+
+func (s *Store) createUpdate(attested *blockData, syncAggregate *cltypes.SyncAggregate, signatureSlot uint64) (*cltypes.LightClientUpdate, error) {
+	version := attested.header.Version()
+	update := cltypes.NewLightClientUpdate(version)
+	update.AttestedHeader = attested.header
+	*update.SyncAggregate = *syncAggregate
+	update.SignatureSlot = signatureSlot
+
+	committee, ok := s.committees.Get(s.syncCommitteePeriod(attested.header.Beacon.Slot) + 1)
+	if !ok {
+		return nil, fmt.Errorf("next sync committee of the block at slot %d is not known", attested.header.Beacon.Slot)
+	}
+	update.NextSyncCommittee = committee
+	for i, h := range attested.nextSyncCommitteeBranch {
+		update.NextSyncCommitteeBranch.Set(i, h)
+	}
+
+	finalizedRoot := attested.finalizedCheckpoint.BlockRoot()
+	if finalizedRoot != (libcommon.Hash{}) {
+		finalized, ok := s.blocks.Get(finalizedRoot)
+		if !ok {
+			// without the finalized header the update carries no finality.
+			return update, nil
+		}
+		update.FinalizedHeader = upgradeHeader(finalized.header, version)
+	}
+	// at genesis the finalized root is zero and so is the finalized header, but the branch is still given.
+	for i, h := range attested.finalityBranch {
+		update.FinalityBranch.Set(i, h)
+	}
+	return update, nil
+}
+
+// hasFinality tells whether the update proves a finalized header, that is if its finality branch is set.
+func hasFinality(update *cltypes.LightClientUpdate) bool {
+	for i := 0; i < update.FinalityBranch.Length(); i++ {
+		if update.FinalityBranch.Get(i) != (libcommon.Hash{}) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) processUpdate(update *cltypes.LightClientUpdate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attestedSlot := update.AttestedHeader.Beacon.Slot
+	if s.optimisticUpdate == nil || attestedSlot > s.optimisticUpdate.AttestedHeader.Beacon.Slot {
+		s.optimisticUpdate = &cltypes.LightClientOptimisticUpdate{
+			AttestedHeader: update.AttestedHeader,
+			SyncAggregate:  update.SyncAggregate,
+			SignatureSlot:  update.SignatureSlot,
+		}
+	}
+	if hasFinality(update) && (s.finalityUpdate == nil ||
+		update.FinalizedHeader.Beacon.Slot > s.finalityUpdate.FinalizedHeader.Beacon.Slot ||
+		(update.FinalizedHeader.Beacon.Slot == s.finalityUpdate.FinalizedHeader.Beacon.Slot && attestedSlot > s.finalityUpdate.AttestedHeader.Beacon.Slot)) {
+		s.finalityUpdate = &cltypes.LightClientFinalityUpdate{
+			AttestedHeader:  update.AttestedHeader,
+			FinalizedHeader: update.FinalizedHeader,
+			FinalityBranch:  update.FinalityBranch,
+			SyncAggregate:   update.SyncAggregate,
+			SignatureSlot:   update.SignatureSlot,
+		}
+	}
+
+	period := s.syncCommitteePeriod(attestedSlot)
+	if best, ok := s.bestUpdates[period]; !ok || s.isBetterUpdate(update, best) {
+		s.bestUpdates[period] = update
+	}
+	for p := range s.bestUpdates {
+		if p+communication.MaximumRequestClientUpdates <= period {
+			delete(s.bestUpdates, p)
+		}
+	}
+}
+
+// isBetterUpdate implements is_better_update of the light client sync protocol.
+func (s *Store) isBetterUpdate(newUpdate, oldUpdate *cltypes.LightClientUpdate) bool {
+	maxParticipants := s.beaconCfg.SyncCommitteeSize
+	newParticipants, oldParticipants := uint64(newUpdate.SyncAggregate.Sum()), uint64(oldUpdate.SyncAggregate.Sum())
+	newSupermajority, oldSupermajority := newParticipants*3 >= maxParticipants*2, oldParticipants*3 >= maxParticipants*2
+	if newSupermajority != oldSupermajority {
+		return newSupermajority
+	}
+	if !newSupermajority && newParticipants != oldParticipants {
+		return newParticipants > oldParticipants
+	}
+
+	// every update carries the next sync committee, it is relevant if signed in the same period as attested.
+	newRelevantSyncCommittee := s.syncCommitteePeriod(newUpdate.AttestedHeader.Beacon.Slot) == s.syncCommitteePeriod(newUpdate.SignatureSlot)
+	oldRelevantSyncCommittee := s.syncCommitteePeriod(oldUpdate.AttestedHeader.Beacon.Slot) == s.syncCommitteePeriod(oldUpdate.SignatureSlot)
+	if newRelevantSyncCommittee != oldRelevantSyncCommittee {
+		return newRelevantSyncCommittee
+	}
+
+	newHasFinality, oldHasFinality := hasFinality(newUpdate), hasFinality(oldUpdate)
+	if newHasFinality != oldHasFinality {
+		return newHasFinality
+	}
+	if newHasFinality {
+		newSyncCommitteeFinality := s.syncCommitteePeriod(newUpdate.FinalizedHeader.Beacon.Slot) == s.syncCommitteePeriod(newUpdate.AttestedHeader.Beacon.Slot)
+		oldSyncCommitteeFinality := s.syncCommitteePeriod(oldUpdate.FinalizedHeader.Beacon.Slot) == s.syncCommitteePeriod(oldUpdate.AttestedHeader.Beacon.Slot)
+		if newSyncCommitteeFinality != oldSyncCommitteeFinality {
+			return newSyncCommitteeFinality
+		}
+	}
+
+	if newParticipants != oldParticipants {
+		return newParticipants > oldParticipants
+	}
+	if newUpdate.AttestedHeader.Beacon.Slot != oldUpdate.AttestedHeader.Beacon.Slot {
+		return newUpdate.AttestedHeader.Beacon.Slot < oldUpdate.AttestedHeader.Beacon.Slot
+	}
+	return newUpdate.SignatureSlot < oldUpdate.SignatureSlot
+}
+
+// Bootstrap returns the bootstrap for a recent block, false if the block is not known.
+func (s *Store) Bootstrap(blockRoot libcommon.Hash) (*cltypes.LightClientBootstrap, bool) {
+	data, ok := s.blocks.Get(blockRoot)
+	if !ok {
+		return nil, false
+	}
+	committee, ok := s.committees.Get(s.syncCommitteePeriod(data.header.Beacon.Slot))
+	if !ok {
+		return nil, false
+	}
+	bootstrap := cltypes.NewLightClientBootstrap(data.header.Version())
+	bootstrap.Header = data.header
+	bootstrap.CurrentSyncCommittee = committee
+	for i, h := range data.currentSyncCommitteeBranch {
+		bootstrap.CurrentSyncCommitteeBranch.Set(i, h)
+	}
+	return bootstrap, true
+}
+
+// Updates returns the best update of up to count consecutive sync committee periods from startPeriod, stopping at
+// the first period without one.
+func (s *Store) Updates(startPeriod, count uint64) []*cltypes.LightClientUpdate {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if count > communication.MaximumRequestClientUpdates {
+		count = communication.MaximumRequestClientUpdates
+	}
+	updates := make([]*cltypes.LightClientUpdate, 0, count)
+	for period := startPeriod; period < startPeriod+count; period++ {
+		update, ok := s.bestUpdates[period]
+		if !ok {
+			break
+		}
+		updates = append(updates, update)
+	}
+	return updates
+}
+
+// FinalityUpdate returns the latest finality update, nil if there is none yet.
+func (s *Store) FinalityUpdate() *cltypes.LightClientFinalityUpdate {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.finalityUpdate
+}
+
+// OptimisticUpdate returns the latest optimistic update, nil if there is none yet.
+func (s *Store) OptimisticUpdate() *cltypes.LightClientOptimisticUpdate {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.optimisticUpdate
+}
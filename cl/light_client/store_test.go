@@ -0,0 +1,50 @@
+package light_client
+
+import (
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+)
+
+func testUpdate(attestedSlot, signatureSlot uint64, participants int, finality bool) *cltypes.LightClientUpdate {
+	update := cltypes.NewLightClientUpdate(clparams.AltairVersion)
+	update.AttestedHeader.Beacon.Slot = attestedSlot
+	update.SignatureSlot = signatureSlot
+	for i := 0; i < participants; i++ {
+		update.SyncAggregate.SyncCommiteeBits[i/8] |= 1 << (i % 8)
+	}
+	if finality {
+		update.FinalizedHeader.Beacon.Slot = attestedSlot - 64
+		update.FinalityBranch.Set(0, libcommon.HexToHash("0x1"))
+	}
+	return update
+}
+
+func TestStoreBestUpdates(t *testing.T) {
+	s, err := NewStore(&clparams.MainnetBeaconConfig)
+	require.NoError(t, err)
+	slotsPerPeriod := clparams.MainnetBeaconConfig.SlotsPerEpoch * clparams.MainnetBeaconConfig.EpochsPerSyncCommitteePeriod
+
+	// a supermajority wins over a larger minority, finality breaks ties in participation class.
+	s.processUpdate(testUpdate(100, 101, 300, true))
+	s.processUpdate(testUpdate(102, 103, 400, false))
+	s.processUpdate(testUpdate(104, 105, 350, true))
+	updates := s.Updates(0, 1)
+	require.Len(t, updates, 1)
+	require.Equal(t, uint64(104), updates[0].AttestedHeader.Beacon.Slot)
+
+	// the optimistic update follows the latest attested header, the finality update the latest finalized one.
+	require.Equal(t, uint64(104), s.OptimisticUpdate().AttestedHeader.Beacon.Slot)
+	require.Equal(t, uint64(104), s.FinalityUpdate().AttestedHeader.Beacon.Slot)
+
+	// ranges stop at the first period without an update.
+	s.processUpdate(testUpdate(slotsPerPeriod+100, slotsPerPeriod+101, 400, true))
+	s.processUpdate(testUpdate(3*slotsPerPeriod+100, 3*slotsPerPeriod+101, 400, true))
+	require.Len(t, s.Updates(0, 4), 2)
+	require.Len(t, s.Updates(3, 4), 1)
+	require.Empty(t, s.Updates(2, 4))
+}
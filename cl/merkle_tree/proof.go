@@ -0,0 +1,35 @@
+package merkle_tree
+
+import (
+	"fmt"
+
+	"github.com/prysmaticlabs/gohashtree"
+)
+
+// MerkleProof computes the branch of the leaf at proofIndex in a tree of 2^depth leaves. Missing leaves past the
+// end of the slice are treated as zero hashes.
+func MerkleProof(depth, proofIndex int, leaves [][32]byte) ([][32]byte, error) {
+	if proofIndex >= len(leaves) || len(leaves) > 1<<depth {
+		return nil, fmt.Errorf("proof index %d out of range for %d leaves at depth %d", proofIndex, len(leaves), depth)
+	}
+	layer := make([][32]byte, len(leaves))
+	copy(layer, leaves)
+	branch := make([][32]byte, depth)
+	for i := 0; i < depth; i++ {
+		if sibling := proofIndex ^ 1; sibling < len(layer) {
+			branch[i] = layer[sibling]
+		} else {
+			branch[i] = ZeroHashes[i]
+		}
+		if len(layer)%2 == 1 {
+			layer = append(layer, ZeroHashes[i])
+		}
+		next := make([][32]byte, len(layer)/2)
+		if err := gohashtree.Hash(next, layer); err != nil {
+			return nil, err
+		}
+		layer = next
+		proofIndex /= 2
+	}
+	return branch, nil
+}
@@ -0,0 +1,114 @@
+package blob_storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/spf13/afero"
+)
+
+const subdivisionSlot = 10_000
+
+// BlobStorage keeps the blob sidecars of recent blocks, for as long as they must be served to peers and API users.
+type BlobStorage interface {
+	WriteBlobSidecars(ctx context.Context, blockRoot libcommon.Hash, blobSidecars []*cltypes.BlobSidecar) error
+	ReadBlobSidecars(ctx context.Context, slot uint64, blockRoot libcommon.Hash) (out []*cltypes.BlobSidecar, found bool, err error)
+	Prune(currentSlot uint64) error
+}
+
+type aferoBlobStorage struct {
+	fs        afero.Fs
+	slotsKept uint64
+}
+
+// NewBlobStore creates a sidecar store which keeps the sidecars of the last slotsKept slots.
+func NewBlobStore(fs afero.Fs, slotsKept uint64) BlobStorage {
+	return &aferoBlobStorage{fs: fs, slotsKept: slotsKept}
+}
+
+// NewBlobStoreFromOsPath creates a sidecar store rooted at path, keeping the sidecars for the retention period of
+// the network.
+func NewBlobStoreFromOsPath(cfg *clparams.BeaconChainConfig, path string) BlobStorage {
+	return NewBlobStore(afero.NewBasePathFs(afero.NewOsFs(), path), cfg.MinEpochsForBlobSidecarsRequests*cfg.SlotsPerEpoch)
+}
+
+// blobSidecarPaths defines the file structure to store a sidecar
+//
+// "/{slot/10_000}/{root}_{index}.sz"
+func blobSidecarPaths(slot uint64, blockRoot libcommon.Hash, index uint64) (folderPath string, filePath string) {
+	folderPath = strconv.FormatUint(slot/subdivisionSlot, 10)
+	return folderPath, fmt.Sprintf("%s/%x_%d.sz", folderPath, blockRoot, index)
+}
+
+func (a *aferoBlobStorage) WriteBlobSidecars(ctx context.Context, blockRoot libcommon.Hash, blobSidecars []*cltypes.BlobSidecar) error {
+	for _, sidecar := range blobSidecars {
+		folderPath, filePath := blobSidecarPaths(sidecar.SignedBlockHeader.Header.Slot, blockRoot, sidecar.Index)
+		if err := a.fs.MkdirAll(folderPath, 0o755); err != nil {
+			return err
+		}
+		encoded, err := sidecar.EncodeSSZ(nil)
+		if err != nil {
+			return err
+		}
+		if err := afero.WriteFile(a.fs, filePath, encoded, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadBlobSidecars returns the stored sidecars of a block, ordered by index. Sidecars which were never received
+// are skipped.
+func (a *aferoBlobStorage) ReadBlobSidecars(ctx context.Context, slot uint64, blockRoot libcommon.Hash) ([]*cltypes.BlobSidecar, bool, error) {
+	var out []*cltypes.BlobSidecar
+	for index := uint64(0); index < cltypes.MaxBlobsPerBlock; index++ {
+		_, filePath := blobSidecarPaths(slot, blockRoot, index)
+		encoded, err := afero.ReadFile(a.fs, filePath)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		sidecar := cltypes.NewBlobSidecar()
+		if err := sidecar.DecodeSSZ(encoded, int(clparams.DenebVersion)); err != nil {
+			return nil, false, fmt.Errorf("decoding blob sidecar %d of block %x: %w", index, blockRoot, err)
+		}
+		out = append(out, sidecar)
+	}
+	return out, len(out) > 0, nil
+}
+
+// Prune removes the sidecars out of the retention period. Sidecars are deleted a whole folder at a time, so a few
+// more slots than required may be kept.
+func (a *aferoBlobStorage) Prune(currentSlot uint64) error {
+	if currentSlot <= a.slotsKept {
+		return nil
+	}
+	oldestSlot := currentSlot - a.slotsKept
+	folders, err := afero.ReadDir(a.fs, "/")
+	if err != nil {
+		return err
+	}
+	for _, folder := range folders {
+		if !folder.IsDir() {
+			continue
+		}
+		subdivision, err := strconv.ParseUint(folder.Name(), 10, 64)
+		if err != nil {
+			continue
+		}
+		if (subdivision+1)*subdivisionSlot > oldestSlot {
+			continue
+		}
+		if err := a.fs.RemoveAll(folder.Name()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package blob_storage
+
+import (
+	"context"
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSidecar(slot, index uint64) *cltypes.BlobSidecar {
+	sidecar := cltypes.NewBlobSidecar()
+	sidecar.Index = index
+	sidecar.Blob[0] = byte(index + 1)
+	sidecar.KzgCommitment[0] = byte(index + 2)
+	sidecar.SignedBlockHeader.Header.Slot = slot
+	return sidecar
+}
+
+func TestBlobStorageWriteRead(t *testing.T) {
+	ctx := context.Background()
+	store := NewBlobStore(afero.NewMemMapFs(), 100)
+	blockRoot := libcommon.HexToHash("0x1")
+
+	_, found, err := store.ReadBlobSidecars(ctx, 5, blockRoot)
+	require.NoError(t, err)
+	require.False(t, found)
+
+	// sidecars can arrive out of order and one at a time from gossip.
+	require.NoError(t, store.WriteBlobSidecars(ctx, blockRoot, []*cltypes.BlobSidecar{newTestSidecar(5, 2)}))
+	require.NoError(t, store.WriteBlobSidecars(ctx, blockRoot, []*cltypes.BlobSidecar{newTestSidecar(5, 0)}))
+
+	sidecars, found, err := store.ReadBlobSidecars(ctx, 5, blockRoot)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, []*cltypes.BlobSidecar{newTestSidecar(5, 0), newTestSidecar(5, 2)}, sidecars)
+
+	_, found, err = store.ReadBlobSidecars(ctx, 5, libcommon.HexToHash("0x2"))
+	require.NoError(t, err)
+	require.False(t, found)
+}
+
+func TestBlobStoragePrune(t *testing.T) {
+	ctx := context.Background()
+	store := NewBlobStore(afero.NewMemMapFs(), 100)
+	oldRoot, recentRoot := libcommon.HexToHash("0x1"), libcommon.HexToHash("0x2")
+	require.NoError(t, store.WriteBlobSidecars(ctx, oldRoot, []*cltypes.BlobSidecar{newTestSidecar(5, 0)}))
+	require.NoError(t, store.WriteBlobSidecars(ctx, recentRoot, []*cltypes.BlobSidecar{newTestSidecar(subdivisionSlot+5, 0)}))
+
+	// the first folder still holds slots within the retention period.
+	require.NoError(t, store.Prune(subdivisionSlot+50))
+	_, found, err := store.ReadBlobSidecars(ctx, 5, oldRoot)
+	require.NoError(t, err)
+	require.True(t, found)
+
+	require.NoError(t, store.Prune(subdivisionSlot+100))
+	_, found, err = store.ReadBlobSidecars(ctx, 5, oldRoot)
+	require.NoError(t, err)
+	require.False(t, found)
+	_, found, err = store.ReadBlobSidecars(ctx, subdivisionSlot+5, recentRoot)
+	require.NoError(t, err)
+	require.True(t, found)
+}
@@ -2,6 +2,7 @@ package persistence
 
 import (
 	"context"
+	"math/big"
 	"testing"
 
 	_ "embed"
@@ -15,6 +16,7 @@ import (
 	"github.com/ledgerwatch/erigon/cl/phase1/execution_client"
 	"github.com/ledgerwatch/erigon/cl/utils"
 	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/turbo/engineapi/engine_types"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/require"
 )
@@ -79,6 +81,14 @@ func (m *mockEngine) GetBodiesByHashes(hashes []libcommon.Hash) ([]*types.RawBod
 	panic("unimplemented")
 }
 
+func (m *mockEngine) AssembleBlock(head libcommon.Hash, attributes *engine_types.PayloadAttributes) (uint64, error) {
+	panic("unimplemented")
+}
+
+func (m *mockEngine) GetAssembledBlock(payloadId uint64, version clparams.StateVersion) (*engine_types.ExecutionPayload, *engine_types.BlobsBundleV1, *big.Int, error) {
+	panic("unimplemented")
+}
+
 //go:embed test_data/test_block.ssz_snappy
 var testBlock []byte
 
@@ -23,6 +23,18 @@ func (b *BeaconState) HashSSZ() (out [32]byte, err error) {
 	return
 }
 
+// LeafProof returns the branch proving the state field at leaf against the state root.
+func (b *BeaconState) LeafProof(leaf StateLeafIndex) ([][32]byte, error) {
+	if err := b.computeDirtyLeaves(); err != nil {
+		return nil, err
+	}
+	leaves := make([][32]byte, len(b.leaves)/32)
+	for i := range leaves {
+		copy(leaves[i][:], b.leaves[i*32:])
+	}
+	return merkle_tree.MerkleProof(int(merkle_tree.GetDepth(uint64(len(leaves)))), int(leaf), leaves)
+}
+
 func preparateRootsForHashing(roots []common.Hash) [][32]byte {
 	ret := make([][32]byte, len(roots))
 	for i := range roots {
@@ -6,6 +6,8 @@ import (
 
 	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/cl/utils"
 )
 
 func TestGetters(t *testing.T) {
@@ -29,3 +31,20 @@ func TestGetters(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, common.Hash(root), common.HexToHash("0x9f1620db18ee06b9cbdf1b7fa9658701063d2bd05d54b09780f6c0a074b4ce5f"))
 }
+
+func TestLeafProof(t *testing.T) {
+	state := GetTestState()
+	stateRoot, err := state.HashSSZ()
+	require.NoError(t, err)
+
+	proof, err := state.LeafProof(NextSyncCommitteeLeafIndex)
+	require.NoError(t, err)
+	branch := make([]common.Hash, len(proof))
+	for i := range proof {
+		branch[i] = proof[i]
+	}
+	committeeRoot, err := state.NextSyncCommittee().HashSSZ()
+	require.NoError(t, err)
+	require.True(t, utils.IsValidMerkleBranch(committeeRoot, branch, 5, uint64(NextSyncCommitteeLeafIndex), stateRoot))
+	require.False(t, utils.IsValidMerkleBranch(committeeRoot, branch, 5, uint64(InactivityScoresLeafIndex), stateRoot))
+}
@@ -3,11 +3,14 @@ package execution_client
 import (
 	"context"
 	"fmt"
+	"math/big"
 
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/gointerfaces/execution"
+	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
 	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/turbo/engineapi/engine_types"
 	"github.com/ledgerwatch/erigon/turbo/execution/eth1/eth1_chain_reader.go"
 )
 
@@ -99,3 +102,11 @@ func (cc *ExecutionClientDirect) GetBodiesByHashes(hashes []libcommon.Hash) ([]*
 func (cc *ExecutionClientDirect) FrozenBlocks() uint64 {
 	return cc.chainRW.FrozenBlocks()
 }
+
+func (cc *ExecutionClientDirect) AssembleBlock(head libcommon.Hash, attributes *engine_types.PayloadAttributes) (uint64, error) {
+	return cc.chainRW.AssembleBlock(head, attributes)
+}
+
+func (cc *ExecutionClientDirect) GetAssembledBlock(payloadId uint64, _ clparams.StateVersion) (*engine_types.ExecutionPayload, *engine_types.BlobsBundleV1, *big.Int, error) {
+	return cc.chainRW.GetAssembledBlock(payloadId)
+}
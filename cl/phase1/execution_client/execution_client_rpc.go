@@ -2,6 +2,7 @@ package execution_client
 
 import (
 	"context"
+	"encoding/binary"
 	"fmt"
 	"github.com/ledgerwatch/erigon-lib/common/hexutil"
 	"math/big"
@@ -149,6 +150,60 @@ func (cc *ExecutionClientRpc) ForkChoiceUpdate(finalized libcommon.Hash, head li
 	return checkPayloadStatus(forkChoiceResp.PayloadStatus)
 }
 
+func (cc *ExecutionClientRpc) AssembleBlock(head libcommon.Hash, attributes *engine_types.PayloadAttributes) (uint64, error) {
+	forkChoiceRequest := engine_types.ForkChoiceState{
+		HeadHash:           head,
+		SafeBlockHash:      head,
+		FinalizedBlockHash: head,
+	}
+	// the attributes tell which fork the payload belongs to
+	engineMethod := rpc_helper.ForkChoiceUpdatedV1
+	if attributes.ParentBeaconBlockRoot != nil {
+		engineMethod = rpc_helper.ForkChoiceUpdatedV3
+	} else if attributes.Withdrawals != nil {
+		engineMethod = rpc_helper.ForkChoiceUpdatedV2
+	}
+	forkChoiceResp := &engine_types.ForkChoiceUpdatedResponse{}
+	log.Debug("[ExecutionClientRpc] Calling EL", "method", engineMethod)
+
+	if err := cc.client.CallContext(cc.ctx, forkChoiceResp, engineMethod, forkChoiceRequest, attributes); err != nil {
+		return 0, fmt.Errorf("execution Client RPC failed to retrieve ForkChoiceUpdate response, err: %w", err)
+	}
+	if err := checkPayloadStatus(forkChoiceResp.PayloadStatus); err != nil {
+		return 0, err
+	}
+	if forkChoiceResp.PayloadId == nil || len(*forkChoiceResp.PayloadId) != 8 {
+		return 0, fmt.Errorf("execution Client RPC did not start building a payload")
+	}
+	return binary.BigEndian.Uint64(*forkChoiceResp.PayloadId), nil
+}
+
+func (cc *ExecutionClientRpc) GetAssembledBlock(payloadId uint64, version clparams.StateVersion) (*engine_types.ExecutionPayload, *engine_types.BlobsBundleV1, *big.Int, error) {
+	id := engine_types.ConvertPayloadId(payloadId)
+	log.Debug("[ExecutionClientRpc] Calling EL", "method", "getPayload", "version", version)
+	switch version {
+	case clparams.BellatrixVersion:
+		// V1 returns the bare payload
+		payload := &engine_types.ExecutionPayload{}
+		if err := cc.client.CallContext(cc.ctx, payload, rpc_helper.GetPayloadV1, id); err != nil {
+			return nil, nil, nil, fmt.Errorf("execution Client RPC failed to retrieve the payload, err: %w", err)
+		}
+		return payload, nil, nil, nil
+	case clparams.CapellaVersion, clparams.DenebVersion:
+		engineMethod := rpc_helper.GetPayloadV2
+		if version == clparams.DenebVersion {
+			engineMethod = rpc_helper.GetPayloadV3
+		}
+		resp := &engine_types.GetPayloadResponse{}
+		if err := cc.client.CallContext(cc.ctx, resp, engineMethod, id); err != nil {
+			return nil, nil, nil, fmt.Errorf("execution Client RPC failed to retrieve the payload, err: %w", err)
+		}
+		return resp.ExecutionPayload, resp.BlobsBundle, resp.BlockValue.ToInt(), nil
+	default:
+		return nil, nil, nil, fmt.Errorf("invalid payload version")
+	}
+}
+
 func checkPayloadStatus(payloadStatus *engine_types.PayloadStatus) error {
 	if payloadStatus == nil {
 		return fmt.Errorf("empty payloadStatus")
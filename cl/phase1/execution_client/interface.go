@@ -1,10 +1,14 @@
 package execution_client
 
 import (
+	"math/big"
+
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 
+	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
 	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/turbo/engineapi/engine_types"
 )
 
 var errContextExceeded = "rpc error: code = DeadlineExceeded desc = context deadline exceeded"
@@ -24,4 +28,7 @@ type ExecutionEngine interface {
 	GetBodiesByHashes(hashes []libcommon.Hash) ([]*types.RawBody, error)
 	// Snapshots
 	FrozenBlocks() uint64
+	// Block production
+	AssembleBlock(head libcommon.Hash, attributes *engine_types.PayloadAttributes) (payloadId uint64, err error)
+	GetAssembledBlock(payloadId uint64, version clparams.StateVersion) (*engine_types.ExecutionPayload, *engine_types.BlobsBundleV1, *big.Int, error)
 }
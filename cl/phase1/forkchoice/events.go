@@ -0,0 +1,86 @@
+package forkchoice
+
+import (
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconevents"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+)
+
+// onNewHead publishes the head event, and the chain_reorg event if the new head does not descend from the previous one.
+func (f *ForkChoiceStore) onNewHead(headRoot libcommon.Hash, headSlot uint64) {
+	if headRoot == f.publishedHeadHash {
+		return
+	}
+	oldHeadRoot, oldHeadSlot := f.publishedHeadHash, f.publishedHeadSlot
+	f.publishedHeadHash, f.publishedHeadSlot = headRoot, headSlot
+
+	header, has := f.forkGraph.GetHeader(headRoot)
+	if !has {
+		return
+	}
+	epoch := f.computeEpochAtSlot(headSlot)
+	f.emitters.Publish(beaconevents.TopicHead, &beaconevents.HeadData{
+		Slot:                      headSlot,
+		Block:                     headRoot,
+		State:                     header.Root,
+		EpochTransition:           f.computeSlotsSinceEpochStart(headSlot) == 0,
+		PreviousDutyDependentRoot: f.dutyDependentRoot(headRoot, epoch, 1),
+		CurrentDutyDependentRoot:  f.dutyDependentRoot(headRoot, epoch, 0),
+	})
+
+	if f.Ancestor(headRoot, oldHeadSlot) == oldHeadRoot {
+		return
+	}
+	oldHeader, has := f.forkGraph.GetHeader(oldHeadRoot)
+	if !has {
+		return
+	}
+	// walk back the old chain until it meets the new one, which happens at the latest at the finalized checkpoint
+	var depth uint64
+	if finalizedSlot := f.computeStartSlotAtEpoch(f.finalizedCheckpoint.Epoch()); oldHeadSlot > finalizedSlot {
+		depth = oldHeadSlot - finalizedSlot
+	}
+	for root := oldHeader.ParentRoot; ; {
+		ancestor, has := f.forkGraph.GetHeader(root)
+		if !has {
+			break
+		}
+		if f.Ancestor(headRoot, ancestor.Slot) == root {
+			depth = oldHeadSlot - ancestor.Slot
+			break
+		}
+		root = ancestor.ParentRoot
+	}
+	f.emitters.Publish(beaconevents.TopicChainReorg, &beaconevents.ChainReorgData{
+		Slot:         headSlot,
+		Depth:        depth,
+		OldHeadBlock: oldHeadRoot,
+		NewHeadBlock: headRoot,
+		OldHeadState: oldHeader.Root,
+		NewHeadState: header.Root,
+		Epoch:        epoch,
+	})
+}
+
+// dutyDependentRoot returns the root of the block the duties of the given number of epochs before the epoch depend on:
+// the last block of the epoch preceding them, or the genesis block.
+func (f *ForkChoiceStore) dutyDependentRoot(headRoot libcommon.Hash, epoch, epochsBefore uint64) libcommon.Hash {
+	if epoch < epochsBefore+1 {
+		return f.Ancestor(headRoot, 0)
+	}
+	return f.Ancestor(headRoot, f.computeStartSlotAtEpoch(epoch-epochsBefore)-1)
+}
+
+// onFinalizedCheckpoint publishes the finalized_checkpoint event. It must be called before the fork graph is pruned.
+func (f *ForkChoiceStore) onFinalizedCheckpoint(checkpoint solid.Checkpoint) {
+	var stateRoot libcommon.Hash
+	if header, has := f.forkGraph.GetHeader(checkpoint.BlockRoot()); has {
+		stateRoot = header.Root
+	}
+	f.emitters.Publish(beaconevents.TopicFinalizedCheckpoint, &beaconevents.FinalizedCheckpointData{
+		Block: checkpoint.BlockRoot(),
+		State: stateRoot,
+		Epoch: checkpoint.Epoch(),
+	})
+}
@@ -5,6 +5,7 @@ import (
 	_ "embed"
 	"testing"
 
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconevents"
 	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
 	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
 	"github.com/ledgerwatch/erigon/cl/phase1/forkchoice"
@@ -51,7 +52,10 @@ func TestForkChoiceBasic(t *testing.T) {
 	anchorState := state.New(&clparams.MainnetBeaconConfig)
 	require.NoError(t, utils.DecodeSSZSnappy(anchorState, anchorStateEncoded, int(clparams.AltairVersion)))
 	pool := pool.NewOperationsPool(&clparams.MainnetBeaconConfig)
-	store, err := forkchoice.NewForkChoiceStore(context.Background(), anchorState, nil, nil, pool, fork_graph.NewForkGraphDisk(anchorState, afero.NewMemMapFs()))
+	emitters := beaconevents.NewEmitters()
+	events, unsubscribe := emitters.Subscribe([]string{beaconevents.TopicHead, beaconevents.TopicBlock, beaconevents.TopicVoluntaryExit})
+	defer unsubscribe()
+	store, err := forkchoice.NewForkChoiceStore(context.Background(), anchorState, nil, nil, pool, fork_graph.NewForkGraphDisk(anchorState, afero.NewMemMapFs()), emitters, nil)
 	require.NoError(t, err)
 	// first steps
 	store.OnTick(0)
@@ -106,4 +110,99 @@ func TestForkChoiceBasic(t *testing.T) {
 	}, true)
 	require.NoError(t, err)
 	require.Equal(t, len(pool.VoluntaryExistsPool.Raw()), 1)
+	// Check the published events
+	var topics []string
+	var heads []libcommon.Hash
+	for len(events) > 0 {
+		event := <-events
+		topics = append(topics, event.Topic)
+		if event.Topic == beaconevents.TopicHead {
+			heads = append(heads, event.Data.(*beaconevents.HeadData).Block)
+		}
+	}
+	require.Equal(t, []string{
+		beaconevents.TopicBlock, beaconevents.TopicHead,
+		beaconevents.TopicBlock, beaconevents.TopicHead,
+		beaconevents.TopicBlock,
+		beaconevents.TopicHead, // the attestation moves the head to 0xd4
+		beaconevents.TopicVoluntaryExit,
+	}, topics)
+	block0xd4Root, err := block0xd4.Block.HashSSZ()
+	require.NoError(t, err)
+	require.Equal(t, []libcommon.Hash{
+		libcommon.HexToHash("0xc9bd7bcb6dfa49dc4e5a67ca75e89062c36b5c300bc25a1b31db4e1a89306071"),
+		libcommon.HexToHash("0x744cc484f6503462f0f3a5981d956bf4fcb3e57ab8687ed006467e05049ee033"),
+		block0xd4Root,
+	}, heads)
+}
+
+func TestOnBlobSidecar(t *testing.T) {
+	block0x3a, block0xc2, block0xd4 := cltypes.NewSignedBeaconBlock(&clparams.MainnetBeaconConfig), cltypes.NewSignedBeaconBlock(&clparams.MainnetBeaconConfig), cltypes.NewSignedBeaconBlock(&clparams.MainnetBeaconConfig)
+	require.NoError(t, utils.DecodeSSZSnappy(block0x3a, block3aEncoded, int(clparams.AltairVersion)))
+	require.NoError(t, utils.DecodeSSZSnappy(block0xc2, blockc2Encoded, int(clparams.AltairVersion)))
+	require.NoError(t, utils.DecodeSSZSnappy(block0xd4, blockd4Encoded, int(clparams.AltairVersion)))
+	anchorState := state.New(&clparams.MainnetBeaconConfig)
+	require.NoError(t, utils.DecodeSSZSnappy(anchorState, anchorStateEncoded, int(clparams.AltairVersion)))
+	store, err := forkchoice.NewForkChoiceStore(context.Background(), anchorState, nil, nil, pool.NewOperationsPool(&clparams.MainnetBeaconConfig), fork_graph.NewForkGraphDisk(anchorState, afero.NewMemMapFs()), beaconevents.NewEmitters(), nil)
+	require.NoError(t, err)
+	store.OnTick(12)
+	require.NoError(t, store.OnBlock(block0x3a, false, true))
+	store.OnTick(36)
+
+	// the sidecars are checked against the header of their block, which is not imported yet
+	sidecar := cltypes.NewBlobSidecar()
+	sidecar.SignedBlockHeader = block0xc2.SignedBeaconBlockHeader()
+	require.NoError(t, store.OnBlobSidecar(sidecar, false))
+	require.NoError(t, store.OnBlobSidecar(sidecar, false))
+
+	tampered := *sidecar.SignedBlockHeader
+	tampered.Signature[0] ^= 1
+	sidecar.SignedBlockHeader = &tampered
+	require.ErrorContains(t, store.OnBlobSidecar(sidecar, false), "signature")
+
+	header := *block0xc2.SignedBeaconBlockHeader().Header
+	header.ProposerIndex++
+	sidecar.SignedBlockHeader = &cltypes.SignedBeaconBlockHeader{Header: &header}
+	require.ErrorContains(t, store.OnBlobSidecar(sidecar, true), "expected proposer")
+
+	header = *block0xc2.SignedBeaconBlockHeader().Header
+	header.ParentRoot = libcommon.HexToHash("0x1")
+	require.ErrorContains(t, store.OnBlobSidecar(sidecar, true), "unknown")
+
+	header = *block0x3a.SignedBeaconBlockHeader().Header
+	header.ParentRoot, err = block0x3a.Block.HashSSZ()
+	require.NoError(t, err)
+	require.ErrorContains(t, store.OnBlobSidecar(sidecar, true), "parent slot")
+
+	header = *block0xc2.SignedBeaconBlockHeader().Header
+	header.Slot = 4
+	require.ErrorContains(t, store.OnBlobSidecar(sidecar, true), "future")
+}
+
+// fork choice processes attestations of the current slot later, so the signature of gossiped ones is checked upfront
+func TestOnSubnetAttestationSignature(t *testing.T) {
+	block0x3a, block0xc2, block0xd4 := cltypes.NewSignedBeaconBlock(&clparams.MainnetBeaconConfig), cltypes.NewSignedBeaconBlock(&clparams.MainnetBeaconConfig), cltypes.NewSignedBeaconBlock(&clparams.MainnetBeaconConfig)
+	require.NoError(t, utils.DecodeSSZSnappy(block0x3a, block3aEncoded, int(clparams.AltairVersion)))
+	require.NoError(t, utils.DecodeSSZSnappy(block0xc2, blockc2Encoded, int(clparams.AltairVersion)))
+	require.NoError(t, utils.DecodeSSZSnappy(block0xd4, blockd4Encoded, int(clparams.AltairVersion)))
+	testAttestation := &solid.Attestation{}
+	require.NoError(t, utils.DecodeSSZSnappy(testAttestation, attestationEncoded, int(clparams.AltairVersion)))
+	anchorState := state.New(&clparams.MainnetBeaconConfig)
+	require.NoError(t, utils.DecodeSSZSnappy(anchorState, anchorStateEncoded, int(clparams.AltairVersion)))
+	pool := pool.NewOperationsPool(&clparams.MainnetBeaconConfig)
+	store, err := forkchoice.NewForkChoiceStore(context.Background(), anchorState, nil, nil, pool, fork_graph.NewForkGraphDisk(anchorState, afero.NewMemMapFs()), beaconevents.NewEmitters(), nil)
+	require.NoError(t, err)
+	store.OnTick(12)
+	require.NoError(t, store.OnBlock(block0x3a, false, true))
+	store.OnTick(36)
+	require.NoError(t, store.OnBlock(block0xc2, false, true))
+	require.NoError(t, store.OnBlock(block0xd4, false, true))
+	// an attestation of the current slot, whose signature is not the one of its single attester
+	testAttestation.SetAggregationBits([]byte{0x01, 0x01})
+	data := testAttestation.AttestantionData()
+	data.SetSlot(3)
+	subnet, err := store.ComputeSubnetForAttestation(data.Slot(), data.ValidatorIndex())
+	require.NoError(t, err)
+	require.ErrorContains(t, store.OnSubnetAttestation(testAttestation, subnet), "invalid aggregate signature")
+	require.False(t, pool.AttestationsPool.Has(testAttestation.Signature()))
 }
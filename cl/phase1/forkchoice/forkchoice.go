@@ -4,9 +4,11 @@ import (
 	"context"
 	"sync"
 
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconevents"
 	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
 	"github.com/ledgerwatch/erigon/cl/freezer"
+	"github.com/ledgerwatch/erigon/cl/light_client"
 	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
 	state2 "github.com/ledgerwatch/erigon/cl/phase1/core/state"
 	"github.com/ledgerwatch/erigon/cl/phase1/execution_client"
@@ -24,6 +26,8 @@ type checkpointComparable string
 const (
 	checkpointsPerCache = 1024
 	allowedCachedStates = 8
+
+	verifiedBlobHeadersPerCache = 64
 )
 
 type preverifiedAppendListsSizes struct {
@@ -57,6 +61,8 @@ type ForkChoiceStore struct {
 	eth2Roots *lru.Cache[libcommon.Hash, libcommon.Hash] // ETH2 root -> ETH1 hash
 	// preverifid sizes
 	preverifiedSizes *lru.Cache[libcommon.Hash, preverifiedAppendListsSizes]
+	// blob sidecar headers whose proposer was checked, with their signature
+	verifiedBlobHeaders *lru.Cache[libcommon.Hash, libcommon.Bytes96]
 
 	mu sync.Mutex
 	// EL
@@ -66,6 +72,12 @@ type ForkChoiceStore struct {
 	// operations pool
 	operationsPool pool.OperationsPool
 	beaconCfg      *clparams.BeaconChainConfig
+	// events
+	emitters          *beaconevents.Emitters
+	publishedHeadHash libcommon.Hash
+	publishedHeadSlot uint64
+	// light client data, produced as blocks are imported
+	lightClientStore *light_client.Store
 }
 
 type LatestMessage struct {
@@ -79,7 +91,7 @@ type childrens struct {
 }
 
 // NewForkChoiceStore initialize a new store from the given anchor state, either genesis or checkpoint sync state.
-func NewForkChoiceStore(ctx context.Context, anchorState *state2.CachingBeaconState, engine execution_client.ExecutionEngine, recorder freezer.Freezer, operationsPool pool.OperationsPool, forkGraph fork_graph.ForkGraph) (*ForkChoiceStore, error) {
+func NewForkChoiceStore(ctx context.Context, anchorState *state2.CachingBeaconState, engine execution_client.ExecutionEngine, recorder freezer.Freezer, operationsPool pool.OperationsPool, forkGraph fork_graph.ForkGraph, emitters *beaconevents.Emitters, lightClientStore *light_client.Store) (*ForkChoiceStore, error) {
 	anchorRoot, err := anchorState.BlockRoot()
 	if err != nil {
 		return nil, err
@@ -106,6 +118,10 @@ func NewForkChoiceStore(ctx context.Context, anchorState *state2.CachingBeaconSt
 	if err != nil {
 		return nil, err
 	}
+	verifiedBlobHeaders, err := lru.New[libcommon.Hash, libcommon.Bytes96](verifiedBlobHeadersPerCache)
+	if err != nil {
+		return nil, err
+	}
 	preverifiedSizes.Add(anchorRoot, preverifiedAppendListsSizes{
 		validatorLength:           uint64(anchorState.ValidatorLength()),
 		historicalRootsLength:     anchorState.HistoricalRootsLength(),
@@ -133,6 +149,11 @@ func NewForkChoiceStore(ctx context.Context, anchorState *state2.CachingBeaconSt
 		beaconCfg:                     anchorState.BeaconConfig(),
 		childrens:                     make(map[libcommon.Hash]childrens),
 		preverifiedSizes:              preverifiedSizes,
+		verifiedBlobHeaders:           verifiedBlobHeaders,
+		emitters:                      emitters,
+		publishedHeadHash:             anchorRoot,
+		publishedHeadSlot:             anchorState.Slot(),
+		lightClientStore:              lightClientStore,
 	}, nil
 }
 
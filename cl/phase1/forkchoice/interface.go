@@ -17,6 +17,7 @@ type ForkChoiceStorage interface {
 type ForkChoiceStorageReader interface {
 	Ancestor(root common.Hash, slot uint64) common.Hash
 	AnchorSlot() uint64
+	ComputeSubnetForAttestation(slot, committeeIndex uint64) (uint64, error)
 	Engine() execution_client.ExecutionEngine
 	FinalizedCheckpoint() solid.Checkpoint
 	FinalizedSlot() uint64
@@ -38,5 +39,7 @@ type ForkChoiceStorageWriter interface {
 	OnAttestation(attestation *solid.Attestation, fromBlock bool) error
 	OnAttesterSlashing(attesterSlashing *cltypes.AttesterSlashing, test bool) error
 	OnBlock(block *cltypes.SignedBeaconBlock, newPayload bool, fullValidation bool) error
+	OnSignedContributionAndProof(signedContribution *cltypes.SignedContributionAndProof, test bool) error
+	OnSyncCommitteeMessage(msg *cltypes.SyncCommitteeMessage, subnet uint64, test bool) error
 	OnTick(time uint64)
 }
@@ -0,0 +1,220 @@
+package forkchoice
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/Giulio2002/bls"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/merkle_tree"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
+	"github.com/ledgerwatch/erigon/cl/utils"
+)
+
+// These are the networking constants of the same name, which have the same value on every network.
+const (
+	attestationSubnetCount          = 64
+	attestationPropagationSlotRange = 32
+)
+
+// OnAggregateAndProof is a non-official handler for aggregates received via gossip. It verifies the aggregator selection
+// and signature and the aggregate signature, then feeds the aggregate to fork choice and pushes it in the pool.
+func (f *ForkChoiceStore) OnAggregateAndProof(signedAggregate *cltypes.SignedAggregateAndProof, test bool) error {
+	aggregateAndProof := signedAggregate.Message
+	aggregate := aggregateAndProof.Aggregate
+	if f.operationsPool.AttestationsPool.Has(aggregate.Signature()) {
+		return nil
+	}
+	data := aggregate.AttestantionData()
+	if err := f.validateAttestationPropagationSlot(data.Slot()); err != nil {
+		return err
+	}
+	if countAggregationBits(aggregate.AggregationBits()) == 0 {
+		return errors.New("aggregate has no participants")
+	}
+
+	f.mu.Lock()
+	headHash, _, err := f.getHead()
+	if err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	s, err := f.forkGraph.GetState(headHash, false)
+	if err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	committee, err := s.GetBeaconCommitee(data.Slot(), data.ValidatorIndex())
+	if err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	inCommittee := false
+	for _, index := range committee {
+		inCommittee = inCommittee || index == aggregateAndProof.AggregatorIndex
+	}
+	if !inCommittee {
+		f.mu.Unlock()
+		return fmt.Errorf("aggregator %d is not part of the committee", aggregateAndProof.AggregatorIndex)
+	}
+	pk, err := s.ValidatorPublicKey(int(aggregateAndProof.AggregatorIndex))
+	if err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	epoch := f.computeEpochAtSlot(data.Slot())
+	selectionDomain, err := s.GetDomain(f.beaconCfg.DomainSelectionProof, epoch)
+	if err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	aggregateDomain, err := s.GetDomain(f.beaconCfg.DomainAggregateAndProof, epoch)
+	if err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	f.mu.Unlock()
+
+	modulo := utils.Max64(1, uint64(len(committee))/f.beaconCfg.TargetAggregatorsPerCommittee)
+	if !isSelectedBySignature(aggregateAndProof.SelectionProof[:], modulo) {
+		return fmt.Errorf("validator %d is not an aggregator", aggregateAndProof.AggregatorIndex)
+	}
+	if !test {
+		slotRoot := merkle_tree.Uint64Root(data.Slot())
+		selectionRoot := utils.Sha256(slotRoot[:], selectionDomain)
+		valid, err := bls.Verify(aggregateAndProof.SelectionProof[:], selectionRoot[:], pk[:])
+		if err != nil {
+			return err
+		}
+		if !valid {
+			return errors.New("invalid selection proof")
+		}
+		messageRoot, err := aggregateAndProof.HashSSZ()
+		if err != nil {
+			return err
+		}
+		signingRoot := utils.Sha256(messageRoot[:], aggregateDomain)
+		valid, err = bls.Verify(signedAggregate.Signature[:], signingRoot[:], pk[:])
+		if err != nil {
+			return err
+		}
+		if !valid {
+			return errors.New("invalid aggregate and proof signature")
+		}
+		if err := f.verifyAttestationSignature(aggregate); err != nil {
+			return err
+		}
+	}
+	if err := f.OnAttestation(aggregate, false); err != nil {
+		return err
+	}
+	f.operationsPool.AttestationsPool.Insert(aggregate.Signature(), aggregate)
+	return nil
+}
+
+// OnSubnetAttestation is a non-official handler for unaggregated attestations received on an attestation subnet. It checks
+// the attestation belongs to the subnet and its signature, then feeds it to fork choice and pushes it in the pool.
+func (f *ForkChoiceStore) OnSubnetAttestation(attestation *solid.Attestation, subnet uint64) error {
+	if f.operationsPool.AttestationsPool.Has(attestation.Signature()) {
+		return nil
+	}
+	data := attestation.AttestantionData()
+	if err := f.validateAttestationPropagationSlot(data.Slot()); err != nil {
+		return err
+	}
+	if countAggregationBits(attestation.AggregationBits()) != 1 {
+		return errors.New("attestation on subnet must have exactly one participant")
+	}
+	expectedSubnet, err := f.ComputeSubnetForAttestation(data.Slot(), data.ValidatorIndex())
+	if err != nil {
+		return err
+	}
+	if expectedSubnet != subnet {
+		return fmt.Errorf("attestation of subnet %d received on subnet %d", expectedSubnet, subnet)
+	}
+	if err := f.verifyAttestationSignature(attestation); err != nil {
+		return err
+	}
+	if err := f.OnAttestation(attestation, false); err != nil {
+		return err
+	}
+	f.operationsPool.AttestationsPool.Insert(attestation.Signature(), attestation)
+	return nil
+}
+
+// verifyAttestationSignature checks the signature of a gossiped attestation against its target state. OnAttestation does
+// not do it for attestations of the current slot, which it processes later, nor for attesting indicies it has cached.
+func (f *ForkChoiceStore) verifyAttestationSignature(attestation *solid.Attestation) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data := attestation.AttestantionData()
+	if _, has := f.forkGraph.GetHeader(data.Target().BlockRoot()); !has {
+		return fmt.Errorf("target root is missing")
+	}
+	targetState, err := f.getCheckpointState(data.Target())
+	if err != nil {
+		return err
+	}
+	attestingIndicies, err := targetState.getAttestingIndicies(&data, attestation.AggregationBits())
+	if err != nil {
+		return err
+	}
+	valid, err := targetState.isValidIndexedAttestation(state.GetIndexedAttestation(attestation, attestingIndicies))
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return fmt.Errorf("invalid attestation signature")
+	}
+	return nil
+}
+
+// ComputeSubnetForAttestation returns the attestation subnet of a committee, according to the current head.
+func (f *ForkChoiceStore) ComputeSubnetForAttestation(slot, committeeIndex uint64) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	headHash, _, err := f.getHead()
+	if err != nil {
+		return 0, err
+	}
+	s, err := f.forkGraph.GetState(headHash, false)
+	if err != nil {
+		return 0, err
+	}
+	committeesPerSlot := s.CommitteeCount(f.computeEpochAtSlot(slot))
+	if committeeIndex >= committeesPerSlot {
+		return 0, fmt.Errorf("committee index %d is out of range", committeeIndex)
+	}
+	committeesSinceEpochStart := committeesPerSlot * (slot % f.beaconCfg.SlotsPerEpoch)
+	return (committeesSinceEpochStart + committeeIndex) % attestationSubnetCount, nil
+}
+
+// validateAttestationPropagationSlot checks the attestation slot is within the gossip propagation range.
+func (f *ForkChoiceStore) validateAttestationPropagationSlot(slot uint64) error {
+	currentSlot := f.Slot()
+	if slot > currentSlot || slot+attestationPropagationSlotRange < currentSlot {
+		return fmt.Errorf("attestation slot %d is out of the propagation range", slot)
+	}
+	return nil
+}
+
+// countAggregationBits returns the amount of bits set in an aggregation bitlist, without its length marker.
+func countAggregationBits(aggregationBits []byte) int {
+	count := 0
+	for _, b := range aggregationBits {
+		count += bits.OnesCount8(b)
+	}
+	if count == 0 {
+		return 0
+	}
+	return count - 1
+}
+
+// isSelectedBySignature tells whether a selection proof designates an aggregator, given the selection modulo.
+func isSelectedBySignature(selectionProof []byte, modulo uint64) bool {
+	hash := utils.Sha256(selectionProof)
+	return binary.LittleEndian.Uint64(hash[:8])%modulo == 0
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconevents"
 	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
 	"github.com/ledgerwatch/erigon/cl/phase1/cache"
 	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
@@ -29,6 +30,7 @@ func (f *ForkChoiceStore) OnAttestation(attestation *solid.Attestation, fromBloc
 	target := data.Target()
 	if cachedIndicies, ok := cache.LoadAttestatingIndicies(&data, attestation.AggregationBits()); ok {
 		f.processAttestingIndicies(attestation, cachedIndicies)
+		f.publishAttestation(attestation, fromBlock)
 		return nil
 	}
 	targetState, err := f.getCheckpointState(target)
@@ -61,9 +63,17 @@ func (f *ForkChoiceStore) OnAttestation(attestation *solid.Attestation, fromBloc
 	cache.StoreAttestation(&data, attestation.AggregationBits(), attestationIndicies)
 	// Lastly update latest messages.
 	f.processAttestingIndicies(attestation, attestationIndicies)
+	f.publishAttestation(attestation, fromBlock)
 	return nil
 }
 
+// publishAttestation publishes the attestation event for attestations received outside of blocks.
+func (f *ForkChoiceStore) publishAttestation(attestation *solid.Attestation, fromBlock bool) {
+	if !fromBlock {
+		f.emitters.Publish(beaconevents.TopicAttestation, attestation)
+	}
+}
+
 // scheduleAttestationForLaterProcessing scheudules an attestation for later processing
 func (f *ForkChoiceStore) scheduleAttestationForLaterProcessing(attestation *solid.Attestation, fromBlock bool) {
 	go func() {
@@ -0,0 +1,90 @@
+package forkchoice
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Giulio2002/bls"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/fork"
+	"github.com/ledgerwatch/erigon/cl/transition"
+)
+
+// OnBlobSidecar is a non-official handler for blob sidecars received via gossip. It checks the block header of the
+// sidecar: its slot, its parent and the signature of the expected proposer. The inclusion and KZG proofs are left to
+// the caller.
+func (f *ForkChoiceStore) OnBlobSidecar(sidecar *cltypes.BlobSidecar, test bool) error {
+	signedHeader := sidecar.SignedBlockHeader
+	header := signedHeader.Header
+	headerRoot, err := header.HashSSZ()
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if header.Slot > f.Slot() {
+		return fmt.Errorf("blob sidecar slot %d is in the future", header.Slot)
+	}
+	finalizedSlot := f.computeStartSlotAtEpoch(f.finalizedCheckpoint.Epoch())
+	if header.Slot <= finalizedSlot {
+		return fmt.Errorf("blob sidecar slot %d is not after the finalized slot %d", header.Slot, finalizedSlot)
+	}
+	parent, has := f.forkGraph.GetHeader(header.ParentRoot)
+	if !has {
+		return fmt.Errorf("blob sidecar parent %x is unknown", header.ParentRoot)
+	}
+	if header.Slot <= parent.Slot {
+		return fmt.Errorf("blob sidecar slot %d is not after its parent slot %d", header.Slot, parent.Slot)
+	}
+	if f.Ancestor(header.ParentRoot, finalizedSlot) != f.finalizedCheckpoint.BlockRoot() {
+		return errors.New("blob sidecar parent does not descend from the finalized checkpoint")
+	}
+	// the sidecars of a block share its header, so the proposer is checked once per block
+	if signature, ok := f.verifiedBlobHeaders.Get(headerRoot); ok && signature == signedHeader.Signature {
+		return nil
+	}
+
+	s, err := f.forkGraph.GetState(header.ParentRoot, true)
+	if err != nil {
+		return err
+	}
+	if s == nil {
+		return fmt.Errorf("blob sidecar parent state %x not found", header.ParentRoot)
+	}
+	if s.Slot() < header.Slot {
+		if err := transition.DefaultMachine.ProcessSlots(s, header.Slot); err != nil {
+			return err
+		}
+	}
+	proposerIndex, err := s.GetBeaconProposerIndex()
+	if err != nil {
+		return err
+	}
+	if header.ProposerIndex != proposerIndex {
+		return fmt.Errorf("blob sidecar proposer %d is not the expected proposer %d", header.ProposerIndex, proposerIndex)
+	}
+	if !test {
+		pk, err := s.ValidatorPublicKey(int(proposerIndex))
+		if err != nil {
+			return err
+		}
+		domain, err := s.GetDomain(f.beaconCfg.DomainBeaconProposer, f.computeEpochAtSlot(header.Slot))
+		if err != nil {
+			return err
+		}
+		signingRoot, err := fork.ComputeSigningRoot(header, domain)
+		if err != nil {
+			return err
+		}
+		valid, err := bls.Verify(signedHeader.Signature[:], signingRoot[:], pk[:])
+		if err != nil {
+			return err
+		}
+		if !valid {
+			return errors.New("invalid blob sidecar proposer signature")
+		}
+	}
+	f.verifiedBlobHeaders.Add(headerRoot, signedHeader.Signature)
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/log/v3"
 
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconevents"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
 	"github.com/ledgerwatch/erigon/cl/freezer"
 	"github.com/ledgerwatch/erigon/cl/phase1/forkchoice/fork_graph"
@@ -80,6 +81,12 @@ func (f *ForkChoiceStore) OnBlock(block *cltypes.SignedBeaconBlock, newPayload,
 		historicalRootsLength:     lastProcessedState.HistoricalRootsLength(),
 		historicalSummariesLength: lastProcessedState.HistoricalSummariesLength(),
 	})
+	if f.lightClientStore != nil {
+		// the light client data only needs the post-state, do it before the justification simulation below alters it.
+		if err := f.lightClientStore.OnBlock(block, blockRoot, lastProcessedState); err != nil {
+			log.Warn("could not produce light client data", "slot", block.Block.Slot, "err", err)
+		}
+	}
 	// Update checkpoints
 	f.updateCheckpoints(lastProcessedState.CurrentJustifiedCheckpoint().Copy(), lastProcessedState.FinalizedCheckpoint().Copy())
 	// First thing save previous values of the checkpoints (avoid memory copy of all states and ensure easy revert)
@@ -106,6 +113,10 @@ func (f *ForkChoiceStore) OnBlock(block *cltypes.SignedBeaconBlock, newPayload,
 	if blockEpoch < currentEpoch {
 		f.updateCheckpoints(lastProcessedState.CurrentJustifiedCheckpoint().Copy(), lastProcessedState.FinalizedCheckpoint().Copy())
 	}
+	f.emitters.Publish(beaconevents.TopicBlock, &beaconevents.BlockData{
+		Slot:  block.Block.Slot,
+		Block: blockRoot,
+	})
 	log.Debug("OnBlock", "elapsed", time.Since(start))
 	return nil
 }
@@ -0,0 +1,205 @@
+package forkchoice
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Giulio2002/bls"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconevents"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
+	"github.com/ledgerwatch/erigon/cl/utils"
+)
+
+// OnSyncCommitteeMessage is a non-official handler for sync committee messages received on a sync committee subnet.
+// It checks the validator belongs to the subnet and pushes the message in the pool.
+func (f *ForkChoiceStore) OnSyncCommitteeMessage(msg *cltypes.SyncCommitteeMessage, subnet uint64, test bool) error {
+	if f.operationsPool.SyncCommitteeMessagesPool.Has(msg.Signature) {
+		return nil
+	}
+	if msg.Slot != f.Slot() {
+		return fmt.Errorf("sync committee message for slot %d is not for the current slot", msg.Slot)
+	}
+
+	f.mu.Lock()
+	headHash, _, err := f.getHead()
+	if err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	s, err := f.forkGraph.GetState(headHash, false)
+	if err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	committee, err := f.syncCommitteeAtSlot(s, msg.Slot)
+	if err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	pk, err := s.ValidatorPublicKey(int(msg.ValidatorIndex))
+	if err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	domain, err := s.GetDomain(f.beaconCfg.DomainSyncCommittee, f.computeEpochAtSlot(msg.Slot))
+	if err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	f.mu.Unlock()
+
+	subcommitteeSize := f.beaconCfg.SyncCommitteeSize / f.beaconCfg.SyncCommitteeSubnetCount
+	inSubnet := false
+	for i, member := range committee {
+		inSubnet = inSubnet || (member == pk && uint64(i)/subcommitteeSize == subnet)
+	}
+	if !inSubnet {
+		return fmt.Errorf("validator %d is not part of sync committee subnet %d", msg.ValidatorIndex, subnet)
+	}
+	if !test {
+		signingRoot := utils.Sha256(msg.BeaconBlockRoot[:], domain)
+		valid, err := bls.Verify(msg.Signature[:], signingRoot[:], pk[:])
+		if err != nil {
+			return err
+		}
+		if !valid {
+			return errors.New("invalid sync committee message signature")
+		}
+	}
+	f.operationsPool.SyncCommitteeMessagesPool.Insert(msg.Signature, msg)
+	return nil
+}
+
+// OnSignedContributionAndProof is a non-official handler for sync committee contributions. It verifies the aggregator
+// selection and the signatures, then pushes the contribution in the pool.
+func (f *ForkChoiceStore) OnSignedContributionAndProof(signedContribution *cltypes.SignedContributionAndProof, test bool) error {
+	contributionAndProof := signedContribution.Message
+	contribution := contributionAndProof.Contribution
+	if f.operationsPool.SyncContributionsPool.Has(contribution.Signature) {
+		return nil
+	}
+	if contribution.Slot != f.Slot() {
+		return fmt.Errorf("contribution for slot %d is not for the current slot", contribution.Slot)
+	}
+	if contribution.SubcommitteeIndex >= f.beaconCfg.SyncCommitteeSubnetCount {
+		return fmt.Errorf("subcommittee index %d is out of range", contribution.SubcommitteeIndex)
+	}
+	if contribution.ParticipantsCount() == 0 {
+		return errors.New("contribution has no participants")
+	}
+	subcommitteeSize := f.beaconCfg.SyncCommitteeSize / f.beaconCfg.SyncCommitteeSubnetCount
+	modulo := utils.Max64(1, subcommitteeSize/f.beaconCfg.TargetAggregatorsPerSyncSubcommittee)
+	if !isSelectedBySignature(contributionAndProof.SelectionProof[:], modulo) {
+		return fmt.Errorf("validator %d is not a sync committee aggregator", contributionAndProof.AggregatorIndex)
+	}
+
+	f.mu.Lock()
+	headHash, _, err := f.getHead()
+	if err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	s, err := f.forkGraph.GetState(headHash, false)
+	if err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	committee, err := f.syncCommitteeAtSlot(s, contribution.Slot)
+	if err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	pk, err := s.ValidatorPublicKey(int(contributionAndProof.AggregatorIndex))
+	if err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	epoch := f.computeEpochAtSlot(contribution.Slot)
+	selectionDomain, err := s.GetDomain(f.beaconCfg.DomainSyncCommitteeSelectionProof, epoch)
+	if err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	contributionDomain, err := s.GetDomain(f.beaconCfg.DomainContributionAndProof, epoch)
+	if err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	syncCommitteeDomain, err := s.GetDomain(f.beaconCfg.DomainSyncCommittee, epoch)
+	if err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	f.mu.Unlock()
+
+	subcommittee := committee[contribution.SubcommitteeIndex*subcommitteeSize : (contribution.SubcommitteeIndex+1)*subcommitteeSize]
+	inSubcommittee := false
+	participants := make([][]byte, 0, contribution.ParticipantsCount())
+	for i, member := range subcommittee {
+		inSubcommittee = inSubcommittee || member == pk
+		if contribution.AggregationBits[i/8]&(1<<(i%8)) != 0 {
+			participants = append(participants, libcommon.CopyBytes(member[:]))
+		}
+	}
+	if !inSubcommittee {
+		return fmt.Errorf("aggregator %d is not part of sync subcommittee %d", contributionAndProof.AggregatorIndex, contribution.SubcommitteeIndex)
+	}
+	if !test {
+		selectionData := &cltypes.SyncAggregatorSelectionData{Slot: contribution.Slot, SubcommitteeIndex: contribution.SubcommitteeIndex}
+		selectionDataRoot, err := selectionData.HashSSZ()
+		if err != nil {
+			return err
+		}
+		selectionRoot := utils.Sha256(selectionDataRoot[:], selectionDomain)
+		valid, err := bls.Verify(contributionAndProof.SelectionProof[:], selectionRoot[:], pk[:])
+		if err != nil {
+			return err
+		}
+		if !valid {
+			return errors.New("invalid sync committee selection proof")
+		}
+		messageRoot, err := contributionAndProof.HashSSZ()
+		if err != nil {
+			return err
+		}
+		signingRoot := utils.Sha256(messageRoot[:], contributionDomain)
+		valid, err = bls.Verify(signedContribution.Signature[:], signingRoot[:], pk[:])
+		if err != nil {
+			return err
+		}
+		if !valid {
+			return errors.New("invalid contribution and proof signature")
+		}
+		blockRoot := utils.Sha256(contribution.BeaconBlockRoot[:], syncCommitteeDomain)
+		valid, err = bls.VerifyAggregate(contribution.Signature[:], blockRoot[:], participants)
+		if err != nil {
+			return err
+		}
+		if !valid {
+			return errors.New("invalid contribution signature")
+		}
+	}
+	f.operationsPool.SyncContributionsPool.Insert(contribution.Signature, contribution)
+	f.emitters.Publish(beaconevents.TopicContributionAndProof, signedContribution)
+	return nil
+}
+
+// syncCommitteeAtSlot returns the public keys of the sync committee expected to sign at the given slot.
+func (f *ForkChoiceStore) syncCommitteeAtSlot(s *state.CachingBeaconState, slot uint64) ([]libcommon.Bytes48, error) {
+	if s.Version() < clparams.AltairVersion {
+		return nil, errors.New("no sync committee before altair")
+	}
+	// messages of a slot are signed by the committee of the following slot, which only matters at period boundaries.
+	period := f.computeEpochAtSlot(slot+1) / f.beaconCfg.EpochsPerSyncCommitteePeriod
+	statePeriod := state.Epoch(s) / f.beaconCfg.EpochsPerSyncCommitteePeriod
+	switch period {
+	case statePeriod:
+		return s.CurrentSyncCommittee().GetCommittee(), nil
+	case statePeriod + 1:
+		return s.NextSyncCommittee().GetCommittee(), nil
+	}
+	return nil, fmt.Errorf("no sync committee known for slot %d", slot)
+}
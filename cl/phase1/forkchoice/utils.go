@@ -22,6 +22,7 @@ func (f *ForkChoiceStore) updateCheckpoints(justifiedCheckpoint, finalizedCheckp
 		f.justifiedCheckpoint = justifiedCheckpoint
 	}
 	if finalizedCheckpoint.Epoch() > f.finalizedCheckpoint.Epoch() {
+		f.onFinalizedCheckpoint(finalizedCheckpoint)
 		f.onNewFinalized(finalizedCheckpoint)
 		f.finalizedCheckpoint = finalizedCheckpoint
 
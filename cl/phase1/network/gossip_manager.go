@@ -6,7 +6,12 @@ import (
 	"github.com/ledgerwatch/erigon-lib/common"
 	"sync"
 
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/ledgerwatch/erigon-lib/crypto/kzg"
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconevents"
 	"github.com/ledgerwatch/erigon/cl/freezer"
+	"github.com/ledgerwatch/erigon/cl/persistence/blob_storage"
 	"github.com/ledgerwatch/erigon/cl/phase1/forkchoice"
 	"github.com/ledgerwatch/erigon/cl/sentinel/peers"
 
@@ -14,6 +19,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/types/ssz"
 	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
 	"github.com/ledgerwatch/erigon/cl/utils"
 	"github.com/ledgerwatch/log/v3"
 )
@@ -26,21 +32,42 @@ type GossipManager struct {
 	// configs
 	beaconConfig  *clparams.BeaconChainConfig
 	genesisConfig *clparams.GenesisConfig
+	emitters      *beaconevents.Emitters
+	blobStorage   blob_storage.BlobStorage
 
 	mu        sync.RWMutex
 	subs      map[int]chan *peers.PeeredObject[*cltypes.SignedBeaconBlock]
 	totalSubs int
+
+	seenBlobSidecars *lru.Cache[blobSidecarKey, struct{}]
+}
+
+// blobSidecarKey identifies the blob sidecars seen on gossip
+type blobSidecarKey struct {
+	blockRoot common.Hash
+	index     uint64
 }
 
+// seenBlobSidecarsSize covers the sidecars of the blocks within the gossip slot range
+const seenBlobSidecarsSize = 1024
+
 func NewGossipReceiver(s sentinel.SentinelClient, forkChoice *forkchoice.ForkChoiceStore,
-	beaconConfig *clparams.BeaconChainConfig, genesisConfig *clparams.GenesisConfig, recorder freezer.Freezer) *GossipManager {
+	beaconConfig *clparams.BeaconChainConfig, genesisConfig *clparams.GenesisConfig, recorder freezer.Freezer, emitters *beaconevents.Emitters, blobStorage blob_storage.BlobStorage) *GossipManager {
+	seenBlobSidecars, err := lru.New[blobSidecarKey, struct{}](seenBlobSidecarsSize)
+	if err != nil {
+		panic(err)
+	}
 	return &GossipManager{
 		sentinel:      s,
 		forkChoice:    forkChoice,
 		beaconConfig:  beaconConfig,
 		genesisConfig: genesisConfig,
 		recorder:      recorder,
+		emitters:      emitters,
+		blobStorage:   blobStorage,
 		subs:          make(map[int]chan *peers.PeeredObject[*cltypes.SignedBeaconBlock]),
+
+		seenBlobSidecars: seenBlobSidecars,
 	}
 }
 
@@ -142,6 +169,62 @@ func (g *GossipManager) onRecv(ctx context.Context, data *sentinel.GossipData, l
 		}
 		g.mu.RUnlock()
 
+	case sentinel.GossipType_BlobSidecarType:
+		sidecar := cltypes.NewBlobSidecar()
+		if err := sidecar.DecodeSSZ(common.CopyBytes(data.Data), int(version)); err != nil {
+			g.sentinel.BanPeer(ctx, data.Peer)
+			l["at"] = "decoding blob sidecar"
+			return err
+		}
+		header := sidecar.SignedBlockHeader.Header
+		l["slot"] = header.Slot
+		if sidecar.Index >= cltypes.MaxBlobsPerBlock {
+			g.sentinel.BanPeer(ctx, data.Peer)
+			return fmt.Errorf("blob sidecar index %d out of range", sidecar.Index)
+		}
+		if data.BlobIndex != nil && uint64(*data.BlobIndex) != sidecar.Index {
+			g.sentinel.BanPeer(ctx, data.Peer)
+			return fmt.Errorf("blob sidecar %d received on the subnet of blob %d", sidecar.Index, *data.BlobIndex)
+		}
+		currentSlotByTime := utils.GetCurrentSlot(g.genesisConfig.GenesisTime, g.beaconConfig.SecondsPerSlot)
+		maxGossipSlotThreshold := uint64(4)
+		// Skip if slot is too far behind.
+		if header.Slot+maxGossipSlotThreshold < currentSlotByTime {
+			return nil
+		}
+		blockRoot, err := header.HashSSZ()
+		if err != nil {
+			return err
+		}
+		seenKey := blobSidecarKey{blockRoot: blockRoot, index: sidecar.Index}
+		if g.seenBlobSidecars.Contains(seenKey) {
+			return nil
+		}
+		if err := g.forkChoice.OnBlobSidecar(sidecar, false); err != nil {
+			l["at"] = "verify blob sidecar header"
+			return err
+		}
+		if !sidecar.VerifyCommitmentInclusionProof() {
+			g.sentinel.BanPeer(ctx, data.Peer)
+			return fmt.Errorf("blob sidecar %d at slot %d has an invalid commitment inclusion proof", sidecar.Index, header.Slot)
+		}
+		if err := kzg.Ctx().VerifyBlobKZGProof(gokzg4844.Blob(sidecar.Blob), gokzg4844.KZGCommitment(sidecar.KzgCommitment), gokzg4844.KZGProof(sidecar.KzgProof)); err != nil {
+			g.sentinel.BanPeer(ctx, data.Peer)
+			l["at"] = "verifying blob kzg proof"
+			return err
+		}
+		// only the first valid sidecar of a block and index is stored and forwarded
+		if seen, _ := g.seenBlobSidecars.ContainsOrAdd(seenKey, struct{}{}); seen {
+			return nil
+		}
+		if err := g.blobStorage.WriteBlobSidecars(ctx, blockRoot, []*cltypes.BlobSidecar{sidecar}); err != nil {
+			l["at"] = "storing blob sidecar"
+			return err
+		}
+		if _, err := g.sentinel.PublishGossip(ctx, data); err != nil {
+			log.Debug("failed publish gossip", "err", err)
+		}
+		g.publishBlobSidecarEvent(blockRoot, sidecar)
 	case sentinel.GossipType_VoluntaryExitGossipType:
 		if err := operationsContract[*cltypes.SignedVoluntaryExit](ctx, g, l, data, int(version), "voluntary exit", g.forkChoice.OnVoluntaryExit); err != nil {
 			return err
@@ -158,6 +241,34 @@ func (g *GossipManager) onRecv(ctx context.Context, data *sentinel.GossipData, l
 		if err := operationsContract[*cltypes.SignedBLSToExecutionChange](ctx, g, l, data, int(version), "bls to execution change", g.forkChoice.OnBlsToExecutionChange); err != nil {
 			return err
 		}
+	case sentinel.GossipType_AggregateAndProofGossipType:
+		if err := operationsContract[*cltypes.SignedAggregateAndProof](ctx, g, l, data, int(version), "aggregate and proof", g.forkChoice.OnAggregateAndProof); err != nil {
+			return err
+		}
+	case sentinel.GossipType_AttestationGossipType:
+		if data.SubnetId == nil {
+			return fmt.Errorf("attestation received without subnet")
+		}
+		subnet := *data.SubnetId
+		if err := operationsContract[*solid.Attestation](ctx, g, l, data, int(version), "attestation", func(attestation *solid.Attestation, _ bool) error {
+			return g.forkChoice.OnSubnetAttestation(attestation, subnet)
+		}); err != nil {
+			return err
+		}
+	case sentinel.GossipType_SyncCommitteeGossipType:
+		if data.SubnetId == nil {
+			return fmt.Errorf("sync committee message received without subnet")
+		}
+		subnet := *data.SubnetId
+		if err := operationsContract[*cltypes.SyncCommitteeMessage](ctx, g, l, data, int(version), "sync committee message", func(msg *cltypes.SyncCommitteeMessage, test bool) error {
+			return g.forkChoice.OnSyncCommitteeMessage(msg, subnet, test)
+		}); err != nil {
+			return err
+		}
+	case sentinel.GossipType_ContributionAndProofGossipType:
+		if err := operationsContract[*cltypes.SignedContributionAndProof](ctx, g, l, data, int(version), "contribution and proof", g.forkChoice.OnSignedContributionAndProof); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -185,3 +296,109 @@ func (g *GossipManager) Start(ctx context.Context) {
 		}
 	}
 }
+
+// PublishBlock gossips a locally produced block and hands it to the block subscribers, so that it is imported
+// and stored the same way as a block received from the network.
+func (g *GossipManager) PublishBlock(ctx context.Context, block *cltypes.SignedBeaconBlock) error {
+	encoded, err := block.EncodeSSZ(nil)
+	if err != nil {
+		return err
+	}
+	if _, err := g.sentinel.PublishGossip(ctx, &sentinel.GossipData{
+		Data: encoded,
+		Type: sentinel.GossipType_BeaconBlockGossipType,
+	}); err != nil {
+		log.Debug("failed publish gossip", "err", err)
+	}
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	for _, v := range g.subs {
+		select {
+		case v <- &peers.PeeredObject[*cltypes.SignedBeaconBlock]{Data: block}:
+		default:
+		}
+	}
+	return nil
+}
+
+// PublishBlobSidecar gossips a blob sidecar of a locally produced block. The sidecar is expected to be already stored.
+func (g *GossipManager) PublishBlobSidecar(ctx context.Context, blockRoot common.Hash, sidecar *cltypes.BlobSidecar) error {
+	encoded, err := sidecar.EncodeSSZ(nil)
+	if err != nil {
+		return err
+	}
+	blobIndex := uint32(sidecar.Index)
+	if _, err := g.sentinel.PublishGossip(ctx, &sentinel.GossipData{
+		Data:      encoded,
+		Type:      sentinel.GossipType_BlobSidecarType,
+		BlobIndex: &blobIndex,
+	}); err != nil {
+		log.Debug("failed publish gossip", "err", err)
+	}
+	g.publishBlobSidecarEvent(blockRoot, sidecar)
+	return nil
+}
+
+func (g *GossipManager) publishBlobSidecarEvent(blockRoot common.Hash, sidecar *cltypes.BlobSidecar) {
+	g.emitters.Publish(beaconevents.TopicBlobSidecar, &beaconevents.BlobSidecarData{
+		BlockRoot:     blockRoot,
+		Index:         sidecar.Index,
+		Slot:          sidecar.SignedBlockHeader.Header.Slot,
+		KzgCommitment: sidecar.KzgCommitment,
+		VersionedHash: common.Hash(kzg.KZGToVersionedHash(gokzg4844.KZGCommitment(sidecar.KzgCommitment))),
+	})
+}
+
+// PublishAggregateAndProof gossips a locally produced aggregate.
+func (g *GossipManager) PublishAggregateAndProof(ctx context.Context, aggregate *cltypes.SignedAggregateAndProof) error {
+	encoded, err := aggregate.EncodeSSZ(nil)
+	if err != nil {
+		return err
+	}
+	_, err = g.sentinel.PublishGossip(ctx, &sentinel.GossipData{
+		Data: encoded,
+		Type: sentinel.GossipType_AggregateAndProofGossipType,
+	})
+	return err
+}
+
+// PublishAttestation gossips a locally produced attestation on its attestation subnet.
+func (g *GossipManager) PublishAttestation(ctx context.Context, attestation *solid.Attestation, subnet uint64) error {
+	encoded, err := attestation.EncodeSSZ(nil)
+	if err != nil {
+		return err
+	}
+	_, err = g.sentinel.PublishGossip(ctx, &sentinel.GossipData{
+		Data:     encoded,
+		Type:     sentinel.GossipType_AttestationGossipType,
+		SubnetId: &subnet,
+	})
+	return err
+}
+
+// PublishSyncCommitteeMessage gossips a locally produced sync committee message on a sync committee subnet.
+func (g *GossipManager) PublishSyncCommitteeMessage(ctx context.Context, msg *cltypes.SyncCommitteeMessage, subnet uint64) error {
+	encoded, err := msg.EncodeSSZ(nil)
+	if err != nil {
+		return err
+	}
+	_, err = g.sentinel.PublishGossip(ctx, &sentinel.GossipData{
+		Data:     encoded,
+		Type:     sentinel.GossipType_SyncCommitteeGossipType,
+		SubnetId: &subnet,
+	})
+	return err
+}
+
+// PublishContributionAndProof gossips a locally produced sync committee contribution.
+func (g *GossipManager) PublishContributionAndProof(ctx context.Context, contribution *cltypes.SignedContributionAndProof) error {
+	encoded, err := contribution.EncodeSSZ(nil)
+	if err != nil {
+		return err
+	}
+	_, err = g.sentinel.PublishGossip(ctx, &sentinel.GossipData{
+		Data: encoded,
+		Type: sentinel.GossipType_ContributionAndProofGossipType,
+	})
+	return err
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/ledgerwatch/erigon/cl/cltypes"
 	"github.com/ledgerwatch/erigon/cl/persistence"
 	"github.com/ledgerwatch/erigon/cl/persistence/beacon_indicies"
+	"github.com/ledgerwatch/erigon/cl/persistence/blob_storage"
 	"github.com/ledgerwatch/erigon/cl/persistence/db_config"
 	state_accessors "github.com/ledgerwatch/erigon/cl/persistence/state"
 	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
@@ -46,6 +47,7 @@ type Cfg struct {
 	sn              *freezeblocks.CaplinSnapshots
 	antiquary       *antiquary.Antiquary
 	syncedData      *synced_data.SyncedDataManager
+	blobStorage     blob_storage.BlobStorage
 
 	hasDownloaded, backfilling bool
 }
@@ -75,6 +77,7 @@ func ClStagesCfg(
 	dbConfig db_config.DatabaseConfiguration,
 	backfilling bool,
 	syncedData *synced_data.SyncedDataManager,
+	blobStorage blob_storage.BlobStorage,
 ) *Cfg {
 	return &Cfg{
 		rpc:             rpc,
@@ -92,6 +95,7 @@ func ClStagesCfg(
 		sn:              sn,
 		backfilling:     backfilling,
 		syncedData:      syncedData,
+		blobStorage:     blobStorage,
 	}
 }
 
@@ -612,6 +616,9 @@ func ConsensusClStages(ctx context.Context,
 							return err
 						}
 					}
+					if err := cfg.blobStorage.Prune(args.seenSlot); err != nil {
+						return err
+					}
 
 					return tx.Commit()
 				},
@@ -45,6 +45,10 @@ const (
 	AttesterSlashingTopic        TopicName = "attester_slashing"
 	BlsToExecutionChangeTopic    TopicName = "bls_to_execution_change"
 	BlobSidecarTopic             TopicName = "blob_sidecar_%d" // This topic needs an index
+
+	SyncCommitteeContributionAndProofTopic TopicName = "sync_committee_contribution_and_proof"
+	BeaconAttestationTopic                 TopicName = "beacon_attestation_%d" // This topic needs a subnet
+	SyncCommitteeTopic                     TopicName = "sync_committee_%d"     // This topic needs a subnet
 )
 
 type GossipTopic struct {
@@ -82,6 +86,11 @@ var BlsToExecutionChangeSsz = GossipTopic{
 	CodecStr: SSZSnappyCodec,
 }
 
+var SyncCommitteeContributionAndProofSsz = GossipTopic{
+	Name:     SyncCommitteeContributionAndProofTopic,
+	CodecStr: SSZSnappyCodec,
+}
+
 type GossipManager struct {
 	ch            chan *pubsub.Message
 	subscriptions map[string]*GossipSubscription
@@ -109,6 +118,26 @@ func GossipSidecarTopics(maxBlobs uint64) (ret []GossipTopic) {
 	return
 }
 
+// GossipAttestationTopics returns the topics of all the attestation subnets.
+func GossipAttestationTopics(subnets uint64) []GossipTopic {
+	return gossipSubnetTopics(BeaconAttestationTopic, subnets)
+}
+
+// GossipSyncCommitteeTopics returns the topics of all the sync committee subnets.
+func GossipSyncCommitteeTopics(subnets uint64) []GossipTopic {
+	return gossipSubnetTopics(SyncCommitteeTopic, subnets)
+}
+
+func gossipSubnetTopics(name TopicName, subnets uint64) (ret []GossipTopic) {
+	for i := uint64(0); i < subnets; i++ {
+		ret = append(ret, GossipTopic{
+			Name:     TopicName(fmt.Sprintf(string(name), i)),
+			CodecStr: SSZSnappyCodec,
+		})
+	}
+	return
+}
+
 func (s *GossipManager) Recv() <-chan *pubsub.Message {
 	return s.ch
 }
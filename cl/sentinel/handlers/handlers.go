@@ -27,6 +27,7 @@ import (
 
 	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/light_client"
 	"github.com/ledgerwatch/erigon/cl/persistence"
 	"github.com/ledgerwatch/log/v3"
 	"github.com/libp2p/go-libp2p/core/host"
@@ -40,16 +41,19 @@ type RateLimits struct {
 	metadataV1Limit int
 	metadataV2Limit int
 	statusLimit     int
+	// shared by the light client protocols
+	lightClientLimit int
 }
 
 const punishmentPeriod = time.Minute
 
 var defaultRateLimits = RateLimits{
-	pingLimit:       5000,
-	goodbyeLimit:    5000,
-	metadataV1Limit: 5000,
-	metadataV2Limit: 5000,
-	statusLimit:     5000,
+	pingLimit:        5000,
+	goodbyeLimit:     5000,
+	metadataV1Limit:  5000,
+	metadataV2Limit:  5000,
+	statusLimit:      5000,
+	lightClientLimit: 5000,
 }
 
 type ConsensusHandlers struct {
@@ -60,6 +64,7 @@ type ConsensusHandlers struct {
 	genesisConfig      *clparams.GenesisConfig
 	ctx                context.Context
 	beaconDB           persistence.RawBeaconBlockChain
+	lightClientStore   *light_client.Store
 	peerRateLimits     sync.Map
 	punishmentEndTimes sync.Map
 }
@@ -71,11 +76,12 @@ const (
 )
 
 func NewConsensusHandlers(ctx context.Context, db persistence.RawBeaconBlockChain, host host.Host,
-	peers *peers.Pool, beaconConfig *clparams.BeaconChainConfig, genesisConfig *clparams.GenesisConfig, metadata *cltypes.Metadata) *ConsensusHandlers {
+	peers *peers.Pool, beaconConfig *clparams.BeaconChainConfig, genesisConfig *clparams.GenesisConfig, metadata *cltypes.Metadata, lightClientStore *light_client.Store) *ConsensusHandlers {
 	c := &ConsensusHandlers{
 		host:               host,
 		metadata:           metadata,
 		beaconDB:           db,
+		lightClientStore:   lightClientStore,
 		genesisConfig:      genesisConfig,
 		beaconConfig:       beaconConfig,
 		ctx:                ctx,
@@ -91,6 +97,11 @@ func NewConsensusHandlers(ctx context.Context, db persistence.RawBeaconBlockChai
 		communication.MetadataProtocolV2:            c.metadataV2Handler,
 		communication.BeaconBlocksByRangeProtocolV1: c.blocksByRangeHandler,
 		communication.BeaconBlocksByRootProtocolV1:  c.beaconBlocksByRootHandler,
+
+		communication.LightClientBootstrapProtocolV1:        c.lightClientBootstrapHandler,
+		communication.LightClientUpdatesByRangeProtocolV1:   c.lightClientUpdatesByRangeHandler,
+		communication.LightClientFinalityUpdateProtocolV1:   c.lightClientFinalityUpdateHandler,
+		communication.LightClientOptimisticUpdateProtocolV1: c.lightClientOptimisticUpdateHandler,
 	}
 
 	c.handlers = map[protocol.ID]network.StreamHandler{}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"github.com/ledgerwatch/erigon-lib/types/ssz"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/fork"
+	"github.com/ledgerwatch/erigon/cl/sentinel/communication"
+	"github.com/ledgerwatch/erigon/cl/sentinel/communication/ssz_snappy"
+	"github.com/ledgerwatch/erigon/cl/utils"
+	"github.com/libp2p/go-libp2p/core/network"
+)
+
+// writeLightClientObject writes a successful response chunk, prefixed by the fork digest of the object version as
+// context bytes.
+func (c *ConsensusHandlers) writeLightClientObject(s network.Stream, obj ssz.Marshaler, version clparams.StateVersion) error {
+	digest, err := fork.ComputeForkDigestForVersion(utils.Uint32ToBytes4(c.beaconConfig.GetForkVersionByVersion(version)), c.genesisConfig.GenesisValidatorRoot)
+	if err != nil {
+		return err
+	}
+	return ssz_snappy.EncodeAndWrite(s, obj, append([]byte{SuccessfulResponsePrefix}, digest[:]...)...)
+}
+
+func (c *ConsensusHandlers) lightClientBootstrapHandler(s network.Stream) error {
+	peerId := s.Conn().RemotePeer().String()
+	if err := c.checkRateLimit(peerId, "lightClientBootstrap", defaultRateLimits.lightClientLimit); err != nil {
+		ssz_snappy.EncodeAndWrite(s, &emptyString{}, RateLimitedPrefix)
+		defer s.Close()
+		return err
+	}
+	req := &cltypes.Root{}
+	if err := ssz_snappy.DecodeAndReadNoForkDigest(s, req, clparams.Phase0Version); err != nil {
+		return err
+	}
+	if c.lightClientStore == nil {
+		return ssz_snappy.EncodeAndWrite(s, &emptyString{}, ResourceUnavaiablePrefix)
+	}
+	bootstrap, ok := c.lightClientStore.Bootstrap(req.Root)
+	if !ok {
+		return ssz_snappy.EncodeAndWrite(s, &emptyString{}, ResourceUnavaiablePrefix)
+	}
+	return c.writeLightClientObject(s, bootstrap, bootstrap.Version())
+}
+
+func (c *ConsensusHandlers) lightClientUpdatesByRangeHandler(s network.Stream) error {
+	peerId := s.Conn().RemotePeer().String()
+	if err := c.checkRateLimit(peerId, "lightClientUpdatesByRange", defaultRateLimits.lightClientLimit); err != nil {
+		ssz_snappy.EncodeAndWrite(s, &emptyString{}, RateLimitedPrefix)
+		defer s.Close()
+		return err
+	}
+	req := &cltypes.LightClientUpdatesByRangeRequest{}
+	if err := ssz_snappy.DecodeAndReadNoForkDigest(s, req, clparams.Phase0Version); err != nil {
+		return err
+	}
+	if c.lightClientStore == nil {
+		return ssz_snappy.EncodeAndWrite(s, &emptyString{}, ResourceUnavaiablePrefix)
+	}
+	count := req.Count
+	if count > communication.MaximumRequestClientUpdates {
+		count = communication.MaximumRequestClientUpdates
+	}
+	// an empty response is a valid answer when there are no updates in range.
+	for _, update := range c.lightClientStore.Updates(req.StartPeriod, count) {
+		if err := c.writeLightClientObject(s, update, update.Version()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *ConsensusHandlers) lightClientFinalityUpdateHandler(s network.Stream) error {
+	peerId := s.Conn().RemotePeer().String()
+	if err := c.checkRateLimit(peerId, "lightClientFinalityUpdate", defaultRateLimits.lightClientLimit); err != nil {
+		ssz_snappy.EncodeAndWrite(s, &emptyString{}, RateLimitedPrefix)
+		defer s.Close()
+		return err
+	}
+	if c.lightClientStore == nil {
+		return ssz_snappy.EncodeAndWrite(s, &emptyString{}, ResourceUnavaiablePrefix)
+	}
+	update := c.lightClientStore.FinalityUpdate()
+	if update == nil {
+		return ssz_snappy.EncodeAndWrite(s, &emptyString{}, ResourceUnavaiablePrefix)
+	}
+	return c.writeLightClientObject(s, update, update.Version())
+}
+
+func (c *ConsensusHandlers) lightClientOptimisticUpdateHandler(s network.Stream) error {
+	peerId := s.Conn().RemotePeer().String()
+	if err := c.checkRateLimit(peerId, "lightClientOptimisticUpdate", defaultRateLimits.lightClientLimit); err != nil {
+		ssz_snappy.EncodeAndWrite(s, &emptyString{}, RateLimitedPrefix)
+		defer s.Close()
+		return err
+	}
+	if c.lightClientStore == nil {
+		return ssz_snappy.EncodeAndWrite(s, &emptyString{}, ResourceUnavaiablePrefix)
+	}
+	update := c.lightClientStore.OptimisticUpdate()
+	if update == nil {
+		return ssz_snappy.EncodeAndWrite(s, &emptyString{}, ResourceUnavaiablePrefix)
+	}
+	return c.writeLightClientObject(s, update, update.Version())
+}
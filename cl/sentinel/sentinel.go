@@ -158,15 +158,16 @@ func (s *Sentinel) createListener() (*discover.UDPv5, error) {
 		return nil, err
 	}
 
-	// TODO: Set up proper attestation number
+	// we join every attestation and sync committee subnet.
+	syncnets := uint64(1)<<s.cfg.BeaconConfig.SyncCommitteeSubnetCount - 1
 	s.metadataV2 = &cltypes.Metadata{
 		SeqNumber: localNode.Seq(),
-		Attnets:   0,
-		Syncnets:  new(uint64),
+		Attnets:   uint64(1)<<s.cfg.NetworkConfig.AttestationSubnetCount - 1,
+		Syncnets:  &syncnets,
 	}
 
 	// Start stream handlers
-	handlers.NewConsensusHandlers(s.ctx, s.db, s.host, s.peers, s.cfg.BeaconConfig, s.cfg.GenesisConfig, s.metadataV2).Start()
+	handlers.NewConsensusHandlers(s.ctx, s.db, s.host, s.peers, s.cfg.BeaconConfig, s.cfg.GenesisConfig, s.metadataV2, s.cfg.LightClientStore).Start()
 
 	net, err := discover.ListenV5(s.ctx, "any", conn, localNode, discCfg)
 	if err != nil {
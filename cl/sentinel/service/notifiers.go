@@ -16,6 +16,7 @@ type gossipObject struct {
 	t         sentinel.GossipType // determine which gossip message we are notifying of
 	pid       string              // pid is the peer id of the sender
 	blobIndex *uint32             // index of the blob
+	subnetId  *uint64             // subnet of attestations and sync committee messages
 }
 
 type gossipNotifier struct {
@@ -59,6 +60,22 @@ func (g *gossipNotifier) notifyBlob(t sentinel.GossipType, data []byte, pid stri
 	}
 }
 
+func (g *gossipNotifier) notifySubnet(t sentinel.GossipType, data []byte, pid string, subnet int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	subnetId := new(uint64)
+	*subnetId = uint64(subnet)
+	for _, ch := range g.notifiers {
+		ch <- gossipObject{
+			data:     data,
+			t:        t,
+			pid:      pid,
+			subnetId: subnetId,
+		}
+	}
+}
+
 func (g *gossipNotifier) addSubscriber() (chan gossipObject, int, error) {
 	g.mu.Lock()
 	defer g.mu.Unlock()
@@ -48,16 +48,33 @@ func NewSentinelServer(ctx context.Context, sentinel *sentinel.Sentinel, logger
 	}
 }
 
+// blobSidecarTopicPrefix is the part of the blob sidecar topic names preceding the index.
+var blobSidecarTopicPrefix = strings.TrimSuffix(string(sentinel.BlobSidecarTopic), "%d")
+
+// attestationTopicPrefix and syncCommitteeTopicPrefix are the parts of the subnet topic names preceding the subnet.
+var (
+	attestationTopicPrefix   = strings.TrimSuffix(string(sentinel.BeaconAttestationTopic), "%d")
+	syncCommitteeTopicPrefix = strings.TrimSuffix(string(sentinel.SyncCommitteeTopic), "%d")
+)
+
 // extractBlobSideCarIndex takes a topic and extract the blob sidecar
 func extractBlobSideCarIndex(topic string) int {
-	// compute the index prefixless
-	startIndex := strings.Index(topic, string(sentinel.BlobSidecarTopic)) + len(sentinel.BlobSidecarTopic)
-	endIndex := strings.Index(topic[:startIndex], "/")
-	blobIndex, err := strconv.Atoi(topic[startIndex:endIndex])
+	return extractTopicIndex(topic, blobSidecarTopicPrefix)
+}
+
+// extractTopicIndex extracts the index following the prefix in the topic name, topics are of the form
+// /eth2/{fork digest}/{prefix}{index}/{encoding}
+func extractTopicIndex(topic, prefix string) int {
+	startIndex := strings.Index(topic, prefix) + len(prefix)
+	endIndex := len(topic)
+	if i := strings.Index(topic[startIndex:], "/"); i >= 0 {
+		endIndex = startIndex + i
+	}
+	index, err := strconv.Atoi(topic[startIndex:endIndex])
 	if err != nil {
 		panic(fmt.Sprintf("should not be substribed to %s", topic))
 	}
-	return blobIndex
+	return index
 }
 
 //BanPeer(context.Context, *Peer) (*EmptyMessage, error)
@@ -78,7 +95,7 @@ func (s *SentinelServer) PublishGossip(_ context.Context, msg *sentinelrpc.Gossi
 	// Snappify payload before sending it to gossip
 	compressedData := utils.CompressSnappy(msg.Data)
 
-	s.trackPeerStatistics(msg.GetPeer().Pid, false, msg.Type.String(), "unknown", len(compressedData))
+	s.trackPeerStatistics(msg.GetPeer().GetPid(), false, msg.Type.String(), "unknown", len(compressedData))
 
 	var subscription *sentinel.GossipSubscription
 
@@ -98,6 +115,19 @@ func (s *SentinelServer) PublishGossip(_ context.Context, msg *sentinelrpc.Gossi
 			return &sentinelrpc.EmptyMessage{}, errors.New("cannot publish sidecar blob with no index")
 		}
 		subscription = manager.GetMatchingSubscription(fmt.Sprintf(string(sentinel.BlobSidecarTopic), *msg.BlobIndex))
+	case sentinelrpc.GossipType_ContributionAndProofGossipType:
+		subscription = manager.GetMatchingSubscription(string(sentinel.SyncCommitteeContributionAndProofTopic))
+	case sentinelrpc.GossipType_AttestationGossipType:
+		if msg.SubnetId == nil {
+			return &sentinelrpc.EmptyMessage{}, errors.New("cannot publish attestation with no subnet")
+		}
+		// the separators keep subnet 1 from matching subnets 10 to 19.
+		subscription = manager.GetMatchingSubscription("/" + fmt.Sprintf(string(sentinel.BeaconAttestationTopic), *msg.SubnetId) + "/")
+	case sentinelrpc.GossipType_SyncCommitteeGossipType:
+		if msg.SubnetId == nil {
+			return &sentinelrpc.EmptyMessage{}, errors.New("cannot publish sync committee message with no subnet")
+		}
+		subscription = manager.GetMatchingSubscription("/" + fmt.Sprintf(string(sentinel.SyncCommitteeTopic), *msg.SubnetId) + "/")
 	default:
 		return &sentinelrpc.EmptyMessage{}, nil
 	}
@@ -128,6 +158,7 @@ func (s *SentinelServer) SubscribeGossip(_ *sentinelrpc.EmptyMessage, stream sen
 					Pid: packet.pid,
 				},
 				BlobIndex: packet.blobIndex,
+				SubnetId:  packet.subnetId,
 			}); err != nil {
 				s.logger.Warn("[Sentinel] Could not relay gossip packet", "reason", err)
 			}
@@ -307,9 +338,16 @@ func (s *SentinelServer) handleGossipPacket(pkt *pubsub.Message) error {
 		s.gossipNotifier.notify(sentinelrpc.GossipType_AttesterSlashingGossipType, data, string(textPid))
 	} else if strings.Contains(*pkt.Topic, string(sentinel.BlsToExecutionChangeTopic)) {
 		s.gossipNotifier.notify(sentinelrpc.GossipType_BlsToExecutionChangeGossipType, data, string(textPid))
-	} else if strings.Contains(*pkt.Topic, string(sentinel.BlobSidecarTopic)) {
+	} else if strings.Contains(*pkt.Topic, blobSidecarTopicPrefix) {
 		// extract the index
 		s.gossipNotifier.notifyBlob(sentinelrpc.GossipType_BlobSidecarType, data, string(textPid), extractBlobSideCarIndex(*pkt.Topic))
+	} else if strings.Contains(*pkt.Topic, string(sentinel.SyncCommitteeContributionAndProofTopic)) {
+		// checked before the sync committee subnets, which share its prefix.
+		s.gossipNotifier.notify(sentinelrpc.GossipType_ContributionAndProofGossipType, data, string(textPid))
+	} else if strings.Contains(*pkt.Topic, attestationTopicPrefix) {
+		s.gossipNotifier.notifySubnet(sentinelrpc.GossipType_AttestationGossipType, data, string(textPid), extractTopicIndex(*pkt.Topic, attestationTopicPrefix))
+	} else if strings.Contains(*pkt.Topic, syncCommitteeTopicPrefix) {
+		s.gossipNotifier.notifySubnet(sentinelrpc.GossipType_SyncCommitteeGossipType, data, string(textPid), extractTopicIndex(*pkt.Topic, syncCommitteeTopicPrefix))
 	}
 	return nil
 }
@@ -30,13 +30,17 @@ func createSentinel(cfg *sentinel.SentinelConfig, db persistence.RawBeaconBlockC
 	}
 	gossipTopics := []sentinel.GossipTopic{
 		sentinel.BeaconBlockSsz,
-		//sentinel.BeaconAggregateAndProofSsz,
+		sentinel.BeaconAggregateAndProofSsz,
 		sentinel.VoluntaryExitSsz,
 		sentinel.ProposerSlashingSsz,
 		sentinel.AttesterSlashingSsz,
 		sentinel.BlsToExecutionChangeSsz,
+		sentinel.SyncCommitteeContributionAndProofSsz,
 	}
-	// gossipTopics = append(gossipTopics, sentinel.GossipSidecarTopics(chain.MaxBlobsPerBlock)...)
+	gossipTopics = append(gossipTopics, sentinel.GossipSidecarTopics(cltypes.MaxBlobsPerBlock)...)
+	// all the subnets are joined, as advertised in the metadata.
+	gossipTopics = append(gossipTopics, sentinel.GossipAttestationTopics(cfg.NetworkConfig.AttestationSubnetCount)...)
+	gossipTopics = append(gossipTopics, sentinel.GossipSyncCommitteeTopics(cfg.BeaconConfig.SyncCommitteeSubnetCount)...)
 
 	for _, v := range gossipTopics {
 		if err := sent.Unsubscribe(v); err != nil {
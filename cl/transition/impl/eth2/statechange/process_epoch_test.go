@@ -95,6 +95,30 @@ func TestProcessRewardsAndPenalties(t *testing.T) {
 	})
 }
 
+func TestComputeAttestationRewards(t *testing.T) {
+	// applying the computed deltas must yield the same state as processing the rewards and penalties.
+	runEpochTransitionConsensusTest(t, startingRewardsPenaltyState, expectedRewardsPenaltyState, func(s abstract.BeaconState) error {
+		eligibleValidators := state.EligibleValidatorsIndicies(s)
+		rewards, idealRewards, err := ComputeAttestationRewards(s, eligibleValidators, GetUnslashedIndiciesSet(s))
+		if err != nil {
+			return err
+		}
+		require.Len(t, idealRewards, int(s.BeaconConfig().MaxEffectiveBalance/s.BeaconConfig().EffectiveBalanceIncrement))
+		for i, index := range eligibleValidators {
+			delta := rewards[i].Head + rewards[i].Target + rewards[i].Source + rewards[i].Inactivity
+			if delta > 0 {
+				err = state.IncreaseBalance(s, index, uint64(delta))
+			} else {
+				err = state.DecreaseBalance(s, index, uint64(-delta))
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func TestProcessRegistryUpdates(t *testing.T) {
 	runEpochTransitionConsensusTest(t, startingRegistryUpdatesState, expectedRegistryUpdatesState, func(s abstract.BeaconState) error {
 		return ProcessRegistryUpdates(s)
@@ -1,20 +1,19 @@
 package statechange
 
 import (
+	"errors"
+
 	"github.com/ledgerwatch/erigon/cl/abstract"
 	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
 	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
 )
 
-func processRewardsAndPenaltiesPostAltair(s abstract.BeaconState, eligibleValidators []uint64, flagsUnslashedIndiciesSet [][]bool) (err error) {
+// participationRewardParameters returns, for each participation flag, the multiplier of the base reward of a
+// participating validator, along with the common denominator of the flag rewards.
+func participationRewardParameters(s abstract.BeaconState, flagsUnslashedIndiciesSet [][]bool) (rewardMultipliers []uint64, rewardDenominator uint64) {
 	beaconConfig := s.BeaconConfig()
 	weights := beaconConfig.ParticipationWeights()
-
-	// Initialize variables
-	totalActiveBalance := s.GetTotalActiveBalance()
-	// Inactivity penalties denominator.
-	inactivityPenaltyDenominator := beaconConfig.InactivityScoreBias * beaconConfig.GetPenaltyQuotient(s.Version())
 	// Make buffer for flag indexes total balances.
 	flagsTotalBalances := make([]uint64, len(weights))
 	s.ForEachValidator(func(validator solid.Validator, validatorIndex, total int) bool {
@@ -26,11 +25,21 @@ func processRewardsAndPenaltiesPostAltair(s abstract.BeaconState, eligibleValida
 		return true
 	})
 	// precomputed multiplier for reward.
-	rewardMultipliers := make([]uint64, len(weights))
+	rewardMultipliers = make([]uint64, len(weights))
 	for i := range weights {
 		rewardMultipliers[i] = weights[i] * (flagsTotalBalances[i] / beaconConfig.EffectiveBalanceIncrement)
 	}
-	rewardDenominator := (totalActiveBalance / beaconConfig.EffectiveBalanceIncrement) * beaconConfig.WeightDenominator
+	rewardDenominator = (s.GetTotalActiveBalance() / beaconConfig.EffectiveBalanceIncrement) * beaconConfig.WeightDenominator
+	return
+}
+
+func processRewardsAndPenaltiesPostAltair(s abstract.BeaconState, eligibleValidators []uint64, flagsUnslashedIndiciesSet [][]bool) (err error) {
+	beaconConfig := s.BeaconConfig()
+	weights := beaconConfig.ParticipationWeights()
+
+	// Inactivity penalties denominator.
+	inactivityPenaltyDenominator := beaconConfig.InactivityScoreBias * beaconConfig.GetPenaltyQuotient(s.Version())
+	rewardMultipliers, rewardDenominator := participationRewardParameters(s, flagsUnslashedIndiciesSet)
 	var baseReward uint64
 	inactivityLeaking := state.InactivityLeaking(s)
 	// Now process deltas and whats nots.
@@ -72,6 +81,81 @@ func processRewardsAndPenaltiesPostAltair(s abstract.BeaconState, eligibleValida
 	return
 }
 
+// AttestationRewards is the breakdown of the rewards and penalties of a validator for its attestation duties of the
+// previous epoch. Penalties are negative.
+type AttestationRewards struct {
+	Head       int64
+	Target     int64
+	Source     int64
+	Inactivity int64
+}
+
+// ComputeAttestationRewards computes the deltas that the post-altair rewards and penalties processing would apply to the
+// eligible validators, without touching their balances. It also returns the ideal rewards of a validator having
+// fulfilled all of its duties, for every effective balance increment: the first entry is for one increment.
+func ComputeAttestationRewards(s abstract.BeaconState, eligibleValidators []uint64, flagsUnslashedIndiciesSet [][]bool) (rewards, idealRewards []AttestationRewards, err error) {
+	if s.Version() == clparams.Phase0Version {
+		return nil, nil, errors.New("attestation rewards are not available before altair")
+	}
+	beaconConfig := s.BeaconConfig()
+	weights := beaconConfig.ParticipationWeights()
+
+	inactivityPenaltyDenominator := beaconConfig.InactivityScoreBias * beaconConfig.GetPenaltyQuotient(s.Version())
+	rewardMultipliers, rewardDenominator := participationRewardParameters(s, flagsUnslashedIndiciesSet)
+	inactivityLeaking := state.InactivityLeaking(s)
+
+	idealRewards = make([]AttestationRewards, beaconConfig.MaxEffectiveBalance/beaconConfig.EffectiveBalanceIncrement)
+	if !inactivityLeaking {
+		for i := range idealRewards {
+			baseReward := uint64(i+1) * s.BaseRewardPerIncrement()
+			for flagIdx := range weights {
+				flagReward(&idealRewards[i], beaconConfig, flagIdx, int64((baseReward*rewardMultipliers[flagIdx])/rewardDenominator))
+			}
+		}
+	}
+
+	rewards = make([]AttestationRewards, len(eligibleValidators))
+	for i, index := range eligibleValidators {
+		baseReward, err := s.BaseReward(index)
+		if err != nil {
+			return nil, nil, err
+		}
+		for flagIdx := range weights {
+			if flagsUnslashedIndiciesSet[flagIdx][index] {
+				if !inactivityLeaking {
+					flagReward(&rewards[i], beaconConfig, flagIdx, int64((baseReward*rewardMultipliers[flagIdx])/rewardDenominator))
+				}
+			} else if flagIdx != int(beaconConfig.TimelyHeadFlagIndex) {
+				flagReward(&rewards[i], beaconConfig, flagIdx, -int64(baseReward*weights[flagIdx]/beaconConfig.WeightDenominator))
+			}
+		}
+		if !flagsUnslashedIndiciesSet[beaconConfig.TimelyTargetFlagIndex][index] {
+			inactivityScore, err := s.ValidatorInactivityScore(int(index))
+			if err != nil {
+				return nil, nil, err
+			}
+			effectiveBalance, err := s.ValidatorEffectiveBalance(int(index))
+			if err != nil {
+				return nil, nil, err
+			}
+			rewards[i].Inactivity = -int64((effectiveBalance * inactivityScore) / inactivityPenaltyDenominator)
+		}
+	}
+	return
+}
+
+// flagReward records the delta of a participation flag in the rewards breakdown.
+func flagReward(rewards *AttestationRewards, beaconConfig *clparams.BeaconChainConfig, flagIdx int, delta int64) {
+	switch uint8(flagIdx) {
+	case beaconConfig.TimelySourceFlagIndex:
+		rewards.Source = delta
+	case beaconConfig.TimelyTargetFlagIndex:
+		rewards.Target = delta
+	case beaconConfig.TimelyHeadFlagIndex:
+		rewards.Head = delta
+	}
+}
+
 // processRewardsAndPenaltiesPhase0 process rewards and penalties for phase0 state.
 func processRewardsAndPenaltiesPhase0(s abstract.BeaconState, eligibleValidators []uint64) (err error) {
 	beaconConfig := s.BeaconConfig()
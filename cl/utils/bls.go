@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"errors"
+
+	blst "github.com/supranational/blst/bindings/go"
+)
+
+// AggregateSignatures aggregates compressed BLS signatures into a single compressed signature.
+func AggregateSignatures(signatures [][]byte) ([96]byte, error) {
+	var out [96]byte
+	if len(signatures) == 0 {
+		return out, errors.New("no signatures to aggregate")
+	}
+	aggregate := new(blst.P2Aggregate)
+	if !aggregate.AggregateCompressed(signatures, true) {
+		return out, errors.New("invalid signature in aggregation")
+	}
+	copy(out[:], aggregate.ToAffine().Compress())
+	return out, nil
+}
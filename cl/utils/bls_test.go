@@ -0,0 +1,43 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/Giulio2002/bls"
+	"github.com/stretchr/testify/require"
+	blst "github.com/supranational/blst/bindings/go"
+
+	"github.com/ledgerwatch/erigon/cl/utils"
+)
+
+var eth2Dst = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
+
+func TestAggregateSignatures(t *testing.T) {
+	msg := []byte("attestation data root")
+	var signatures, publicKeys [][]byte
+	for i := byte(1); i <= 3; i++ {
+		ikm := make([]byte, 32)
+		ikm[0] = i
+		sk := blst.KeyGen(ikm)
+		publicKeys = append(publicKeys, new(blst.P1Affine).From(sk).Compress())
+		signatures = append(signatures, new(blst.P2Affine).Sign(sk, msg, eth2Dst).Compress())
+	}
+
+	aggregate, err := utils.AggregateSignatures(signatures)
+	require.NoError(t, err)
+	valid, err := bls.VerifyAggregate(aggregate[:], msg, publicKeys)
+	require.NoError(t, err)
+	require.True(t, valid)
+
+	// a subset of the signers does not verify against all the keys.
+	aggregate, err = utils.AggregateSignatures(signatures[:2])
+	require.NoError(t, err)
+	valid, err = bls.VerifyAggregate(aggregate[:], msg, publicKeys)
+	require.NoError(t, err)
+	require.False(t, valid)
+
+	_, err = utils.AggregateSignatures(nil)
+	require.Error(t, err)
+	_, err = utils.AggregateSignatures([][]byte{make([]byte, 96)})
+	require.Error(t, err)
+}
@@ -0,0 +1,128 @@
+package validator
+
+import (
+	"errors"
+
+	"github.com/ledgerwatch/erigon/cl/beacon/handler"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+)
+
+// updateDuties fetches the duties of the local validators for the epoch, unless they are already known.
+func (s *Service) updateDuties(epoch uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.hasDuties && s.dutiesEpoch == epoch {
+		return nil
+	}
+	if err := s.updateIndices(); err != nil {
+		return err
+	}
+
+	allProposers, err := s.api.ProposerDuties(epoch)
+	if err != nil {
+		return err
+	}
+	proposers := []handler.ProposerDuty{}
+	for _, duty := range allProposers {
+		if _, ok := s.keys[duty.Pubkey]; ok {
+			proposers = append(proposers, duty)
+		}
+	}
+
+	attesters := []handler.AttesterDuty{}
+	if len(s.indices) > 0 {
+		if attesters, _, err = s.api.AttesterDuties(epoch, s.validatorIndices()); err != nil {
+			return err
+		}
+	}
+
+	s.dutiesEpoch, s.hasDuties = epoch, true
+	s.proposerDuties, s.attesterDuties = proposers, attesters
+	return nil
+}
+
+// syncDutiesAt returns the sync committee duties of the local validators at the given slot. Messages of a slot are
+// signed by the committee of the following slot, so the duties of a period start one slot early.
+func (s *Service) syncDutiesAt(slot uint64) ([]handler.SyncDuty, error) {
+	epoch := (slot + 1) / s.beaconCfg.SlotsPerEpoch
+	if s.beaconCfg.GetCurrentStateVersion(epoch) < clparams.AltairVersion {
+		return nil, nil
+	}
+	period := epoch / s.beaconCfg.EpochsPerSyncCommitteePeriod
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if duties, ok := s.syncDuties[period]; ok {
+		return duties, nil
+	}
+	if err := s.updateIndices(); err != nil {
+		return nil, err
+	}
+	if len(s.indices) == 0 {
+		return nil, nil
+	}
+	duties, err := s.api.SyncDuties(epoch, s.validatorIndices())
+	if err != nil {
+		return nil, err
+	}
+	for p := range s.syncDuties {
+		if p < period {
+			delete(s.syncDuties, p)
+		}
+	}
+	s.syncDuties[period] = duties
+	return duties, nil
+}
+
+// validatorIndices returns the set of the known indices of the local validators.
+func (s *Service) validatorIndices() map[uint64]struct{} {
+	indices := make(map[uint64]struct{}, len(s.indices))
+	for _, index := range s.indices {
+		indices[index] = struct{}{}
+	}
+	return indices
+}
+
+// updateIndices looks up the validator index of the local keys which are not known yet, as deposits may have been
+// processed since the last lookup.
+func (s *Service) updateIndices() error {
+	if len(s.indices) == len(s.keys) {
+		return nil
+	}
+	headRoot, _, err := s.forkchoice.GetHead()
+	if err != nil {
+		return err
+	}
+	headState, err := s.forkchoice.GetStateAtBlockRoot(headRoot, false)
+	if err != nil {
+		return err
+	}
+	if headState == nil {
+		return errors.New("head state is not available")
+	}
+	for pubkey := range s.keys {
+		if _, ok := s.indices[pubkey]; ok {
+			continue
+		}
+		if index, ok := headState.ValidatorIndexByPubkey(pubkey); ok {
+			s.indices[pubkey] = index
+		}
+	}
+	return nil
+}
+
+// dutiesAt returns the duties of the local validators at the given slot.
+func (s *Service) dutiesAt(slot uint64) (proposers []handler.ProposerDuty, attesters []handler.AttesterDuty) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, duty := range s.proposerDuties {
+		if duty.Slot == slot {
+			proposers = append(proposers, duty)
+		}
+	}
+	for _, duty := range s.attesterDuties {
+		if duty.Slot == slot {
+			attesters = append(attesters, duty)
+		}
+	}
+	return
+}
@@ -0,0 +1,195 @@
+// Package keystore reads EIP-2335 validator keystores and signs with the keys they hold.
+package keystore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Keystore is an EIP-2335 keystore, holding an encrypted BLS12-381 secret key.
+type Keystore struct {
+	Crypto struct {
+		Kdf      cryptoModule `json:"kdf"`
+		Checksum cryptoModule `json:"checksum"`
+		Cipher   cryptoModule `json:"cipher"`
+	} `json:"crypto"`
+	Description string `json:"description"`
+	Pubkey      string `json:"pubkey"`
+	Path        string `json:"path"`
+	UUID        string `json:"uuid"`
+	Version     int    `json:"version"`
+}
+
+type cryptoModule struct {
+	Function string          `json:"function"`
+	Params   json.RawMessage `json:"params"`
+	Message  string          `json:"message"`
+}
+
+type scryptParams struct {
+	Dklen int    `json:"dklen"`
+	N     int    `json:"n"`
+	P     int    `json:"p"`
+	R     int    `json:"r"`
+	Salt  string `json:"salt"`
+}
+
+type pbkdf2Params struct {
+	Dklen int    `json:"dklen"`
+	C     int    `json:"c"`
+	Prf   string `json:"prf"`
+	Salt  string `json:"salt"`
+}
+
+type aesParams struct {
+	IV string `json:"iv"`
+}
+
+// ReadKeystore parses a keystore file.
+func ReadKeystore(path string) (*Keystore, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ks := &Keystore{}
+	if err := json.Unmarshal(raw, ks); err != nil {
+		return nil, fmt.Errorf("keystore %s: %w", path, err)
+	}
+	if ks.Version != 4 {
+		return nil, fmt.Errorf("keystore %s: unsupported version %d", path, ks.Version)
+	}
+	return ks, nil
+}
+
+// ReadKeystores parses all the keystore files, with a .json extension, of a directory.
+func ReadKeystores(dir string) ([]*Keystore, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	keystores := make([]*Keystore, 0, len(paths))
+	for _, path := range paths {
+		ks, err := ReadKeystore(path)
+		if err != nil {
+			return nil, err
+		}
+		keystores = append(keystores, ks)
+	}
+	return keystores, nil
+}
+
+// Decrypt derives the decryption key from the password, checks it against the checksum and decrypts the secret key.
+func (ks *Keystore) Decrypt(password string) (*SecretKey, error) {
+	decryptionKey, err := ks.deriveKey(normalizePassword(password))
+	if err != nil {
+		return nil, err
+	}
+	if len(decryptionKey) < 32 {
+		return nil, fmt.Errorf("decryption key is too short: %d bytes", len(decryptionKey))
+	}
+
+	if ks.Crypto.Checksum.Function != "sha256" {
+		return nil, fmt.Errorf("unsupported checksum function %s", ks.Crypto.Checksum.Function)
+	}
+	cipherMessage, err := hex.DecodeString(ks.Crypto.Cipher.Message)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cipher message: %w", err)
+	}
+	expectedChecksum, err := hex.DecodeString(ks.Crypto.Checksum.Message)
+	if err != nil {
+		return nil, fmt.Errorf("invalid checksum message: %w", err)
+	}
+	checksum := sha256.Sum256(append(libcommon.CopyBytes(decryptionKey[16:32]), cipherMessage...))
+	if !bytes.Equal(checksum[:], expectedChecksum) {
+		return nil, errors.New("invalid password")
+	}
+
+	if ks.Crypto.Cipher.Function != "aes-128-ctr" {
+		return nil, fmt.Errorf("unsupported cipher function %s", ks.Crypto.Cipher.Function)
+	}
+	var params aesParams
+	if err := json.Unmarshal(ks.Crypto.Cipher.Params, &params); err != nil {
+		return nil, fmt.Errorf("invalid cipher params: %w", err)
+	}
+	iv, err := hex.DecodeString(params.IV)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cipher iv: %w", err)
+	}
+	block, err := aes.NewCipher(decryptionKey[:16])
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != block.BlockSize() {
+		return nil, fmt.Errorf("invalid cipher iv length %d", len(iv))
+	}
+	secret := make([]byte, len(cipherMessage))
+	cipher.NewCTR(block, iv).XORKeyStream(secret, cipherMessage)
+
+	sk, err := NewSecretKey(secret)
+	if err != nil {
+		return nil, err
+	}
+	if ks.Pubkey != "" {
+		pk := sk.PublicKey()
+		if !strings.EqualFold(strings.TrimPrefix(ks.Pubkey, "0x"), hex.EncodeToString(pk[:])) {
+			return nil, fmt.Errorf("decrypted key does not match public key %s", ks.Pubkey)
+		}
+	}
+	return sk, nil
+}
+
+func (ks *Keystore) deriveKey(password []byte) ([]byte, error) {
+	switch ks.Crypto.Kdf.Function {
+	case "scrypt":
+		var params scryptParams
+		if err := json.Unmarshal(ks.Crypto.Kdf.Params, &params); err != nil {
+			return nil, fmt.Errorf("invalid kdf params: %w", err)
+		}
+		salt, err := hex.DecodeString(params.Salt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid kdf salt: %w", err)
+		}
+		return scrypt.Key(password, salt, params.N, params.R, params.P, params.Dklen)
+	case "pbkdf2":
+		var params pbkdf2Params
+		if err := json.Unmarshal(ks.Crypto.Kdf.Params, &params); err != nil {
+			return nil, fmt.Errorf("invalid kdf params: %w", err)
+		}
+		if params.Prf != "hmac-sha256" {
+			return nil, fmt.Errorf("unsupported kdf prf %s", params.Prf)
+		}
+		salt, err := hex.DecodeString(params.Salt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid kdf salt: %w", err)
+		}
+		return pbkdf2.Key(password, salt, params.C, params.Dklen, sha256.New), nil
+	}
+	return nil, fmt.Errorf("unsupported kdf function %s", ks.Crypto.Kdf.Function)
+}
+
+// normalizePassword applies the NFKD normalization and strips the control codes, as mandated by EIP-2335.
+func normalizePassword(password string) []byte {
+	normalized := norm.NFKD.String(password)
+	out := make([]byte, 0, len(normalized))
+	for _, r := range normalized {
+		if r < 0x20 || (r >= 0x7f && r <= 0x9f) {
+			continue
+		}
+		out = append(out, string(r)...)
+	}
+	return out
+}
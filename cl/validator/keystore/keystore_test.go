@@ -0,0 +1,29 @@
+package keystore
+
+import (
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/stretchr/testify/require"
+)
+
+// testPassword is the password of the EIP-2335 test vectors, which normalizes to "testpassword🔑".
+const testPassword = "𝔱𝔢𝔰𝔱𝔭𝔞𝔰𝔰𝔴𝔬𝔯𝔡🔑"
+
+func TestKeystoreDecrypt(t *testing.T) {
+	ks, err := ReadKeystore("testdata/pbkdf2.json")
+	require.NoError(t, err)
+	sk, err := ks.Decrypt(testPassword)
+	require.NoError(t, err)
+	require.Equal(t, libcommon.Hex2Bytes("000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f"), sk.key.Serialize())
+
+	_, err = ks.Decrypt("testpassword")
+	require.Error(t, err)
+}
+
+func TestReadKeystores(t *testing.T) {
+	keystores, err := ReadKeystores("testdata")
+	require.NoError(t, err)
+	require.Len(t, keystores, 1)
+	require.Equal(t, "m/12381/60/0/0", keystores[0].Path)
+}
@@ -0,0 +1,39 @@
+package keystore
+
+import (
+	"github.com/Giulio2002/bls"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	blst "github.com/supranational/blst/bindings/go"
+)
+
+// signatureDST is the domain separation tag of the proof of possession scheme used by the beacon chain.
+var signatureDST = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
+
+// SecretKey is a BLS12-381 secret key of a validator.
+type SecretKey struct {
+	key       *blst.SecretKey
+	publicKey libcommon.Bytes48
+}
+
+// NewSecretKey builds a secret key from its 32 bytes big endian encoding.
+func NewSecretKey(b []byte) (*SecretKey, error) {
+	// the key length and range are checked by the bls library
+	if _, err := bls.NewPrivateKeyFromBytes(b); err != nil {
+		return nil, err
+	}
+	k := &SecretKey{key: new(blst.SecretKey).Deserialize(b)}
+	copy(k.publicKey[:], new(blst.P1Affine).From(k.key).Compress())
+	return k, nil
+}
+
+// PublicKey returns the compressed public key of the secret key.
+func (k *SecretKey) PublicKey() libcommon.Bytes48 {
+	return k.publicKey
+}
+
+// Sign signs a message, usually a signing root, and returns the compressed signature.
+func (k *SecretKey) Sign(msg []byte) (libcommon.Bytes96, error) {
+	var signature libcommon.Bytes96
+	copy(signature[:], new(blst.P2Affine).Sign(k.key, msg, signatureDST).Compress())
+	return signature, nil
+}
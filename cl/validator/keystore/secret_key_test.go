@@ -0,0 +1,34 @@
+package keystore
+
+import (
+	"testing"
+
+	"github.com/Giulio2002/bls"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretKeySign(t *testing.T) {
+	raw := libcommon.Hex2Bytes("263dbd792f5b1be47ed85f8938c0f29586af0d3ac7b977f21c278fe1462040e3")
+	sk, err := NewSecretKey(raw)
+	require.NoError(t, err)
+	// the signatures are checked against the blst based implementation.
+	for _, msg := range [][]byte{{}, []byte("abc"), libcommon.Hash{0x1}.Bytes()} {
+		sig, err := sk.Sign(msg)
+		require.NoError(t, err)
+		pk := sk.PublicKey()
+		valid, err := bls.Verify(sig[:], msg, pk[:])
+		require.NoError(t, err)
+		require.True(t, valid)
+		valid, err = bls.Verify(sig[:], []byte("another message"), pk[:])
+		require.NoError(t, err)
+		require.False(t, valid)
+	}
+}
+
+func TestSecretKeyOutOfRange(t *testing.T) {
+	_, err := NewSecretKey(make([]byte, 32))
+	require.Error(t, err)
+	_, err = NewSecretKey(make([]byte, 31))
+	require.Error(t, err)
+}
@@ -0,0 +1,203 @@
+// Package validator is a validator client built into Caplin. It performs the duties of the validators whose keys are
+// held locally, following the node's own fork choice and calling the beacon API handler in process.
+package validator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/beacon/handler"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/phase1/forkchoice"
+	"github.com/ledgerwatch/erigon/cl/validator/keystore"
+	"github.com/ledgerwatch/erigon/cl/validator/slashing_protection"
+	"github.com/ledgerwatch/log/v3"
+)
+
+// BeaconAPI is the part of the beacon API the validator client relies on, it is implemented by the beacon API handler.
+type BeaconAPI interface {
+	ProposerDuties(epoch uint64) ([]handler.ProposerDuty, error)
+	AttesterDuties(epoch uint64, indices map[uint64]struct{}) ([]handler.AttesterDuty, libcommon.Hash, error)
+	SyncDuties(epoch uint64, indices map[uint64]struct{}) ([]handler.SyncDuty, error)
+
+	ProduceBlock(slot uint64, randaoReveal libcommon.Bytes96, graffiti libcommon.Hash) (*handler.BlockContents, error)
+	PublishBlock(ctx context.Context, contents *handler.SignedBlockContents) error
+	AttestationData(slot, committeeIndex uint64) (solid.AttestationData, error)
+	PublishAttestations(ctx context.Context, attestations []*solid.Attestation) error
+	AggregateAttestation(dataRoot libcommon.Hash, slot uint64) (*solid.Attestation, error)
+	PublishAggregateAndProofs(ctx context.Context, aggregates []*cltypes.SignedAggregateAndProof) error
+	PublishSyncCommitteeMessages(ctx context.Context, messages []*cltypes.SyncCommitteeMessage) error
+	SyncCommitteeContribution(slot, subcommitteeIndex uint64, blockRoot libcommon.Hash) (*cltypes.SyncCommitteeContribution, error)
+	PublishContributionAndProofs(ctx context.Context, contributions []*cltypes.SignedContributionAndProof) error
+}
+
+// Service performs the block proposals, attestations, aggregations and sync committee duties of the local validators.
+type Service struct {
+	beaconCfg  *clparams.BeaconChainConfig
+	genesisCfg *clparams.GenesisConfig
+	api        BeaconAPI
+	forkchoice forkchoice.ForkChoiceStorageReader
+	protection *slashing_protection.SlashingProtection
+	keys       map[libcommon.Bytes48]*keystore.SecretKey
+	logger     log.Logger
+
+	mu              sync.Mutex
+	indices         map[libcommon.Bytes48]uint64
+	dutiesEpoch     uint64
+	hasDuties       bool
+	proposerDuties  []handler.ProposerDuty
+	attesterDuties  []handler.AttesterDuty
+	syncDuties      map[uint64][]handler.SyncDuty // by sync committee period
+	lastHandledSlot uint64
+	hasHandledASlot bool
+}
+
+// NewService creates the validator client of the given keys.
+func NewService(beaconCfg *clparams.BeaconChainConfig, genesisCfg *clparams.GenesisConfig, api BeaconAPI, forkchoice forkchoice.ForkChoiceStorageReader,
+	protection *slashing_protection.SlashingProtection, keys []*keystore.SecretKey, logger log.Logger) *Service {
+	keysByPubkey := make(map[libcommon.Bytes48]*keystore.SecretKey, len(keys))
+	for _, key := range keys {
+		keysByPubkey[key.PublicKey()] = key
+	}
+	return &Service{
+		beaconCfg:  beaconCfg,
+		genesisCfg: genesisCfg,
+		api:        api,
+		forkchoice: forkchoice,
+		protection: protection,
+		keys:       keysByPubkey,
+		logger:     logger,
+		indices:    make(map[libcommon.Bytes48]uint64),
+		syncDuties: make(map[uint64][]handler.SyncDuty),
+	}
+}
+
+// LoadKeys decrypts all the keystores of a directory with the password stored in passwordFile.
+func LoadKeys(keystoresDir, passwordFile string) ([]*keystore.SecretKey, error) {
+	password, err := os.ReadFile(passwordFile)
+	if err != nil {
+		return nil, err
+	}
+	keystores, err := keystore.ReadKeystores(keystoresDir)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*keystore.SecretKey, 0, len(keystores))
+	for _, ks := range keystores {
+		key, err := ks.Decrypt(strings.TrimRight(string(password), "\r\n"))
+		if err != nil {
+			return nil, fmt.Errorf("keystore of %s: %w", ks.Pubkey, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Start runs the duties of each slot until the context is cancelled.
+func (s *Service) Start(ctx context.Context) {
+	s.logger.Info("[Validator] started", "keys", len(s.keys))
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		slot := s.forkchoice.Slot()
+		if s.hasHandledASlot && slot <= s.lastHandledSlot {
+			continue
+		}
+		s.lastHandledSlot, s.hasHandledASlot = slot, true
+		// the node cannot perform duties on top of a head it is still syncing.
+		if _, headSlot, err := s.forkchoice.GetHead(); err != nil || headSlot+s.beaconCfg.SlotsPerEpoch < slot {
+			continue
+		}
+		go s.runSlot(ctx, slot)
+	}
+}
+
+// runSlot proposes at the start of the slot, attests and votes for the head a third into it and aggregates at two
+// thirds, as the head is expected to be known by then.
+func (s *Service) runSlot(ctx context.Context, slot uint64) {
+	if err := s.updateDuties(slot / s.beaconCfg.SlotsPerEpoch); err != nil {
+		s.logger.Warn("[Validator] could not fetch duties", "slot", slot, "err", err)
+		return
+	}
+	proposers, attesters := s.dutiesAt(slot)
+	syncDuties, err := s.syncDutiesAt(slot)
+	if err != nil {
+		s.logger.Warn("[Validator] could not fetch sync committee duties", "slot", slot, "err", err)
+	}
+	for _, duty := range proposers {
+		if err := s.proposeBlock(ctx, duty); err != nil {
+			s.logger.Warn("[Validator] could not propose block", "slot", slot, "validator", duty.ValidatorIndex, "err", err)
+		}
+	}
+	if len(attesters) == 0 && len(syncDuties) == 0 {
+		return
+	}
+
+	if !s.sleepUntil(ctx, slot, 1) {
+		return
+	}
+	attested := make([]handler.AttesterDuty, 0, len(attesters))
+	for _, duty := range attesters {
+		if err := s.attest(ctx, duty); err != nil {
+			s.logger.Warn("[Validator] could not attest", "slot", slot, "validator", duty.ValidatorIndex, "err", err)
+			continue
+		}
+		attested = append(attested, duty)
+	}
+	// sync committee members vote for the head, and contribute for the very same block.
+	var headRoot libcommon.Hash
+	if len(syncDuties) > 0 {
+		if headRoot, _, err = s.forkchoice.GetHead(); err != nil {
+			s.logger.Warn("[Validator] could not get the head", "slot", slot, "err", err)
+			syncDuties = nil
+		}
+	}
+	voted := make([]handler.SyncDuty, 0, len(syncDuties))
+	for _, duty := range syncDuties {
+		if err := s.voteForHead(ctx, slot, headRoot, duty); err != nil {
+			s.logger.Warn("[Validator] could not publish sync committee message", "slot", slot, "validator", duty.ValidatorIndex, "err", err)
+			continue
+		}
+		voted = append(voted, duty)
+	}
+
+	if !s.sleepUntil(ctx, slot, 2) {
+		return
+	}
+	for _, duty := range attested {
+		if err := s.aggregate(ctx, duty); err != nil {
+			s.logger.Warn("[Validator] could not aggregate", "slot", slot, "validator", duty.ValidatorIndex, "err", err)
+		}
+	}
+	for _, duty := range voted {
+		if err := s.contribute(ctx, slot, headRoot, duty); err != nil {
+			s.logger.Warn("[Validator] could not publish sync committee contribution", "slot", slot, "validator", duty.ValidatorIndex, "err", err)
+		}
+	}
+}
+
+// sleepUntil waits until the given third of the slot and tells whether the service is still running.
+func (s *Service) sleepUntil(ctx context.Context, slot, thirds uint64) bool {
+	at := time.Unix(int64(s.genesisCfg.GenesisTime+slot*s.beaconCfg.SecondsPerSlot), 0).
+		Add(time.Duration(thirds*s.beaconCfg.SecondsPerSlot) * time.Second / 3)
+	timer := time.NewTimer(time.Until(at))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
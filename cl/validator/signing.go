@@ -0,0 +1,250 @@
+package validator
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/types/ssz"
+	"github.com/ledgerwatch/erigon/cl/beacon/handler"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/fork"
+	"github.com/ledgerwatch/erigon/cl/merkle_tree"
+	"github.com/ledgerwatch/erigon/cl/utils"
+	"github.com/ledgerwatch/erigon/cl/validator/keystore"
+)
+
+// signingRoot computes the root to sign for an object, under the fork scheduled at the given epoch.
+func (s *Service) signingRoot(root libcommon.Hash, domainType libcommon.Bytes4, epoch uint64) (libcommon.Hash, error) {
+	forkVersion := s.beaconCfg.GetForkVersionByVersion(s.beaconCfg.GetCurrentStateVersion(epoch))
+	domain, err := fork.ComputeDomain(domainType[:], utils.Uint32ToBytes4(forkVersion), s.genesisCfg.GenesisValidatorRoot)
+	if err != nil {
+		return libcommon.Hash{}, err
+	}
+	return utils.Sha256(root[:], domain), nil
+}
+
+func (s *Service) key(pubkey libcommon.Bytes48) (*keystore.SecretKey, error) {
+	key, ok := s.keys[pubkey]
+	if !ok {
+		return nil, fmt.Errorf("no local key for %x", pubkey)
+	}
+	return key, nil
+}
+
+func hashRoot(obj ssz.HashableSSZ) (libcommon.Hash, error) {
+	root, err := obj.HashSSZ()
+	return libcommon.Hash(root), err
+}
+
+// isAggregator tells whether a selection proof selects its signer as an aggregator, one in modulo being selected.
+func isAggregator(selectionProof libcommon.Bytes96, modulo uint64) bool {
+	proofHash := utils.Sha256(selectionProof[:])
+	return binary.LittleEndian.Uint64(proofHash[:8])%modulo == 0
+}
+
+// proposeBlock has the node produce a block, then signs and publishes it once slashing protection accepted it.
+func (s *Service) proposeBlock(ctx context.Context, duty handler.ProposerDuty) error {
+	key, err := s.key(duty.Pubkey)
+	if err != nil {
+		return err
+	}
+	epoch := duty.Slot / s.beaconCfg.SlotsPerEpoch
+	randaoRoot, err := s.signingRoot(merkle_tree.Uint64Root(epoch), s.beaconCfg.DomainRandao, epoch)
+	if err != nil {
+		return err
+	}
+	randaoReveal, err := key.Sign(randaoRoot[:])
+	if err != nil {
+		return err
+	}
+
+	contents, err := s.api.ProduceBlock(duty.Slot, randaoReveal, libcommon.Hash{})
+	if err != nil {
+		return err
+	}
+	block := contents.Block
+
+	blockRoot, err := hashRoot(block)
+	if err != nil {
+		return err
+	}
+	root, err := s.signingRoot(blockRoot, s.beaconCfg.DomainBeaconProposer, epoch)
+	if err != nil {
+		return err
+	}
+	if err := s.protection.CheckAndRecordBlock(duty.Pubkey, block.Slot, root); err != nil {
+		return err
+	}
+	signature, err := key.Sign(root[:])
+	if err != nil {
+		return err
+	}
+	signed := &cltypes.SignedBeaconBlock{Block: block, Signature: signature}
+	if err := s.api.PublishBlock(ctx, &handler.SignedBlockContents{SignedBlock: signed, KzgProofs: contents.KzgProofs, Blobs: contents.Blobs}); err != nil {
+		return err
+	}
+	s.logger.Info("[Validator] proposed block", "slot", duty.Slot, "validator", duty.ValidatorIndex, "root", blockRoot)
+	return nil
+}
+
+// attest signs and publishes the attestation of a validator, once slashing protection accepted it.
+func (s *Service) attest(ctx context.Context, duty handler.AttesterDuty) error {
+	key, err := s.key(duty.Pubkey)
+	if err != nil {
+		return err
+	}
+	data, err := s.api.AttestationData(duty.Slot, duty.CommitteeIndex)
+	if err != nil {
+		return err
+	}
+	dataRoot, err := hashRoot(data)
+	if err != nil {
+		return err
+	}
+	root, err := s.signingRoot(dataRoot, s.beaconCfg.DomainBeaconAttester, data.Target().Epoch())
+	if err != nil {
+		return err
+	}
+	if err := s.protection.CheckAndRecordAttestation(duty.Pubkey, data.Source().Epoch(), data.Target().Epoch(), root); err != nil {
+		return err
+	}
+	signature, err := key.Sign(root[:])
+	if err != nil {
+		return err
+	}
+	// the aggregation bits are a bitlist, terminated by a bit marking its length.
+	aggregationBits := make([]byte, duty.CommitteeLength/8+1)
+	aggregationBits[duty.ValidatorCommitteeIndex/8] |= 1 << (duty.ValidatorCommitteeIndex % 8)
+	aggregationBits[duty.CommitteeLength/8] |= 1 << (duty.CommitteeLength % 8)
+	attestation := solid.NewAttestionFromParameters(aggregationBits, data, signature)
+	return s.api.PublishAttestations(ctx, []*solid.Attestation{attestation})
+}
+
+// aggregate publishes the aggregate of the committee of a validator, if the validator was selected as an aggregator.
+func (s *Service) aggregate(ctx context.Context, duty handler.AttesterDuty) error {
+	key, err := s.key(duty.Pubkey)
+	if err != nil {
+		return err
+	}
+	epoch := duty.Slot / s.beaconCfg.SlotsPerEpoch
+	selectionRoot, err := s.signingRoot(merkle_tree.Uint64Root(duty.Slot), s.beaconCfg.DomainSelectionProof, epoch)
+	if err != nil {
+		return err
+	}
+	selectionProof, err := key.Sign(selectionRoot[:])
+	if err != nil {
+		return err
+	}
+	if !isAggregator(selectionProof, utils.Max64(1, duty.CommitteeLength/s.beaconCfg.TargetAggregatorsPerCommittee)) {
+		return nil
+	}
+
+	data, err := s.api.AttestationData(duty.Slot, duty.CommitteeIndex)
+	if err != nil {
+		return err
+	}
+	dataRoot, err := hashRoot(data)
+	if err != nil {
+		return err
+	}
+	aggregate, err := s.api.AggregateAttestation(dataRoot, duty.Slot)
+	if err != nil {
+		return err
+	}
+	message := &cltypes.AggregateAndProof{AggregatorIndex: duty.ValidatorIndex, Aggregate: aggregate, SelectionProof: selectionProof}
+	messageRoot, err := hashRoot(message)
+	if err != nil {
+		return err
+	}
+	root, err := s.signingRoot(messageRoot, s.beaconCfg.DomainAggregateAndProof, epoch)
+	if err != nil {
+		return err
+	}
+	signature, err := key.Sign(root[:])
+	if err != nil {
+		return err
+	}
+	return s.api.PublishAggregateAndProofs(ctx, []*cltypes.SignedAggregateAndProof{{Message: message, Signature: signature}})
+}
+
+// voteForHead signs and publishes the sync committee message of a validator for the head block.
+func (s *Service) voteForHead(ctx context.Context, slot uint64, headRoot libcommon.Hash, duty handler.SyncDuty) error {
+	key, err := s.key(duty.Pubkey)
+	if err != nil {
+		return err
+	}
+	root, err := s.signingRoot(headRoot, s.beaconCfg.DomainSyncCommittee, slot/s.beaconCfg.SlotsPerEpoch)
+	if err != nil {
+		return err
+	}
+	signature, err := key.Sign(root[:])
+	if err != nil {
+		return err
+	}
+	return s.api.PublishSyncCommitteeMessages(ctx, []*cltypes.SyncCommitteeMessage{{
+		Slot:            slot,
+		BeaconBlockRoot: headRoot,
+		ValidatorIndex:  duty.ValidatorIndex,
+		Signature:       signature,
+	}})
+}
+
+// contribute publishes the contribution of each subcommittee of a validator for which it is selected as an aggregator.
+func (s *Service) contribute(ctx context.Context, slot uint64, headRoot libcommon.Hash, duty handler.SyncDuty) error {
+	key, err := s.key(duty.Pubkey)
+	if err != nil {
+		return err
+	}
+	epoch := slot / s.beaconCfg.SlotsPerEpoch
+	subcommitteeSize := s.beaconCfg.SyncCommitteeSize / s.beaconCfg.SyncCommitteeSubnetCount
+	modulo := utils.Max64(1, subcommitteeSize/s.beaconCfg.TargetAggregatorsPerSyncSubcommittee)
+	contributed := make(map[uint64]struct{})
+	for _, position := range duty.ValidatorSyncCommitteeIndices {
+		subcommitteeIndex := position / subcommitteeSize
+		if _, ok := contributed[subcommitteeIndex]; ok {
+			continue
+		}
+		contributed[subcommitteeIndex] = struct{}{}
+
+		selectionDataRoot, err := hashRoot(&cltypes.SyncAggregatorSelectionData{Slot: slot, SubcommitteeIndex: subcommitteeIndex})
+		if err != nil {
+			return err
+		}
+		selectionRoot, err := s.signingRoot(selectionDataRoot, s.beaconCfg.DomainSyncCommitteeSelectionProof, epoch)
+		if err != nil {
+			return err
+		}
+		selectionProof, err := key.Sign(selectionRoot[:])
+		if err != nil {
+			return err
+		}
+		if !isAggregator(selectionProof, modulo) {
+			continue
+		}
+
+		contribution, err := s.api.SyncCommitteeContribution(slot, subcommitteeIndex, headRoot)
+		if err != nil {
+			return err
+		}
+		message := &cltypes.ContributionAndProof{AggregatorIndex: duty.ValidatorIndex, Contribution: contribution, SelectionProof: selectionProof}
+		messageRoot, err := hashRoot(message)
+		if err != nil {
+			return err
+		}
+		root, err := s.signingRoot(messageRoot, s.beaconCfg.DomainContributionAndProof, epoch)
+		if err != nil {
+			return err
+		}
+		signature, err := key.Sign(root[:])
+		if err != nil {
+			return err
+		}
+		if err := s.api.PublishContributionAndProofs(ctx, []*cltypes.SignedContributionAndProof{{Message: message, Signature: signature}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
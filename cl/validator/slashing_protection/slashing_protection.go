@@ -0,0 +1,268 @@
+// Package slashing_protection keeps track of what the local validators signed, refusing to sign anything slashable,
+// and imports and exports that history in the EIP-3076 interchange format.
+package slashing_protection
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/spf13/afero"
+)
+
+const interchangeFormatVersion = "5"
+
+var (
+	ErrSlashableBlock       = errors.New("block proposal is slashable")
+	ErrSlashableAttestation = errors.New("attestation is slashable")
+)
+
+// Interchange is the EIP-3076 slashing protection interchange format.
+type Interchange struct {
+	Metadata struct {
+		InterchangeFormatVersion string         `json:"interchange_format_version"`
+		GenesisValidatorsRoot    libcommon.Hash `json:"genesis_validators_root"`
+	} `json:"metadata"`
+	Data []InterchangeValidator `json:"data"`
+}
+
+type InterchangeValidator struct {
+	Pubkey             libcommon.Bytes48        `json:"pubkey"`
+	SignedBlocks       []InterchangeBlock       `json:"signed_blocks"`
+	SignedAttestations []InterchangeAttestation `json:"signed_attestations"`
+}
+
+type InterchangeBlock struct {
+	Slot        uint64          `json:"slot,string"`
+	SigningRoot *libcommon.Hash `json:"signing_root,omitempty"`
+}
+
+type InterchangeAttestation struct {
+	SourceEpoch uint64          `json:"source_epoch,string"`
+	TargetEpoch uint64          `json:"target_epoch,string"`
+	SigningRoot *libcommon.Hash `json:"signing_root,omitempty"`
+}
+
+// validatorHistory follows the minimal strategy of EIP-3076: only the latest block and the highest source and target
+// epochs are kept, along with the signing roots to allow signing the very same message twice.
+type validatorHistory struct {
+	hasBlock        bool
+	blockSlot       uint64
+	blockRoot       libcommon.Hash
+	hasAttestation  bool
+	sourceEpoch     uint64
+	targetEpoch     uint64
+	attestationRoot libcommon.Hash
+}
+
+// SlashingProtection is the slashing protection database of the local validators, persisted as an interchange file.
+type SlashingProtection struct {
+	mu                    sync.Mutex
+	fs                    afero.Fs
+	path                  string
+	genesisValidatorsRoot libcommon.Hash
+	validators            map[libcommon.Bytes48]*validatorHistory
+}
+
+// NewSlashingProtection opens the slashing protection database stored at path, creating it if it does not exist yet.
+func NewSlashingProtection(fs afero.Fs, path string, genesisValidatorsRoot libcommon.Hash) (*SlashingProtection, error) {
+	s := &SlashingProtection{
+		fs:                    fs,
+		path:                  path,
+		genesisValidatorsRoot: genesisValidatorsRoot,
+		validators:            make(map[libcommon.Bytes48]*validatorHistory),
+	}
+	f, err := fs.Open(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if err := s.Import(f); err != nil {
+		return nil, fmt.Errorf("slashing protection database %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// NewSlashingProtectionFromOsPath opens the slashing protection database stored at path on the disk.
+func NewSlashingProtectionFromOsPath(path string, genesisValidatorsRoot libcommon.Hash) (*SlashingProtection, error) {
+	return NewSlashingProtection(afero.NewOsFs(), path, genesisValidatorsRoot)
+}
+
+func (s *SlashingProtection) history(pubkey libcommon.Bytes48) *validatorHistory {
+	h, ok := s.validators[pubkey]
+	if !ok {
+		h = &validatorHistory{}
+		s.validators[pubkey] = h
+	}
+	return h
+}
+
+// CheckAndRecordBlock refuses to sign a block at or before the latest signed one, unless it is the very same block,
+// and otherwise records it before the signature is produced.
+func (s *SlashingProtection) CheckAndRecordBlock(pubkey libcommon.Bytes48, slot uint64, signingRoot libcommon.Hash) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h := s.history(pubkey)
+	if h.hasBlock {
+		if slot == h.blockSlot && signingRoot == h.blockRoot {
+			return nil
+		}
+		if slot <= h.blockSlot {
+			return fmt.Errorf("%w: slot %d, latest signed slot %d", ErrSlashableBlock, slot, h.blockSlot)
+		}
+	}
+	previous := *h
+	h.hasBlock, h.blockSlot, h.blockRoot = true, slot, signingRoot
+	if err := s.persist(); err != nil {
+		*h = previous
+		return err
+	}
+	return nil
+}
+
+// CheckAndRecordAttestation refuses to sign an attestation whose source is below the highest signed source or whose
+// target is not above the highest signed target, unless it is the very same attestation, and otherwise records it
+// before the signature is produced.
+func (s *SlashingProtection) CheckAndRecordAttestation(pubkey libcommon.Bytes48, sourceEpoch, targetEpoch uint64, signingRoot libcommon.Hash) error {
+	if sourceEpoch > targetEpoch {
+		return fmt.Errorf("%w: source epoch %d is after target epoch %d", ErrSlashableAttestation, sourceEpoch, targetEpoch)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h := s.history(pubkey)
+	if h.hasAttestation {
+		if sourceEpoch == h.sourceEpoch && targetEpoch == h.targetEpoch && signingRoot == h.attestationRoot {
+			return nil
+		}
+		if sourceEpoch < h.sourceEpoch {
+			return fmt.Errorf("%w: source epoch %d, highest signed source epoch %d", ErrSlashableAttestation, sourceEpoch, h.sourceEpoch)
+		}
+		if targetEpoch <= h.targetEpoch {
+			return fmt.Errorf("%w: target epoch %d, highest signed target epoch %d", ErrSlashableAttestation, targetEpoch, h.targetEpoch)
+		}
+	}
+	previous := *h
+	h.hasAttestation, h.sourceEpoch, h.targetEpoch, h.attestationRoot = true, sourceEpoch, targetEpoch, signingRoot
+	if err := s.persist(); err != nil {
+		*h = previous
+		return err
+	}
+	return nil
+}
+
+// Import merges an interchange file into the database. Only the latest block and the highest epochs of each validator
+// are kept, which is all the minimal strategy needs.
+func (s *SlashingProtection) Import(r io.Reader) error {
+	var interchange Interchange
+	if err := json.NewDecoder(r).Decode(&interchange); err != nil {
+		return err
+	}
+	if interchange.Metadata.InterchangeFormatVersion != interchangeFormatVersion {
+		return fmt.Errorf("unsupported interchange format version %s", interchange.Metadata.InterchangeFormatVersion)
+	}
+	if interchange.Metadata.GenesisValidatorsRoot != s.genesisValidatorsRoot {
+		return fmt.Errorf("interchange genesis validators root %x does not match %x", interchange.Metadata.GenesisValidatorsRoot, s.genesisValidatorsRoot)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, validator := range interchange.Data {
+		h := s.history(validator.Pubkey)
+		for _, block := range validator.SignedBlocks {
+			if h.hasBlock && block.Slot <= h.blockSlot {
+				continue
+			}
+			h.hasBlock, h.blockSlot, h.blockRoot = true, block.Slot, libcommon.Hash{}
+			if block.SigningRoot != nil {
+				h.blockRoot = *block.SigningRoot
+			}
+		}
+		for _, attestation := range validator.SignedAttestations {
+			if !h.hasAttestation || attestation.SourceEpoch > h.sourceEpoch {
+				h.sourceEpoch = attestation.SourceEpoch
+			}
+			if !h.hasAttestation || attestation.TargetEpoch > h.targetEpoch {
+				h.targetEpoch, h.attestationRoot = attestation.TargetEpoch, libcommon.Hash{}
+				if attestation.SigningRoot != nil {
+					h.attestationRoot = *attestation.SigningRoot
+				}
+			}
+			h.hasAttestation = true
+		}
+	}
+	return s.persist()
+}
+
+// Export writes the database as an interchange file.
+func (s *SlashingProtection) Export(w io.Writer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.export(w)
+}
+
+func (s *SlashingProtection) export(w io.Writer) error {
+	interchange := Interchange{Data: make([]InterchangeValidator, 0, len(s.validators))}
+	interchange.Metadata.InterchangeFormatVersion = interchangeFormatVersion
+	interchange.Metadata.GenesisValidatorsRoot = s.genesisValidatorsRoot
+	for pubkey, h := range s.validators {
+		validator := InterchangeValidator{
+			Pubkey:             pubkey,
+			SignedBlocks:       []InterchangeBlock{},
+			SignedAttestations: []InterchangeAttestation{},
+		}
+		if h.hasBlock {
+			root := h.blockRoot
+			validator.SignedBlocks = append(validator.SignedBlocks, InterchangeBlock{Slot: h.blockSlot, SigningRoot: &root})
+		}
+		if h.hasAttestation {
+			root := h.attestationRoot
+			validator.SignedAttestations = append(validator.SignedAttestations, InterchangeAttestation{SourceEpoch: h.sourceEpoch, TargetEpoch: h.targetEpoch, SigningRoot: &root})
+		}
+		interchange.Data = append(interchange.Data, validator)
+	}
+	sort.Slice(interchange.Data, func(i, j int) bool {
+		return string(interchange.Data[i].Pubkey[:]) < string(interchange.Data[j].Pubkey[:])
+	})
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(interchange)
+}
+
+// persist writes the database to a temporary file which then replaces the previous one, so that a crash never leaves
+// a truncated database behind. The directory is synced as well, otherwise the rename itself may be lost on a crash and
+// a signature be produced without its record.
+func (s *SlashingProtection) persist() error {
+	tmpPath := s.path + ".tmp"
+	f, err := s.fs.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	if err := s.export(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := s.fs.Rename(tmpPath, s.path); err != nil {
+		return err
+	}
+	dir, err := s.fs.Open(filepath.Dir(s.path))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
@@ -0,0 +1,109 @@
+package slashing_protection
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testGenesisValidatorsRoot = libcommon.HexToHash("0x04700007fabc8282644aed6d1c7c9e21d38a03a0c4ba193f3afe428824b3a673")
+	testPubkey                = libcommon.Bytes48{0xb8}
+)
+
+func TestCheckAndRecordBlock(t *testing.T) {
+	s, err := NewSlashingProtection(afero.NewMemMapFs(), "slashing_protection.json", testGenesisValidatorsRoot)
+	require.NoError(t, err)
+
+	require.NoError(t, s.CheckAndRecordBlock(testPubkey, 10, libcommon.Hash{1}))
+	// signing the very same block again is harmless.
+	require.NoError(t, s.CheckAndRecordBlock(testPubkey, 10, libcommon.Hash{1}))
+	require.ErrorIs(t, s.CheckAndRecordBlock(testPubkey, 10, libcommon.Hash{2}), ErrSlashableBlock)
+	require.ErrorIs(t, s.CheckAndRecordBlock(testPubkey, 9, libcommon.Hash{3}), ErrSlashableBlock)
+	require.NoError(t, s.CheckAndRecordBlock(testPubkey, 11, libcommon.Hash{4}))
+	// other validators are not affected.
+	require.NoError(t, s.CheckAndRecordBlock(libcommon.Bytes48{0xa9}, 5, libcommon.Hash{5}))
+}
+
+func TestCheckAndRecordAttestation(t *testing.T) {
+	s, err := NewSlashingProtection(afero.NewMemMapFs(), "slashing_protection.json", testGenesisValidatorsRoot)
+	require.NoError(t, err)
+
+	require.NoError(t, s.CheckAndRecordAttestation(testPubkey, 2, 3, libcommon.Hash{1}))
+	require.NoError(t, s.CheckAndRecordAttestation(testPubkey, 2, 3, libcommon.Hash{1}))
+	// double vote.
+	require.ErrorIs(t, s.CheckAndRecordAttestation(testPubkey, 2, 3, libcommon.Hash{2}), ErrSlashableAttestation)
+	// surrounded vote.
+	require.ErrorIs(t, s.CheckAndRecordAttestation(testPubkey, 1, 4, libcommon.Hash{3}), ErrSlashableAttestation)
+	require.ErrorIs(t, s.CheckAndRecordAttestation(testPubkey, 5, 4, libcommon.Hash{4}), ErrSlashableAttestation)
+	require.NoError(t, s.CheckAndRecordAttestation(testPubkey, 3, 4, libcommon.Hash{5}))
+}
+
+func TestPersistence(t *testing.T) {
+	// the database is replaced by a rename, which is checked against the real file system.
+	fs := afero.NewOsFs()
+	path := filepath.Join(t.TempDir(), "slashing_protection.json")
+	s, err := NewSlashingProtection(fs, path, testGenesisValidatorsRoot)
+	require.NoError(t, err)
+	require.NoError(t, s.CheckAndRecordBlock(testPubkey, 10, libcommon.Hash{1}))
+	require.NoError(t, s.CheckAndRecordAttestation(testPubkey, 2, 3, libcommon.Hash{2}))
+	_, err = fs.Stat(path + ".tmp")
+	require.True(t, os.IsNotExist(err))
+
+	reopened, err := NewSlashingProtection(fs, path, testGenesisValidatorsRoot)
+	require.NoError(t, err)
+	require.ErrorIs(t, reopened.CheckAndRecordBlock(testPubkey, 10, libcommon.Hash{3}), ErrSlashableBlock)
+	require.ErrorIs(t, reopened.CheckAndRecordAttestation(testPubkey, 2, 3, libcommon.Hash{4}), ErrSlashableAttestation)
+	require.NoError(t, reopened.CheckAndRecordAttestation(testPubkey, 2, 3, libcommon.Hash{2}))
+
+	_, err = NewSlashingProtection(fs, path, libcommon.Hash{})
+	require.Error(t, err)
+}
+
+const testInterchange = `{
+  "metadata": {
+    "interchange_format_version": "5",
+    "genesis_validators_root": "0x04700007fabc8282644aed6d1c7c9e21d38a03a0c4ba193f3afe428824b3a673"
+  },
+  "data": [
+    {
+      "pubkey": "0xb845089a1457f811bfc000588fbb4e713669be8ce060ea6be3c6ece09afc3794106c91ca73acda5e5457122d58723bed",
+      "signed_blocks": [
+        {"slot": "81952", "signing_root": "0x4ff6f743a43f3b4f95350831aeaf0a122a1a392922c45d804280284a69eb850b"},
+        {"slot": "81951"}
+      ],
+      "signed_attestations": [
+        {"source_epoch": "2290", "target_epoch": "3007", "signing_root": "0x587d6a4f59a58fe24f406e0502413e77fe1babddee641fda30034ed37ecc884d"},
+        {"source_epoch": "2291", "target_epoch": "3006"}
+      ]
+    }
+  ]
+}`
+
+func TestImportExport(t *testing.T) {
+	s, err := NewSlashingProtection(afero.NewMemMapFs(), "slashing_protection.json", testGenesisValidatorsRoot)
+	require.NoError(t, err)
+	require.NoError(t, s.Import(strings.NewReader(testInterchange)))
+
+	pubkey := libcommon.Bytes48{}
+	require.NoError(t, pubkey.UnmarshalText([]byte("0xb845089a1457f811bfc000588fbb4e713669be8ce060ea6be3c6ece09afc3794106c91ca73acda5e5457122d58723bed")))
+	require.ErrorIs(t, s.CheckAndRecordBlock(pubkey, 81952, libcommon.Hash{}), ErrSlashableBlock)
+	require.NoError(t, s.CheckAndRecordBlock(pubkey, 81952, libcommon.HexToHash("0x4ff6f743a43f3b4f95350831aeaf0a122a1a392922c45d804280284a69eb850b")))
+	// the highest source and target come from different attestations.
+	require.ErrorIs(t, s.CheckAndRecordAttestation(pubkey, 2290, 3008, libcommon.Hash{}), ErrSlashableAttestation)
+	require.ErrorIs(t, s.CheckAndRecordAttestation(pubkey, 2291, 3007, libcommon.Hash{}), ErrSlashableAttestation)
+
+	var exported bytes.Buffer
+	require.NoError(t, s.Export(&exported))
+	other, err := NewSlashingProtection(afero.NewMemMapFs(), "slashing_protection.json", testGenesisValidatorsRoot)
+	require.NoError(t, err)
+	require.NoError(t, other.Import(bytes.NewReader(exported.Bytes())))
+	require.ErrorIs(t, other.CheckAndRecordBlock(pubkey, 81952, libcommon.Hash{}), ErrSlashableBlock)
+	require.NoError(t, other.CheckAndRecordAttestation(pubkey, 2291, 3008, libcommon.Hash{}))
+}
@@ -2,6 +2,7 @@ package caplin1
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/ledgerwatch/erigon/cl/antiquary"
 	"github.com/ledgerwatch/erigon/cl/beacon"
 	"github.com/ledgerwatch/erigon/cl/beacon/beacon_router_configuration"
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconevents"
 	"github.com/ledgerwatch/erigon/cl/beacon/handler"
 	"github.com/ledgerwatch/erigon/cl/beacon/synced_data"
 	"github.com/ledgerwatch/erigon/cl/beacon/validatorapi"
@@ -17,15 +19,18 @@ import (
 	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
 	"github.com/ledgerwatch/erigon/cl/freezer"
 	freezer2 "github.com/ledgerwatch/erigon/cl/freezer"
+	"github.com/ledgerwatch/erigon/cl/light_client"
 	"github.com/ledgerwatch/erigon/eth/ethconfig"
 	"github.com/ledgerwatch/erigon/turbo/snapshotsync/freezeblocks"
 
 	"github.com/ledgerwatch/erigon/cl/persistence"
 	persistence2 "github.com/ledgerwatch/erigon/cl/persistence"
 	"github.com/ledgerwatch/erigon/cl/persistence/beacon_indicies"
+	"github.com/ledgerwatch/erigon/cl/persistence/blob_storage"
 	"github.com/ledgerwatch/erigon/cl/persistence/db_config"
 	"github.com/ledgerwatch/erigon/cl/persistence/format/snapshot_format"
 	state_accessors "github.com/ledgerwatch/erigon/cl/persistence/state"
+	"github.com/ledgerwatch/erigon/cl/persistence/state/historical_states_reader"
 	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
 	"github.com/ledgerwatch/erigon/cl/phase1/execution_client"
 	"github.com/ledgerwatch/erigon/cl/phase1/forkchoice"
@@ -34,6 +39,8 @@ import (
 	"github.com/ledgerwatch/erigon/cl/phase1/stages"
 	"github.com/ledgerwatch/erigon/cl/pool"
 	"github.com/ledgerwatch/erigon/cl/rpc"
+	"github.com/ledgerwatch/erigon/cl/validator"
+	"github.com/ledgerwatch/erigon/cl/validator/slashing_protection"
 	"github.com/spf13/afero"
 
 	"github.com/Giulio2002/bls"
@@ -87,7 +94,7 @@ func OpenCaplinDatabase(ctx context.Context,
 func RunCaplinPhase1(ctx context.Context, sentinel sentinel.SentinelClient, engine execution_client.ExecutionEngine,
 	beaconConfig *clparams.BeaconChainConfig, genesisConfig *clparams.GenesisConfig, state *state.CachingBeaconState,
 	caplinFreezer freezer.Freezer, dirs datadir.Dirs, cfg beacon_router_configuration.RouterConfiguration, eth1Getter snapshot_format.ExecutionBlockReaderByNumber,
-	snDownloader proto_downloader.DownloaderClient, backfilling bool, states bool) error {
+	snDownloader proto_downloader.DownloaderClient, caplinConfig clparams.CaplinConfig, lightClientStore *light_client.Store) error {
 	rawDB, af := persistence.AferoRawBeaconBlockChainFromOsPath(beaconConfig, dirs.CaplinHistory)
 	beaconDB, db, err := OpenCaplinDatabase(ctx, db_config.DefaultDatabaseConfiguration, beaconConfig, rawDB, dirs.CaplinIndexing, engine, false)
 	if err != nil {
@@ -118,8 +125,9 @@ func RunCaplinPhase1(ctx context.Context, sentinel sentinel.SentinelClient, engi
 		return err
 	}
 	fcuFs := afero.NewBasePathFs(afero.NewOsFs(), caplinFcuPath)
+	emitters := beaconevents.NewEmitters()
 
-	forkChoice, err := forkchoice.NewForkChoiceStore(ctx, state, engine, caplinFreezer, pool, fork_graph.NewForkGraphDisk(state, fcuFs))
+	forkChoice, err := forkchoice.NewForkChoiceStore(ctx, state, engine, caplinFreezer, pool, fork_graph.NewForkGraphDisk(state, fcuFs), emitters, lightClientStore)
 	if err != nil {
 		logger.Error("Could not create forkchoice", "err", err)
 		return err
@@ -132,7 +140,8 @@ func RunCaplinPhase1(ctx context.Context, sentinel sentinel.SentinelClient, engi
 		}
 		return true
 	})
-	gossipManager := network.NewGossipReceiver(sentinel, forkChoice, beaconConfig, genesisConfig, caplinFreezer)
+	blobStorage := blob_storage.NewBlobStoreFromOsPath(beaconConfig, dirs.CaplinBlobs)
+	gossipManager := network.NewGossipReceiver(sentinel, forkChoice, beaconConfig, genesisConfig, caplinFreezer, emitters, blobStorage)
 	{ // start ticking forkChoice
 		go func() {
 			tickInterval := time.NewTicker(50 * time.Millisecond)
@@ -149,20 +158,6 @@ func RunCaplinPhase1(ctx context.Context, sentinel sentinel.SentinelClient, engi
 	}
 
 	syncedDataManager := synced_data.NewSyncedDataManager(cfg.Active, beaconConfig)
-	if cfg.Active {
-		apiHandler := handler.NewApiHandler(genesisConfig, beaconConfig, rawDB, db, forkChoice, pool, rcsn, syncedDataManager)
-		headApiHandler := &validatorapi.ValidatorApiHandler{
-			FC:             forkChoice,
-			BeaconChainCfg: beaconConfig,
-			GenesisCfg:     genesisConfig,
-		}
-		go beacon.ListenAndServe(&beacon.LayeredBeaconHandler{
-			ValidatorApi: headApiHandler,
-			ArchiveApi:   apiHandler,
-		}, cfg)
-		log.Info("Beacon API started", "addr", cfg.Address)
-	}
-
 	{ // start the gossip manager
 		go gossipManager.Start(ctx)
 		logger.Info("Started Ethereum 2.0 Gossip Service")
@@ -204,7 +199,7 @@ func RunCaplinPhase1(ctx context.Context, sentinel sentinel.SentinelClient, engi
 
 	vTables := state_accessors.NewStaticValidatorTable()
 	// Read the the current table
-	if states {
+	if caplinConfig.Archive {
 		if err := state_accessors.ReadValidatorsTable(tx, vTables); err != nil {
 			return err
 		}
@@ -214,7 +209,30 @@ func RunCaplinPhase1(ctx context.Context, sentinel sentinel.SentinelClient, engi
 	if err != nil {
 		return err
 	}
-	antiq := antiquary.NewAntiquary(ctx, genesisState, vTables, beaconConfig, dirs, snDownloader, db, csn, rcsn, beaconDB, logger, states, af)
+	statesReader := historical_states_reader.NewHistoricalStatesReader(beaconConfig, rcsn, vTables, af, genesisState)
+	apiHandler := handler.NewApiHandler(genesisConfig, beaconConfig, rawDB, db, forkChoice, pool, rcsn, syncedDataManager, emitters, statesReader, gossipManager, blobStorage, lightClientStore)
+	if cfg.Active {
+		headApiHandler := &validatorapi.ValidatorApiHandler{
+			FC:             forkChoice,
+			Emitters:       emitters,
+			BeaconChainCfg: beaconConfig,
+			GenesisCfg:     genesisConfig,
+		}
+		go beacon.ListenAndServe(&beacon.LayeredBeaconHandler{
+			ValidatorApi: headApiHandler,
+			ArchiveApi:   apiHandler,
+		}, cfg)
+		log.Info("Beacon API started", "addr", cfg.Address)
+	}
+	if caplinConfig.ValidatorKeystoresDir != "" {
+		validatorService, err := newValidatorService(caplinConfig, dirs, beaconConfig, genesisConfig, apiHandler, forkChoice, logger)
+		if err != nil {
+			return err
+		}
+		go validatorService.Start(ctx)
+	}
+
+	antiq := antiquary.NewAntiquary(ctx, genesisState, vTables, beaconConfig, dirs, snDownloader, db, csn, rcsn, beaconDB, logger, caplinConfig.Archive, af)
 	// Create the antiquary
 	go func() {
 		if err := antiq.Loop(); err != nil {
@@ -226,7 +244,7 @@ func RunCaplinPhase1(ctx context.Context, sentinel sentinel.SentinelClient, engi
 		return err
 	}
 
-	stageCfg := stages.ClStagesCfg(beaconRpc, antiq, genesisConfig, beaconConfig, state, engine, gossipManager, forkChoice, beaconDB, db, csn, dirs.Tmp, dbConfig, backfilling, syncedDataManager)
+	stageCfg := stages.ClStagesCfg(beaconRpc, antiq, genesisConfig, beaconConfig, state, engine, gossipManager, forkChoice, beaconDB, db, csn, dirs.Tmp, dbConfig, caplinConfig.Backfilling, syncedDataManager, blobStorage)
 	sync := stages.ConsensusClStages(ctx, stageCfg)
 
 	logger.Info("[Caplin] starting clstages loop")
@@ -237,3 +255,32 @@ func RunCaplinPhase1(ctx context.Context, sentinel sentinel.SentinelClient, engi
 	}
 	return err
 }
+
+// newValidatorService decrypts the validator keys and opens the slashing protection database of the built-in
+// validator client.
+func newValidatorService(caplinConfig clparams.CaplinConfig, dirs datadir.Dirs, beaconConfig *clparams.BeaconChainConfig, genesisConfig *clparams.GenesisConfig,
+	api validator.BeaconAPI, forkChoice forkchoice.ForkChoiceStorageReader, logger log.Logger) (*validator.Service, error) {
+	keys, err := validator.LoadKeys(caplinConfig.ValidatorKeystoresDir, caplinConfig.ValidatorPasswordFile)
+	if err != nil {
+		return nil, err
+	}
+	protectionPath := path.Join(dirs.DataDir, "caplin", "slashing_protection.json")
+	if err := os.MkdirAll(path.Dir(protectionPath), 0o755); err != nil {
+		return nil, err
+	}
+	protection, err := slashing_protection.NewSlashingProtectionFromOsPath(protectionPath, genesisConfig.GenesisValidatorRoot)
+	if err != nil {
+		return nil, err
+	}
+	if caplinConfig.ValidatorSlashingProtectionImport != "" {
+		f, err := os.Open(caplinConfig.ValidatorSlashingProtectionImport)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		if err := protection.Import(f); err != nil {
+			return nil, fmt.Errorf("importing slashing protection interchange: %w", err)
+		}
+	}
+	return validator.NewService(beaconConfig, genesisConfig, api, forkChoice, protection, keys, logger), nil
+}
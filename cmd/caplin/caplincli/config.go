@@ -39,6 +39,7 @@ type CaplinCliCfg struct {
 	EngineAPIAddr         string        `json:"engine_api_addr"`
 	EngineAPIPort         int           `json:"engine_api_port"`
 	JwtSecret             []byte
+	CaplinConfig          clparams.CaplinConfig
 
 	InitalState *state.CachingBeaconState
 	Dirs        datadir.Dirs
@@ -102,6 +103,10 @@ func SetupCaplinCli(ctx *cli.Context) (cfg *CaplinCliCfg, err error) {
 	cfg.TransitionChain = ctx.Bool(caplinflags.TransitionChainFlag.Name)
 	cfg.InitialSync = ctx.Bool(caplinflags.InitSyncFlag.Name)
 
+	cfg.CaplinConfig.ValidatorKeystoresDir = ctx.String(utils.CaplinValidatorKeystoresFlag.Name)
+	cfg.CaplinConfig.ValidatorPasswordFile = ctx.String(utils.CaplinValidatorPasswordFileFlag.Name)
+	cfg.CaplinConfig.ValidatorSlashingProtectionImport = ctx.String(utils.CaplinValidatorSlashingProtectionImportFlag.Name)
+
 	return cfg, err
 }
 
@@ -23,6 +23,9 @@ var CliFlags = []cli.Flag{
 	&EngineApiPortFlag,
 	&JwtSecret,
 	&utils.DataDirFlag,
+	&utils.CaplinValidatorKeystoresFlag,
+	&utils.CaplinValidatorPasswordFileFlag,
+	&utils.CaplinValidatorSlashingProtectionImportFlag,
 }
 
 var (
@@ -20,6 +20,7 @@ import (
 	"github.com/ledgerwatch/erigon/cl/cltypes"
 	"github.com/ledgerwatch/erigon/cl/fork"
 	freezer2 "github.com/ledgerwatch/erigon/cl/freezer"
+	"github.com/ledgerwatch/erigon/cl/light_client"
 	"github.com/ledgerwatch/erigon/cl/phase1/core"
 	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
 	execution_client2 "github.com/ledgerwatch/erigon/cl/phase1/execution_client"
@@ -79,14 +80,20 @@ func runCaplinNode(cliCtx *cli.Context) error {
 		return err
 	}
 
+	lightClientStore, err := light_client.NewStore(cfg.BeaconCfg)
+	if err != nil {
+		return err
+	}
+
 	sentinel, err := service.StartSentinelService(&sentinel.SentinelConfig{
-		IpAddr:        cfg.Addr,
-		Port:          int(cfg.Port),
-		TCPPort:       cfg.ServerTcpPort,
-		GenesisConfig: cfg.GenesisCfg,
-		NetworkConfig: cfg.NetworkCfg,
-		BeaconConfig:  cfg.BeaconCfg,
-		NoDiscovery:   cfg.NoDiscovery,
+		IpAddr:           cfg.Addr,
+		Port:             int(cfg.Port),
+		TCPPort:          cfg.ServerTcpPort,
+		GenesisConfig:    cfg.GenesisCfg,
+		NetworkConfig:    cfg.NetworkCfg,
+		BeaconConfig:     cfg.BeaconCfg,
+		NoDiscovery:      cfg.NoDiscovery,
+		LightClientStore: lightClientStore,
 	}, nil, &service.ServerConfig{Network: cfg.ServerProtocol, Addr: cfg.ServerAddr}, nil, &cltypes.Status{
 		ForkDigest:     forkDigest,
 		FinalizedRoot:  state.FinalizedCheckpoint().BlockRoot(),
@@ -128,5 +135,5 @@ func runCaplinNode(cliCtx *cli.Context) error {
 		WriteTimeout:    cfg.BeaconApiWriteTimeout,
 		IdleTimeout:     cfg.BeaconApiWriteTimeout,
 		Active:          !cfg.NoBeaconApi,
-	}, nil, nil, false, false)
+	}, nil, nil, cfg.CaplinConfig, lightClientStore)
 }
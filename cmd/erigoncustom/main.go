@@ -6,6 +6,7 @@ import (
 
 	"github.com/urfave/cli/v2"
 
+	"github.com/ledgerwatch/erigon/core/vm"
 	erigonapp "github.com/ledgerwatch/erigon/turbo/app"
 	erigoncli "github.com/ledgerwatch/erigon/turbo/cli"
 )
@@ -21,6 +22,21 @@ const (
 	customBucketName = "ch.torquem.demo.tgcustom.CUSTOM_BUCKET" //nolint
 )
 
+// echoPrecompile is a custom precompiled contract returning its input.
+// It is enabled by listing it in the "customPrecompiles" section of the chain config, e.g.
+// "customPrecompiles": {"0x0000000000000000000000000000000000000100": {"name": "echo"}}
+type echoPrecompile struct{}
+
+func (echoPrecompile) RequiredGas(input []byte) uint64  { return 15 + 3*uint64((len(input)+31)/32) }
+func (echoPrecompile) Run(input []byte) ([]byte, error) { return input, nil }
+
+// registering custom precompiles before the chain config is loaded
+func init() {
+	if err := vm.RegisterPrecompiledContract("echo", echoPrecompile{}); err != nil {
+		panic(err)
+	}
+}
+
 // the regular main function
 func main() {
 	// initializing Erigon application here and providing our custom flag
@@ -0,0 +1,364 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package t8ntool
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ledgerwatch/erigon/common/math"
+	"github.com/ledgerwatch/erigon/consensus/clique"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/rlp"
+)
+
+//go:generate gencodec -type header -field-override headerMarshaling -out gen_header.go
+type header struct {
+	ParentHash            libcommon.Hash     `json:"parentHash"`
+	OmmerHash             *libcommon.Hash    `json:"sha3Uncles"`
+	Coinbase              *libcommon.Address `json:"miner"`
+	Root                  libcommon.Hash     `json:"stateRoot"        gencodec:"required"`
+	TxHash                *libcommon.Hash    `json:"transactionsRoot"`
+	ReceiptHash           *libcommon.Hash    `json:"receiptsRoot"`
+	Bloom                 types.Bloom        `json:"logsBloom"`
+	Difficulty            *big.Int           `json:"difficulty"`
+	Number                *big.Int           `json:"number"           gencodec:"required"`
+	GasLimit              uint64             `json:"gasLimit"         gencodec:"required"`
+	GasUsed               uint64             `json:"gasUsed"`
+	Time                  uint64             `json:"timestamp"        gencodec:"required"`
+	Extra                 []byte             `json:"extraData"`
+	MixDigest             libcommon.Hash     `json:"mixHash"`
+	Nonce                 *types.BlockNonce  `json:"nonce"`
+	BaseFee               *big.Int           `json:"baseFeePerGas"`
+	WithdrawalsHash       *libcommon.Hash    `json:"withdrawalsRoot"`
+	BlobGasUsed           *uint64            `json:"blobGasUsed"`
+	ExcessBlobGas         *uint64            `json:"excessBlobGas"`
+	ParentBeaconBlockRoot *libcommon.Hash    `json:"parentBeaconBlockRoot"`
+}
+
+type headerMarshaling struct {
+	Difficulty    *math.HexOrDecimal256
+	Number        *math.HexOrDecimal256
+	GasLimit      math.HexOrDecimal64
+	GasUsed       math.HexOrDecimal64
+	Time          math.HexOrDecimal64
+	Extra         hexutility.Bytes
+	BaseFee       *math.HexOrDecimal256
+	BlobGasUsed   *math.HexOrDecimal64
+	ExcessBlobGas *math.HexOrDecimal64
+}
+
+type bbInput struct {
+	Header      *header             `json:"header,omitempty"`
+	OmmersRlp   []string            `json:"ommers,omitempty"`
+	TxRlp       string              `json:"txs,omitempty"`
+	Withdrawals []*types.Withdrawal `json:"withdrawals,omitempty"`
+	Clique      *cliqueInput        `json:"clique,omitempty"`
+
+	Txs    []types.Transaction `json:"-"`
+	Ommers []*types.Header     `json:"-"`
+}
+
+type cliqueInput struct {
+	Key       *ecdsa.PrivateKey
+	Voted     *libcommon.Address
+	Authorize *bool
+	Vanity    libcommon.Hash
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (c *cliqueInput) UnmarshalJSON(input []byte) error {
+	var x struct {
+		Key       *libcommon.Hash    `json:"secretKey"`
+		Voted     *libcommon.Address `json:"voted"`
+		Authorize *bool              `json:"authorize"`
+		Vanity    libcommon.Hash     `json:"vanity"`
+	}
+	if err := json.Unmarshal(input, &x); err != nil {
+		return err
+	}
+	if x.Key == nil {
+		return errors.New("missing required field 'secretKey' for cliqueInput")
+	}
+	if ecdsaKey, err := crypto.ToECDSA(x.Key[:]); err != nil {
+		return err
+	} else {
+		c.Key = ecdsaKey
+	}
+	c.Voted = x.Voted
+	c.Authorize = x.Authorize
+	c.Vanity = x.Vanity
+	return nil
+}
+
+// ToHeader converts i into a *types.Header, using the values derived from the body
+// for the roots which are not given explicitly.
+func (i *bbInput) ToHeader() *types.Header {
+	header := &types.Header{
+		ParentHash:            i.Header.ParentHash,
+		UncleHash:             types.EmptyUncleHash,
+		Coinbase:              libcommon.Address{},
+		Root:                  i.Header.Root,
+		TxHash:                types.EmptyRootHash,
+		ReceiptHash:           types.EmptyRootHash,
+		Bloom:                 i.Header.Bloom,
+		Difficulty:            new(big.Int),
+		Number:                i.Header.Number,
+		GasLimit:              i.Header.GasLimit,
+		GasUsed:               i.Header.GasUsed,
+		Time:                  i.Header.Time,
+		Extra:                 i.Header.Extra,
+		MixDigest:             i.Header.MixDigest,
+		BaseFee:               i.Header.BaseFee,
+		WithdrawalsHash:       i.Header.WithdrawalsHash,
+		BlobGasUsed:           i.Header.BlobGasUsed,
+		ExcessBlobGas:         i.Header.ExcessBlobGas,
+		ParentBeaconBlockRoot: i.Header.ParentBeaconBlockRoot,
+	}
+
+	// Fill optional values.
+	if i.Header.OmmerHash != nil {
+		header.UncleHash = *i.Header.OmmerHash
+	} else if len(i.Ommers) != 0 {
+		// Calculate the ommer hash if none is provided and there are ommers to hash
+		header.UncleHash = types.CalcUncleHash(i.Ommers)
+	}
+	if i.Header.Coinbase != nil {
+		header.Coinbase = *i.Header.Coinbase
+	}
+	if i.Header.TxHash != nil {
+		header.TxHash = *i.Header.TxHash
+	} else if len(i.Txs) != 0 {
+		header.TxHash = types.DeriveSha(types.Transactions(i.Txs))
+	}
+	if i.Header.ReceiptHash != nil {
+		header.ReceiptHash = *i.Header.ReceiptHash
+	}
+	if i.Header.Nonce != nil {
+		header.Nonce = *i.Header.Nonce
+	}
+	if i.Header.Difficulty != nil {
+		header.Difficulty = i.Header.Difficulty
+	}
+	if header.WithdrawalsHash == nil && i.Withdrawals != nil {
+		h := types.DeriveSha(types.Withdrawals(i.Withdrawals))
+		header.WithdrawalsHash = &h
+	}
+	return header
+}
+
+// SealHeader seals the given header using the configured engine.
+func (i *bbInput) SealHeader(header *types.Header) error {
+	switch {
+	case i.Clique != nil:
+		return i.sealClique(header)
+	default:
+		return nil
+	}
+}
+
+// sealClique seals the given header using clique.
+func (i *bbInput) sealClique(header *types.Header) error {
+	// If any clique value overwrites an explicit header value, fail
+	// to avoid silently building a block with unexpected values.
+	if i.Header.Extra != nil {
+		return errors.New("both Extra and Clique provided")
+	}
+	if i.Clique.Voted != nil {
+		if i.Header.Coinbase != nil {
+			return errors.New("both Voted and Coinbase provided")
+		}
+		header.Coinbase = *i.Clique.Voted
+	}
+	if i.Clique.Authorize != nil {
+		if i.Header.Nonce != nil {
+			return errors.New("both Authorize and Nonce provided")
+		}
+		if *i.Clique.Authorize {
+			header.Nonce = [8]byte{}
+		} else {
+			header.Nonce = [8]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+		}
+	}
+	// Extra is fixed 32 byte vanity and 65 byte signature
+	header.Extra = make([]byte, 32+65)
+	copy(header.Extra[0:32], i.Clique.Vanity.Bytes())
+
+	// Sign the seal hash and fill in the rest of the extra data
+	h := clique.SealHash(header)
+	sighash, err := crypto.Sign(h[:], i.Clique.Key)
+	if err != nil {
+		return err
+	}
+	copy(header.Extra[32:], sighash)
+	return nil
+}
+
+// BuildBlock constructs a block from the given inputs.
+func BuildBlock(ctx *cli.Context) error {
+	log.Root().SetHandler(log.LvlFilterHandler(log.LvlInfo, log.StderrHandler))
+
+	baseDir, err := createBasedir(ctx)
+	if err != nil {
+		return NewError(ErrorIO, fmt.Errorf("failed creating output basedir: %v", err))
+	}
+	inputData, err := readBlockInput(ctx)
+	if err != nil {
+		return err
+	}
+	header := inputData.ToHeader()
+	if err = inputData.SealHeader(header); err != nil {
+		return err
+	}
+	block := types.NewBlockFromStorage(header.Hash(), header, inputData.Txs, inputData.Ommers, inputData.Withdrawals)
+	return dispatchBlock(ctx, baseDir, block)
+}
+
+func readBlockInput(ctx *cli.Context) (*bbInput, error) {
+	var (
+		headerStr      = ctx.String(InputHeaderFlag.Name)
+		ommersStr      = ctx.String(InputOmmersFlag.Name)
+		withdrawalsStr = ctx.String(InputWithdrawalsFlag.Name)
+		txsStr         = ctx.String(InputTxsRlpFlag.Name)
+		cliqueStr      = ctx.String(SealCliqueFlag.Name)
+		inputData      = &bbInput{}
+	)
+	if headerStr == stdinSelector || ommersStr == stdinSelector || txsStr == stdinSelector || cliqueStr == stdinSelector || withdrawalsStr == stdinSelector {
+		decoder := json.NewDecoder(os.Stdin)
+		if err := decoder.Decode(inputData); err != nil {
+			return nil, NewError(ErrorJson, fmt.Errorf("failed unmarshaling input: %v", err))
+		}
+	}
+	if cliqueStr != stdinSelector && cliqueStr != "" {
+		var clique cliqueInput
+		if err := readFile(cliqueStr, "clique", &clique); err != nil {
+			return nil, err
+		}
+		inputData.Clique = &clique
+	}
+	if headerStr != stdinSelector {
+		var env header
+		if err := readFile(headerStr, "header", &env); err != nil {
+			return nil, err
+		}
+		inputData.Header = &env
+	}
+	if inputData.Header == nil {
+		return nil, NewError(ErrorJson, errors.New("missing block header"))
+	}
+	if ommersStr != stdinSelector && ommersStr != "" {
+		var ommers []string
+		if err := readFile(ommersStr, "ommers", &ommers); err != nil {
+			return nil, err
+		}
+		inputData.OmmersRlp = ommers
+	}
+	if withdrawalsStr != stdinSelector && withdrawalsStr != "" {
+		var withdrawals []*types.Withdrawal
+		if err := readFile(withdrawalsStr, "withdrawals", &withdrawals); err != nil {
+			return nil, err
+		}
+		inputData.Withdrawals = withdrawals
+	}
+	if txsStr != stdinSelector {
+		var txs string
+		if err := readFile(txsStr, "txs", &txs); err != nil {
+			return nil, err
+		}
+		inputData.TxRlp = txs
+	}
+	// Deserialize rlp txs and ommers
+	if inputData.TxRlp != "" {
+		txs, err := decodeTransactions(libcommon.FromHex(inputData.TxRlp))
+		if err != nil {
+			return nil, NewError(ErrorRlp, fmt.Errorf("unable to decode transaction from rlp data: %v", err))
+		}
+		inputData.Txs = txs
+	}
+	ommers := []*types.Header{}
+	for _, str := range inputData.OmmersRlp {
+		var ommer types.Block
+		if err := rlp.DecodeBytes(libcommon.FromHex(str), &ommer); err != nil {
+			return nil, NewError(ErrorRlp, fmt.Errorf("unable to decode ommer from rlp data: %v", err))
+		}
+		ommers = append(ommers, ommer.Header())
+	}
+	inputData.Ommers = ommers
+
+	return inputData, nil
+}
+
+// decodeTransactions decodes an RLP list of transactions.
+func decodeTransactions(body []byte) ([]types.Transaction, error) {
+	it, err := rlp.NewListIterator(body)
+	if err != nil {
+		return nil, err
+	}
+	var txs []types.Transaction
+	for it.Next() {
+		if err := it.Err(); err != nil {
+			return nil, err
+		}
+		tx, err := types.DecodeTransaction(it.Value())
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+	return txs, it.Err()
+}
+
+// dispatchBlock writes the output data to either stderr or stdout, or to the specified
+// files
+func dispatchBlock(ctx *cli.Context, baseDir string, block *types.Block) error {
+	raw, _ := rlp.EncodeToBytes(block)
+	type blockInfo struct {
+		Rlp  hexutility.Bytes `json:"rlp"`
+		Hash libcommon.Hash   `json:"hash"`
+	}
+	enc := blockInfo{
+		Rlp:  raw,
+		Hash: block.Hash(),
+	}
+	b, err := json.MarshalIndent(enc, "", "  ")
+	if err != nil {
+		return NewError(ErrorJson, fmt.Errorf("failed marshalling output: %v", err))
+	}
+	switch dest := ctx.String(OutputBlockFlag.Name); dest {
+	case "stdout":
+		os.Stdout.Write(b)
+		os.Stdout.WriteString("\n")
+	case "stderr":
+		os.Stderr.Write(b)
+		os.Stderr.WriteString("\n")
+	default:
+		if err := saveFile(baseDir, dest, enc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package t8ntool
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/rlp"
+	"github.com/ledgerwatch/erigon/tests"
+)
+
+type result struct {
+	Error        error
+	Address      libcommon.Address
+	Hash         libcommon.Hash
+	IntrinsicGas uint64
+}
+
+// MarshalJSON marshals as JSON with a hash.
+func (r *result) MarshalJSON() ([]byte, error) {
+	type xx struct {
+		Error        string             `json:"error,omitempty"`
+		Address      *libcommon.Address `json:"address,omitempty"`
+		Hash         *libcommon.Hash    `json:"hash,omitempty"`
+		IntrinsicGas hexutil.Uint64     `json:"intrinsicGas,omitempty"`
+	}
+	var out xx
+	if r.Error != nil {
+		out.Error = r.Error.Error()
+	}
+	if r.Address != (libcommon.Address{}) {
+		out.Address = &r.Address
+	}
+	if r.Hash != (libcommon.Hash{}) {
+		out.Hash = &r.Hash
+	}
+	out.IntrinsicGas = hexutil.Uint64(r.IntrinsicGas)
+	return json.Marshal(out)
+}
+
+// Transaction validates the RLP encoded transactions given as input, and reports their
+// senders, hashes and intrinsic gas, or why they are invalid.
+func Transaction(ctx *cli.Context) error {
+	log.Root().SetHandler(log.LvlFilterHandler(log.LvlInfo, log.StderrHandler))
+
+	var (
+		txStr       = ctx.String(InputTxsFlag.Name)
+		inputData   = &input{}
+		chainConfig *chain.Config
+	)
+	// Construct the chainconfig
+	if cConf, _, err := tests.GetChainConfig(ctx.String(ForknameFlag.Name)); err != nil {
+		return NewError(ErrorVMConfig, fmt.Errorf("failed constructing chain configuration: %v", err))
+	} else { //nolint:golint
+		chainConfig = cConf
+	}
+	// Set the chain id
+	chainConfig.ChainID = big.NewInt(ctx.Int64(ChainIDFlag.Name))
+	var body hexutility.Bytes
+	if txStr == stdinSelector {
+		decoder := json.NewDecoder(os.Stdin)
+		if err := decoder.Decode(inputData); err != nil {
+			return NewError(ErrorJson, fmt.Errorf("failed unmarshaling input: %v", err))
+		}
+		// Decode the body of already signed transactions
+		body = libcommon.FromHex(inputData.TxRlp)
+	} else {
+		// Read input from file
+		if !strings.HasSuffix(txStr, ".rlp") {
+			return NewError(ErrorIO, errors.New("only rlp supported"))
+		}
+		if err := readFile(txStr, "txs", &body); err != nil {
+			return err
+		}
+	}
+	signer := types.MakeSigner(chainConfig, 0, 0)
+	// We now have the transactions in 'body', which is supposed to be an
+	// rlp list of transactions
+	it, err := rlp.NewListIterator([]byte(body))
+	if err != nil {
+		return err
+	}
+	var results []result
+	for it.Next() {
+		if err := it.Err(); err != nil {
+			return NewError(ErrorIO, err)
+		}
+		tx, err := types.DecodeTransaction(it.Value())
+		if err != nil {
+			results = append(results, result{Error: err})
+			continue
+		}
+		r := result{Hash: tx.Hash()}
+		if sender, err := signer.Sender(tx); err != nil {
+			r.Error = err
+			results = append(results, r)
+			continue
+		} else {
+			r.Address = sender
+		}
+		// Check intrinsic gas
+		if gas, err := core.IntrinsicGas(tx.GetData(), tx.GetAccessList(), tx.GetTo() == nil,
+			chainConfig.IsHomestead(0), chainConfig.IsIstanbul(0), chainConfig.IsShanghai(0)); err != nil {
+			r.Error = err
+			results = append(results, r)
+			continue
+		} else {
+			r.IntrinsicGas = gas
+			if tx.GetGas() < gas {
+				r.Error = fmt.Errorf("%w: have %d, want %d", core.ErrIntrinsicGas, tx.GetGas(), gas)
+				results = append(results, r)
+				continue
+			}
+		}
+		// Validate the fields which are 256 bits wide, but must not overflow when multiplied by the gas
+		gas := uint256.NewInt(tx.GetGas())
+		switch {
+		case tx.GetNonce()+1 < tx.GetNonce():
+			r.Error = errors.New("nonce exceeds 2^64-1")
+		case tx.GetFeeCap().Cmp(tx.GetTip()) < 0:
+			r.Error = errors.New("maxFeePerGas < maxPriorityFeePerGas")
+		case overflowsWithGas(tx.GetPrice(), gas):
+			r.Error = errors.New("gas * gasPrice exceeds 256 bits")
+		case overflowsWithGas(tx.GetFeeCap(), gas):
+			r.Error = errors.New("gas * maxFeePerGas exceeds 256 bits")
+		}
+		// Check whether the init code size has been exceeded.
+		if chainConfig.IsShanghai(0) && tx.GetTo() == nil && len(tx.GetData()) > params.MaxInitCodeSize {
+			r.Error = errors.New("max initcode size exceeded")
+		}
+		results = append(results, r)
+	}
+	out, err := json.MarshalIndent(results, "", "  ")
+	fmt.Println(string(out))
+	return err
+}
+
+func overflowsWithGas(price, gas *uint256.Int) bool {
+	_, overflow := new(uint256.Int).MulOverflow(price, gas)
+	return overflow
+}
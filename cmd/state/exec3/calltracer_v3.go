@@ -36,6 +36,7 @@ func (ct *CallTracer) CaptureEnter(typ vm.OpCode, from libcommon.Address, to lib
 	}
 	ct.froms[from], ct.tos[to] = struct{}{}, struct{}{}
 }
+func (ct *CallTracer) IgnoresOpcodes() bool { return true }
 func (ct *CallTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
 }
 func (ct *CallTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
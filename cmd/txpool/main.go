@@ -52,10 +52,15 @@ var (
 	priceLimit    uint64
 	accountSlots  uint64
 	blobSlots     uint64
+	blobPoolLimit string
 	priceBump     uint64
 	blobPriceBump uint64
 
 	noTxGossip bool
+	policyFile string
+
+	journalLifetime  time.Duration
+	rebroadcastEvery time.Duration
 
 	commitEvery time.Duration
 )
@@ -79,10 +84,14 @@ func init() {
 	rootCmd.PersistentFlags().Uint64Var(&priceLimit, "txpool.pricelimit", txpoolcfg.DefaultConfig.MinFeeCap, "Minimum gas price (fee cap) limit to enforce for acceptance into the pool")
 	rootCmd.PersistentFlags().Uint64Var(&accountSlots, "txpool.accountslots", txpoolcfg.DefaultConfig.AccountSlots, "Minimum number of executable transaction slots guaranteed per account")
 	rootCmd.PersistentFlags().Uint64Var(&blobSlots, "txpool.blobslots", txpoolcfg.DefaultConfig.BlobSlots, "Max allowed total number of blobs (within type-3 txs) per account")
+	rootCmd.PersistentFlags().StringVar(&blobPoolLimit, utils.TxPoolBlobPoolLimitFlag.Name, utils.TxPoolBlobPoolLimitFlag.Value, utils.TxPoolBlobPoolLimitFlag.Usage)
 	rootCmd.PersistentFlags().Uint64Var(&priceBump, "txpool.pricebump", txpoolcfg.DefaultConfig.PriceBump, "Price bump percentage to replace an already existing transaction")
 	rootCmd.PersistentFlags().Uint64Var(&blobPriceBump, "txpool.blobpricebump", txpoolcfg.DefaultConfig.BlobPriceBump, "Price bump percentage to replace an existing blob (type-3) transaction")
 	rootCmd.PersistentFlags().DurationVar(&commitEvery, utils.TxPoolCommitEveryFlag.Name, utils.TxPoolCommitEveryFlag.Value, utils.TxPoolCommitEveryFlag.Usage)
 	rootCmd.PersistentFlags().BoolVar(&noTxGossip, utils.TxPoolGossipDisableFlag.Name, utils.TxPoolGossipDisableFlag.Value, utils.TxPoolGossipDisableFlag.Usage)
+	rootCmd.PersistentFlags().StringVar(&policyFile, utils.TxPoolPolicyFlag.Name, utils.TxPoolPolicyFlag.Value, utils.TxPoolPolicyFlag.Usage)
+	rootCmd.PersistentFlags().DurationVar(&journalLifetime, utils.TxPoolJournalLifetimeFlag.Name, utils.TxPoolJournalLifetimeFlag.Value, utils.TxPoolJournalLifetimeFlag.Usage)
+	rootCmd.PersistentFlags().DurationVar(&rebroadcastEvery, utils.TxPoolRebroadcastEveryFlag.Name, utils.TxPoolRebroadcastEveryFlag.Value, utils.TxPoolRebroadcastEveryFlag.Usage)
 	rootCmd.Flags().StringSliceVar(&traceSenders, utils.TxPoolTraceSendersFlag.Name, []string{}, utils.TxPoolTraceSendersFlag.Usage)
 }
 
@@ -147,9 +156,15 @@ func doTxpool(ctx context.Context, logger log.Logger) error {
 	cfg.MinFeeCap = priceLimit
 	cfg.AccountSlots = accountSlots
 	cfg.BlobSlots = blobSlots
+	if err := cfg.BlobPoolLimit.UnmarshalText([]byte(blobPoolLimit)); err != nil {
+		return fmt.Errorf("--%s: %w", utils.TxPoolBlobPoolLimitFlag.Name, err)
+	}
 	cfg.PriceBump = priceBump
 	cfg.BlobPriceBump = blobPriceBump
 	cfg.NoGossip = noTxGossip
+	cfg.PolicyFile = policyFile
+	cfg.LocalJournalLifetime = journalLifetime
+	cfg.RebroadcastEvery = rebroadcastEvery
 
 	cacheConfig := kvcache.DefaultCoherentConfig
 	cacheConfig.MetricsLabel = "txpool"
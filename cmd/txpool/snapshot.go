@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/c2h5oh/datasize"
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/grpcutil"
+	proto_txpool "github.com/ledgerwatch/erigon-lib/gointerfaces/txpool"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/turbo/debug"
+)
+
+// snapshotTx is a line of a txpool snapshot file. Rlp is the transaction in the same encoding as eth_sendRawTransaction,
+// which for blob transactions includes their sidecar (blobs, commitments and proofs).
+type snapshotTx struct {
+	SubPool string           `json:"subPool"`
+	Sender  common.Address   `json:"sender"`
+	Rlp     hexutility.Bytes `json:"rlp"`
+}
+
+var (
+	snapshotFile  string
+	maxImportSize datasize.ByteSize = 3 * datasize.MB // stay under the default 4MB limit of grpc requests
+)
+
+func init() {
+	for _, cmd := range []*cobra.Command{exportCmd, importCmd} {
+		cmd.Flags().StringVar(&txpoolApiAddr, "txpool.api.addr", "localhost:9094", "txpool service <host>:<port>, the private api of Erigon for its embedded txpool")
+		cmd.Flags().StringVar(&snapshotFile, "file", "txpool.jsonl", "snapshot file, one JSON transaction per line")
+		rootCmd.AddCommand(cmd)
+	}
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Dump all sub-pools (pending, baseFee, queued) of a running txpool to a file",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := debug.SetupCobra(cmd, "txpool")
+		client, err := connectTxPool()
+		if err != nil {
+			logger.Error(err.Error())
+			return
+		}
+		if err := exportTxs(cmd.Context(), client, snapshotFile, logger); err != nil {
+			logger.Error(err.Error())
+		}
+	},
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Add the transactions of an exported file to a running txpool, as local transactions",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := debug.SetupCobra(cmd, "txpool")
+		client, err := connectTxPool()
+		if err != nil {
+			logger.Error(err.Error())
+			return
+		}
+		if err := importTxs(cmd.Context(), client, snapshotFile, logger); err != nil {
+			logger.Error(err.Error())
+		}
+	},
+}
+
+func connectTxPool() (proto_txpool.TxpoolClient, error) {
+	creds, err := grpcutil.TLS(TLSCACert, TLSCertfile, TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to txpool: %w", err)
+	}
+	conn, err := grpcutil.Connect(creds, txpoolApiAddr)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to txpool: %w", err)
+	}
+	return proto_txpool.NewTxpoolClient(conn), nil
+}
+
+// exportTxs writes all the transactions of the pool to file, in sender and nonce order
+func exportTxs(ctx context.Context, client proto_txpool.TxpoolClient, file string, logger log.Logger) error {
+	// blobs make the reply much bigger than usual grpc messages
+	all, err := client.All(ctx, &proto_txpool.AllRequest{}, grpc.MaxCallRecvMsgSize(int(4*datasize.GB)))
+	if err != nil {
+		return err
+	}
+	lines := make([]snapshotTx, len(all.Txs))
+	nonces := make([]uint64, len(all.Txs))
+	for i, txn := range all.Txs {
+		lines[i] = snapshotTx{
+			SubPool: txn.TxnType.String(),
+			Sender:  gointerfaces.ConvertH160toAddress(txn.Sender),
+			Rlp:     txn.RlpTx,
+		}
+		decoded, err := types.DecodeWrappedTransaction(txn.RlpTx)
+		if err != nil {
+			return fmt.Errorf("transaction %d of %x: %w", i, lines[i].Sender, err)
+		}
+		nonces[i] = decoded.GetNonce()
+	}
+	// the pool does not guarantee any order, but the import needs the transactions of a sender without nonce gaps
+	order := make([]int, len(lines))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := order[i], order[j]
+		if c := bytes.Compare(lines[a].Sender[:], lines[b].Sender[:]); c != 0 {
+			return c < 0
+		}
+		return nonces[a] < nonces[b]
+	})
+
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	counts := map[string]int{}
+	for _, i := range order {
+		line := lines[i]
+		if err := enc.Encode(line); err != nil {
+			return err
+		}
+		counts[line.SubPool]++
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	logger.Info("[txpool] exported", "file", file, "txs", len(all.Txs), "by sub-pool", counts)
+	return nil
+}
+
+// importTxs adds the transactions of file to the pool as local transactions, in batches of at most maxImportSize
+func importTxs(ctx context.Context, client proto_txpool.TxpoolClient, file string, logger log.Logger) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	results := map[string]int{}
+	var batch [][]byte
+	var batchSize datasize.ByteSize
+	add := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		reply, err := client.Add(ctx, &proto_txpool.AddRequest{RlpTxs: batch})
+		if err != nil {
+			return err
+		}
+		for _, imported := range reply.Imported {
+			results[imported.String()]++
+		}
+		batch, batchSize = nil, 0
+		return nil
+	}
+	// the file is in sender and nonce order, so that the transactions of a sender are added without nonce gaps
+	dec := json.NewDecoder(bufio.NewReader(f))
+	for {
+		var line snapshotTx
+		if err := dec.Decode(&line); err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		if batchSize+datasize.ByteSize(len(line.Rlp)) > maxImportSize {
+			if err := add(); err != nil {
+				return err
+			}
+		}
+		batch, batchSize = append(batch, line.Rlp), batchSize+datasize.ByteSize(len(line.Rlp))
+	}
+	if err := add(); err != nil {
+		return err
+	}
+	logger.Info("[txpool] imported", "file", file, "results", results)
+	return nil
+}
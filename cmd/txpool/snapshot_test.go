@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/c2h5oh/datasize"
+	"github.com/holiman/uint256"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces"
+	proto_txpool "github.com/ledgerwatch/erigon-lib/gointerfaces/txpool"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/rlp"
+)
+
+// testTxpoolClient serves All from a fixed reply, and records the batches of Add
+type testTxpoolClient struct {
+	proto_txpool.TxpoolClient
+	all     *proto_txpool.AllReply
+	batches [][][]byte
+}
+
+func (c *testTxpoolClient) All(context.Context, *proto_txpool.AllRequest, ...grpc.CallOption) (*proto_txpool.AllReply, error) {
+	return c.all, nil
+}
+
+func (c *testTxpoolClient) Add(_ context.Context, in *proto_txpool.AddRequest, _ ...grpc.CallOption) (*proto_txpool.AddReply, error) {
+	c.batches = append(c.batches, in.RlpTxs)
+	return &proto_txpool.AddReply{Imported: make([]proto_txpool.ImportResult, len(in.RlpTxs))}, nil
+}
+
+func encodeTx(t *testing.T, txn types.Transaction) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, txn.MarshalBinary(&buf))
+	return buf.Bytes()
+}
+
+func dynamicFeeTx(nonce uint64) types.Transaction {
+	to := libcommon.HexToAddress("0x1234")
+	return &types.DynamicFeeTransaction{
+		CommonTx: types.CommonTx{Nonce: nonce, Gas: 21_000, To: &to, Value: uint256.NewInt(1)},
+		ChainID:  uint256.NewInt(1),
+		Tip:      uint256.NewInt(1),
+		FeeCap:   uint256.NewInt(100),
+	}
+}
+
+// encodeBlobTx encodes a blob transaction with its sidecar, as in eth_sendRawTransaction
+func encodeBlobTx(t *testing.T, txw *types.BlobTxWrapper) []byte {
+	txn := encodeTx(t, &txw.Tx)
+	wrapped, err := rlp.EncodeToBytes([]interface{}{rlp.RawValue(txn[1:]), txw.Blobs, txw.Commitments, txw.Proofs})
+	require.NoError(t, err)
+	return append([]byte{types.BlobTxType}, wrapped...)
+}
+
+func blobTx(nonce uint64) *types.BlobTxWrapper {
+	txn := types.BlobTx{
+		DynamicFeeTransaction: *dynamicFeeTx(nonce).(*types.DynamicFeeTransaction),
+		MaxFeePerBlobGas:      uint256.NewInt(10),
+		BlobVersionedHashes:   []libcommon.Hash{{0x01, 1}, {0x01, 2}},
+	}
+	wrapper := &types.BlobTxWrapper{
+		Tx:          txn,
+		Commitments: make(types.BlobKzgs, 2),
+		Blobs:       make(types.Blobs, 2),
+		Proofs:      make(types.KZGProofs, 2),
+	}
+	for i := range wrapper.Blobs {
+		wrapper.Commitments[i][0] = byte(i + 1)
+		wrapper.Blobs[i][0] = byte(i + 1)
+		wrapper.Proofs[i][0] = byte(i + 1)
+	}
+	return wrapper
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	senderA, senderB := libcommon.HexToAddress("0xa"), libcommon.HexToAddress("0xb")
+	blob := encodeBlobTx(t, blobTx(1))
+	a0, a1, a2 := encodeTx(t, dynamicFeeTx(0)), blob, encodeTx(t, dynamicFeeTx(2))
+	b5, b6 := encodeTx(t, dynamicFeeTx(5)), encodeTx(t, dynamicFeeTx(6))
+	tx := func(sender libcommon.Address, subPool proto_txpool.AllReply_TxnType, rlpTx []byte) *proto_txpool.AllReply_Tx {
+		return &proto_txpool.AllReply_Tx{Sender: gointerfaces.ConvertAddressToH160(sender), TxnType: subPool, RlpTx: rlpTx}
+	}
+	// the pool replies in no particular order
+	exported := &testTxpoolClient{all: &proto_txpool.AllReply{Txs: []*proto_txpool.AllReply_Tx{
+		tx(senderB, proto_txpool.AllReply_QUEUED, b6),
+		tx(senderA, proto_txpool.AllReply_BASE_FEE, a2),
+		tx(senderA, proto_txpool.AllReply_PENDING, a0),
+		tx(senderB, proto_txpool.AllReply_PENDING, b5),
+		tx(senderA, proto_txpool.AllReply_PENDING, a1),
+	}}}
+	file := filepath.Join(t.TempDir(), "txpool.jsonl")
+	require.NoError(t, exportTxs(context.Background(), exported, file, log.New()))
+
+	// a batch holds the blob transaction alone, with its sidecar
+	defer func(size datasize.ByteSize) { maxImportSize = size }(maxImportSize)
+	maxImportSize = datasize.ByteSize(len(blob))
+
+	imported := &testTxpoolClient{}
+	require.NoError(t, importTxs(context.Background(), imported, file, log.New()))
+	require.Equal(t, [][][]byte{{a0}, {a1}, {a2, b5, b6}}, imported.batches)
+
+	decoded, err := types.DecodeWrappedTransaction(imported.batches[1][0])
+	require.NoError(t, err)
+	require.IsType(t, &types.BlobTxWrapper{}, decoded)
+	require.Equal(t, blobTx(1).Blobs, decoded.(*types.BlobTxWrapper).Blobs)
+	require.Equal(t, blobTx(1).Proofs, decoded.(*types.BlobTxWrapper).Proofs)
+}
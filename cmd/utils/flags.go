@@ -19,8 +19,10 @@ package utils
 
 import (
 	"crypto/ecdsa"
+	"encoding/hex"
 	"fmt"
 	"math/big"
+	"os"
 	"path/filepath"
 	"runtime"
 	"strconv"
@@ -187,6 +189,11 @@ var (
 		Usage: "Max allowed total number of blobs (within type-3 txs) per account",
 		Value: txpoolcfg.DefaultConfig.BlobSlots,
 	}
+	TxPoolBlobPoolLimitFlag = cli.StringFlag{
+		Name:  "txpool.blobpoollimit",
+		Usage: "Max total size of the blob (type-3) transactions, sidecars included, which are stored on disk",
+		Value: txpoolcfg.DefaultConfig.BlobPoolLimit.String(),
+	}
 	TxPoolGlobalSlotsFlag = cli.Uint64Flag{
 		Name:  "txpool.globalslots",
 		Usage: "Maximum number of executable transaction slots for all accounts",
@@ -217,6 +224,21 @@ var (
 		Usage: "Comma separated list of addresses, whose transactions will traced in transaction pool with debug printing",
 		Value: "",
 	}
+	TxPoolPolicyFlag = cli.StringFlag{
+		Name:  "txpool.policy",
+		Usage: "JSON file with deny lists of senders, recipients and method selectors, reloaded when it changes",
+		Value: "",
+	}
+	TxPoolJournalLifetimeFlag = cli.DurationFlag{
+		Name:  "txpool.journal.lifetime",
+		Usage: "How long local transactions are journaled, to be added again after a restart or an unwind and rebroadcast until they are mined. 0 disables the journal",
+		Value: txpoolcfg.DefaultConfig.LocalJournalLifetime,
+	}
+	TxPoolRebroadcastEveryFlag = cli.DurationFlag{
+		Name:  "txpool.rebroadcast.every",
+		Usage: "Delay before rebroadcasting pending local transactions, doubled after each rebroadcast",
+		Value: txpoolcfg.DefaultConfig.RebroadcastEvery,
+	}
 	TxPoolCommitEveryFlag = cli.DurationFlag{
 		Name:  "txpool.commit.every",
 		Usage: "How often transactions should be committed to the storage",
@@ -268,6 +290,25 @@ var (
 		Name:  "miner.noverify",
 		Usage: "Disable remote sealing verification",
 	}
+	// Builder API settings
+	BuilderRelaysFlag = cli.StringFlag{
+		Name:  "builder.relays",
+		Usage: "Comma separated URL list of the relays to submit the blocks built to, enables the builder API",
+	}
+	BuilderBeaconURLFlag = cli.StringFlag{
+		Name:  "builder.beacon",
+		Usage: "URL of the beacon API, for the payload attributes of the blocks to build",
+		Value: "http://localhost:5555",
+	}
+	BuilderSecretKeyFileFlag = cli.StringFlag{
+		Name:  "builder.secretkeyfile",
+		Usage: "File with the hex encoded BLS secret key signing the bids of the builder",
+	}
+	BuilderBuildTimeFlag = cli.DurationFlag{
+		Name:  "builder.buildtime",
+		Usage: "Time spent building each block before submitting it to the relays",
+		Value: ethconfig.Defaults.Builder.BuildTime,
+	}
 	VMEnableDebugFlag = cli.BoolFlag{
 		Name:  "vmdebug",
 		Usage: "Record information useful for VM and contract debugging",
@@ -915,6 +956,21 @@ var (
 		Usage: "enables archival node in caplin (Experimental, does not work)",
 		Value: false,
 	}
+	CaplinValidatorKeystoresFlag = cli.StringFlag{
+		Name:  "caplin.validator.keystores",
+		Usage: "directory of the EIP-2335 keystores of the validators run by caplin, enables the built-in validator client",
+		Value: "",
+	}
+	CaplinValidatorPasswordFileFlag = cli.StringFlag{
+		Name:  "caplin.validator.password-file",
+		Usage: "file holding the password of the validator keystores",
+		Value: "",
+	}
+	CaplinValidatorSlashingProtectionImportFlag = cli.StringFlag{
+		Name:  "caplin.validator.slashing-protection-import",
+		Usage: "EIP-3076 slashing protection interchange file to import at startup",
+		Value: "",
+	}
 )
 
 var MetricFlags = []cli.Flag{&MetricsEnabledFlag, &MetricsHTTPFlag, &MetricsPortFlag}
@@ -1366,6 +1422,11 @@ func setTxPool(ctx *cli.Context, fullCfg *ethconfig.Config) {
 	if ctx.IsSet(TxPoolBlobSlotsFlag.Name) {
 		fullCfg.TxPool.BlobSlots = ctx.Uint64(TxPoolBlobSlotsFlag.Name)
 	}
+	if ctx.IsSet(TxPoolBlobPoolLimitFlag.Name) {
+		if err := fullCfg.TxPool.BlobPoolLimit.UnmarshalText([]byte(ctx.String(TxPoolBlobPoolLimitFlag.Name))); err != nil {
+			Fatalf("Invalid --%s: %s", TxPoolBlobPoolLimitFlag.Name, err)
+		}
+	}
 	if ctx.IsSet(TxPoolGlobalSlotsFlag.Name) {
 		cfg.GlobalSlots = ctx.Uint64(TxPoolGlobalSlotsFlag.Name)
 	}
@@ -1393,6 +1454,15 @@ func setTxPool(ctx *cli.Context, fullCfg *ethconfig.Config) {
 	if ctx.IsSet(TxPoolBlobPriceBumpFlag.Name) {
 		fullCfg.TxPool.BlobPriceBump = ctx.Uint64(TxPoolBlobPriceBumpFlag.Name)
 	}
+	if ctx.IsSet(TxPoolPolicyFlag.Name) {
+		fullCfg.TxPool.PolicyFile = ctx.String(TxPoolPolicyFlag.Name)
+	}
+	if ctx.IsSet(TxPoolJournalLifetimeFlag.Name) {
+		fullCfg.TxPool.LocalJournalLifetime = ctx.Duration(TxPoolJournalLifetimeFlag.Name)
+	}
+	if ctx.IsSet(TxPoolRebroadcastEveryFlag.Name) {
+		fullCfg.TxPool.RebroadcastEvery = ctx.Duration(TxPoolRebroadcastEveryFlag.Name)
+	}
 	cfg.CommitEvery = common2.RandomizeDuration(ctx.Duration(TxPoolCommitEveryFlag.Name))
 }
 
@@ -1512,6 +1582,26 @@ func setMiner(ctx *cli.Context, cfg *params.MiningConfig) {
 	}
 }
 
+func setBuilder(ctx *cli.Context, cfg *ethconfig.BuilderConfig) {
+	if !ctx.IsSet(BuilderRelaysFlag.Name) {
+		return
+	}
+	cfg.Relays = libcommon.CliString2Array(ctx.String(BuilderRelaysFlag.Name))
+	cfg.BeaconURL = ctx.String(BuilderBeaconURLFlag.Name)
+	cfg.BuildTime = ctx.Duration(BuilderBuildTimeFlag.Name)
+	if !ctx.IsSet(BuilderSecretKeyFileFlag.Name) {
+		Fatalf("Flag --%s is required with --%s", BuilderSecretKeyFileFlag.Name, BuilderRelaysFlag.Name)
+	}
+	data, err := os.ReadFile(ctx.String(BuilderSecretKeyFileFlag.Name))
+	if err != nil {
+		Fatalf("Option %s: %v", BuilderSecretKeyFileFlag.Name, err)
+	}
+	cfg.SecretKey, err = hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(data)), "0x"))
+	if err != nil || len(cfg.SecretKey) != 32 {
+		Fatalf("Option %s: expected a hex encoded 32 bytes key", BuilderSecretKeyFileFlag.Name)
+	}
+}
+
 func setWhitelist(ctx *cli.Context, cfg *ethconfig.Config) {
 	whitelist := ctx.String(WhitelistFlag.Name)
 	if whitelist == "" {
@@ -1547,6 +1637,9 @@ func setBeaconAPI(ctx *cli.Context, cfg *ethconfig.Config) {
 func setCaplin(ctx *cli.Context, cfg *ethconfig.Config) {
 	cfg.CaplinConfig.Backfilling = ctx.Bool(CaplinBackfillingFlag.Name) || ctx.Bool(CaplinArchiveFlag.Name)
 	cfg.CaplinConfig.Archive = ctx.Bool(CaplinArchiveFlag.Name)
+	cfg.CaplinConfig.ValidatorKeystoresDir = ctx.String(CaplinValidatorKeystoresFlag.Name)
+	cfg.CaplinConfig.ValidatorPasswordFile = ctx.String(CaplinValidatorPasswordFileFlag.Name)
+	cfg.CaplinConfig.ValidatorSlashingProtectionImport = ctx.String(CaplinValidatorSlashingProtectionImportFlag.Name)
 }
 
 func setSilkworm(ctx *cli.Context, cfg *ethconfig.Config) {
@@ -1660,6 +1753,7 @@ func SetEthConfig(ctx *cli.Context, nodeConfig *nodecfg.Config, cfg *ethconfig.C
 	setEthash(ctx, nodeConfig.Dirs.DataDir, cfg)
 	setClique(ctx, &cfg.Clique, nodeConfig.Dirs.DataDir)
 	setMiner(ctx, &cfg.Miner)
+	setBuilder(ctx, &cfg.Builder)
 	setWhitelist(ctx, cfg)
 	setBorConfig(ctx, cfg)
 	setSilkworm(ctx, cfg)
@@ -0,0 +1,42 @@
+package verkletrie
+
+import (
+	"fmt"
+
+	"github.com/gballet/go-verkle"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/ledgerwatch/erigon/core/rawdb"
+)
+
+// GenerateWitness builds the execution witness of the given keys against the verkle
+// tree with the given root: the serialized multiproof and the pre-state values of
+// the keys. Only the nodes on the paths of the keys are loaded from the database.
+func GenerateWitness(tx kv.RwTx, root libcommon.Hash, keys [][]byte) ([]byte, []verkle.KeyValuePair, error) {
+	if len(keys) == 0 {
+		return nil, nil, nil
+	}
+	rootNode, err := rawdb.ReadVerkleNode(tx, root)
+	if err != nil {
+		return nil, nil, err
+	}
+	resolverFunc := func(root []byte) ([]byte, error) {
+		return tx.GetOne(kv.VerkleTrie, root)
+	}
+
+	keyVals := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		// Get resolves the hashed nodes along the path, so that the proof can be built from memory
+		value, err := rootNode.Get(key, resolverFunc)
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading verkle key %x: %w", key, err)
+		}
+		keyVals[string(key)] = value
+	}
+	proof, _, _, _, err := verkle.MakeVerkleMultiProof(rootNode, keys, keyVals)
+	if err != nil {
+		return nil, nil, err
+	}
+	return verkle.SerializeProof(proof)
+}
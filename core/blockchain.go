@@ -70,6 +70,7 @@ type EphemeralExecResult struct {
 	Difficulty       *math.HexOrDecimal256 `json:"currentDifficulty" gencodec:"required"`
 	GasUsed          math.HexOrDecimal64   `json:"gasUsed"`
 	StateSyncReceipt *types.Receipt        `json:"-"`
+	Witness          *vm.AccessWitness     `json:"-"` // verkle leaves accessed by the block (EIP-4762), nil before the Verkle fork
 }
 
 // ExecuteBlockEphemerally runs a block from provided stateReader and
@@ -103,6 +104,11 @@ func ExecuteBlockEphemerally(
 		return nil, err
 	}
 
+	vmConfig.Witness = nil
+	if chainConfig.IsVerkle(header.Time) {
+		vmConfig.Witness = vm.NewAccessWitness()
+	}
+
 	noop := state.NewNoopWriter()
 	//fmt.Printf("====txs processing start: %d====\n", block.NumberU64())
 	for i, tx := range block.Transactions() {
@@ -137,17 +143,33 @@ func ExecuteBlockEphemerally(
 		}
 	}
 
+	return finishBlockExecution(chainConfig, vmConfig, engine, block, stateReader, stateWriter, chainReader, ibs,
+		includedTxs, receipts, rejectedTxs, *usedGas, *usedBlobGas, logger)
+}
+
+// finishBlockExecution checks the results of the execution of the block transactions against the header,
+// finalizes the block and writes the resulting state to the stateWriter.
+func finishBlockExecution(
+	chainConfig *chain.Config, vmConfig *vm.Config,
+	engine consensus.Engine, block *types.Block,
+	stateReader state.StateReader, stateWriter state.WriterWithChangeSets,
+	chainReader consensus.ChainReader, ibs *state.IntraBlockState,
+	includedTxs types.Transactions, receipts types.Receipts, rejectedTxs []*RejectedTx,
+	usedGas, usedBlobGas uint64,
+	logger log.Logger,
+) (*EphemeralExecResult, error) {
+	header := block.Header()
 	receiptSha := types.DeriveSha(receipts)
 	if !vmConfig.StatelessExec && chainConfig.IsByzantium(header.Number.Uint64()) && !vmConfig.NoReceipts && receiptSha != block.ReceiptHash() {
 		return nil, fmt.Errorf("mismatched receipt headers for block %d (%s != %s)", block.NumberU64(), receiptSha.Hex(), block.ReceiptHash().Hex())
 	}
 
-	if !vmConfig.StatelessExec && *usedGas != header.GasUsed {
-		return nil, fmt.Errorf("gas used by execution: %d, in header: %d", *usedGas, header.GasUsed)
+	if !vmConfig.StatelessExec && usedGas != header.GasUsed {
+		return nil, fmt.Errorf("gas used by execution: %d, in header: %d", usedGas, header.GasUsed)
 	}
 
-	if header.BlobGasUsed != nil && *usedBlobGas != *header.BlobGasUsed {
-		return nil, fmt.Errorf("blob gas used by execution: %d, in header: %d", *usedBlobGas, *header.BlobGasUsed)
+	if header.BlobGasUsed != nil && usedBlobGas != *header.BlobGasUsed {
+		return nil, fmt.Errorf("blob gas used by execution: %d, in header: %d", usedBlobGas, *header.BlobGasUsed)
 	}
 
 	var bloom types.Bloom
@@ -171,8 +193,9 @@ func ExecuteBlockEphemerally(
 		LogsHash:    rlpHash(blockLogs),
 		Receipts:    receipts,
 		Difficulty:  (*math.HexOrDecimal256)(header.Difficulty),
-		GasUsed:     math.HexOrDecimal64(*usedGas),
+		GasUsed:     math.HexOrDecimal64(usedGas),
 		Rejected:    rejectedTxs,
+		Witness:     vmConfig.Witness,
 	}
 
 	if chainConfig.Bor != nil {
@@ -311,11 +334,15 @@ func FinalizeBlockExecution(
 
 func InitializeBlockExecution(engine consensus.Engine, chain consensus.ChainHeaderReader, header *types.Header,
 	cc *chain.Config, ibs *state.IntraBlockState, logger log.Logger,
+) error {
+	return initializeBlockExecution(engine, chain, header, cc, ibs, state.NewNoopWriter(), logger)
+}
+
+func initializeBlockExecution(engine consensus.Engine, chain consensus.ChainHeaderReader, header *types.Header,
+	cc *chain.Config, ibs *state.IntraBlockState, stateWriter state.StateWriter, logger log.Logger,
 ) error {
 	engine.Initialize(cc, chain, header, ibs, func(contract libcommon.Address, data []byte, ibState *state.IntraBlockState, header *types.Header, constCall bool) ([]byte, error) {
 		return SysCallContract(contract, data, cc, ibState, header, engine, constCall)
 	}, logger)
-	noop := state.NewNoopWriter()
-	ibs.FinalizeTx(cc.Rules(header.Number.Uint64(), header.Time), noop)
-	return nil
+	return ibs.FinalizeTx(cc.Rules(header.Number.Uint64(), header.Time), stateWriter)
 }
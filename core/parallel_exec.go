@@ -0,0 +1,310 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/core/vm/evmtypes"
+)
+
+// ParallelLogger is an EVMLogger which can trace the parallel execution of a block: every speculative
+// execution of a transaction is traced by a child logger, which is merged into the parent logger if
+// the execution is committed. Transactions re-executed serially are traced by the parent logger.
+type ParallelLogger interface {
+	vm.EVMLogger
+	NewChild() vm.EVMLogger
+	MergeChild(child vm.EVMLogger)
+}
+
+var errParallelExecutionAborted = errors.New("parallel execution aborted")
+
+// ExecuteBlockParallel is ExecuteBlockEphemerally executing the transactions of the block optimistically
+// in parallel, by the given number of workers. Every transaction is executed speculatively on the state
+// committed so far (see state.VersionedState), then the executions are committed in order. An execution
+// which read state written by a transaction committed after it started, or which failed, is discarded and
+// the transaction is re-executed serially, so the result is always the same as ExecuteBlockEphemerally's.
+//
+// Blocks which can't be executed in parallel (see canExecuteInParallel) are executed by ExecuteBlockEphemerally.
+// The stateReader is only used from the calling goroutine, so it can be bound to a database transaction.
+func ExecuteBlockParallel(
+	chainConfig *chain.Config, vmConfig *vm.Config,
+	blockHashFunc func(n uint64) libcommon.Hash,
+	engine consensus.Engine, block *types.Block,
+	stateReader state.StateReader, stateWriter state.WriterWithChangeSets,
+	chainReader consensus.ChainReader, getTracer func(txIndex int, txHash libcommon.Hash) (vm.EVMLogger, error),
+	workers int,
+	logger log.Logger,
+) (*EphemeralExecResult, error) {
+	if !canExecuteInParallel(chainConfig, vmConfig, block, workers) {
+		return ExecuteBlockEphemerally(chainConfig, vmConfig, blockHashFunc, engine, block, stateReader, stateWriter, chainReader, getTracer, logger)
+	}
+
+	defer blockExecutionTimer.ObserveDuration(time.Now())
+	ibs := state.New(stateReader)
+	header := block.Header()
+	txs := block.Transactions()
+	rules := chainConfig.Rules(header.Number.Uint64(), header.Time)
+
+	var usedGas, usedBlobGas uint64
+	gp := new(GasPool)
+	gp.AddGas(block.GasLimit()).AddBlobGas(chainConfig.GetMaxBlobGasPerBlock())
+
+	vs := state.NewVersionedState()
+	// the changes made before the first transaction are part of the state the transactions start from
+	if err := initializeBlockExecution(engine, chainReader, header, chainConfig, ibs, vs.Writer(0), logger); err != nil {
+		return nil, err
+	}
+	vmConfig.Witness = nil
+
+	requests := make(chan func())
+	quit := make(chan struct{})
+	e := &parallelExecutor{
+		chainConfig:  chainConfig,
+		vmConfig:     *vmConfig,
+		header:       header,
+		blockHash:    block.Hash(),
+		txs:          txs,
+		rules:        rules,
+		signer:       types.MakeSigner(chainConfig, header.Number.Uint64(), header.Time),
+		vs:           vs,
+		base:         &mainThreadReader{reader: stateReader, requests: requests, quit: quit},
+		speculations: make([]*speculation, len(txs)),
+		done:         make(chan int, len(txs)),
+	}
+	e.vmConfig.SkipAnalysis = SkipAnalysis(chainConfig, header.Number.Uint64())
+	e.blockContext = NewEVMBlockContext(header, func(n uint64) (hash libcommon.Hash) {
+		if err := e.base.run(func() { hash = blockHashFunc(n) }); err != nil {
+			panic(err) // the speculation is discarded
+		}
+		return hash
+	}, engine, nil)
+
+	jobs := make(chan int, len(txs))
+	for i := range txs {
+		jobs <- i
+	}
+	close(jobs)
+	if workers > len(txs) {
+		workers = len(txs)
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				select {
+				case <-quit:
+					return
+				default:
+				}
+				e.speculations[i] = e.speculate(i)
+				e.done <- i
+			}
+		}()
+	}
+	defer func() {
+		close(quit)
+		wg.Wait()
+	}()
+
+	ready := make([]bool, len(txs))
+	receipts := make(types.Receipts, 0, len(txs))
+	parentLogger, _ := vmConfig.Tracer.(ParallelLogger)
+	for i, tx := range txs {
+		for !ready[i] {
+			select {
+			case request := <-requests:
+				request()
+			case j := <-e.done:
+				ready[j] = true
+			}
+		}
+		s := e.speculations[i]
+
+		ibs.SetTxContext(tx.Hash(), block.Hash(), i)
+		writer := vs.Writer(i + 1)
+		var receipt *types.Receipt
+		if s.err == nil && gp.Gas() >= tx.GetGas() && gp.BlobGas() >= tx.GetBlobGas() && vs.Validate(s.reader) {
+			if err := gp.SubGas(s.result.UsedGas); err != nil {
+				return nil, err
+			}
+			if err := gp.SubBlobGas(tx.GetBlobGas()); err != nil {
+				return nil, err
+			}
+			ibs.ApplyTxChanges(s.ibs)
+			if err := ibs.FinalizeTx(rules, writer); err != nil {
+				return nil, err
+			}
+			usedGas += s.result.UsedGas
+			usedBlobGas += tx.GetBlobGas()
+			if !vmConfig.NoReceipts {
+				receipt = newReceipt(header, tx, s.msg, s.result, usedGas, ibs)
+			}
+			if parentLogger != nil {
+				parentLogger.MergeChild(s.tracer)
+			}
+		} else {
+			var err error
+			receipt, _, err = ApplyTransaction(chainConfig, blockHashFunc, engine, nil, gp, ibs, writer, header, tx, &usedGas, &usedBlobGas, *vmConfig)
+			if err != nil {
+				return nil, fmt.Errorf("could not apply tx %d from block %d [%v]: %w", i, block.NumberU64(), tx.Hash().Hex(), err)
+			}
+		}
+		e.speculations[i] = nil
+		if !vmConfig.NoReceipts {
+			receipts = append(receipts, receipt)
+		}
+	}
+
+	return finishBlockExecution(chainConfig, vmConfig, engine, block, stateReader, stateWriter, chainReader, ibs,
+		txs, receipts, nil, usedGas, usedBlobGas, logger)
+}
+
+// canExecuteInParallel returns true if the transactions of the block can be executed by ExecuteBlockParallel.
+// Bor and AuRa blocks are executed serially because of their system transactions, Verkle blocks because of
+// the access witness.
+func canExecuteInParallel(chainConfig *chain.Config, vmConfig *vm.Config, block *types.Block, workers int) bool {
+	if workers < 2 || len(block.Transactions()) < 2 {
+		return false
+	}
+	if chainConfig.Bor != nil || chainConfig.Aura != nil || chainConfig.IsVerkle(block.Time()) {
+		return false
+	}
+	if vmConfig.StatelessExec {
+		return false
+	}
+	// per transaction tracers from getTracer, and tracers which can't follow the speculative executions,
+	// only work serially
+	if vmConfig.Debug || vmConfig.Tracer != nil {
+		if _, ok := vmConfig.Tracer.(ParallelLogger); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+type parallelExecutor struct {
+	chainConfig  *chain.Config
+	vmConfig     vm.Config
+	header       *types.Header
+	blockHash    libcommon.Hash
+	blockContext evmtypes.BlockContext
+	txs          types.Transactions
+	rules        *chain.Rules
+	signer       *types.Signer
+	vs           *state.VersionedState
+	base         *mainThreadReader
+	speculations []*speculation
+	done         chan int
+}
+
+// speculation is the result of the speculative execution of a transaction.
+type speculation struct {
+	ibs    *state.IntraBlockState
+	reader *state.VersionedReader
+	msg    types.Message
+	result *ExecutionResult
+	tracer vm.EVMLogger
+	err    error
+}
+
+func (e *parallelExecutor) speculate(txIndex int) (s *speculation) {
+	tx := e.txs[txIndex]
+	s = &speculation{reader: state.NewVersionedReader(e.vs, e.base)}
+	defer func() {
+		// the execution may have observed inconsistent state
+		if r := recover(); r != nil {
+			s.err = fmt.Errorf("speculative execution of tx %d panicked: %v", txIndex, r)
+		}
+	}()
+	s.ibs = state.New(s.reader)
+	s.ibs.SetTxContext(tx.Hash(), e.blockHash, txIndex)
+
+	cfg := e.vmConfig
+	if logger, ok := cfg.Tracer.(ParallelLogger); ok {
+		s.tracer = logger.NewChild()
+		cfg.Tracer = s.tracer
+	}
+	s.msg, s.err = tx.AsMessage(*e.signer, e.header.BaseFee, e.rules)
+	if s.err != nil {
+		return s
+	}
+	s.msg.SetCheckNonce(true)
+	txContext := NewEVMTxContext(s.msg)
+	if cfg.TraceJumpDest {
+		txContext.TxHash = tx.Hash()
+	}
+	evm := vm.NewEVM(e.blockContext, txContext, s.ibs, e.chainConfig, cfg)
+	gp := new(GasPool).AddGas(tx.GetGas()).AddBlobGas(tx.GetBlobGas())
+	if s.result, s.err = ApplyMessage(evm, s.msg, gp, true /* refunds */, false /* gasBailout */); s.err != nil {
+		return s
+	}
+	s.err = s.ibs.Error()
+	return s
+}
+
+// mainThreadReader serves the reads of the workers from the state reader of the goroutine executing
+// the block, which may be bound to a database transaction.
+type mainThreadReader struct {
+	reader   state.StateReader
+	requests chan<- func()
+	quit     <-chan struct{}
+}
+
+func (r *mainThreadReader) run(f func()) error {
+	done := make(chan struct{})
+	select {
+	case r.requests <- func() { f(); close(done) }:
+	case <-r.quit:
+		return errParallelExecutionAborted
+	}
+	<-done
+	return nil
+}
+
+func (r *mainThreadReader) ReadAccountData(address libcommon.Address) (account *accounts.Account, err error) {
+	if err1 := r.run(func() { account, err = r.reader.ReadAccountData(address) }); err1 != nil {
+		return nil, err1
+	}
+	return account, err
+}
+
+func (r *mainThreadReader) ReadAccountStorage(address libcommon.Address, incarnation uint64, key *libcommon.Hash) (enc []byte, err error) {
+	if err1 := r.run(func() { enc, err = r.reader.ReadAccountStorage(address, incarnation, key) }); err1 != nil {
+		return nil, err1
+	}
+	return enc, err
+}
+
+func (r *mainThreadReader) ReadAccountCode(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash) (code []byte, err error) {
+	if err1 := r.run(func() { code, err = r.reader.ReadAccountCode(address, incarnation, codeHash) }); err1 != nil {
+		return nil, err1
+	}
+	return code, err
+}
+
+func (r *mainThreadReader) ReadAccountCodeSize(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash) (size int, err error) {
+	if err1 := r.run(func() { size, err = r.reader.ReadAccountCodeSize(address, incarnation, codeHash) }); err1 != nil {
+		return 0, err1
+	}
+	return size, err
+}
+
+func (r *mainThreadReader) ReadAccountIncarnation(address libcommon.Address) (incarnation uint64, err error) {
+	if err1 := r.run(func() { incarnation, err = r.reader.ReadAccountIncarnation(address) }); err1 != nil {
+		return 0, err1
+	}
+	return incarnation, err
+}
@@ -0,0 +1,151 @@
+package core_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/holiman/uint256"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/eth/calltracer"
+	"github.com/ledgerwatch/erigon/eth/stagedsync"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/turbo/stages/mock"
+)
+
+type recordingWriter struct {
+	writes []string
+}
+
+func (w *recordingWriter) UpdateAccountData(address libcommon.Address, original, account *accounts.Account) error {
+	w.writes = append(w.writes, fmt.Sprintf("account %x: nonce %d balance %d incarnation %d code %x", address, account.Nonce, &account.Balance, account.Incarnation, account.CodeHash))
+	return nil
+}
+
+func (w *recordingWriter) UpdateAccountCode(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash, code []byte) error {
+	w.writes = append(w.writes, fmt.Sprintf("code %x/%d: %x", address, incarnation, code))
+	return nil
+}
+
+func (w *recordingWriter) DeleteAccount(address libcommon.Address, original *accounts.Account) error {
+	w.writes = append(w.writes, fmt.Sprintf("delete %x", address))
+	return nil
+}
+
+func (w *recordingWriter) WriteAccountStorage(address libcommon.Address, incarnation uint64, key *libcommon.Hash, original, value *uint256.Int) error {
+	w.writes = append(w.writes, fmt.Sprintf("storage %x/%d/%x: %d -> %d", address, incarnation, *key, original, value))
+	return nil
+}
+
+func (w *recordingWriter) CreateContract(address libcommon.Address) error {
+	w.writes = append(w.writes, fmt.Sprintf("create %x", address))
+	return nil
+}
+
+func (w *recordingWriter) WriteChangeSets() error { return nil }
+func (w *recordingWriter) WriteHistory() error    { return nil }
+
+func TestExecuteBlockParallel(t *testing.T) {
+	t.Parallel()
+	var keys [8]*ecdsa.PrivateKey
+	var addrs [8]libcommon.Address
+	alloc := types.GenesisAlloc{}
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		addrs[i] = crypto.PubkeyToAddress(keys[i].PublicKey)
+		alloc[addrs[i]] = types.GenesisAccount{Balance: big.NewInt(params.Ether)}
+	}
+	// increments the counter in slot 0 and emits an empty log
+	counterCode := libcommon.FromHex("60005460010160005560006000a000")
+	counter := libcommon.HexToAddress("0xc0")
+	alloc[counter] = types.GenesisAccount{Code: counterCode, Balance: new(big.Int)}
+
+	gspec := &types.Genesis{Config: params.TestChainConfig, Alloc: alloc}
+	m := mock.MockWithGenesis(t, gspec, keys[0], false)
+	signer := types.LatestSignerForChainID(m.ChainConfig.ChainID)
+
+	chain, err := core.GenerateChain(m.ChainConfig, m.Genesis, m.Engine, m.DB, 1, func(i int, block *core.BlockGen) {
+		block.SetCoinbase(addrs[7])
+		send := func(key int, to *libcommon.Address, value uint64, data []byte) {
+			var tx types.Transaction
+			if to == nil {
+				tx = types.NewContractCreation(block.TxNonce(addrs[key]), uint256.NewInt(value), 200_000, uint256.NewInt(params.GWei), data)
+			} else {
+				tx = types.NewTransaction(block.TxNonce(addrs[key]), *to, uint256.NewInt(value), 100_000, uint256.NewInt(params.GWei), data)
+			}
+			signed, err := types.SignTx(tx, *signer, keys[key])
+			require.NoError(t, err)
+			block.AddTx(signed)
+		}
+		for j := 0; j < 4; j++ {
+			// transactions of the same sender
+			send(0, &addrs[j+1], 1000, nil)
+			// independent transfers
+			to := libcommon.BigToAddress(big.NewInt(int64(0x100 + j)))
+			send(j+1, &to, 1, nil)
+			// storage conflicts
+			send(5, &counter, 0, nil)
+		}
+		// a contract created and called in the block
+		created := crypto.CreateAddress(addrs[6], block.TxNonce(addrs[6]))
+		send(6, nil, 0, counterDeployment(counterCode))
+		send(6, &created, 0, nil)
+		send(0, &created, 0, nil)
+		// the coinbase spending its fees
+		send(7, &addrs[1], 1, nil)
+	})
+	require.NoError(t, err)
+	block := chain.Blocks[0]
+
+	executeWith := func(workers int, vmConfig vm.Config, getTracer func(int, libcommon.Hash) (vm.EVMLogger, error)) (*core.EphemeralExecResult, []string) {
+		tx, err := m.DB.BeginRw(context.Background())
+		require.NoError(t, err)
+		defer tx.Rollback()
+		writer := &recordingWriter{}
+		getHashFn := core.GetHashFn(block.Header(), func(hash libcommon.Hash, number uint64) *types.Header { return nil })
+		chainReader := stagedsync.ChainReader{Cfg: *m.ChainConfig, Db: tx, BlockReader: m.BlockReader}
+		res, err := core.ExecuteBlockParallel(m.ChainConfig, &vmConfig, getHashFn, m.Engine, block, state.NewPlainStateReader(tx), writer, chainReader, getTracer, workers, log.New())
+		require.NoError(t, err)
+		sort.Strings(writer.writes)
+		return res, writer.writes
+	}
+	execute := func(workers int) (*core.EphemeralExecResult, []string) {
+		return executeWith(workers, vm.Config{Debug: true, Tracer: calltracer.NewCallTracer()}, nil)
+	}
+	serial, serialWrites := execute(1)
+	for i := 0; i < 10; i++ {
+		parallel, parallelWrites := execute(4)
+		require.Equal(t, serialWrites, parallelWrites)
+		require.Equal(t, serial.ReceiptRoot, parallel.ReceiptRoot)
+		require.Equal(t, serial.Receipts, parallel.Receipts)
+	}
+
+	// per transaction tracers only work serially, the block is executed serially instead
+	var traced []libcommon.Hash
+	getTracer := func(txIndex int, txHash libcommon.Hash) (vm.EVMLogger, error) {
+		traced = append(traced, txHash)
+		return calltracer.NewCallTracer(), nil
+	}
+	traceRes, traceWrites := executeWith(4, vm.Config{Debug: true}, getTracer)
+	require.Equal(t, len(block.Transactions()), len(traced))
+	require.Equal(t, serialWrites, traceWrites)
+	require.Equal(t, serial.ReceiptRoot, traceRes.ReceiptRoot)
+}
+
+// counterDeployment returns the init code deploying the given code, up to 32 bytes long.
+func counterDeployment(code []byte) []byte {
+	deployment := append([]byte{byte(vm.PUSH1) + byte(len(code)) - 1}, code...)
+	return append(deployment, byte(vm.PUSH1), 0, byte(vm.MSTORE), byte(vm.PUSH1), byte(len(code)), byte(vm.PUSH1), byte(32-len(code)), byte(vm.RETURN))
+}
@@ -1500,3 +1500,45 @@ func ReadDBSchemaVersion(tx kv.Tx) (major, minor, patch uint32, ok bool, err err
 	patch = binary.BigEndian.Uint32(existingVersion[8:])
 	return major, minor, patch, true, nil
 }
+
+type verkleWitness struct {
+	Proof   []byte
+	KeyVals []verkle.KeyValuePair
+}
+
+// WriteVerkleWitness stores the execution witness of a block: the serialized verkle multiproof
+// and the pre-state values of the accessed keys.
+func WriteVerkleWitness(tx kv.RwTx, blockNum uint64, proof []byte, keyVals []verkle.KeyValuePair) error {
+	encoded, err := rlp.EncodeToBytes(&verkleWitness{Proof: proof, KeyVals: keyVals})
+	if err != nil {
+		return err
+	}
+	return tx.Put(kv.VerkleWitnesses, hexutility.EncodeTs(blockNum), encoded)
+}
+
+// ReadVerkleWitness returns the execution witness of a block, or nil if it wasn't generated.
+// Witnesses are only generated at the chain tip, see eth/stagedsync/README.md.
+func ReadVerkleWitness(tx kv.Getter, blockNum uint64) ([]byte, []verkle.KeyValuePair, error) {
+	encoded, err := tx.GetOne(kv.VerkleWitnesses, hexutility.EncodeTs(blockNum))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(encoded) == 0 {
+		return nil, nil, nil
+	}
+	var witness verkleWitness
+	if err := rlp.DecodeBytes(encoded, &witness); err != nil {
+		return nil, nil, fmt.Errorf("invalid verkle witness RLP for block %d: %w", blockNum, err)
+	}
+	return witness.Proof, witness.KeyVals, nil
+}
+
+// TruncateVerkleWitnesses - remove witnesses of blocks >= blockFrom
+func TruncateVerkleWitnesses(tx kv.RwTx, blockFrom uint64) error {
+	if err := tx.ForEach(kv.VerkleWitnesses, hexutility.EncodeTs(blockFrom), func(k, _ []byte) error {
+		return tx.Delete(kv.VerkleWitnesses, k)
+	}); err != nil {
+		return fmt.Errorf("TruncateVerkleWitnesses: %w", err)
+	}
+	return nil
+}
@@ -141,6 +141,45 @@ func (sdb *IntraBlockState) Reset() {
 	sdb.logSize = 0
 }
 
+// Copy returns an independent copy of the state, reading from the same state reader. The journal is not copied, so
+// it must be called between transactions.
+func (sdb *IntraBlockState) Copy() *IntraBlockState {
+	cpy := &IntraBlockState{
+		stateReader:       sdb.stateReader,
+		stateObjects:      make(map[libcommon.Address]*stateObject, len(sdb.stateObjects)),
+		stateObjectsDirty: make(map[libcommon.Address]struct{}, len(sdb.stateObjectsDirty)),
+		nilAccounts:       make(map[libcommon.Address]struct{}, len(sdb.nilAccounts)),
+		savedErr:          sdb.savedErr,
+		thash:             sdb.thash,
+		bhash:             sdb.bhash,
+		txIndex:           sdb.txIndex,
+		logs:              make(map[libcommon.Hash][]*types.Log, len(sdb.logs)),
+		logSize:           sdb.logSize,
+		accessList:        newAccessList(),
+		transientStorage:  newTransientStorage(),
+		journal:           newJournal(),
+		trace:             sdb.trace,
+		balanceInc:        make(map[libcommon.Address]*BalanceIncrease, len(sdb.balanceInc)),
+	}
+	for addr, so := range sdb.stateObjects {
+		cpy.stateObjects[addr] = so.deepCopy(cpy)
+	}
+	for addr := range sdb.stateObjectsDirty {
+		cpy.stateObjectsDirty[addr] = struct{}{}
+	}
+	for addr := range sdb.nilAccounts {
+		cpy.nilAccounts[addr] = struct{}{}
+	}
+	for hash, logs := range sdb.logs {
+		cpy.logs[hash] = append([]*types.Log(nil), logs...)
+	}
+	for addr, bi := range sdb.balanceInc {
+		bi := *bi
+		cpy.balanceInc[addr] = &bi
+	}
+	return cpy
+}
+
 func (sdb *IntraBlockState) AddLog(log2 *types.Log) {
 	sdb.journal.append(addLogChange{txhash: sdb.thash})
 	log2.TxHash = sdb.thash
@@ -688,6 +727,50 @@ func (sdb *IntraBlockState) FinalizeTx(chainRules *chain.Rules, stateWriter Stat
 	return nil
 }
 
+// ApplyTxChanges applies the changes made by a transaction executed on txState, over a reader serving
+// the current state of sdb (see VersionedReader), as if the transaction was executed on sdb.
+// It must be called instead of FinalizeTx on txState, and followed by FinalizeTx on sdb.
+func (sdb *IntraBlockState) ApplyTxChanges(txState *IntraBlockState) {
+	for addr := range txState.journal.dirties {
+		sdb.journal.dirty(addr)
+		so, exist := txState.stateObjects[addr]
+		if !exist {
+			continue
+		}
+		obj := sdb.getStateObject(addr)
+		if so.newlyCreated || obj == nil {
+			obj = sdb.createObject(addr, obj)
+			obj.createdContract = so.createdContract
+		} else if so.selfdestructed {
+			obj.createdContract = false
+		}
+		obj.data.Copy(&so.data)
+		obj.selfdestructed = so.selfdestructed
+		if so.dirtyCode {
+			obj.setCode(so.data.CodeHash, so.code)
+		}
+		for key, value := range so.dirtyStorage {
+			key := key
+			var committed uint256.Int
+			obj.GetCommittedState(&key, &committed) // load the original value, as SetState would
+			obj.setState(&key, value)
+		}
+	}
+	for addr, bi := range txState.balanceInc {
+		if !bi.transferred {
+			sdb.AddBalance(addr, &bi.increase)
+		}
+	}
+	for _, l := range txState.logs[txState.thash] {
+		l.Index = sdb.logSize
+		sdb.logs[txState.thash] = append(sdb.logs[txState.thash], l)
+		sdb.logSize++
+	}
+	if txState.savedErr != nil {
+		sdb.setErrorUnsafe(txState.savedErr)
+	}
+}
+
 // CommitBlock finalizes the state by removing the self destructed objects
 // and clears the journal as well as the refunds.
 func (sdb *IntraBlockState) CommitBlock(chainRules *chain.Rules, stateWriter StateWriter) error {
@@ -127,6 +127,18 @@ func (so *stateObject) EncodeRLP(w io.Writer) error {
 	return rlp.Encode(w, so.data)
 }
 
+func (so *stateObject) deepCopy(db *IntraBlockState) *stateObject {
+	cpy := *so
+	cpy.db = db
+	cpy.originStorage = so.originStorage.Copy()
+	cpy.blockOriginStorage = so.blockOriginStorage.Copy()
+	cpy.dirtyStorage = so.dirtyStorage.Copy()
+	if so.fakeStorage != nil {
+		cpy.fakeStorage = so.fakeStorage.Copy()
+	}
+	return &cpy
+}
+
 // setError remembers the first non-nil error it is called with.
 func (so *stateObject) setError(err error) {
 	if so.db.savedErr == nil {
@@ -276,6 +276,38 @@ func TestSnapshot2(t *testing.T) {
 	}
 }
 
+// the copy of a state is modified without changing the original one
+func TestCopy(t *testing.T) {
+	t.Parallel()
+	_, tx := memdb.NewTestTx(t)
+	orig := New(NewPlainState(tx, 1, nil))
+	addr := toAddr([]byte("so0"))
+	var key common.Hash
+	orig.AddBalance(addr, uint256.NewInt(42))
+	orig.SetState(addr, &key, *uint256.NewInt(17))
+	if err := orig.FinalizeTx(&chain.Rules{}, NewNoopWriter()); err != nil {
+		t.Fatal("error while finalizing transaction", err)
+	}
+
+	cpy := orig.Copy()
+	cpy.AddBalance(addr, uint256.NewInt(1))
+	cpy.SetNonce(addr, 1)
+	cpy.SetState(addr, &key, *uint256.NewInt(18))
+	if err := cpy.FinalizeTx(&chain.Rules{}, NewNoopWriter()); err != nil {
+		t.Fatal("error while finalizing transaction", err)
+	}
+
+	var value uint256.Int
+	orig.GetState(addr, &key, &value)
+	if balance, nonce := orig.GetBalance(addr), orig.GetNonce(addr); balance.Uint64() != 42 || nonce != 0 || value.Uint64() != 17 {
+		t.Fatalf("original changed: balance %d, nonce %d, storage %d", balance, nonce, &value)
+	}
+	cpy.GetState(addr, &key, &value)
+	if balance, nonce := cpy.GetBalance(addr), cpy.GetNonce(addr); balance.Uint64() != 43 || nonce != 1 || value.Uint64() != 18 {
+		t.Fatalf("copy not changed: balance %d, nonce %d, storage %d", balance, nonce, &value)
+	}
+}
+
 func compareStateObjects(so0, so1 *stateObject, t *testing.T) {
 	if so0.Address() != so1.Address() {
 		t.Fatalf("Address mismatch: have %v, want %v", so0.address, so1.address)
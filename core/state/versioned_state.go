@@ -0,0 +1,276 @@
+package state
+
+import (
+	"sync"
+
+	"github.com/holiman/uint256"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+)
+
+// VersionedState supports the optimistic parallel execution of the transactions of a block.
+// It holds the state written by the transactions committed so far, on top of the state at the
+// beginning of the block, and the version of every item - the number of the commit which wrote it
+// last, or zero if it still has its value from the beginning of the block. Transactions are executed
+// speculatively on their own IntraBlockState over a VersionedReader, which records the versions
+// of the items read, so that the execution can be validated when the transaction is committed:
+// it is valid if none of the items it read were written in the meantime.
+//
+// VersionedState is safe for concurrent use.
+type VersionedState struct {
+	lock     sync.RWMutex
+	accounts map[libcommon.Address]versionedAccount
+	storage  map[versionedStorageKey]versionedStorage
+	code     map[libcommon.Hash][]byte
+	// incarnations of the contracts created in the block, their storage is not in the database
+	created        map[libcommon.Address]uint64
+	pendingCreated map[libcommon.Address]struct{}
+}
+
+type versionedAccount struct {
+	account *accounts.Account // nil if the account doesn't exist
+	version int
+}
+
+type versionedStorageKey struct {
+	address     libcommon.Address
+	incarnation uint64
+	key         libcommon.Hash
+}
+
+type versionedStorage struct {
+	value   uint256.Int
+	version int
+}
+
+func NewVersionedState() *VersionedState {
+	return &VersionedState{
+		accounts:       map[libcommon.Address]versionedAccount{},
+		storage:        map[versionedStorageKey]versionedStorage{},
+		code:           map[libcommon.Hash][]byte{},
+		created:        map[libcommon.Address]uint64{},
+		pendingCreated: map[libcommon.Address]struct{}{},
+	}
+}
+
+// Writer returns a StateWriter recording the changes of a committed transaction with the given version,
+// which must be greater than the versions of all the transactions committed before.
+func (vs *VersionedState) Writer(version int) StateWriter {
+	return &versionedWriter{vs: vs, version: version}
+}
+
+// Validate returns true if none of the items read by the reader have been written since they were read.
+func (vs *VersionedState) Validate(r *VersionedReader) bool {
+	if r.conflict {
+		return false
+	}
+	vs.lock.RLock()
+	defer vs.lock.RUnlock()
+	for addr, version := range r.accountReads {
+		if vs.accounts[addr].version != version {
+			return false
+		}
+	}
+	for key, version := range r.storageReads {
+		if vs.storage[key].version != version {
+			return false
+		}
+	}
+	return true
+}
+
+func (vs *VersionedState) readAccount(addr libcommon.Address, base StateReader) (*accounts.Account, int, error) {
+	vs.lock.RLock()
+	entry, ok := vs.accounts[addr]
+	vs.lock.RUnlock()
+	if !ok {
+		account, err := base.ReadAccountData(addr)
+		if err != nil {
+			return nil, 0, err
+		}
+		vs.lock.Lock()
+		// the account may have been written while it was read from the base
+		if entry, ok = vs.accounts[addr]; !ok {
+			entry = versionedAccount{account: account}
+			vs.accounts[addr] = entry
+		}
+		vs.lock.Unlock()
+	}
+	if entry.account == nil {
+		return nil, entry.version, nil
+	}
+	var account accounts.Account
+	account.Copy(entry.account)
+	return &account, entry.version, nil
+}
+
+func (vs *VersionedState) readStorage(key versionedStorageKey, base StateReader) (uint256.Int, int, error) {
+	vs.lock.RLock()
+	entry, ok := vs.storage[key]
+	incarnation, created := vs.created[key.address]
+	vs.lock.RUnlock()
+	if ok || (created && incarnation == key.incarnation) {
+		return entry.value, entry.version, nil
+	}
+	enc, err := base.ReadAccountStorage(key.address, key.incarnation, &key.key)
+	if err != nil {
+		return uint256.Int{}, 0, err
+	}
+	vs.lock.Lock()
+	defer vs.lock.Unlock()
+	if entry, ok = vs.storage[key]; !ok {
+		entry.value.SetBytes(enc)
+		vs.storage[key] = entry
+	}
+	return entry.value, entry.version, nil
+}
+
+func (vs *VersionedState) readCode(codeHash libcommon.Hash) ([]byte, bool) {
+	vs.lock.RLock()
+	defer vs.lock.RUnlock()
+	code, ok := vs.code[codeHash]
+	return code, ok
+}
+
+func (vs *VersionedState) writeCode(codeHash libcommon.Hash, code []byte) {
+	vs.lock.Lock()
+	defer vs.lock.Unlock()
+	vs.code[codeHash] = code
+}
+
+// VersionedReader is the StateReader of a transaction executed speculatively over a VersionedState.
+// Items which are not in the VersionedState are read from the base reader, which must serve
+// the state at the beginning of the block.
+type VersionedReader struct {
+	vs           *VersionedState
+	base         StateReader
+	accountReads map[libcommon.Address]int
+	storageReads map[versionedStorageKey]int
+	conflict     bool
+}
+
+func NewVersionedReader(vs *VersionedState, base StateReader) *VersionedReader {
+	return &VersionedReader{
+		vs:           vs,
+		base:         base,
+		accountReads: map[libcommon.Address]int{},
+		storageReads: map[versionedStorageKey]int{},
+	}
+}
+
+func (r *VersionedReader) ReadAccountData(address libcommon.Address) (*accounts.Account, error) {
+	account, version, err := r.vs.readAccount(address, r.base)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := r.accountReads[address]; !ok {
+		r.accountReads[address] = version
+	}
+	return account, nil
+}
+
+func (r *VersionedReader) ReadAccountStorage(address libcommon.Address, incarnation uint64, key *libcommon.Hash) ([]byte, error) {
+	k := versionedStorageKey{address: address, incarnation: incarnation, key: *key}
+	value, version, err := r.vs.readStorage(k, r.base)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := r.storageReads[k]; !ok {
+		r.storageReads[k] = version
+	}
+	if value.IsZero() {
+		return nil, nil
+	}
+	return value.Bytes(), nil
+}
+
+func (r *VersionedReader) ReadAccountCode(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash) ([]byte, error) {
+	// code is addressed by its hash, so it is never stale
+	if code, ok := r.vs.readCode(codeHash); ok {
+		return code, nil
+	}
+	code, err := r.base.ReadAccountCode(address, incarnation, codeHash)
+	if err != nil {
+		return nil, err
+	}
+	if len(code) > 0 {
+		r.vs.writeCode(codeHash, code)
+	}
+	return code, nil
+}
+
+func (r *VersionedReader) ReadAccountCodeSize(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash) (int, error) {
+	code, err := r.ReadAccountCode(address, incarnation, codeHash)
+	return len(code), err
+}
+
+// ReadAccountIncarnation returns the incarnation of the account at the beginning of the block. A serial
+// execution would take it from the account deleted by a previous transaction of the block instead,
+// so the execution is treated as conflicting if the account has been written in the block.
+func (r *VersionedReader) ReadAccountIncarnation(address libcommon.Address) (uint64, error) {
+	if _, version, err := r.vs.readAccount(address, r.base); err != nil {
+		return 0, err
+	} else if version != 0 {
+		r.conflict = true
+	}
+	if _, ok := r.accountReads[address]; !ok {
+		r.accountReads[address] = 0
+	}
+	return r.base.ReadAccountIncarnation(address)
+}
+
+type versionedWriter struct {
+	vs      *VersionedState
+	version int
+}
+
+func (w *versionedWriter) UpdateAccountData(address libcommon.Address, original, account *accounts.Account) error {
+	w.vs.lock.Lock()
+	defer w.vs.lock.Unlock()
+	if _, ok := w.vs.pendingCreated[address]; ok {
+		delete(w.vs.pendingCreated, address)
+		w.vs.created[address] = account.Incarnation
+	}
+	entry, ok := w.vs.accounts[address]
+	if ok && entry.account != nil && entry.account.Equals(account) {
+		return nil
+	}
+	var a accounts.Account
+	a.Copy(account)
+	w.vs.accounts[address] = versionedAccount{account: &a, version: w.version}
+	return nil
+}
+
+func (w *versionedWriter) UpdateAccountCode(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash, code []byte) error {
+	w.vs.writeCode(codeHash, code)
+	return nil
+}
+
+func (w *versionedWriter) DeleteAccount(address libcommon.Address, original *accounts.Account) error {
+	w.vs.lock.Lock()
+	defer w.vs.lock.Unlock()
+	if entry, ok := w.vs.accounts[address]; ok && entry.account == nil {
+		return nil
+	}
+	w.vs.accounts[address] = versionedAccount{version: w.version}
+	return nil
+}
+
+func (w *versionedWriter) WriteAccountStorage(address libcommon.Address, incarnation uint64, key *libcommon.Hash, original, value *uint256.Int) error {
+	k := versionedStorageKey{address: address, incarnation: incarnation, key: *key}
+	w.vs.lock.Lock()
+	defer w.vs.lock.Unlock()
+	if entry, ok := w.vs.storage[k]; ok && entry.value.Eq(value) {
+		return nil
+	}
+	w.vs.storage[k] = versionedStorage{value: *value, version: w.version}
+	return nil
+}
+
+func (w *versionedWriter) CreateContract(address libcommon.Address) error {
+	w.vs.lock.Lock()
+	defer w.vs.lock.Unlock()
+	w.vs.pendingCreated[address] = struct{}{}
+	return nil
+}
@@ -66,6 +66,9 @@ func applyTransaction(config *chain.Config, engine consensus.EngineReader, gp *G
 	if err = ibs.FinalizeTx(rules, stateWriter); err != nil {
 		return nil, nil, err
 	}
+	if cfg.Witness != nil {
+		cfg.Witness.Merge(evm.Accesses)
+	}
 	*usedGas += result.UsedGas
 	if usedBlobGas != nil {
 		*usedBlobGas += tx.GetBlobGas()
@@ -75,29 +78,35 @@ func applyTransaction(config *chain.Config, engine consensus.EngineReader, gp *G
 	// based on the eip phase, we're passing whether the root touch-delete accounts.
 	var receipt *types.Receipt
 	if !cfg.NoReceipts {
-		// by the tx.
-		receipt = &types.Receipt{Type: tx.Type(), CumulativeGasUsed: *usedGas}
-		if result.Failed() {
-			receipt.Status = types.ReceiptStatusFailed
-		} else {
-			receipt.Status = types.ReceiptStatusSuccessful
-		}
-		receipt.TxHash = tx.Hash()
-		receipt.GasUsed = result.UsedGas
-		// if the transaction created a contract, store the creation address in the receipt.
-		if msg.To() == nil {
-			receipt.ContractAddress = crypto.CreateAddress(evm.Origin, tx.GetNonce())
-		}
-		// Set the receipt logs and create a bloom for filtering
-		receipt.Logs = ibs.GetLogs(tx.Hash())
-		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
-		receipt.BlockNumber = header.Number
-		receipt.TransactionIndex = uint(ibs.TxIndex())
+		receipt = newReceipt(header, tx, msg, result, *usedGas, ibs)
 	}
 
 	return receipt, result.ReturnData, err
 }
 
+// newReceipt creates the receipt of a transaction, whose logs have been recorded in ibs.
+func newReceipt(header *types.Header, tx types.Transaction, msg types.Message, result *ExecutionResult, cumulativeGasUsed uint64, ibs *state.IntraBlockState) *types.Receipt {
+	// by the tx.
+	receipt := &types.Receipt{Type: tx.Type(), CumulativeGasUsed: cumulativeGasUsed}
+	if result.Failed() {
+		receipt.Status = types.ReceiptStatusFailed
+	} else {
+		receipt.Status = types.ReceiptStatusSuccessful
+	}
+	receipt.TxHash = tx.Hash()
+	receipt.GasUsed = result.UsedGas
+	// if the transaction created a contract, store the creation address in the receipt.
+	if msg.To() == nil {
+		receipt.ContractAddress = crypto.CreateAddress(msg.From(), tx.GetNonce())
+	}
+	// Set the receipt logs and create a bloom for filtering
+	receipt.Logs = ibs.GetLogs(tx.Hash())
+	receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+	receipt.BlockNumber = header.Number
+	receipt.TransactionIndex = uint(ibs.TxIndex())
+	return receipt
+}
+
 // ApplyTransaction attempts to apply a transaction to the given state database
 // and uses the input parameters for its environment. It returns the receipt
 // for the transaction, gas used and an error if the transaction failed,
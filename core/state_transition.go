@@ -402,6 +402,13 @@ func (st *StateTransition) TransitionDb(refunds bool, gasBailout bool) (*Executi
 	// - prepare accessList(post-berlin)
 	// - reset transient storage(eip 1153)
 	st.state.Prepare(rules, msg.From(), coinbase, msg.To(), vm.ActivePrecompiles(rules), msg.AccessList())
+	if rules.IsVerkle {
+		// EIP-4762: the sender and the recipient are part of the witness, but aren't charged for
+		st.evm.Accesses.TouchTxOriginAndComputeGas(msg.From())
+		if msg.To() != nil {
+			st.evm.Accesses.TouchTxTargetAndComputeGas(*msg.To(), !msg.Value().IsZero())
+		}
+	}
 
 	var (
 		ret   []byte
@@ -438,6 +445,9 @@ func (st *StateTransition) TransitionDb(refunds bool, gasBailout bool) (*Executi
 	amount := new(uint256.Int).SetUint64(st.gasUsed())
 	amount.Mul(amount, effectiveTip) // gasUsed * effectiveTip = how much goes to the block producer (miner, validator)
 	st.state.AddBalance(coinbase, amount)
+	if rules.IsVerkle {
+		st.evm.Accesses.TouchBalance(coinbase, true)
+	}
 	if !msg.IsFree() && rules.IsLondon {
 		burntContractAddress := st.evm.ChainConfig().GetBurntContract(st.evm.Context.BlockNumber)
 		if burntContractAddress != nil {
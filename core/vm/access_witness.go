@@ -0,0 +1,247 @@
+package vm
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/holiman/uint256"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/turbo/trie/vtree"
+)
+
+type accessMode byte
+
+const (
+	accessRead accessMode = 1 << iota
+	accessWrite
+)
+
+// branchAccessKey identifies a stem of the verkle tree, i.e. a group of 256 leaves.
+type branchAccessKey struct {
+	addr      libcommon.Address
+	treeIndex uint256.Int
+}
+
+// chunkAccessKey identifies a single leaf of the verkle tree.
+type chunkAccessKey struct {
+	branchAccessKey
+	leafKey byte
+}
+
+// AccessWitness keeps track of the verkle tree leaves accessed during execution, as
+// defined by EIP-4762. Every method returns the gas that has to be charged for the
+// access: a branch (stem) and a chunk (leaf) are charged only the first time they are
+// read or written. Keys are stored as (address, tree index, sub index) and the
+// pedersen hashes are only computed when the witness is requested.
+type AccessWitness struct {
+	branches map[branchAccessKey]accessMode
+	chunks   map[chunkAccessKey]accessMode
+}
+
+func NewAccessWitness() *AccessWitness {
+	return &AccessWitness{
+		branches: make(map[branchAccessKey]accessMode),
+		chunks:   make(map[chunkAccessKey]accessMode),
+	}
+}
+
+// Merge is used to merge the witness that got generated during the execution
+// of a tx, with the accumulation of witnesses that were generated during the
+// execution of all the txs preceding this one in a given block.
+func (aw *AccessWitness) Merge(other *AccessWitness) {
+	if other == nil {
+		return
+	}
+	for k, mode := range other.branches {
+		aw.branches[k] |= mode
+	}
+	for k, mode := range other.chunks {
+		aw.chunks[k] |= mode
+	}
+}
+
+// Copy returns a deep copy of the witness.
+func (aw *AccessWitness) Copy() *AccessWitness {
+	cpy := NewAccessWitness()
+	cpy.Merge(aw)
+	return cpy
+}
+
+// Len returns the number of leaves touched so far.
+func (aw *AccessWitness) Len() int {
+	return len(aw.chunks)
+}
+
+// Keys returns the sorted verkle tree keys of all the leaves touched so far.
+func (aw *AccessWitness) Keys() [][]byte {
+	keys := make([][]byte, 0, len(aw.chunks))
+	for k := range aw.chunks {
+		treeIndex := k.treeIndex
+		keys = append(keys, vtree.GetTreeKey(k.addr[:], &treeIndex, k.leafKey))
+	}
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i], keys[j]) < 0 })
+	return keys
+}
+
+// TouchAndChargeProofOfAbsence charges the reads of all the account header leaves.
+func (aw *AccessWitness) TouchAndChargeProofOfAbsence(addr libcommon.Address) uint64 {
+	var gas uint64
+	gas += aw.touchAccountLeaf(addr, vtree.VersionLeafKey, false)
+	gas += aw.touchAccountLeaf(addr, vtree.BalanceLeafKey, false)
+	gas += aw.touchAccountLeaf(addr, vtree.NonceLeafKey, false)
+	gas += aw.touchAccountLeaf(addr, vtree.CodeKeccakLeafKey, false)
+	gas += aw.touchAccountLeaf(addr, vtree.CodeSizeLeafKey, false)
+	return gas
+}
+
+// TouchAndChargeMessageCall charges the reads needed to call into an account.
+func (aw *AccessWitness) TouchAndChargeMessageCall(addr libcommon.Address) uint64 {
+	var gas uint64
+	gas += aw.touchAccountLeaf(addr, vtree.VersionLeafKey, false)
+	gas += aw.touchAccountLeaf(addr, vtree.CodeSizeLeafKey, false)
+	return gas
+}
+
+// TouchAndChargeValueTransfer charges the balance writes of a value-bearing call.
+func (aw *AccessWitness) TouchAndChargeValueTransfer(callerAddr, targetAddr libcommon.Address) uint64 {
+	var gas uint64
+	gas += aw.touchAccountLeaf(callerAddr, vtree.BalanceLeafKey, true)
+	gas += aw.touchAccountLeaf(targetAddr, vtree.BalanceLeafKey, true)
+	return gas
+}
+
+// TouchAndChargeContractCreateInit charges the writes performed when a contract
+// creation starts, before the init code is executed.
+func (aw *AccessWitness) TouchAndChargeContractCreateInit(addr libcommon.Address, createSendsValue bool) uint64 {
+	var gas uint64
+	gas += aw.touchAccountLeaf(addr, vtree.VersionLeafKey, true)
+	gas += aw.touchAccountLeaf(addr, vtree.NonceLeafKey, true)
+	if createSendsValue {
+		gas += aw.touchAccountLeaf(addr, vtree.BalanceLeafKey, true)
+	}
+	return gas
+}
+
+// TouchAndChargeContractCreateCompleted charges the writes of all the account header
+// leaves once a contract has been successfully deployed.
+func (aw *AccessWitness) TouchAndChargeContractCreateCompleted(addr libcommon.Address) uint64 {
+	var gas uint64
+	gas += aw.touchAccountLeaf(addr, vtree.VersionLeafKey, true)
+	gas += aw.touchAccountLeaf(addr, vtree.BalanceLeafKey, true)
+	gas += aw.touchAccountLeaf(addr, vtree.NonceLeafKey, true)
+	gas += aw.touchAccountLeaf(addr, vtree.CodeKeccakLeafKey, true)
+	gas += aw.touchAccountLeaf(addr, vtree.CodeSizeLeafKey, true)
+	return gas
+}
+
+// TouchAndChargeAccountCreation charges the writes of an account created by a value
+// transfer, e.g. to a SELFDESTRUCT beneficiary. All its header leaves are written, as
+// for a deployed contract.
+func (aw *AccessWitness) TouchAndChargeAccountCreation(addr libcommon.Address) uint64 {
+	return aw.TouchAndChargeContractCreateCompleted(addr)
+}
+
+// TouchTxOriginAndComputeGas adds the transaction sender to the witness. The gas is
+// returned for completeness, but EIP-4762 doesn't charge it to the transaction.
+func (aw *AccessWitness) TouchTxOriginAndComputeGas(originAddr libcommon.Address) uint64 {
+	var gas uint64
+	gas += aw.touchAccountLeaf(originAddr, vtree.VersionLeafKey, false)
+	gas += aw.touchAccountLeaf(originAddr, vtree.CodeSizeLeafKey, false)
+	gas += aw.touchAccountLeaf(originAddr, vtree.CodeKeccakLeafKey, false)
+	gas += aw.touchAccountLeaf(originAddr, vtree.NonceLeafKey, true)
+	gas += aw.touchAccountLeaf(originAddr, vtree.BalanceLeafKey, true)
+	return gas
+}
+
+// TouchTxTargetAndComputeGas adds the transaction recipient to the witness. The gas is
+// returned for completeness, but EIP-4762 doesn't charge it to the transaction.
+func (aw *AccessWitness) TouchTxTargetAndComputeGas(targetAddr libcommon.Address, sendsValue bool) uint64 {
+	var gas uint64
+	gas += aw.touchAccountLeaf(targetAddr, vtree.VersionLeafKey, false)
+	gas += aw.touchAccountLeaf(targetAddr, vtree.CodeSizeLeafKey, false)
+	gas += aw.touchAccountLeaf(targetAddr, vtree.CodeKeccakLeafKey, false)
+	gas += aw.touchAccountLeaf(targetAddr, vtree.NonceLeafKey, false)
+	gas += aw.touchAccountLeaf(targetAddr, vtree.BalanceLeafKey, sendsValue)
+	return gas
+}
+
+// TouchBalance charges the access to the balance leaf of an account.
+func (aw *AccessWitness) TouchBalance(addr libcommon.Address, isWrite bool) uint64 {
+	return aw.touchAccountLeaf(addr, vtree.BalanceLeafKey, isWrite)
+}
+
+// TouchCodeSize charges the access to the code size leaf of an account.
+func (aw *AccessWitness) TouchCodeSize(addr libcommon.Address, isWrite bool) uint64 {
+	return aw.touchAccountLeaf(addr, vtree.CodeSizeLeafKey, isWrite)
+}
+
+// TouchCodeHash charges the access to the code hash leaf of an account.
+func (aw *AccessWitness) TouchCodeHash(addr libcommon.Address, isWrite bool) uint64 {
+	return aw.touchAccountLeaf(addr, vtree.CodeKeccakLeafKey, isWrite)
+}
+
+// TouchSlotAndChargeGas charges the access to a storage slot.
+func (aw *AccessWitness) TouchSlotAndChargeGas(addr libcommon.Address, slot libcommon.Hash, isWrite bool) uint64 {
+	var key uint256.Int
+	key.SetBytes32(slot[:])
+	treeIndex, subIndex := vtree.GetTreeKeyStorageSlotTreeIndexes(&key)
+	return aw.touchAddressAndChargeGas(addr, treeIndex, subIndex, isWrite)
+}
+
+// TouchCodeChunksRangeAndChargeGas charges the access to the code chunks covering
+// the [startPC, startPC+size) range of a code of length codeLen. Chunks past the end
+// of the code are not part of the tree and are not charged.
+func (aw *AccessWitness) TouchCodeChunksRangeAndChargeGas(addr libcommon.Address, startPC, size, codeLen uint64, isWrite bool) uint64 {
+	if size == 0 || startPC >= codeLen {
+		return 0
+	}
+	endPC := startPC + size - 1
+	if endPC < startPC || endPC >= codeLen {
+		endPC = codeLen - 1
+	}
+	var (
+		gas   uint64
+		chunk uint256.Int
+	)
+	for chunkNumber := startPC / 31; chunkNumber <= endPC/31; chunkNumber++ {
+		chunk.SetUint64(chunkNumber)
+		treeIndex, subIndex := vtree.GetTreeKeyCodeChunkIndices(&chunk)
+		gas += aw.touchAddressAndChargeGas(addr, treeIndex, subIndex, isWrite)
+	}
+	return gas
+}
+
+func (aw *AccessWitness) touchAccountLeaf(addr libcommon.Address, leafKey byte, isWrite bool) uint64 {
+	return aw.touchAddressAndChargeGas(addr, new(uint256.Int), leafKey, isWrite)
+}
+
+func (aw *AccessWitness) touchAddressAndChargeGas(addr libcommon.Address, treeIndex *uint256.Int, subIndex byte, isWrite bool) uint64 {
+	branchKey := branchAccessKey{addr: addr, treeIndex: *treeIndex}
+	chunkKey := chunkAccessKey{branchAccessKey: branchKey, leafKey: subIndex}
+
+	var gas uint64
+	branchMode, chunkMode := aw.branches[branchKey], aw.chunks[chunkKey]
+	if branchMode&accessRead == 0 {
+		gas += params.WitnessBranchReadCost
+		branchMode |= accessRead
+	}
+	if chunkMode&accessRead == 0 {
+		gas += params.WitnessChunkReadCost
+		chunkMode |= accessRead
+	}
+	if isWrite {
+		if branchMode&accessWrite == 0 {
+			gas += params.WitnessBranchWriteCost
+			branchMode |= accessWrite
+		}
+		if chunkMode&accessWrite == 0 {
+			gas += params.WitnessChunkWriteCost
+			chunkMode |= accessWrite
+		}
+	}
+	aw.branches[branchKey], aw.chunks[chunkKey] = branchMode, chunkMode
+	return gas
+}
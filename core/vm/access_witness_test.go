@@ -0,0 +1,92 @@
+package vm
+
+import (
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/params"
+)
+
+func TestAccessWitnessGas(t *testing.T) {
+	t.Parallel()
+	var (
+		addr  = libcommon.HexToAddress("0x1234")
+		other = libcommon.HexToAddress("0x5678")
+		aw    = NewAccessWitness()
+	)
+
+	// Cold read of a leaf: branch + chunk
+	if gas := aw.TouchBalance(addr, false); gas != params.WitnessBranchReadCost+params.WitnessChunkReadCost {
+		t.Fatalf("cold balance read: got %d", gas)
+	}
+	// Warm read is free
+	if gas := aw.TouchBalance(addr, false); gas != 0 {
+		t.Fatalf("warm balance read: got %d", gas)
+	}
+	// Another leaf in the same stem only pays for the chunk
+	if gas := aw.TouchCodeSize(addr, false); gas != params.WitnessChunkReadCost {
+		t.Fatalf("code size read: got %d", gas)
+	}
+	// First write of an already read leaf pays for the write costs only
+	if gas := aw.TouchBalance(addr, true); gas != params.WitnessBranchWriteCost+params.WitnessChunkWriteCost {
+		t.Fatalf("balance write: got %d", gas)
+	}
+	if gas := aw.TouchCodeHash(addr, true); gas != params.WitnessChunkReadCost+params.WitnessChunkWriteCost {
+		t.Fatalf("code hash write: got %d", gas)
+	}
+
+	// Storage slots below 64 live in the account stem, other slots don't
+	if gas := aw.TouchSlotAndChargeGas(addr, libcommon.Hash{31: 1}, false); gas != params.WitnessChunkReadCost {
+		t.Fatalf("header slot read: got %d", gas)
+	}
+	if gas := aw.TouchSlotAndChargeGas(addr, libcommon.Hash{0: 1}, false); gas != params.WitnessBranchReadCost+params.WitnessChunkReadCost {
+		t.Fatalf("main storage slot read: got %d", gas)
+	}
+
+	// The first 128-64 chunks are in the account stem too, 31 bytes per chunk
+	if gas := aw.TouchCodeChunksRangeAndChargeGas(addr, 0, 32, 100, false); gas != 2*params.WitnessChunkReadCost {
+		t.Fatalf("code chunks read: got %d", gas)
+	}
+	// Range is clamped to the code length
+	if gas := aw.TouchCodeChunksRangeAndChargeGas(addr, 60, 1000, 100, false); gas != 2*params.WitnessChunkReadCost {
+		t.Fatalf("clamped code chunks read: got %d", gas)
+	}
+	if gas := aw.TouchCodeChunksRangeAndChargeGas(addr, 100, 1, 100, false); gas != 0 {
+		t.Fatalf("out of code read: got %d", gas)
+	}
+
+	block := NewAccessWitness()
+	block.Merge(aw)
+	if gas := block.TouchAndChargeValueTransfer(addr, other); gas != params.WitnessBranchReadCost+params.WitnessChunkReadCost+params.WitnessBranchWriteCost+params.WitnessChunkWriteCost {
+		t.Fatalf("value transfer after merge: got %d", gas)
+	}
+	if block.Len() != aw.Len()+1 {
+		t.Fatalf("merged witness has %d leaves, expected %d", block.Len(), aw.Len()+1)
+	}
+
+	// Creating the target of the transfer writes the remaining header leaves
+	if gas := block.TouchAndChargeAccountCreation(other); gas != 4*(params.WitnessChunkReadCost+params.WitnessChunkWriteCost) {
+		t.Fatalf("account creation after value transfer: got %d", gas)
+	}
+}
+
+func TestAccessWitnessKeys(t *testing.T) {
+	t.Parallel()
+	aw := NewAccessWitness()
+	aw.TouchAndChargeProofOfAbsence(libcommon.HexToAddress("0x1234"))
+	aw.TouchSlotAndChargeGas(libcommon.HexToAddress("0x1234"), libcommon.Hash{0: 1}, true)
+
+	keys := aw.Keys()
+	if len(keys) != 6 {
+		t.Fatalf("expected 6 keys, got %d", len(keys))
+	}
+	for i, key := range keys {
+		if len(key) != 32 {
+			t.Fatalf("key %d has length %d", i, len(key))
+		}
+		if i > 0 && string(keys[i-1]) >= string(key) {
+			t.Fatalf("keys are not sorted")
+		}
+	}
+}
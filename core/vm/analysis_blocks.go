@@ -0,0 +1,101 @@
+package vm
+
+import (
+	"math"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+)
+
+// The interpreter can charge the static gas of a whole basic block when entering it, instead of
+// charging every instruction separately. A basic block starts at the beginning of the code, at a
+// JUMPDEST or after an instruction ending the previous block, and ends with a control flow change
+// or with an instruction which observes the remaining gas (GAS, SSTORE's stipend check, calls and
+// creates), so that those observe the same amount of gas as when charging per instruction.
+//
+// Charging upfront doesn't change the outcome of the execution: the remaining instructions of the
+// block are either all executed, or the frame fails with an error, which consumes all the gas anyway.
+// When the gas left is not enough for the whole block, the interpreter falls back to charging per
+// instruction until the next block, so that running out of gas happens at the same instruction.
+
+// endsBasicBlock returns true if the instruction is the last one of its basic block.
+func endsBasicBlock(op OpCode) bool {
+	switch op {
+	case STOP, JUMP, JUMPI, RETURN, REVERT, SELFDESTRUCT,
+		GAS, SSTORE, CALL, CALLCODE, DELEGATECALL, STATICCALL, CREATE, CREATE2:
+		return true
+	}
+	return false
+}
+
+// blockGasAnalysis computes the static gas of the basic blocks of the code for the given
+// instruction set. The result holds, at the position of the first instruction of every block,
+// the static gas of the block plus one, and zero elsewhere.
+func blockGasAnalysis(code []byte, jt *JumpTable) []uint32 {
+	blocks := make([]uint32, len(code))
+	start, gas := 0, uint64(0)
+	endBlock := func(next int) {
+		blocks[start] = uint32(gas + 1)
+		start, gas = next, 0
+	}
+	for pc := 0; pc < len(code); {
+		op := OpCode(code[pc])
+		constantGas := jt[op].constantGas
+		if pc != start && (op == JUMPDEST || gas+constantGas >= math.MaxUint32) {
+			endBlock(pc)
+		}
+		gas += constantGas
+		pc++
+		if op >= PUSH1 && op <= PUSH32 {
+			pc += int(op - PUSH1 + 1)
+		}
+		if endsBasicBlock(op) {
+			if pc >= len(code) {
+				break
+			}
+			endBlock(pc)
+		}
+	}
+	if start < len(code) {
+		blocks[start] = uint32(gas + 1)
+	}
+	return blocks
+}
+
+// DefaultAnalysisCacheSize is the number of pieces of code analysed by the execution stage kept in memory.
+const DefaultAnalysisCacheSize = 4096
+
+type analysisKey struct {
+	codeHash libcommon.Hash
+	jt       *JumpTable
+}
+
+// AnalysisCache keeps the basic block analysis of recently executed code, keyed by code hash.
+// It is safe for concurrent use, and is meant to be shared by all the EVMs executing blocks.
+type AnalysisCache struct {
+	blocks *lru.Cache[analysisKey, []uint32]
+}
+
+// NewAnalysisCache creates a cache holding the analysis of up to size pieces of code.
+func NewAnalysisCache(size int) *AnalysisCache {
+	blocks, err := lru.New[analysisKey, []uint32](size)
+	if err != nil {
+		panic(err)
+	}
+	return &AnalysisCache{blocks: blocks}
+}
+
+// blockGas returns the basic block analysis of the contract code for the given instruction set.
+func (c *AnalysisCache) blockGas(contract *Contract, jt *JumpTable) []uint32 {
+	if contract.CodeHash == (libcommon.Hash{}) {
+		// not worth analysing code we can't cache
+		return nil
+	}
+	key := analysisKey{codeHash: contract.CodeHash, jt: jt}
+	if blocks, ok := c.blocks.Get(key); ok {
+		return blocks
+	}
+	blocks := blockGasAnalysis(contract.Code, jt)
+	c.blocks.Add(key, blocks)
+	return blocks
+}
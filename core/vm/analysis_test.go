@@ -17,12 +17,14 @@
 package vm
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/holiman/uint256"
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 
 	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/params"
 )
 
 func TestJumpDestAnalysis(t *testing.T) {
@@ -52,6 +54,30 @@ func TestJumpDestAnalysis(t *testing.T) {
 	}
 }
 
+func TestBlockGasAnalysis(t *testing.T) {
+	t.Parallel()
+	code := []byte{
+		byte(PUSH1), byte(JUMPDEST), // push data is not a block start
+		byte(PUSH1), 0x01,
+		byte(ADD),
+		byte(JUMPDEST),
+		byte(GAS),
+		byte(POP),
+		byte(PUSH2), 0x00, 0x05,
+		byte(JUMPI),
+		byte(STOP),
+	}
+	jt := &cancunInstructionSet
+	expected := make([]uint32, len(code))
+	expected[0] = uint32(3*GasFastestStep + 1)                  // PUSH1, PUSH1, ADD
+	expected[5] = uint32(params.JumpdestGas + GasQuickStep + 1) // JUMPDEST, GAS
+	expected[7] = uint32(GasQuickStep + GasFastestStep + GasSlowStep + 1)
+	expected[12] = 1
+	if blocks := blockGasAnalysis(code, jt); !reflect.DeepEqual(blocks, expected) {
+		t.Fatalf("expected %v, got %v", expected, blocks)
+	}
+}
+
 func BenchmarkJumpdestAnalysisEmpty_1200k(bench *testing.B) {
 	// 1.4 ms
 	code := make([]byte, 1200000)
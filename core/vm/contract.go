@@ -56,6 +56,9 @@ type Contract struct {
 	CodeAddr *libcommon.Address
 	Input    []byte
 
+	// IsDeployment is set for init code, which (unlike deployed code) is not part of the verkle tree
+	IsDeployment bool
+
 	Gas   uint64
 	value *uint256.Int
 }
@@ -179,6 +182,15 @@ func (c *Contract) Address() libcommon.Address {
 	return c.self
 }
 
+// codeAddress returns the address the executing code is stored at, which
+// differs from the contract address for DELEGATECALL and CALLCODE.
+func (c *Contract) codeAddress() libcommon.Address {
+	if c.CodeAddr != nil {
+		return *c.CodeAddr
+	}
+	return c.self
+}
+
 // Value returns the contract's value (sent to it from it's caller)
 func (c *Contract) Value() *uint256.Int {
 	return c.value
@@ -152,19 +152,20 @@ func init() {
 	}
 }
 
-// ActivePrecompiles returns the precompiles enabled with the current configuration.
+// ActivePrecompiles returns the precompiles enabled with the current configuration,
+// including the chain-specific ones.
 func ActivePrecompiles(rules *chain.Rules) []libcommon.Address {
 	switch {
 	case rules.IsCancun:
-		return PrecompiledAddressesCancun
+		return withCustomPrecompiles(rules, PrecompiledAddressesCancun)
 	case rules.IsBerlin:
-		return PrecompiledAddressesBerlin
+		return withCustomPrecompiles(rules, PrecompiledAddressesBerlin)
 	case rules.IsIstanbul:
-		return PrecompiledAddressesIstanbul
+		return withCustomPrecompiles(rules, PrecompiledAddressesIstanbul)
 	case rules.IsByzantium:
-		return PrecompiledAddressesByzantium
+		return withCustomPrecompiles(rules, PrecompiledAddressesByzantium)
 	default:
-		return PrecompiledAddressesHomestead
+		return withCustomPrecompiles(rules, PrecompiledAddressesHomestead)
 	}
 }
 
@@ -0,0 +1,85 @@
+package vm
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+)
+
+// Chain-specific precompiled contracts are activated by the chain config (see chain.Config.CustomPrecompiles),
+// which refers to implementations by name. Binaries embedding Erigon register these implementations
+// with RegisterPrecompiledContract before any block is executed, typically from an init function.
+var (
+	customPrecompilesLock sync.RWMutex
+	customPrecompiles     = map[string]PrecompiledContract{}
+)
+
+// RegisterPrecompiledContract makes a PrecompiledContract implementation available under the given name.
+func RegisterPrecompiledContract(name string, p PrecompiledContract) error {
+	if name == "" {
+		return fmt.Errorf("precompiled contract name must not be empty")
+	}
+	if p == nil {
+		return fmt.Errorf("precompiled contract %q is nil", name)
+	}
+	customPrecompilesLock.Lock()
+	defer customPrecompilesLock.Unlock()
+	if _, ok := customPrecompiles[name]; ok {
+		return fmt.Errorf("precompiled contract %q is already registered", name)
+	}
+	customPrecompiles[name] = p
+	return nil
+}
+
+// CheckCustomPrecompiles verifies that all the precompiles scheduled by the chain config are registered,
+// and that none of them shadows a standard precompile, of any fork.
+func CheckCustomPrecompiles(config *chain.Config) error {
+	if config == nil || len(config.CustomPrecompiles.Configs()) == 0 {
+		return nil
+	}
+	customPrecompilesLock.RLock()
+	defer customPrecompilesLock.RUnlock()
+	for addr, precompile := range config.CustomPrecompiles.Configs() {
+		if _, ok := customPrecompiles[precompile.Name]; !ok {
+			return fmt.Errorf("custom precompile %q at %x is not registered", precompile.Name, addr)
+		}
+		for _, standard := range []map[libcommon.Address]PrecompiledContract{PrecompiledContractsHomestead, PrecompiledContractsByzantium,
+			PrecompiledContractsIstanbul, PrecompiledContractsBerlin, PrecompiledContractsCancun, PrecompiledContractsBLS} {
+			if _, ok := standard[addr]; ok {
+				return fmt.Errorf("custom precompile %q at %x shadows a standard precompile", precompile.Name, addr)
+			}
+		}
+	}
+	return nil
+}
+
+// customPrecompile returns the chain-specific precompile active at the given address, if any.
+func customPrecompile(rules *chain.Rules, addr libcommon.Address) (PrecompiledContract, bool) {
+	name, ok := rules.CustomPrecompiles[addr]
+	if !ok {
+		return nil, false
+	}
+	customPrecompilesLock.RLock()
+	defer customPrecompilesLock.RUnlock()
+	p, ok := customPrecompiles[name]
+	return p, ok
+}
+
+// withCustomPrecompiles appends the addresses of the active chain-specific precompiles to the standard ones.
+func withCustomPrecompiles(rules *chain.Rules, addresses []libcommon.Address) []libcommon.Address {
+	if len(rules.CustomPrecompiles) == 0 {
+		return addresses
+	}
+	custom := make([]libcommon.Address, 0, len(rules.CustomPrecompiles))
+	for addr := range rules.CustomPrecompiles {
+		if _, ok := customPrecompile(rules, addr); ok {
+			custom = append(custom, addr)
+		}
+	}
+	sort.Slice(custom, func(i, j int) bool { return bytes.Compare(custom[i][:], custom[j][:]) < 0 })
+	return append(append(make([]libcommon.Address, 0, len(addresses)+len(custom)), addresses...), custom...)
+}
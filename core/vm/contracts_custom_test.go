@@ -0,0 +1,95 @@
+package vm
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/core/vm/evmtypes"
+	"github.com/ledgerwatch/erigon/params"
+)
+
+type echoPrecompile struct{}
+
+func (echoPrecompile) RequiredGas(input []byte) uint64  { return uint64(len(input)) }
+func (echoPrecompile) Run(input []byte) ([]byte, error) { return input, nil }
+
+// registerPrecompiledContract registers the implementation for the duration of the test
+func registerPrecompiledContract(t *testing.T, name string, p PrecompiledContract) {
+	t.Helper()
+	if err := RegisterPrecompiledContract(name, p); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		customPrecompilesLock.Lock()
+		defer customPrecompilesLock.Unlock()
+		delete(customPrecompiles, name)
+	})
+}
+
+func TestCustomPrecompiles(t *testing.T) {
+	registerPrecompiledContract(t, "test-echo", echoPrecompile{})
+	if err := RegisterPrecompiledContract("test-echo", echoPrecompile{}); err == nil {
+		t.Fatal("expected an error registering the same name twice")
+	}
+
+	addr := libcommon.HexToAddress("0x0100")
+	config := *params.TestChainConfig
+	config.CustomPrecompiles = chain.NewCustomPrecompiles(map[libcommon.Address]chain.CustomPrecompileConfig{
+		addr: {Name: "test-echo", ActivationTime: 10},
+	})
+	if err := CheckCustomPrecompiles(&config); err != nil {
+		t.Fatal(err)
+	}
+
+	standard := ActivePrecompiles(params.TestChainConfig.Rules(0, 10))
+	if n := len(ActivePrecompiles(config.Rules(0, 9))); n != len(standard) {
+		t.Fatalf("expected %d precompiles before activation, got %d", len(standard), n)
+	}
+	env := NewEVM(evmtypes.BlockContext{BlockNumber: 0, Time: 9}, evmtypes.TxContext{}, &dummyStatedb{}, &config, Config{})
+	if _, ok := env.precompile(addr); ok {
+		t.Fatal("custom precompile active before its activation time")
+	}
+
+	active := ActivePrecompiles(config.Rules(0, 10))
+	if len(active) != len(standard)+1 || active[len(active)-1] != addr {
+		t.Fatalf("custom precompile missing from the active precompiles: %v", active)
+	}
+	env = NewEVM(evmtypes.BlockContext{BlockNumber: 0, Time: 10}, evmtypes.TxContext{}, &dummyStatedb{}, &config, Config{})
+	p, ok := env.precompile(addr)
+	if !ok {
+		t.Fatal("custom precompile not active after its activation time")
+	}
+	input := []byte{1, 2, 3}
+	output, gasLeft, err := RunPrecompiledContract(p, input, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(output, input) || gasLeft != 97 {
+		t.Fatalf("unexpected result: output %x, gas left %d", output, gasLeft)
+	}
+}
+
+func TestCheckCustomPrecompiles(t *testing.T) {
+	t.Parallel()
+	config := *params.TestChainConfig
+	config.CustomPrecompiles = chain.NewCustomPrecompiles(map[libcommon.Address]chain.CustomPrecompileConfig{
+		libcommon.HexToAddress("0x0101"): {Name: "test-unregistered"},
+	})
+	if err := CheckCustomPrecompiles(&config); err == nil {
+		t.Fatal("expected an error for an unregistered precompile")
+	}
+
+	registerPrecompiledContract(t, "test-shadowing", echoPrecompile{})
+	// the precompiles of all forks are reserved, whether they are active on the chain or not
+	for _, standard := range []libcommon.Address{libcommon.BytesToAddress([]byte{0x01}), libcommon.BytesToAddress([]byte{0x0a}), libcommon.BytesToAddress([]byte{0x0c})} {
+		config.CustomPrecompiles = chain.NewCustomPrecompiles(map[libcommon.Address]chain.CustomPrecompileConfig{
+			standard: {Name: "test-shadowing"},
+		})
+		if err := CheckCustomPrecompiles(&config); err == nil {
+			t.Fatalf("expected an error for a precompile shadowing the standard one at %x", standard)
+		}
+	}
+}
@@ -48,8 +48,10 @@ func (evm *EVM) precompile(addr libcommon.Address) (PrecompiledContract, bool) {
 	default:
 		precompiles = PrecompiledContractsHomestead
 	}
-	p, ok := precompiles[addr]
-	return p, ok
+	if p, ok := precompiles[addr]; ok {
+		return p, true
+	}
+	return customPrecompile(evm.chainRules, addr)
 }
 
 // run runs the given contract and takes care of running precompiles with a fallback to the byte code interpreter.
@@ -90,6 +92,9 @@ type EVM struct {
 	// available gas is calculated in gasCall* according to the 63/64 rule and later
 	// applied in opCall*.
 	callGasTemp uint64
+	// Accesses keeps track of the verkle tree leaves touched by the current transaction (EIP-4762).
+	// It is only set once the Verkle fork is active.
+	Accesses *AccessWitness
 }
 
 // NewEVM returns a new EVM. The returned EVM is not thread safe and should
@@ -103,6 +108,9 @@ func NewEVM(blockCtx evmtypes.BlockContext, txCtx evmtypes.TxContext, state evmt
 		chainConfig:     chainConfig,
 		chainRules:      chainConfig.Rules(blockCtx.BlockNumber, blockCtx.Time),
 	}
+	if evm.chainRules.IsVerkle {
+		evm.Accesses = NewAccessWitness()
+	}
 
 	evm.interpreter = NewEVMInterpreter(evm, vmConfig)
 
@@ -114,6 +122,9 @@ func NewEVM(blockCtx evmtypes.BlockContext, txCtx evmtypes.TxContext, state evmt
 func (evm *EVM) Reset(txCtx evmtypes.TxContext, ibs evmtypes.IntraBlockState) {
 	evm.TxContext = txCtx
 	evm.intraBlockState = ibs
+	if evm.chainRules.IsVerkle {
+		evm.Accesses = NewAccessWitness()
+	}
 
 	// ensure the evm is reset to be used again
 	atomic.StoreInt32(&evm.abort, 0)
@@ -125,6 +136,10 @@ func (evm *EVM) ResetBetweenBlocks(blockCtx evmtypes.BlockContext, txCtx evmtype
 	evm.intraBlockState = ibs
 	evm.config = vmConfig
 	evm.chainRules = chainRules
+	evm.Accesses = nil
+	if chainRules.IsVerkle {
+		evm.Accesses = NewAccessWitness()
+	}
 
 	evm.interpreter = NewEVMInterpreter(evm, vmConfig)
 
@@ -387,12 +402,21 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 	// The contract is a scoped environment for this execution context only.
 	contract := NewContract(caller, address, value, gas, evm.config.SkipAnalysis)
 	contract.SetCodeOptionalHash(&address, codeAndHash)
+	contract.IsDeployment = true
+
+	if evm.chainRules.IsVerkle {
+		if !contract.UseGas(evm.Accesses.TouchAndChargeContractCreateInit(address, !value.IsZero())) {
+			err = ErrOutOfGas
+		}
+	}
 
 	if evm.config.NoRecursion && depth > 0 {
 		return nil, address, gas, nil
 	}
 
-	ret, err = run(evm, contract, nil, false)
+	if err == nil {
+		ret, err = run(evm, contract, nil, false)
+	}
 
 	// EIP-170: Contract code size limit
 	if err == nil && evm.chainRules.IsSpuriousDragon && len(ret) > params.MaxCodeSize {
@@ -413,6 +437,11 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 	// by the error checking condition below.
 	if err == nil {
 		createDataGas := uint64(len(ret)) * params.CreateDataGas
+		if evm.chainRules.IsVerkle {
+			// EIP-4762: the per-byte cost is replaced by the writes of the code chunks and the account header
+			createDataGas = evm.Accesses.TouchCodeChunksRangeAndChargeGas(address, 0, uint64(len(ret)), uint64(len(ret)), true)
+			createDataGas += evm.Accesses.TouchAndChargeContractCreateCompleted(address)
+		}
 		if contract.UseGas(createDataGas) {
 			evm.intraBlockState.SetCode(address, ret)
 		} else if evm.chainRules.IsHomestead {
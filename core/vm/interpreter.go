@@ -42,6 +42,10 @@ type Config struct {
 	RestoreState  bool      // Revert all changes made to the state (useful for constant system calls)
 
 	ExtraEips []int // Additional EIPS that are to be enabled
+
+	Witness *AccessWitness // Accumulates the verkle access events of the applied transactions (EIP-4762), if set
+
+	AnalysisCache *AnalysisCache // Cache of analysed code, enables charging the static gas per basic block if set
 }
 
 var pool = sync.Pool{
@@ -93,6 +97,8 @@ type EVMInterpreter struct {
 	*VM
 	jt    *JumpTable // EVM instruction table
 	depth int
+
+	analysisCache *AnalysisCache // set if the static gas is charged per basic block
 }
 
 // structcheck doesn't see embedding
@@ -124,6 +130,8 @@ func copyJumpTable(jt *JumpTable) *JumpTable {
 func NewEVMInterpreter(evm *EVM, cfg Config) *EVMInterpreter {
 	var jt *JumpTable
 	switch {
+	case evm.ChainRules().IsVerkle:
+		jt = &verkleInstructionSet
 	case evm.ChainRules().IsPrague:
 		jt = &pragueInstructionSet
 	case evm.ChainRules().IsCancun:
@@ -160,12 +168,19 @@ func NewEVMInterpreter(evm *EVM, cfg Config) *EVMInterpreter {
 		}
 	}
 
+	// The gas reported to opcode tracers must be accurate, and EIP-4762 charges code chunks per instruction
+	var analysisCache *AnalysisCache
+	if len(cfg.ExtraEips) == 0 && !evm.ChainRules().IsVerkle && (!cfg.Debug || ignoresOpcodes(cfg.Tracer)) {
+		analysisCache = cfg.AnalysisCache
+	}
+
 	return &EVMInterpreter{
 		VM: &VM{
 			evm: evm,
 			cfg: cfg,
 		},
-		jt: jt,
+		jt:            jt,
+		analysisCache: analysisCache,
 	}
 }
 
@@ -207,7 +222,13 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 		gasCopy uint64 // for Tracer to log gas remaining before execution
 		logged  bool   // deferred Tracer should ignore already logged steps
 		res     []byte // result of the opcode execution function
+		// static gas of the basic blocks, see blockGasAnalysis
+		blockGas   []uint32
+		precharged bool // the static gas of the current basic block is already charged
 	)
+	if in.analysisCache != nil {
+		blockGas = in.analysisCache.blockGas(contract, in.jt)
+	}
 
 	mem.Reset()
 
@@ -264,6 +285,23 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 		} else if sLen > operation.maxStack {
 			return nil, &ErrStackOverflow{stackLen: sLen, limit: operation.maxStack}
 		}
+		if blockGas != nil {
+			if _pc < uint64(len(blockGas)) && blockGas[_pc] != 0 {
+				// entering a basic block: charge it upfront, or per instruction if there is not enough gas left
+				precharged = contract.UseGas(uint64(blockGas[_pc] - 1))
+			}
+			if precharged {
+				cost = 0
+			}
+		}
+		if in.evm.chainRules.IsVerkle && !contract.IsDeployment {
+			// EIP-4762: charge for the code chunks holding the opcode and its immediate data
+			codeSize := uint64(1)
+			if operation.isPush {
+				codeSize += uint64(operation.opNum)
+			}
+			cost += in.evm.Accesses.TouchCodeChunksRangeAndChargeGas(contract.codeAddress(), _pc, codeSize, uint64(len(contract.Code)), false)
+		}
 		if !contract.UseGas(cost) {
 			return nil, ErrOutOfGas
 		}
@@ -63,6 +63,7 @@ var (
 	shanghaiInstructionSet         = newShanghaiInstructionSet()
 	cancunInstructionSet           = newCancunInstructionSet()
 	pragueInstructionSet           = newPragueInstructionSet()
+	verkleInstructionSet           = newVerkleInstructionSet()
 )
 
 // JumpTable contains the EVM opcodes supported at a given fork.
@@ -86,6 +87,15 @@ func validateAndFillMaxStack(jt *JumpTable) {
 	}
 }
 
+// newVerkleInstructionSet returns the prague instructions with the
+// EIP-4762 (verkle witness) gas schedule.
+func newVerkleInstructionSet() JumpTable {
+	instructionSet := newPragueInstructionSet()
+	enable4762(&instructionSet)
+	validateAndFillMaxStack(&instructionSet)
+	return instructionSet
+}
+
 // newPragueInstructionSet returns the frontier, homestead, byzantium,
 // constantinople, istanbul, petersburg, berlin, london, paris, shanghai,
 // cancun, and prague instructions.
@@ -43,6 +43,17 @@ type EVMLogger interface {
 	CaptureFault(pc uint64, op OpCode, gas, cost uint64, scope *ScopeContext, depth int, err error)
 }
 
+// OpcodeIgnoringLogger is implemented by the EVMLoggers whose CaptureState and CaptureFault are no-ops.
+// The interpreter doesn't report exact per-opcode gas to them, see AnalysisCache.
+type OpcodeIgnoringLogger interface {
+	IgnoresOpcodes() bool
+}
+
+func ignoresOpcodes(tracer EVMLogger) bool {
+	l, ok := tracer.(OpcodeIgnoringLogger)
+	return ok && l.IgnoresOpcodes()
+}
+
 // FlushableTracer is a Tracer extension whose accumulated traces has to be
 // flushed once the tracing is completed.
 type FlushableTracer interface {
@@ -0,0 +1,181 @@
+package vm
+
+import (
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/math"
+
+	"github.com/ledgerwatch/erigon/core/vm/stack"
+	"github.com/ledgerwatch/erigon/params"
+)
+
+// The EIP-4762 gas functions below replace the EIP-2929 warm/cold accounting with
+// the witness costs of the accessed verkle leaves. An access that has already been
+// paid for in the current transaction costs WARM_STORAGE_READ_COST.
+
+func warmIfFree(witnessGas uint64) uint64 {
+	if witnessGas == 0 {
+		return params.WarmStorageReadCostEIP2929
+	}
+	return witnessGas
+}
+
+func gasSLoad4762(evm *EVM, contract *Contract, stack *stack.Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	slot := libcommon.Hash(stack.Peek().Bytes32())
+	return warmIfFree(evm.Accesses.TouchSlotAndChargeGas(contract.Address(), slot, false)), nil
+}
+
+func gasSStore4762(evm *EVM, contract *Contract, stack *stack.Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	slot := libcommon.Hash(stack.Peek().Bytes32())
+	return warmIfFree(evm.Accesses.TouchSlotAndChargeGas(contract.Address(), slot, true)), nil
+}
+
+func gasBalance4762(evm *EVM, contract *Contract, stack *stack.Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	addr := libcommon.Address(stack.Peek().Bytes20())
+	return warmIfFree(evm.Accesses.TouchBalance(addr, false)), nil
+}
+
+func gasExtCodeSize4762(evm *EVM, contract *Contract, stack *stack.Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	addr := libcommon.Address(stack.Peek().Bytes20())
+	return warmIfFree(evm.Accesses.TouchCodeSize(addr, false)), nil
+}
+
+func gasExtCodeHash4762(evm *EVM, contract *Contract, stack *stack.Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	addr := libcommon.Address(stack.Peek().Bytes20())
+	return warmIfFree(evm.Accesses.TouchCodeHash(addr, false)), nil
+}
+
+func gasCodeCopy4762(evm *EVM, contract *Contract, stack *stack.Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	gas, err := gasCodeCopy(evm, contract, stack, mem, memorySize)
+	if err != nil {
+		return 0, err
+	}
+	if contract.IsDeployment {
+		// init code is not part of the tree
+		return gas, nil
+	}
+	codeOffset, length := stack.Back(1), stack.Back(2)
+	uint64CodeOffset, overflow := codeOffset.Uint64WithOverflow()
+	if overflow {
+		uint64CodeOffset = 0xffffffffffffffff
+	}
+	chunksGas := evm.Accesses.TouchCodeChunksRangeAndChargeGas(contract.codeAddress(), uint64CodeOffset, length.Uint64(), uint64(len(contract.Code)), false)
+	if gas, overflow = math.SafeAdd(gas, chunksGas); overflow {
+		return 0, ErrGasUintOverflow
+	}
+	return gas, nil
+}
+
+func gasExtCodeCopy4762(evm *EVM, contract *Contract, stack *stack.Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	// memory expansion first (dynamic part of pre-2929 implementation)
+	gas, err := gasExtCodeCopy(evm, contract, stack, mem, memorySize)
+	if err != nil {
+		return 0, err
+	}
+	addr := libcommon.Address(stack.Peek().Bytes20())
+	witnessGas := warmIfFree(evm.Accesses.TouchAndChargeMessageCall(addr))
+	codeOffset, length := stack.Back(2), stack.Back(3)
+	uint64CodeOffset, overflow := codeOffset.Uint64WithOverflow()
+	if overflow {
+		uint64CodeOffset = 0xffffffffffffffff
+	}
+	codeLen := uint64(evm.IntraBlockState().GetCodeSize(addr))
+	witnessGas += evm.Accesses.TouchCodeChunksRangeAndChargeGas(addr, uint64CodeOffset, length.Uint64(), codeLen, false)
+	if gas, overflow = math.SafeAdd(gas, witnessGas); overflow {
+		return 0, ErrGasUintOverflow
+	}
+	return gas, nil
+}
+
+func makeCallVariantGasCallEIP4762(oldCalculator gasFunc, transfersValue bool) gasFunc {
+	return func(evm *EVM, contract *Contract, stack *stack.Stack, mem *Memory, memorySize uint64) (uint64, error) {
+		addr := libcommon.Address(stack.Back(1).Bytes20())
+		var witnessGas uint64
+		if _, isPrecompile := evm.precompile(addr); !isPrecompile {
+			witnessGas = warmIfFree(evm.Accesses.TouchAndChargeMessageCall(addr))
+		}
+		if transfersValue && !stack.Back(2).IsZero() {
+			witnessGas += evm.Accesses.TouchAndChargeValueTransfer(contract.Address(), addr)
+		}
+		// Charge the witness costs here already, to correctly calculate available
+		// gas for call
+		if !contract.UseGas(witnessGas) {
+			return 0, ErrOutOfGas
+		}
+		gas, err := oldCalculator(evm, contract, stack, mem, memorySize)
+		if err != nil {
+			return gas, err
+		}
+		// Same as for EIP-2929: add the witness charge back, so that it becomes part of
+		// the dynamic gas reported to tracers.
+		contract.Gas += witnessGas
+		var overflow bool
+		if gas, overflow = math.SafeAdd(gas, witnessGas); overflow {
+			return 0, ErrGasUintOverflow
+		}
+		return gas, nil
+	}
+}
+
+var (
+	gasCallEIP4762         = makeCallVariantGasCallEIP4762(gasCall, true)
+	gasCallCodeEIP4762     = makeCallVariantGasCallEIP4762(gasCallCode, true)
+	gasDelegateCallEIP4762 = makeCallVariantGasCallEIP4762(gasDelegateCall, false)
+	gasStaticCallEIP4762   = makeCallVariantGasCallEIP4762(gasStaticCall, false)
+)
+
+func gasSelfdestructEIP4762(evm *EVM, contract *Contract, stack *stack.Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	var (
+		beneficiaryAddr = libcommon.Address(stack.Peek().Bytes20())
+		contractAddr    = contract.Address()
+		transfersValue  = !evm.IntraBlockState().GetBalance(contractAddr).IsZero()
+	)
+	gas := evm.Accesses.TouchBalance(contractAddr, transfersValue)
+	if beneficiaryAddr != contractAddr {
+		gas += evm.Accesses.TouchBalance(beneficiaryAddr, transfersValue)
+		// Sending value to an empty account creates it: charge the creation like
+		// before EIP-4762, and the writes of the header leaves of the new account.
+		if transfersValue && evm.IntraBlockState().Empty(beneficiaryAddr) {
+			gas += params.CreateBySelfdestructGas
+			gas += evm.Accesses.TouchAndChargeAccountCreation(beneficiaryAddr)
+		}
+	}
+	return gas, nil
+}
+
+// enable4762 applies EIP-4762 (Statelessness gas cost changes)
+// - Replaces the EIP-2929 access costs with verkle witness costs
+// - Charges the code chunks accessed by the executing contract (in the interpreter loop)
+func enable4762(jt *JumpTable) {
+	jt[SLOAD].constantGas = 0
+	jt[SLOAD].dynamicGas = gasSLoad4762
+
+	jt[SSTORE].dynamicGas = gasSStore4762
+
+	jt[BALANCE].constantGas = 0
+	jt[BALANCE].dynamicGas = gasBalance4762
+
+	jt[EXTCODESIZE].constantGas = 0
+	jt[EXTCODESIZE].dynamicGas = gasExtCodeSize4762
+
+	jt[EXTCODEHASH].constantGas = 0
+	jt[EXTCODEHASH].dynamicGas = gasExtCodeHash4762
+
+	jt[CODECOPY].dynamicGas = gasCodeCopy4762
+
+	jt[EXTCODECOPY].constantGas = 0
+	jt[EXTCODECOPY].dynamicGas = gasExtCodeCopy4762
+
+	jt[CALL].constantGas = 0
+	jt[CALL].dynamicGas = gasCallEIP4762
+
+	jt[CALLCODE].constantGas = 0
+	jt[CALLCODE].dynamicGas = gasCallCodeEIP4762
+
+	jt[STATICCALL].constantGas = 0
+	jt[STATICCALL].dynamicGas = gasStaticCallEIP4762
+
+	jt[DELEGATECALL].constantGas = 0
+	jt[DELEGATECALL].dynamicGas = gasDelegateCallEIP4762
+
+	jt[SELFDESTRUCT].dynamicGas = gasSelfdestructEIP4762
+}
@@ -17,6 +17,7 @@
 package runtime
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"math/big"
@@ -381,6 +382,18 @@ func benchmarkNonModifyingCode(b *testing.B, gas uint64, code []byte, name strin
 			vmenv.Call(sender, destination, nil, gas, cfg.Value, false /* bailout */) // nolint:errcheck
 		}
 	})
+
+	// same code, with the static gas charged per basic block
+	cfg.EVMConfig.AnalysisCache = vm.NewAnalysisCache(vm.DefaultAnalysisCacheSize)
+	vmenv = NewEnv(cfg)
+	vmenv.Call(sender, destination, nil, gas, cfg.Value, false /* bailout */) // nolint:errcheck
+
+	b.Run(name+"-blockgas", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			vmenv.Call(sender, destination, nil, gas, cfg.Value, false /* bailout */) // nolint:errcheck
+		}
+	})
 }
 
 // BenchmarkSimpleLoop test a pretty simple loop which loops until OOG
@@ -481,6 +494,18 @@ func BenchmarkSimpleLoop(b *testing.B) {
 		byte(vm.JUMP),
 	}
 
+	arithmeticLoop := []byte{
+		byte(vm.PUSH1), 0, // [ count ]
+		byte(vm.JUMPDEST),
+		byte(vm.PUSH1), 1,
+		byte(vm.ADD),
+		byte(vm.DUP1), byte(vm.PUSH1), 3, byte(vm.MUL), byte(vm.PUSH1), 7, byte(vm.XOR), byte(vm.POP),
+		byte(vm.DUP1), byte(vm.PUSH1), 5, byte(vm.SHL), byte(vm.PUSH1), 2, byte(vm.AND), byte(vm.POP),
+		byte(vm.DUP1), byte(vm.DUP1), byte(vm.EQ), byte(vm.ISZERO), byte(vm.POP),
+		byte(vm.PUSH1), 2, // jumpdestination
+		byte(vm.JUMP),
+	}
+
 	//tracer := vm.NewJSONLogger(nil, os.Stdout)
 	//Execute(loopingCode, nil, &Config{
 	//	EVMConfig: vm.Config{
@@ -494,11 +519,58 @@ func BenchmarkSimpleLoop(b *testing.B) {
 	benchmarkNonModifyingCode(b, 100000000, callInexistant, "call-nonexist-100M")
 	benchmarkNonModifyingCode(b, 100000000, callEOA, "call-EOA-100M")
 	benchmarkNonModifyingCode(b, 100000000, calllRevertingContractWithInput, "call-reverting-100M")
+	benchmarkNonModifyingCode(b, 100000000, arithmeticLoop, "loop-arithmetic-100M")
 
 	//benchmarkNonModifyingCode(10000000, staticCallIdentity, "staticcall-identity-10M", b)
 	//benchmarkNonModifyingCode(10000000, loopingCode, "loop-10M", b)
 }
 
+// TestBlockGasCharging checks that charging the static gas per basic block gives the same
+// results as charging it per instruction, in particular when running out of gas.
+func TestBlockGasCharging(t *testing.T) {
+	t.Parallel()
+	address := libcommon.HexToAddress("0xaa")
+	loop := []byte{
+		byte(vm.PUSH1), 3, // [ count ]
+		byte(vm.JUMPDEST),
+		byte(vm.PUSH1), 1, byte(vm.SWAP1), byte(vm.SUB),
+		byte(vm.DUP1), byte(vm.DUP1), byte(vm.SSTORE),
+		byte(vm.GAS), byte(vm.PUSH1), 0, byte(vm.MSTORE), // gas observed by GAS
+		byte(vm.DUP1), byte(vm.PUSH1), 2, byte(vm.JUMPI),
+		byte(vm.PUSH1), 32, byte(vm.PUSH1), 0, byte(vm.RETURN),
+	}
+	stipend := []byte{
+		byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.SSTORE),
+		byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.SSTORE), // gas observed by the SSTORE stipend check
+	}
+	for i := 0; i < 20; i++ {
+		stipend = append(stipend, byte(vm.PUSH1), 0, byte(vm.POP))
+	}
+
+	_, tx := memdb.NewTestTx(t)
+	run := func(code []byte, gas uint64, cache *vm.AnalysisCache) ([]byte, uint64, error) {
+		cfg := &Config{
+			State:     state.New(state.NewDbStateReader(tx)),
+			GasLimit:  gas,
+			EVMConfig: vm.Config{AnalysisCache: cache},
+		}
+		cfg.State.SetCode(address, code)
+		return Call(address, nil, cfg)
+	}
+
+	for _, code := range [][]byte{loop, stipend} {
+		cache := vm.NewAnalysisCache(16)
+		for gas := uint64(0); gas < 70000; gas += 3 {
+			ret, leftOverGas, err := run(code, gas, nil)
+			blockRet, blockLeftOverGas, blockErr := run(code, gas, cache)
+			if leftOverGas != blockLeftOverGas || fmt.Sprint(err) != fmt.Sprint(blockErr) || !bytes.Equal(ret, blockRet) {
+				t.Fatalf("code %x, gas %d: got (%x, %d, %v) per instruction, (%x, %d, %v) per block",
+					code, gas, ret, leftOverGas, err, blockRet, blockLeftOverGas, blockErr)
+			}
+		}
+	}
+}
+
 // TestEip2929Cases contains various testcases that are used for
 // EIP-2929 about gas repricings
 func TestEip2929Cases(t *testing.T) {
@@ -17,6 +17,7 @@
 package chain
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"sort"
@@ -68,6 +69,10 @@ type Config struct {
 	CancunTime   *big.Int `json:"cancunTime,omitempty"`
 	PragueTime   *big.Int `json:"pragueTime,omitempty"`
 
+	// EIP-6800/EIP-4762: switch to the verkle state tree and its gas schedule (verkle devnets only).
+	// Execution witnesses are only stored for the blocks executed at the chain tip.
+	VerkleTime *big.Int `json:"verkleTime,omitempty"`
+
 	// Optional EIP-4844 parameters
 	MinBlobGasPrice            *uint64 `json:"minBlobGasPrice,omitempty"`
 	MaxBlobGasPerBlock         *uint64 `json:"maxBlobGasPerBlock,omitempty"`
@@ -77,6 +82,10 @@ type Config struct {
 	// (Optional) governance contract where EIP-1559 fees will be sent to that otherwise would be burnt since the London fork
 	BurntContract map[string]common.Address `json:"burntContract,omitempty"`
 
+	// (Optional) chain-specific precompiled contracts, keyed by address.
+	// The implementations are registered by the binary under the given names, see vm.RegisterPrecompiledContract
+	CustomPrecompiles *CustomPrecompiles `json:"customPrecompiles,omitempty"`
+
 	// Various consensus engines
 	Ethash *EthashConfig `json:"ethash,omitempty"`
 	Clique *CliqueConfig `json:"clique,omitempty"`
@@ -219,6 +228,11 @@ func (c *Config) IsPrague(time uint64) bool {
 	return isForked(c.PragueTime, time)
 }
 
+// IsVerkle returns whether time is either equal to the Verkle fork time or greater.
+func (c *Config) IsVerkle(time uint64) bool {
+	return isForked(c.VerkleTime, time)
+}
+
 func (c *Config) GetBurntContract(num uint64) *common.Address {
 	if len(c.BurntContract) == 0 {
 		return nil
@@ -452,6 +466,80 @@ func (c *CliqueConfig) String() string {
 	return "clique"
 }
 
+// CustomPrecompileConfig schedules a chain-specific precompiled contract.
+type CustomPrecompileConfig struct {
+	Name           string `json:"name"`                     // Name the implementation is registered under
+	ActivationTime uint64 `json:"activationTime,omitempty"` // Block time from which the precompile is active, 0 means from genesis
+}
+
+// CustomPrecompiles are the chain-specific precompiled contracts of a chain, keyed by address. The precompiles active
+// from every activation time are computed once, when the set is created, so that Rules don't build them again.
+type CustomPrecompiles struct {
+	configs  map[common.Address]CustomPrecompileConfig
+	schedule []activeCustomPrecompiles // by increasing activation time
+}
+
+type activeCustomPrecompiles struct {
+	time   uint64
+	active map[common.Address]string
+}
+
+// NewCustomPrecompiles schedules the given precompiles, keyed by address
+func NewCustomPrecompiles(configs map[common.Address]CustomPrecompileConfig) *CustomPrecompiles {
+	p := &CustomPrecompiles{configs: configs}
+	times := make([]uint64, 0, len(configs))
+	for _, config := range configs {
+		times = append(times, config.ActivationTime)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	for i, time := range times {
+		if i > 0 && time == times[i-1] {
+			continue
+		}
+		active := make(map[common.Address]string, len(configs))
+		for addr, config := range configs {
+			if config.ActivationTime <= time {
+				active[addr] = config.Name
+			}
+		}
+		p.schedule = append(p.schedule, activeCustomPrecompiles{time: time, active: active})
+	}
+	return p
+}
+
+// Configs returns the configs of the precompiles, by address. The map must not be modified.
+func (p *CustomPrecompiles) Configs() map[common.Address]CustomPrecompileConfig {
+	if p == nil {
+		return nil
+	}
+	return p.configs
+}
+
+// Active returns the names of the precompiles active at the given time, by address. The map must not be modified.
+func (p *CustomPrecompiles) Active(time uint64) map[common.Address]string {
+	if p == nil {
+		return nil
+	}
+	i := sort.Search(len(p.schedule), func(i int) bool { return p.schedule[i].time > time })
+	if i == 0 {
+		return nil
+	}
+	return p.schedule[i-1].active
+}
+
+func (p *CustomPrecompiles) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.configs)
+}
+
+func (p *CustomPrecompiles) UnmarshalJSON(data []byte) error {
+	var configs map[common.Address]CustomPrecompileConfig
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return err
+	}
+	*p = *NewCustomPrecompiles(configs)
+	return nil
+}
+
 // BorConfig is the consensus engine configs for Matic bor based sealing.
 type BorConfig struct {
 	Period                map[string]uint64 `json:"period"`                // Number of seconds between blocks to enforce
@@ -642,7 +730,9 @@ type Rules struct {
 	IsHomestead, IsTangerineWhistle, IsSpuriousDragon       bool
 	IsByzantium, IsConstantinople, IsPetersburg, IsIstanbul bool
 	IsBerlin, IsLondon, IsShanghai, IsCancun, IsPrague      bool
+	IsVerkle                                                bool
 	IsAura                                                  bool
+	CustomPrecompiles                                       map[common.Address]string // active chain-specific precompiles, by name
 }
 
 // Rules ensures c's ChainID is not nil and returns a new Rules instance
@@ -666,7 +756,9 @@ func (c *Config) Rules(num uint64, time uint64) *Rules {
 		IsShanghai:         c.IsShanghai(time) || c.IsAgra(num),
 		IsCancun:           c.IsCancun(time),
 		IsPrague:           c.IsPrague(time),
+		IsVerkle:           c.IsVerkle(time),
 		IsAura:             c.Aura != nil,
+		CustomPrecompiles:  c.CustomPrecompiles.Active(time),
 	}
 }
 
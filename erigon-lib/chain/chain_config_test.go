@@ -17,6 +17,7 @@
 package chain
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -66,3 +67,23 @@ func TestBorKeyValueConfigHelper(t *testing.T) {
 	assert.Equal(t, borKeyValueConfigHelper(burntContract, 41874000), address2)
 	assert.Equal(t, borKeyValueConfigHelper(burntContract, 41874000+1), address2)
 }
+
+func TestCustomPrecompiles(t *testing.T) {
+	var config Config
+	assert.NoError(t, json.Unmarshal([]byte(`{"customPrecompiles": {
+		"0x0000000000000000000000000000000000000100": {"name": "echo"},
+		"0x0000000000000000000000000000000000000101": {"name": "later", "activationTime": 10}
+	}}`), &config))
+	echo, later := common.HexToAddress("0x0100"), common.HexToAddress("0x0101")
+	assert.Equal(t, map[common.Address]string{echo: "echo"}, config.Rules(0, 9).CustomPrecompiles)
+	assert.Equal(t, map[common.Address]string{echo: "echo", later: "later"}, config.Rules(0, 10).CustomPrecompiles)
+
+	enc, err := json.Marshal(config.CustomPrecompiles)
+	assert.NoError(t, err)
+	var decoded CustomPrecompiles
+	assert.NoError(t, json.Unmarshal(enc, &decoded))
+	assert.Equal(t, config.CustomPrecompiles.Configs(), decoded.Configs())
+
+	assert.Nil(t, NewCustomPrecompiles(map[common.Address]CustomPrecompileConfig{later: {Name: "later", ActivationTime: 10}}).Active(9))
+	assert.Nil(t, (&Config{}).Rules(0, 10).CustomPrecompiles)
+}
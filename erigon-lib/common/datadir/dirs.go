@@ -46,6 +46,7 @@ type Dirs struct {
 	Nodes           string
 	CaplinHistory   string
 	CaplinIndexing  string
+	CaplinBlobs     string
 }
 
 func New(datadir string) Dirs {
@@ -74,11 +75,12 @@ func New(datadir string) Dirs {
 		Nodes:           filepath.Join(datadir, "nodes"),
 		CaplinHistory:   filepath.Join(datadir, "caplin/history"),
 		CaplinIndexing:  filepath.Join(datadir, "caplin/indexing"),
+		CaplinBlobs:     filepath.Join(datadir, "caplin/blobs"),
 	}
 
 	dir.MustExist(dirs.Chaindata, dirs.Tmp,
 		dirs.SnapIdx, dirs.SnapHistory, dirs.SnapDomain, dirs.SnapAccessors,
-		dirs.Downloader, dirs.TxPool, dirs.Nodes, dirs.CaplinHistory, dirs.CaplinIndexing)
+		dirs.Downloader, dirs.TxPool, dirs.Nodes, dirs.CaplinHistory, dirs.CaplinIndexing, dirs.CaplinBlobs)
 	return dirs
 }
 
@@ -119,3 +119,15 @@ func (s *TxPoolClient) Status(ctx context.Context, in *txpool_proto.StatusReques
 func (s *TxPoolClient) Nonce(ctx context.Context, in *txpool_proto.NonceRequest, opts ...grpc.CallOption) (*txpool_proto.NonceReply, error) {
 	return s.server.Nonce(ctx, in)
 }
+
+func (s *TxPoolClient) TransactionStatus(ctx context.Context, in *txpool_proto.TransactionStatusRequest, opts ...grpc.CallOption) (*txpool_proto.TransactionStatusReply, error) {
+	return s.server.TransactionStatus(ctx, in)
+}
+
+func (s *TxPoolClient) LocalTransactions(ctx context.Context, in *txpool_proto.LocalTransactionsRequest, opts ...grpc.CallOption) (*txpool_proto.LocalTransactionsReply, error) {
+	return s.server.LocalTransactions(ctx, in)
+}
+
+func (s *TxPoolClient) AddBundle(ctx context.Context, in *txpool_proto.AddBundleRequest, opts ...grpc.CallOption) (*txpool_proto.AddBundleReply, error) {
+	return s.server.AddBundle(ctx, in)
+}
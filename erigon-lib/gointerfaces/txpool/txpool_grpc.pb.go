@@ -21,15 +21,18 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	Txpool_Version_FullMethodName      = "/txpool.Txpool/Version"
-	Txpool_FindUnknown_FullMethodName  = "/txpool.Txpool/FindUnknown"
-	Txpool_Add_FullMethodName          = "/txpool.Txpool/Add"
-	Txpool_Transactions_FullMethodName = "/txpool.Txpool/Transactions"
-	Txpool_All_FullMethodName          = "/txpool.Txpool/All"
-	Txpool_Pending_FullMethodName      = "/txpool.Txpool/Pending"
-	Txpool_OnAdd_FullMethodName        = "/txpool.Txpool/OnAdd"
-	Txpool_Status_FullMethodName       = "/txpool.Txpool/Status"
-	Txpool_Nonce_FullMethodName        = "/txpool.Txpool/Nonce"
+	Txpool_Version_FullMethodName           = "/txpool.Txpool/Version"
+	Txpool_FindUnknown_FullMethodName       = "/txpool.Txpool/FindUnknown"
+	Txpool_Add_FullMethodName               = "/txpool.Txpool/Add"
+	Txpool_Transactions_FullMethodName      = "/txpool.Txpool/Transactions"
+	Txpool_All_FullMethodName               = "/txpool.Txpool/All"
+	Txpool_Pending_FullMethodName           = "/txpool.Txpool/Pending"
+	Txpool_OnAdd_FullMethodName             = "/txpool.Txpool/OnAdd"
+	Txpool_Status_FullMethodName            = "/txpool.Txpool/Status"
+	Txpool_Nonce_FullMethodName             = "/txpool.Txpool/Nonce"
+	Txpool_TransactionStatus_FullMethodName = "/txpool.Txpool/TransactionStatus"
+	Txpool_LocalTransactions_FullMethodName = "/txpool.Txpool/LocalTransactions"
+	Txpool_AddBundle_FullMethodName         = "/txpool.Txpool/AddBundle"
 )
 
 // TxpoolClient is the client API for Txpool service.
@@ -55,6 +58,12 @@ type TxpoolClient interface {
 	Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusReply, error)
 	// returns nonce for given account
 	Nonce(ctx context.Context, in *NonceRequest, opts ...grpc.CallOption) (*NonceReply, error)
+	// returns the sub-pool and position of a transaction, or why it was discarded
+	TransactionStatus(ctx context.Context, in *TransactionStatusRequest, opts ...grpc.CallOption) (*TransactionStatusReply, error)
+	// returns the journaled local transactions, until they are mined in a finalized block
+	LocalTransactions(ctx context.Context, in *LocalTransactionsRequest, opts ...grpc.CallOption) (*LocalTransactionsReply, error)
+	// adds a bundle of transactions, to be included atomically at the top of a block
+	AddBundle(ctx context.Context, in *AddBundleRequest, opts ...grpc.CallOption) (*AddBundleReply, error)
 }
 
 type txpoolClient struct {
@@ -169,6 +178,33 @@ func (c *txpoolClient) Nonce(ctx context.Context, in *NonceRequest, opts ...grpc
 	return out, nil
 }
 
+func (c *txpoolClient) TransactionStatus(ctx context.Context, in *TransactionStatusRequest, opts ...grpc.CallOption) (*TransactionStatusReply, error) {
+	out := new(TransactionStatusReply)
+	err := c.cc.Invoke(ctx, Txpool_TransactionStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *txpoolClient) LocalTransactions(ctx context.Context, in *LocalTransactionsRequest, opts ...grpc.CallOption) (*LocalTransactionsReply, error) {
+	out := new(LocalTransactionsReply)
+	err := c.cc.Invoke(ctx, Txpool_LocalTransactions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *txpoolClient) AddBundle(ctx context.Context, in *AddBundleRequest, opts ...grpc.CallOption) (*AddBundleReply, error) {
+	out := new(AddBundleReply)
+	err := c.cc.Invoke(ctx, Txpool_AddBundle_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TxpoolServer is the server API for Txpool service.
 // All implementations must embed UnimplementedTxpoolServer
 // for forward compatibility
@@ -192,6 +228,12 @@ type TxpoolServer interface {
 	Status(context.Context, *StatusRequest) (*StatusReply, error)
 	// returns nonce for given account
 	Nonce(context.Context, *NonceRequest) (*NonceReply, error)
+	// returns the sub-pool and position of a transaction, or why it was discarded
+	TransactionStatus(context.Context, *TransactionStatusRequest) (*TransactionStatusReply, error)
+	// returns the journaled local transactions, until they are mined in a finalized block
+	LocalTransactions(context.Context, *LocalTransactionsRequest) (*LocalTransactionsReply, error)
+	// adds a bundle of transactions, to be included atomically at the top of a block
+	AddBundle(context.Context, *AddBundleRequest) (*AddBundleReply, error)
 	mustEmbedUnimplementedTxpoolServer()
 }
 
@@ -226,6 +268,15 @@ func (UnimplementedTxpoolServer) Status(context.Context, *StatusRequest) (*Statu
 func (UnimplementedTxpoolServer) Nonce(context.Context, *NonceRequest) (*NonceReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Nonce not implemented")
 }
+func (UnimplementedTxpoolServer) TransactionStatus(context.Context, *TransactionStatusRequest) (*TransactionStatusReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TransactionStatus not implemented")
+}
+func (UnimplementedTxpoolServer) LocalTransactions(context.Context, *LocalTransactionsRequest) (*LocalTransactionsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LocalTransactions not implemented")
+}
+func (UnimplementedTxpoolServer) AddBundle(context.Context, *AddBundleRequest) (*AddBundleReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddBundle not implemented")
+}
 func (UnimplementedTxpoolServer) mustEmbedUnimplementedTxpoolServer() {}
 
 // UnsafeTxpoolServer may be embedded to opt out of forward compatibility for this service.
@@ -404,6 +455,60 @@ func _Txpool_Nonce_Handler(srv interface{}, ctx context.Context, dec func(interf
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Txpool_TransactionStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransactionStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TxpoolServer).TransactionStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Txpool_TransactionStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TxpoolServer).TransactionStatus(ctx, req.(*TransactionStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Txpool_LocalTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LocalTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TxpoolServer).LocalTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Txpool_LocalTransactions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TxpoolServer).LocalTransactions(ctx, req.(*LocalTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Txpool_AddBundle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddBundleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TxpoolServer).AddBundle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Txpool_AddBundle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TxpoolServer).AddBundle(ctx, req.(*AddBundleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Txpool_ServiceDesc is the grpc.ServiceDesc for Txpool service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -443,6 +548,18 @@ var Txpool_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Nonce",
 			Handler:    _Txpool_Nonce_Handler,
 		},
+		{
+			MethodName: "TransactionStatus",
+			Handler:    _Txpool_TransactionStatus_Handler,
+		},
+		{
+			MethodName: "LocalTransactions",
+			Handler:    _Txpool_LocalTransactions_Handler,
+		},
+		{
+			MethodName: "AddBundle",
+			Handler:    _Txpool_AddBundle_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -238,6 +238,9 @@ const VerkleRoots = "VerkleRoots"
 // Mapping [Verkle Root] => [Rlp-Encoded Verkle Node]
 const VerkleTrie = "VerkleTrie"
 
+// Mapping [block number] => [Rlp-Encoded execution witness (verkle proof + pre-state key-values)]
+const VerkleWitnesses = "VerkleWitnesses"
+
 const (
 	// DatabaseInfo is used to store information about data layout.
 	DatabaseInfo = "DbInfo"
@@ -635,6 +638,7 @@ var ChaindataTables = []string{
 
 	VerkleRoots,
 	VerkleTrie,
+	VerkleWitnesses,
 	// Beacon stuff
 	BeaconState,
 	BeaconBlocks,
@@ -681,12 +685,18 @@ var ChaindataTables = []string{
 
 const (
 	RecentLocalTransaction = "RecentLocalTransaction" // sequence_u64 -> tx_hash
+	PrivateTransaction     = "PrivateTransaction"     // tx_hash -> expiry_block_u64 (0 - never expires)
+	ConditionalTransaction = "ConditionalTransaction" // tx_hash -> conditions_json
+	LocalTransaction       = "LocalTransaction"       // tx_hash -> added_unix_u64+mined_block_u64+nonce_u64+sender+tx_rlp : journal of local txs
 	PoolTransaction        = "PoolTransaction"        // txHash -> sender_id_u64+tx_rlp
 	PoolInfo               = "PoolInfo"               // option_key -> option_value
 )
 
 var TxPoolTables = []string{
 	RecentLocalTransaction,
+	PrivateTransaction,
+	ConditionalTransaction,
+	LocalTransaction,
 	PoolTransaction,
 	PoolInfo,
 }
@@ -0,0 +1,117 @@
+/*
+   Copyright 2023 The Erigon contributors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package txpool
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+)
+
+const blobStoreExt = ".tx"
+
+// blobStore keeps the blob transactions of the pool on disk, sidecars included, so that a blob-heavy pool doesn't hold
+// the blobs in memory. There is one file per transaction, with the same content as kv.PoolTransaction values: the
+// sender followed by the RLP of the transaction wrapped with its blobs, commitments and proofs.
+//
+// Files are written without fsync: after a crash, a truncated transaction is dropped on the next start.
+type blobStore struct {
+	dir string
+}
+
+func openBlobStore(dir string) (*blobStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &blobStore{dir: dir}, nil
+}
+
+func (s *blobStore) path(hash []byte) string {
+	return filepath.Join(s.dir, hex.EncodeToString(hash)+blobStoreExt)
+}
+
+func (s *blobStore) put(hash []byte, sender common.Address, rlpTx []byte) error {
+	v := make([]byte, length.Addr+len(rlpTx))
+	copy(v, sender[:])
+	copy(v[length.Addr:], rlpTx)
+	// write then rename, so that readers never see a partial file
+	tmp := s.path(hash) + ".tmp"
+	if err := os.WriteFile(tmp, v, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path(hash))
+}
+
+// get returns a nil rlp if the transaction is not in the store
+func (s *blobStore) get(hash []byte) (rlpTx []byte, sender common.Address, err error) {
+	v, err := os.ReadFile(s.path(hash))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, sender, nil
+	}
+	if err != nil {
+		return nil, sender, err
+	}
+	if len(v) <= length.Addr {
+		return nil, sender, fmt.Errorf("blob store: %x is truncated", hash)
+	}
+	return v[length.Addr:], *(*[length.Addr]byte)(v[:length.Addr]), nil
+}
+
+func (s *blobStore) delete(hash []byte) error {
+	if err := os.Remove(s.path(hash)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// forEach walks all transactions of the store, and cleans up the files of interrupted writes
+func (s *blobStore) forEach(f func(hash []byte, sender common.Address, rlpTx []byte) error) error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasSuffix(name, blobStoreExt) {
+			if err := os.Remove(filepath.Join(s.dir, name)); err != nil {
+				return err
+			}
+			continue
+		}
+		hash, err := hex.DecodeString(strings.TrimSuffix(name, blobStoreExt))
+		if err != nil || len(hash) != length.Hash {
+			continue
+		}
+		rlpTx, sender, err := s.get(hash)
+		if err != nil || rlpTx == nil {
+			if err := s.delete(hash); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := f(hash, sender, rlpTx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+/*
+   Copyright 2023 The Erigon contributors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package txpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces"
+	proto_txpool "github.com/ledgerwatch/erigon-lib/gointerfaces/txpool"
+	"github.com/ledgerwatch/erigon-lib/txpool/txpoolcfg"
+	"github.com/ledgerwatch/erigon-lib/types"
+)
+
+// MaxBundles is the maximum number of bundles waiting for their target block
+const MaxBundles = 1024
+
+var (
+	ErrBundleTooLate  = errors.New("bundle target block already mined")
+	ErrBundlePoolFull = errors.New("too many bundles")
+)
+
+// Bundle is a list of transactions to be included atomically and in order in the given block (eth_sendBundle): either
+// all of them are included, or none. A transaction may only revert if its hash is in RevertingTxHashes.
+// Timestamps equal to 0 are not set. Bundles are not persisted, and never announced to peers.
+type Bundle struct {
+	Hash              common.Hash
+	Txs               [][]byte // rlp of the transactions, as received by eth_sendRawTransaction
+	TxHashes          []common.Hash
+	BlockNumber       uint64
+	MinTimestamp      uint64
+	MaxTimestamp      uint64
+	RevertingTxHashes []common.Hash
+
+	txSlots types.TxSlots // parsed transactions, with their senders
+}
+
+// BundleHash is the hash of a bundle: the keccak256 of the hashes of its transactions
+func BundleHash(txHashes []common.Hash) common.Hash {
+	h := sha3.NewLegacyKeccak256()
+	for _, txHash := range txHashes {
+		h.Write(txHash[:])
+	}
+	var hash common.Hash
+	h.Sum(hash[:0])
+	return hash
+}
+
+// CanRevert tells whether the transaction of the bundle is allowed to revert
+func (b *Bundle) CanRevert(txHash common.Hash) bool {
+	for _, h := range b.RevertingTxHashes {
+		if h == txHash {
+			return true
+		}
+	}
+	return false
+}
+
+// checkBlock tells whether a block of the given number and timestamp is the target of the bundle
+func (b *Bundle) checkBlock(number, timestamp uint64) bool {
+	return number == b.BlockNumber && timestamp >= b.MinTimestamp && (b.MaxTimestamp == 0 || timestamp <= b.MaxTimestamp)
+}
+
+// AddBundle adds a bundle, for the next block if its target block is 0. Adding a known bundle again is a noop.
+// The transactions are parsed, which sets TxHashes and Hash, and must all pass the checks of the local transactions.
+func (p *TxPool) AddBundle(ctx context.Context, bundle *Bundle) error {
+	if len(bundle.Txs) == 0 {
+		return errors.New("empty bundle")
+	}
+	if bundle.MaxTimestamp != 0 && bundle.MaxTimestamp < bundle.MinTimestamp {
+		return fmt.Errorf("bundle maxTimestamp %d is before minTimestamp %d", bundle.MaxTimestamp, bundle.MinTimestamp)
+	}
+	parseCtx := types.NewTxParseContext(p.chainID).ChainIDRequired()
+	parseCtx.ValidateRLP(p.ValidateSerializedTxn)
+	bundle.txSlots = types.TxSlots{}
+	bundle.TxHashes = make([]common.Hash, len(bundle.Txs))
+	for i, rlp := range bundle.Txs {
+		bundle.txSlots.Resize(uint(i + 1))
+		bundle.txSlots.Txs[i] = &types.TxSlot{}
+		bundle.txSlots.IsLocal[i] = true
+		if _, err := parseCtx.ParseTransaction(rlp, 0, bundle.txSlots.Txs[i], bundle.txSlots.Senders.At(i), false /* hasEnvelope */, false /* wrappedWithBlobs */, nil); err != nil {
+			return fmt.Errorf("bundle transaction %d: %w", i, err)
+		}
+		if bundle.txSlots.Txs[i].Type == types.BlobTxType {
+			return fmt.Errorf("bundle transaction %d: blob transactions are not supported in bundles", i)
+		}
+		bundle.TxHashes[i] = bundle.txSlots.Txs[i].IDHash
+	}
+	bundle.Hash = BundleHash(bundle.TxHashes)
+
+	coreDb, cache := p.coreDBWithCache()
+	coreTx, err := coreDb.BeginRo(ctx)
+	if err != nil {
+		return err
+	}
+	defer coreTx.Rollback()
+	cacheView, err := cache.View(ctx, coreTx)
+	if err != nil {
+		return err
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if err := p.senders.registerNewSenders(&bundle.txSlots, p.logger); err != nil {
+		return err
+	}
+	for i, txn := range bundle.txSlots.Txs {
+		if reason := p.validateTx(txn, true /* isLocal */, cacheView); reason != txpoolcfg.Success {
+			return fmt.Errorf("bundle transaction %d: %s", i, reason)
+		}
+	}
+	lastSeenBlock := p.lastSeenBlock.Load()
+	if bundle.BlockNumber == 0 {
+		bundle.BlockNumber = lastSeenBlock + 1
+	}
+	if bundle.BlockNumber <= lastSeenBlock {
+		return fmt.Errorf("%w: target %d, latest %d", ErrBundleTooLate, bundle.BlockNumber, lastSeenBlock)
+	}
+	for _, b := range p.bundles {
+		if b.Hash == bundle.Hash && b.BlockNumber == bundle.BlockNumber {
+			return nil
+		}
+	}
+	if len(p.bundles) >= MaxBundles {
+		return ErrBundlePoolFull
+	}
+	p.bundles = append(p.bundles, bundle)
+	return nil
+}
+
+// CheckBundlePolicy tells whether the transactions of the bundle are still allowed by the admission policy, which
+// may have been reloaded since the bundle was added
+func (p *TxPool) CheckBundlePolicy(bundle *Bundle) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.checkBundlePolicyLocked(bundle)
+}
+
+func (p *TxPool) checkBundlePolicyLocked(bundle *Bundle) error {
+	if p.policy == nil {
+		return nil
+	}
+	for i, txn := range bundle.txSlots.Txs {
+		if rule, ok := p.policy.check(bundle.txSlots.Senders.AddressAt(i), txn); !ok {
+			return fmt.Errorf("bundle transaction %x: %s by rule %s", txn.IDHash, txpoolcfg.PolicyRejected, rule)
+		}
+	}
+	return nil
+}
+
+// Bundles returns the bundles targeting a block of the given number and timestamp, in arrival order
+func (p *TxPool) Bundles(blockNum, timestamp uint64) []*Bundle {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	var bundles []*Bundle
+	for _, b := range p.bundles {
+		if b.checkBlock(blockNum, timestamp) {
+			bundles = append(bundles, b)
+		}
+	}
+	return bundles
+}
+
+// discardPastBundlesLocked drops the bundles targeting the given block or an earlier one
+func (p *TxPool) discardPastBundlesLocked(blockNum uint64) {
+	kept := p.bundles[:0]
+	for _, b := range p.bundles {
+		if b.BlockNumber > blockNum {
+			kept = append(kept, b)
+		}
+	}
+	for i := len(kept); i < len(p.bundles); i++ {
+		p.bundles[i] = nil
+	}
+	p.bundles = kept
+}
+
+// discardDeniedBundlesLocked drops the bundles with a transaction the admission policy denies
+func (p *TxPool) discardDeniedBundlesLocked() {
+	kept := p.bundles[:0]
+	for _, b := range p.bundles {
+		if err := p.checkBundlePolicyLocked(b); err != nil {
+			p.logger.Debug("[txpool] dropping bundle", "bundle", b.Hash, "err", err)
+			continue
+		}
+		kept = append(kept, b)
+	}
+	for i := len(kept); i < len(p.bundles); i++ {
+		p.bundles[i] = nil
+	}
+	p.bundles = kept
+}
+
+// BundleFromProto converts the request of the AddBundle gRPC method. The transactions are not parsed, so TxHashes
+// and Hash are not set until the bundle is added to the pool.
+func BundleFromProto(in *proto_txpool.AddBundleRequest) *Bundle {
+	b := &Bundle{
+		Txs:          in.RlpTxs,
+		BlockNumber:  in.BlockNumber,
+		MinTimestamp: in.MinTimestamp,
+		MaxTimestamp: in.MaxTimestamp,
+	}
+	for _, h := range in.RevertingTxHashes {
+		b.RevertingTxHashes = append(b.RevertingTxHashes, gointerfaces.ConvertH256ToHash(h))
+	}
+	return b
+}
+
+// BundleToProto converts a bundle to the request of the AddBundle gRPC method
+func BundleToProto(b *Bundle) *proto_txpool.AddBundleRequest {
+	out := &proto_txpool.AddBundleRequest{
+		RlpTxs:       b.Txs,
+		BlockNumber:  b.BlockNumber,
+		MinTimestamp: b.MinTimestamp,
+		MaxTimestamp: b.MaxTimestamp,
+	}
+	for _, h := range b.RevertingTxHashes {
+		out.RevertingTxHashes = append(out.RevertingTxHashes, gointerfaces.ConvertHashToH256(h))
+	}
+	return out
+}
@@ -0,0 +1,140 @@
+/*
+   Copyright 2023 The Erigon contributors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package txpool
+
+import (
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
+	proto_txpool "github.com/ledgerwatch/erigon-lib/gointerfaces/txpool"
+)
+
+// TxConditions are the preconditions of a conditional transaction (ERC-4337 eth_sendRawTransactionConditional): it
+// may only be included in a block within the bounds, on top of the given storage of the known accounts.
+// Bounds equal to 0 are not set.
+type TxConditions struct {
+	BlockNumberMin uint64                           `json:"blockNumberMin,omitempty"`
+	BlockNumberMax uint64                           `json:"blockNumberMax,omitempty"`
+	TimestampMin   uint64                           `json:"timestampMin,omitempty"`
+	TimestampMax   uint64                           `json:"timestampMax,omitempty"`
+	KnownAccounts  map[common.Address]*KnownAccount `json:"knownAccounts,omitempty"`
+}
+
+// KnownAccount is the expected storage of an account: either its storage root, or the values of some of its slots.
+type KnownAccount struct {
+	StorageRoot *common.Hash                `json:"storageRoot,omitempty"`
+	Slots       map[common.Hash]common.Hash `json:"slots,omitempty"`
+}
+
+// CheckBlock tells whether a block of the given number and timestamp is within the bounds.
+func (c *TxConditions) CheckBlock(number, timestamp uint64) bool {
+	return number >= c.BlockNumberMin && timestamp >= c.TimestampMin && !c.Expired(number, timestamp)
+}
+
+// Expired tells whether blocks from the given number and timestamp on are all out of the bounds.
+func (c *TxConditions) Expired(number, timestamp uint64) bool {
+	return (c.BlockNumberMax != 0 && number > c.BlockNumberMax) || (c.TimestampMax != 0 && timestamp > c.TimestampMax)
+}
+
+// holdAfter tells whether the known accounts still have the expected storage after the given changes. The storage
+// root of an account is assumed to change with any of its slots.
+func (c *TxConditions) holdAfter(stateChange *remote.StateChange) bool {
+	for _, change := range stateChange.Changes {
+		known, ok := c.KnownAccounts[gointerfaces.ConvertH160toAddress(change.Address)]
+		if !ok {
+			continue
+		}
+		if change.Action == remote.Action_REMOVE {
+			if known.StorageRoot != nil && *known.StorageRoot != emptyStorageRoot {
+				return false
+			}
+			for _, value := range known.Slots {
+				if value != (common.Hash{}) {
+					return false
+				}
+			}
+			continue
+		}
+		if known.StorageRoot != nil && len(change.StorageChanges) > 0 {
+			return false
+		}
+		for _, storageChange := range change.StorageChanges {
+			value, ok := known.Slots[gointerfaces.ConvertH256ToHash(storageChange.Location)]
+			if ok && value != common.BytesToHash(storageChange.Data) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// emptyStorageRoot is the storage root of an account without storage
+var emptyStorageRoot = common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+
+// ConditionsFromProto converts the conditions of a gRPC request, nil meaning no conditions.
+func ConditionsFromProto(in *proto_txpool.TxConditions) *TxConditions {
+	if in == nil {
+		return nil
+	}
+	c := &TxConditions{
+		BlockNumberMin: in.BlockNumberMin,
+		BlockNumberMax: in.BlockNumberMax,
+		TimestampMin:   in.TimestampMin,
+		TimestampMax:   in.TimestampMax,
+	}
+	if len(in.KnownAccounts) > 0 {
+		c.KnownAccounts = make(map[common.Address]*KnownAccount, len(in.KnownAccounts))
+	}
+	for _, account := range in.KnownAccounts {
+		known := &KnownAccount{}
+		if account.StorageRoot != nil {
+			root := common.Hash(gointerfaces.ConvertH256ToHash(account.StorageRoot))
+			known.StorageRoot = &root
+		} else {
+			known.Slots = make(map[common.Hash]common.Hash, len(account.Slots))
+			for _, slot := range account.Slots {
+				known.Slots[gointerfaces.ConvertH256ToHash(slot.Key)] = gointerfaces.ConvertH256ToHash(slot.Value)
+			}
+		}
+		c.KnownAccounts[gointerfaces.ConvertH160toAddress(account.Address)] = known
+	}
+	return c
+}
+
+// ConditionsToProto is the reverse of ConditionsFromProto.
+func ConditionsToProto(c *TxConditions) *proto_txpool.TxConditions {
+	if c == nil {
+		return nil
+	}
+	out := &proto_txpool.TxConditions{
+		BlockNumberMin: c.BlockNumberMin,
+		BlockNumberMax: c.BlockNumberMax,
+		TimestampMin:   c.TimestampMin,
+		TimestampMax:   c.TimestampMax,
+	}
+	for address, known := range c.KnownAccounts {
+		account := &proto_txpool.KnownAccount{Address: gointerfaces.ConvertAddressToH160(address)}
+		if known.StorageRoot != nil {
+			account.StorageRoot = gointerfaces.ConvertHashToH256(*known.StorageRoot)
+		}
+		for key, value := range known.Slots {
+			account.Slots = append(account.Slots, &proto_txpool.KnownSlot{Key: gointerfaces.ConvertHashToH256(key), Value: gointerfaces.ConvertHashToH256(value)})
+		}
+		out.KnownAccounts = append(out.KnownAccounts, account)
+	}
+	return out
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/gointerfaces/grpcutil"
 	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
 	"github.com/ledgerwatch/erigon-lib/gointerfaces/sentry"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/types"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/rlp"
 	types2 "github.com/ledgerwatch/erigon-lib/types"
@@ -235,21 +236,12 @@ func (f *Fetch) handleInboundMessage(ctx context.Context, req *sentry.InboundMes
 			return err
 		}
 		if len(unknownHashes) > 0 {
-			var encodedRequest []byte
-			var messageID sentry.MessageId
-			if encodedRequest, err = types2.EncodeGetPooledTransactions66(unknownHashes, uint64(1), nil); err != nil {
-				return err
-			}
-			messageID = sentry.MessageId_GET_POOLED_TRANSACTIONS_66
-			if _, err = sentryClient.SendMessageById(f.ctx, &sentry.SendMessageByIdRequest{
-				Data:   &sentry.OutboundMessageData{Id: messageID, Data: encodedRequest},
-				PeerId: req.PeerId,
-			}, &grpc.EmptyCallOption{}); err != nil {
+			if err := f.requestPooledTxs(req.PeerId, unknownHashes, sentryClient); err != nil {
 				return err
 			}
 		}
 	case sentry.MessageId_NEW_POOLED_TRANSACTION_HASHES_68:
-		_, _, hashes, _, err := rlp.ParseAnnouncements(req.Data, 0)
+		_, sizes, hashes, _, err := rlp.ParseAnnouncements(req.Data, 0)
 		if err != nil {
 			return fmt.Errorf("parsing NewPooledTransactionHashes88: %w", err)
 		}
@@ -257,20 +249,28 @@ func (f *Fetch) handleInboundMessage(ctx context.Context, req *sentry.InboundMes
 		if err != nil {
 			return err
 		}
-
-		if len(unknownHashes) > 0 {
-			var encodedRequest []byte
-			var messageID sentry.MessageId
-			if encodedRequest, err = types2.EncodeGetPooledTransactions66(unknownHashes, uint64(1), nil); err != nil {
-				return err
+		if len(unknownHashes) == 0 {
+			return nil
+		}
+		announcedSizes := make(map[string]uint32, len(sizes))
+		for i, size := range sizes {
+			announcedSizes[string(hashes[i*32:(i+1)*32])] = size
+		}
+		// split the request by the announced sizes, so that replies stay small and blob txs are fetched a few at a time
+		for len(unknownHashes) > 0 {
+			n, requestSize := 0, 0
+			for n < len(unknownHashes) {
+				size := int(announcedSizes[string(unknownHashes[n:n+32])])
+				if n > 0 && requestSize+size > p2pTxPacketLimit {
+					break
+				}
+				requestSize += size
+				n += 32
 			}
-			messageID = sentry.MessageId_GET_POOLED_TRANSACTIONS_66
-			if _, err = sentryClient.SendMessageById(f.ctx, &sentry.SendMessageByIdRequest{
-				Data:   &sentry.OutboundMessageData{Id: messageID, Data: encodedRequest},
-				PeerId: req.PeerId,
-			}, &grpc.EmptyCallOption{}); err != nil {
+			if err := f.requestPooledTxs(req.PeerId, unknownHashes[:n], sentryClient); err != nil {
 				return err
 			}
+			unknownHashes = unknownHashes[n:]
 		}
 	case sentry.MessageId_GET_POOLED_TRANSACTIONS_66:
 		//TODO: handleInboundMessage is single-threaded - means it can accept as argument couple buffers (or analog of txParseContext). Protobuf encoding will copy data anyway, but DirectClient doesn't
@@ -298,6 +298,9 @@ func (f *Fetch) handleInboundMessage(ctx context.Context, req *sentry.InboundMes
 			}
 
 			txnHash := hashes[i:cmp.Min(i+hashSize, len(hashes))]
+			if f.pool.IsPrivate(txnHash) {
+				continue
+			}
 			txn, err := f.pool.GetRlp(tx, txnHash)
 			if err != nil {
 				return err
@@ -348,6 +351,9 @@ func (f *Fetch) handleInboundMessage(ctx context.Context, req *sentry.InboundMes
 			}); err != nil {
 				return err
 			}
+			// "Nodes MUST NOT automatically broadcast blob transactions to their peers" - EIP-4844, they are fetched
+			// after an announcement instead
+			txs = withoutBlobTxs(txs)
 		case sentry.MessageId_POOLED_TRANSACTIONS_66:
 			if err := f.threadSafeParsePooledTxn(func(parseContext *types2.TxParseContext) error {
 				if _, _, err := types2.ParsePooledTransactions66(req.Data, 0, parseContext, &txs, func(hash []byte) error {
@@ -380,6 +386,28 @@ func (f *Fetch) handleInboundMessage(ctx context.Context, req *sentry.InboundMes
 	return nil
 }
 
+func (f *Fetch) requestPooledTxs(peerID *types.H512, hashes types2.Hashes, sentryClient sentry.SentryClient) error {
+	encodedRequest, err := types2.EncodeGetPooledTransactions66(hashes, uint64(1), nil)
+	if err != nil {
+		return err
+	}
+	_, err = sentryClient.SendMessageById(f.ctx, &sentry.SendMessageByIdRequest{
+		Data:   &sentry.OutboundMessageData{Id: sentry.MessageId_GET_POOLED_TRANSACTIONS_66, Data: encodedRequest},
+		PeerId: peerID,
+	}, &grpc.EmptyCallOption{})
+	return err
+}
+
+func withoutBlobTxs(txs types2.TxSlots) types2.TxSlots {
+	var res types2.TxSlots
+	for i, txn := range txs.Txs {
+		if txn.Type != types2.BlobTxType {
+			res.Append(txn, txs.Senders.At(i), txs.IsLocal[i])
+		}
+	}
+	return res
+}
+
 func (f *Fetch) receivePeerLoop(sentryClient sentry.SentryClient) {
 	for {
 		select {
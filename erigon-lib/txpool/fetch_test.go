@@ -30,7 +30,9 @@ import (
 	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
 	"github.com/ledgerwatch/erigon-lib/gointerfaces/sentry"
 	"github.com/ledgerwatch/erigon-lib/gointerfaces/types"
+	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/erigon-lib/rlp"
 	types3 "github.com/ledgerwatch/erigon-lib/types"
 	"github.com/ledgerwatch/log/v3"
 	"github.com/stretchr/testify/assert"
@@ -68,6 +70,37 @@ func TestFetch(t *testing.T) {
 
 }
 
+func TestFetchAnnouncedBySize(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := NewMockSentry(ctx)
+	sentryClient := direct.NewSentryClientDirect(direct.ETH68, m)
+	pool := &PoolMock{
+		StartedFunc: func() bool { return true },
+		FilterKnownIdHashesFunc: func(tx kv.Tx, hashes types3.Hashes) (types3.Hashes, error) {
+			return hashes[32:], nil // the first one is known
+		},
+	}
+	fetch := NewFetch(ctx, []direct.SentryClient{sentryClient}, pool, &remote.KVClientMock{}, nil, memdb.NewTestPoolDB(t), *u256.N1, log.New())
+
+	// 3 small txs, then 2 blob txs which don't fit in one request
+	txTypes, sizes, hashes := []byte{2, 2, 2, 3, 3}, []uint32{100, 100, 100, 80_000, 80_000}, toHashes(1, 2, 3, 4, 5)
+	data := make([]byte, rlp.AnnouncementsLen(txTypes, sizes, hashes))
+	rlp.EncodeAnnouncements(txTypes, sizes, hashes, data)
+	err := fetch.handleInboundMessage(ctx, &sentry.InboundMessage{Id: sentry.MessageId_NEW_POOLED_TRANSACTION_HASHES_68, Data: data, PeerId: peerID}, sentryClient)
+	require.NoError(t, err)
+
+	calls := m.SendMessageByIdCalls()
+	require.Equal(t, 2, len(calls))
+	for i, expected := range []types3.Hashes{toHashes(2, 3, 4), toHashes(5)} {
+		require.Equal(t, sentry.MessageId_GET_POOLED_TRANSACTIONS_66, calls[i].SendMessageByIdRequest.Data.Id)
+		_, requested, _, err := types3.ParseGetPooledTransactions66(calls[i].SendMessageByIdRequest.Data.Data, 0, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []byte(expected), requested)
+	}
+}
+
 func TestSendTxPropagate(t *testing.T) {
 	ctx, cancelFn := context.WithCancel(context.Background())
 	defer cancelFn()
@@ -0,0 +1,401 @@
+/*
+   Copyright 2023 The Erigon contributors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package txpool
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/txpool/txpoolcfg"
+	"github.com/ledgerwatch/erigon-lib/types"
+)
+
+// maxRebroadcastBackoff caps the delay between two rebroadcasts of a local tx, as a multiple of cfg.RebroadcastEvery
+const maxRebroadcastBackoff = 32
+
+const journalHeaderLen = 8 + 8 + 8 + length.Addr // added, mined block, nonce, sender
+
+// localJournal keeps the local transactions until they are mined in a finalized block, or for cfg.LocalJournalLifetime.
+// The pool adds them again whenever they are not in the pool anymore (sub-pool overflow, restart, unwind to a chain
+// which doesn't include them), and rebroadcasts the pending ones. The txs of a sender are kept whatever their nonce
+// gaps: the ones which can't be executed yet wait in the queued sub-pool.
+//
+// Private and conditional txs are not journaled, as adding them again without their options would change them.
+// It is not thread-safe: the pool uses it under its lock.
+type localJournal struct {
+	txs   map[string]*journalTx // tx_hash => journaled tx
+	dirty map[string]struct{}   // tx_hashes to write, or to delete, on the next flush
+}
+
+type journalTx struct {
+	sender     common.Address
+	nonce      uint64
+	rlp        []byte // wrapped with blobs for blob txs, nil once written to kv.LocalTransaction
+	added      uint64 // unix time
+	minedBlock uint64 // 0 if the tx isn't mined
+
+	// not persisted
+	dropReason    txpoolcfg.DiscardReason // why the tx is not in the pool anymore, NotSet if it is
+	broadcasts    int                     // rebroadcasts since the tx was journaled
+	lastBroadcast time.Time
+}
+
+func newLocalJournal() *localJournal {
+	return &localJournal{txs: map[string]*journalTx{}, dirty: map[string]struct{}{}}
+}
+
+func (j *localJournal) add(hashStr string, sender common.Address, nonce uint64, rlpTx []byte, now time.Time) {
+	if _, ok := j.txs[hashStr]; ok {
+		return
+	}
+	j.txs[hashStr] = &journalTx{sender: sender, nonce: nonce, rlp: rlpTx, added: uint64(now.Unix()), lastBroadcast: now}
+	j.dirty[hashStr] = struct{}{}
+}
+
+func (j *localJournal) remove(hashStr string) {
+	if _, ok := j.txs[hashStr]; !ok {
+		return
+	}
+	delete(j.txs, hashStr)
+	j.dirty[hashStr] = struct{}{}
+}
+
+// onAdd is called when a tx enters the pool, for example when the block which included it is unwound
+func (j *localJournal) onAdd(hashStr string) {
+	jt, ok := j.txs[hashStr]
+	if !ok {
+		return
+	}
+	jt.dropReason = txpoolcfg.NotSet
+	if jt.minedBlock != 0 {
+		jt.minedBlock = 0
+		j.dirty[hashStr] = struct{}{}
+	}
+}
+
+// onDiscard is called when a tx leaves the pool, blockNum being the last block seen by the pool
+func (j *localJournal) onDiscard(hashStr string, reason txpoolcfg.DiscardReason, blockNum uint64) {
+	jt, ok := j.txs[hashStr]
+	if !ok {
+		return
+	}
+	switch reason {
+	case txpoolcfg.Mined:
+		jt.minedBlock = blockNum
+		j.dirty[hashStr] = struct{}{}
+	case txpoolcfg.ReplacedByHigherTip, txpoolcfg.NonceTooLow, txpoolcfg.PolicyRejected:
+		// replaced by the user, another tx with the same nonce was mined, or denied: adding it again is pointless
+		j.remove(hashStr)
+	default:
+		jt.dropReason = reason
+	}
+}
+
+// onFinalized forgets the txs mined up to the finalized block
+func (j *localJournal) onFinalized(finalizedBlock uint64) {
+	for hashStr, jt := range j.txs {
+		if jt.minedBlock != 0 && jt.minedBlock <= finalizedBlock {
+			j.remove(hashStr)
+		}
+	}
+}
+
+// rebroadcastDue tells whether a pending tx was last broadcast long enough ago
+func (jt *journalTx) rebroadcastDue(now time.Time, every time.Duration) bool {
+	backoff := maxRebroadcastBackoff
+	if jt.broadcasts < 5 {
+		backoff = 1 << jt.broadcasts
+	}
+	return now.Sub(jt.lastBroadcast) >= time.Duration(backoff)*every
+}
+
+func (j *localJournal) load(tx kv.Tx) error {
+	it, err := tx.Range(kv.LocalTransaction, nil, nil)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for it.HasNext() {
+		k, v, err := it.Next()
+		if err != nil {
+			return err
+		}
+		if len(v) <= journalHeaderLen {
+			return fmt.Errorf("local tx journal: %x is truncated", k)
+		}
+		// the rlp is read from the db when the tx has to be added again
+		j.txs[string(k)] = &journalTx{
+			added:         binary.BigEndian.Uint64(v),
+			minedBlock:    binary.BigEndian.Uint64(v[8:]),
+			nonce:         binary.BigEndian.Uint64(v[16:]),
+			sender:        *(*[length.Addr]byte)(v[24:journalHeaderLen]),
+			lastBroadcast: now,
+		}
+	}
+	return nil
+}
+
+// flush writes the txs added, mined, unwound or removed since the last flush, and drops their rlp from memory
+func (j *localJournal) flush(tx kv.RwTx) error {
+	for hashStr := range j.dirty {
+		jt, ok := j.txs[hashStr]
+		if !ok {
+			if err := tx.Delete(kv.LocalTransaction, []byte(hashStr)); err != nil {
+				return err
+			}
+			continue
+		}
+		var v []byte
+		if jt.rlp != nil {
+			v = make([]byte, journalHeaderLen+len(jt.rlp))
+			copy(v[journalHeaderLen:], jt.rlp)
+		} else {
+			// only the mined block changed
+			prev, err := tx.GetOne(kv.LocalTransaction, []byte(hashStr))
+			if err != nil {
+				return err
+			}
+			if len(prev) <= journalHeaderLen {
+				continue
+			}
+			v = common.Copy(prev)
+		}
+		binary.BigEndian.PutUint64(v, jt.added)
+		binary.BigEndian.PutUint64(v[8:], jt.minedBlock)
+		binary.BigEndian.PutUint64(v[16:], jt.nonce)
+		copy(v[24:journalHeaderLen], jt.sender[:])
+		if err := tx.Put(kv.LocalTransaction, []byte(hashStr), v); err != nil {
+			return err
+		}
+	}
+	for hashStr := range j.dirty {
+		if jt, ok := j.txs[hashStr]; ok {
+			jt.rlp = nil
+		}
+		delete(j.dirty, hashStr)
+	}
+	return nil
+}
+
+// getRlp returns the rlp of a journaled tx, from memory or from the db
+func (j *localJournal) getRlp(tx kv.Tx, hashStr string, jt *journalTx) ([]byte, error) {
+	if jt.rlp != nil {
+		return jt.rlp, nil
+	}
+	v, err := tx.GetOne(kv.LocalTransaction, []byte(hashStr))
+	if err != nil {
+		return nil, err
+	}
+	if len(v) <= journalHeaderLen {
+		return nil, nil
+	}
+	return common.Copy(v[journalHeaderLen:]), nil
+}
+
+// processLocalJournal forgets the expired txs of the journal, adds again the ones which are not in the pool, and returns
+// the announcements of the pending ones which are due for a rebroadcast
+func (p *TxPool) processLocalJournal(ctx context.Context, db kv.RoDB) (types.Announcements, error) {
+	var rebroadcasts types.Announcements
+	if !p.Started() {
+		return rebroadcasts, nil
+	}
+	now := time.Now()
+	type dropped struct {
+		hashStr string
+		jt      journalTx
+	}
+	var toAdd []dropped
+
+	p.lock.Lock()
+	lastSeenBlock := p.lastSeenBlock.Load()
+	expiry := uint64(now.Add(-p.cfg.LocalJournalLifetime).Unix())
+	for hashStr, jt := range p.journal.txs {
+		if jt.added < expiry {
+			p.journal.remove(hashStr)
+			continue
+		}
+		mt, ok := p.byHash[hashStr]
+		if !ok {
+			// unwound blocks are usually re-added by OnNewBlock, this is for the rest
+			if jt.minedBlock == 0 || jt.minedBlock > lastSeenBlock {
+				toAdd = append(toAdd, dropped{hashStr: hashStr, jt: *jt})
+			}
+			continue
+		}
+		if mt.currentSubPool != PendingSubPool || !jt.rebroadcastDue(now, p.cfg.RebroadcastEvery) {
+			continue
+		}
+		jt.broadcasts++
+		jt.lastBroadcast = now
+		rebroadcasts.Append(mt.Tx.Type, mt.Tx.Size, mt.Tx.IDHash[:])
+	}
+	p.lock.Unlock()
+
+	if len(toAdd) == 0 {
+		return rebroadcasts, nil
+	}
+	// add the txs of a sender in nonce order, so that the first ones are not considered as gaps
+	sort.Slice(toAdd, func(i, j int) bool {
+		if c := bytes.Compare(toAdd[i].jt.sender[:], toAdd[j].jt.sender[:]); c != 0 {
+			return c < 0
+		}
+		return toAdd[i].jt.nonce < toAdd[j].jt.nonce
+	})
+	if err := db.View(ctx, func(tx kv.Tx) error {
+		parseCtx := types.NewTxParseContext(p.chainID)
+		parseCtx.WithSender(false)
+		var txs types.TxSlots
+		hashes := make([]string, 0, len(toAdd))
+		for _, d := range toAdd {
+			rlpTx, err := p.journal.getRlp(tx, d.hashStr, &d.jt)
+			if err != nil {
+				return err
+			}
+			txn := &types.TxSlot{}
+			if rlpTx != nil {
+				_, err = parseCtx.ParseTransaction(rlpTx, 0, txn, nil, false /* hasEnvelope */, true /* wrappedWithBlobs */, nil)
+			}
+			if rlpTx == nil || err != nil {
+				p.logger.Warn("[txpool] local tx journal: dropping unreadable tx", "idHash", fmt.Sprintf("%x", d.hashStr), "err", err)
+				p.lock.Lock()
+				p.journal.remove(d.hashStr)
+				p.lock.Unlock()
+				continue
+			}
+			i := len(hashes)
+			txs.Resize(uint(i + 1))
+			txs.Txs[i] = txn
+			txs.IsLocal[i] = true
+			copy(txs.Senders.At(i), d.jt.sender[:])
+			hashes = append(hashes, d.hashStr)
+		}
+		if len(hashes) == 0 {
+			return nil
+		}
+		reasons, err := p.AddLocalTxs(ctx, txs, tx)
+		if err != nil {
+			return err
+		}
+		p.lock.Lock()
+		defer p.lock.Unlock()
+		added := 0
+		for i, hashStr := range hashes {
+			if _, ok := p.byHash[hashStr]; ok {
+				added++
+				continue
+			}
+			if reasons[i] == txpoolcfg.NonceTooLow {
+				p.journal.remove(hashStr)
+			} else if jt, ok := p.journal.txs[hashStr]; ok {
+				jt.dropReason = reasons[i]
+			}
+		}
+		p.logger.Debug("[txpool] local tx journal: added again", "txs", added, "of", len(hashes))
+		return nil
+	}); err != nil {
+		return rebroadcasts, err
+	}
+	return rebroadcasts, nil
+}
+
+// rebroadcastLocalTxs sends the pending local txs to some peers again, and announces them to some others
+func (p *TxPool) rebroadcastLocalTxs(ctx context.Context, db kv.RoDB, send *Send, announcements types.Announcements) {
+	var txTypes []byte
+	var txSizes []uint32
+	var txHashes types.Hashes
+	var txRlps [][]byte
+	if err := db.View(ctx, func(tx kv.Tx) error {
+		for i := 0; i < announcements.Len(); i++ {
+			t, size, hash := announcements.At(i)
+			slotRlp, err := p.GetRlp(tx, hash)
+			if err != nil {
+				return err
+			}
+			if len(slotRlp) == 0 {
+				continue // mined meanwhile
+			}
+			txTypes = append(txTypes, t)
+			txSizes = append(txSizes, size)
+			txHashes = append(txHashes, hash...)
+			// "Nodes MUST NOT automatically broadcast blob transactions to their peers" - EIP-4844
+			if t != types.BlobTxType {
+				txRlps = append(txRlps, slotRlp)
+			}
+		}
+		return nil
+	}); err != nil {
+		p.logger.Error("[txpool] collect local txs to rebroadcast", "err", err)
+		return
+	}
+	const localTxsBroadcastMaxPeers uint64 = 10
+	send.BroadcastPooledTxs(txRlps, localTxsBroadcastMaxPeers)
+	send.AnnouncePooledTxs(txTypes, txSizes, txHashes, localTxsBroadcastMaxPeers*2)
+	p.logger.Debug("[txpool] local txs rebroadcast", "txs", txHashes.Len())
+}
+
+// LocalTxStatus is the state of a journaled local tx
+type LocalTxStatus struct {
+	IDHash        [32]byte
+	Sender        common.Address
+	Nonce         uint64
+	SubPool       SubPoolType             // 0 if the tx is not in the pool
+	MinedBlock    uint64                  // 0 if the tx is not mined
+	DropReason    txpoolcfg.DiscardReason // why the tx is not in the pool, if it's neither there nor mined
+	Broadcasts    int
+	LastBroadcast time.Time
+}
+
+// LocalTxs returns the journaled local txs, by sender and nonce
+func (p *TxPool) LocalTxs() []LocalTxStatus {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.journal == nil {
+		return nil
+	}
+	lastSeenBlock := p.lastSeenBlock.Load()
+	statuses := make([]LocalTxStatus, 0, len(p.journal.txs))
+	for hashStr, jt := range p.journal.txs {
+		status := LocalTxStatus{
+			IDHash:        *(*[32]byte)([]byte(hashStr)),
+			Sender:        jt.sender,
+			Nonce:         jt.nonce,
+			DropReason:    jt.dropReason,
+			Broadcasts:    jt.broadcasts,
+			LastBroadcast: jt.lastBroadcast,
+		}
+		if mt, ok := p.byHash[hashStr]; ok {
+			status.SubPool, status.DropReason = mt.currentSubPool, txpoolcfg.NotSet
+		} else if jt.minedBlock <= lastSeenBlock {
+			status.MinedBlock = jt.minedBlock
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if c := bytes.Compare(statuses[i].Sender[:], statuses[j].Sender[:]); c != 0 {
+			return c < 0
+		}
+		return statuses[i].Nonce < statuses[j].Nonce
+	})
+	return statuses
+}
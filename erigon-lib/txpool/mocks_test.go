@@ -25,6 +25,9 @@ var _ Pool = &PoolMock{}
 //			AddLocalTxsFunc: func(ctx context.Context, newTxs types2.TxSlots, tx kv.Tx) ([]txpoolcfg.DiscardReason, error) {
 //				panic("mock out the AddLocalTxs method")
 //			},
+//			AddLocalTxsWithOptionsFunc: func(ctx context.Context, newTxs types2.TxSlots, options []LocalTxOptions, tx kv.Tx) ([]txpoolcfg.DiscardReason, error) {
+//				panic("mock out the AddLocalTxsWithOptions method")
+//			},
 //			AddNewGoodPeerFunc: func(peerID types2.PeerID)  {
 //				panic("mock out the AddNewGoodPeer method")
 //			},
@@ -43,6 +46,9 @@ var _ Pool = &PoolMock{}
 //			IdHashKnownFunc: func(tx kv.Tx, hash []byte) (bool, error) {
 //				panic("mock out the IdHashKnown method")
 //			},
+//			IsPrivateFunc: func(idHash []byte) bool {
+//				panic("mock out the IsPrivate method")
+//			},
 //			OnNewBlockFunc: func(ctx context.Context, stateChanges *remote.StateChangeBatch, unwindTxs types2.TxSlots, minedTxs types2.TxSlots, tx kv.Tx) error {
 //				panic("mock out the OnNewBlock method")
 //			},
@@ -62,6 +68,9 @@ type PoolMock struct {
 	// AddLocalTxsFunc mocks the AddLocalTxs method.
 	AddLocalTxsFunc func(ctx context.Context, newTxs types2.TxSlots, tx kv.Tx) ([]txpoolcfg.DiscardReason, error)
 
+	// AddLocalTxsWithOptionsFunc mocks the AddLocalTxsWithOptions method.
+	AddLocalTxsWithOptionsFunc func(ctx context.Context, newTxs types2.TxSlots, options []LocalTxOptions, tx kv.Tx) ([]txpoolcfg.DiscardReason, error)
+
 	// AddNewGoodPeerFunc mocks the AddNewGoodPeer method.
 	AddNewGoodPeerFunc func(peerID types2.PeerID)
 
@@ -80,6 +89,9 @@ type PoolMock struct {
 	// IdHashKnownFunc mocks the IdHashKnown method.
 	IdHashKnownFunc func(tx kv.Tx, hash []byte) (bool, error)
 
+	// IsPrivateFunc mocks the IsPrivate method.
+	IsPrivateFunc func(idHash []byte) bool
+
 	// OnNewBlockFunc mocks the OnNewBlock method.
 	OnNewBlockFunc func(ctx context.Context, stateChanges *remote.StateChangeBatch, unwindTxs types2.TxSlots, minedTxs types2.TxSlots, tx kv.Tx) error
 
@@ -100,6 +112,17 @@ type PoolMock struct {
 			// Tx is the tx argument value.
 			Tx kv.Tx
 		}
+		// AddLocalTxsWithOptions holds details about calls to the AddLocalTxsWithOptions method.
+		AddLocalTxsWithOptions []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// NewTxs is the newTxs argument value.
+			NewTxs types2.TxSlots
+			// Options is the options argument value.
+			Options []LocalTxOptions
+			// Tx is the tx argument value.
+			Tx kv.Tx
+		}
 		// AddNewGoodPeer holds details about calls to the AddNewGoodPeer method.
 		AddNewGoodPeer []struct {
 			// PeerID is the peerID argument value.
@@ -140,6 +163,11 @@ type PoolMock struct {
 			// Hash is the hash argument value.
 			Hash []byte
 		}
+		// IsPrivate holds details about calls to the IsPrivate method.
+		IsPrivate []struct {
+			// IdHash is the idHash argument value.
+			IdHash []byte
+		}
 		// OnNewBlock holds details about calls to the OnNewBlock method.
 		OnNewBlock []struct {
 			// Ctx is the ctx argument value.
@@ -162,16 +190,18 @@ type PoolMock struct {
 			SerializedTxn []byte
 		}
 	}
-	lockAddLocalTxs           sync.RWMutex
-	lockAddNewGoodPeer        sync.RWMutex
-	lockAddRemoteTxs          sync.RWMutex
-	lockFilterKnownIdHashes   sync.RWMutex
-	lockGetKnownBlobTxn       sync.RWMutex
-	lockGetRlp                sync.RWMutex
-	lockIdHashKnown           sync.RWMutex
-	lockOnNewBlock            sync.RWMutex
-	lockStarted               sync.RWMutex
-	lockValidateSerializedTxn sync.RWMutex
+	lockAddLocalTxs            sync.RWMutex
+	lockAddLocalTxsWithOptions sync.RWMutex
+	lockAddNewGoodPeer         sync.RWMutex
+	lockAddRemoteTxs           sync.RWMutex
+	lockFilterKnownIdHashes    sync.RWMutex
+	lockGetKnownBlobTxn        sync.RWMutex
+	lockGetRlp                 sync.RWMutex
+	lockIdHashKnown            sync.RWMutex
+	lockIsPrivate              sync.RWMutex
+	lockOnNewBlock             sync.RWMutex
+	lockStarted                sync.RWMutex
+	lockValidateSerializedTxn  sync.RWMutex
 }
 
 // AddLocalTxs calls AddLocalTxsFunc.
@@ -218,6 +248,54 @@ func (mock *PoolMock) AddLocalTxsCalls() []struct {
 	return calls
 }
 
+// AddLocalTxsWithOptions calls AddLocalTxsWithOptionsFunc.
+func (mock *PoolMock) AddLocalTxsWithOptions(ctx context.Context, newTxs types2.TxSlots, options []LocalTxOptions, tx kv.Tx) ([]txpoolcfg.DiscardReason, error) {
+	callInfo := struct {
+		Ctx     context.Context
+		NewTxs  types2.TxSlots
+		Options []LocalTxOptions
+		Tx      kv.Tx
+	}{
+		Ctx:     ctx,
+		NewTxs:  newTxs,
+		Options: options,
+		Tx:      tx,
+	}
+	mock.lockAddLocalTxsWithOptions.Lock()
+	mock.calls.AddLocalTxsWithOptions = append(mock.calls.AddLocalTxsWithOptions, callInfo)
+	mock.lockAddLocalTxsWithOptions.Unlock()
+	if mock.AddLocalTxsWithOptionsFunc == nil {
+		var (
+			discardReasonsOut []txpoolcfg.DiscardReason
+			errOut            error
+		)
+		return discardReasonsOut, errOut
+	}
+	return mock.AddLocalTxsWithOptionsFunc(ctx, newTxs, options, tx)
+}
+
+// AddLocalTxsWithOptionsCalls gets all the calls that were made to AddLocalTxsWithOptions.
+// Check the length with:
+//
+//	len(mockedPool.AddLocalTxsWithOptionsCalls())
+func (mock *PoolMock) AddLocalTxsWithOptionsCalls() []struct {
+	Ctx     context.Context
+	NewTxs  types2.TxSlots
+	Options []LocalTxOptions
+	Tx      kv.Tx
+} {
+	var calls []struct {
+		Ctx     context.Context
+		NewTxs  types2.TxSlots
+		Options []LocalTxOptions
+		Tx      kv.Tx
+	}
+	mock.lockAddLocalTxsWithOptions.RLock()
+	calls = mock.calls.AddLocalTxsWithOptions
+	mock.lockAddLocalTxsWithOptions.RUnlock()
+	return calls
+}
+
 // AddNewGoodPeer calls AddNewGoodPeerFunc.
 func (mock *PoolMock) AddNewGoodPeer(peerID types2.PeerID) {
 	callInfo := struct {
@@ -446,6 +524,41 @@ func (mock *PoolMock) IdHashKnownCalls() []struct {
 	return calls
 }
 
+// IsPrivate calls IsPrivateFunc.
+func (mock *PoolMock) IsPrivate(idHash []byte) bool {
+	callInfo := struct {
+		IdHash []byte
+	}{
+		IdHash: idHash,
+	}
+	mock.lockIsPrivate.Lock()
+	mock.calls.IsPrivate = append(mock.calls.IsPrivate, callInfo)
+	mock.lockIsPrivate.Unlock()
+	if mock.IsPrivateFunc == nil {
+		var (
+			bOut bool
+		)
+		return bOut
+	}
+	return mock.IsPrivateFunc(idHash)
+}
+
+// IsPrivateCalls gets all the calls that were made to IsPrivate.
+// Check the length with:
+//
+//	len(mockedPool.IsPrivateCalls())
+func (mock *PoolMock) IsPrivateCalls() []struct {
+	IdHash []byte
+} {
+	var calls []struct {
+		IdHash []byte
+	}
+	mock.lockIsPrivate.RLock()
+	calls = mock.calls.IsPrivate
+	mock.lockIsPrivate.RUnlock()
+	return calls
+}
+
 // OnNewBlock calls OnNewBlockFunc.
 func (mock *PoolMock) OnNewBlock(ctx context.Context, stateChanges *remote.StateChangeBatch, unwindTxs types2.TxSlots, minedTxs types2.TxSlots, tx kv.Tx) error {
 	callInfo := struct {
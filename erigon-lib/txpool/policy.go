@@ -0,0 +1,181 @@
+/*
+   Copyright 2023 The Erigon contributors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package txpool
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/metrics"
+	"github.com/ledgerwatch/erigon-lib/types"
+)
+
+// PolicyRule denies the transactions of some senders, to some recipients, or calling some methods. A policy file is
+// a JSON list of rules:
+//
+//	{"rules": [{
+//		"name": "sanctions",
+//		"senders": ["0x..."],
+//		"recipients": ["0x..."],
+//		"selectors": ["0xa9059cbb"],
+//		"contracts": {"0x...": ["0x095ea7b3"]}
+//	}]}
+//
+// Selectors are denied whatever the called contract, while contracts deny the given selectors of a contract only.
+type PolicyRule struct {
+	Name       string                                `json:"name"`
+	Senders    []common.Address                      `json:"senders,omitempty"`
+	Recipients []common.Address                      `json:"recipients,omitempty"`
+	Selectors  []hexutility.Bytes                    `json:"selectors,omitempty"`
+	Contracts  map[common.Address][]hexutility.Bytes `json:"contracts,omitempty"`
+}
+
+type policyRule struct {
+	name       string
+	senders    map[common.Address]struct{}
+	recipients map[common.Address]struct{}
+	selectors  map[[4]byte]struct{}
+	contracts  map[common.Address]map[[4]byte]struct{}
+	rejected   metrics.Counter
+}
+
+// Policy is the set of admission rules of the pool, loaded from a file and reloaded when the file changes.
+// It is not thread-safe: the pool uses it under its lock.
+type Policy struct {
+	path    string
+	modTime time.Time
+	rules   []*policyRule
+}
+
+// LoadPolicy reads the policy file at the given path
+func LoadPolicy(path string) (*Policy, error) {
+	p := &Policy{path: path}
+	if _, err := p.reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// reload reads the policy file again if it was modified since the last read. On error, the current rules are kept.
+func (p *Policy) reload() (bool, error) {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return false, err
+	}
+	if info.ModTime().Equal(p.modTime) {
+		return false, nil
+	}
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return false, err
+	}
+	var file struct {
+		Rules []PolicyRule `json:"rules"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return false, fmt.Errorf("policy file %s: %w", p.path, err)
+	}
+	rules := make([]*policyRule, 0, len(file.Rules))
+	for i, rule := range file.Rules {
+		compiled, err := compilePolicyRule(rule)
+		if err != nil {
+			return false, fmt.Errorf("policy file %s, rule %d: %w", p.path, i, err)
+		}
+		rules = append(rules, compiled)
+	}
+	p.modTime, p.rules = info.ModTime(), rules
+	return true, nil
+}
+
+func compilePolicyRule(rule PolicyRule) (*policyRule, error) {
+	if rule.Name == "" {
+		return nil, fmt.Errorf("rule without name")
+	}
+	if strings.ContainsAny(rule.Name, "\"\\\n") {
+		return nil, fmt.Errorf("rule name %q is not a valid metrics label", rule.Name)
+	}
+	r := &policyRule{
+		name:       rule.Name,
+		senders:    make(map[common.Address]struct{}, len(rule.Senders)),
+		recipients: make(map[common.Address]struct{}, len(rule.Recipients)),
+		contracts:  make(map[common.Address]map[[4]byte]struct{}, len(rule.Contracts)),
+		rejected:   metrics.GetOrCreateCounter(fmt.Sprintf(`txpool_policy_rejected{rule="%s"}`, rule.Name)),
+	}
+	for _, sender := range rule.Senders {
+		r.senders[sender] = struct{}{}
+	}
+	for _, recipient := range rule.Recipients {
+		r.recipients[recipient] = struct{}{}
+	}
+	var err error
+	if r.selectors, err = selectorSet(rule.Selectors); err != nil {
+		return nil, err
+	}
+	for contract, selectors := range rule.Contracts {
+		if r.contracts[contract], err = selectorSet(selectors); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func selectorSet(selectors []hexutility.Bytes) (map[[4]byte]struct{}, error) {
+	set := make(map[[4]byte]struct{}, len(selectors))
+	for _, selector := range selectors {
+		if len(selector) != 4 {
+			return nil, fmt.Errorf("selector %x is not 4 bytes long", []byte(selector))
+		}
+		set[[4]byte(selector)] = struct{}{}
+	}
+	return set, nil
+}
+
+func (r *policyRule) denies(sender common.Address, txn *types.TxSlot) bool {
+	if _, ok := r.senders[sender]; ok {
+		return true
+	}
+	if txn.Creation {
+		return false
+	}
+	if _, ok := r.recipients[txn.To]; ok {
+		return true
+	}
+	if txn.DataLen < 4 {
+		return false
+	}
+	if _, ok := r.selectors[txn.Selector]; ok {
+		return true
+	}
+	_, ok := r.contracts[txn.To][txn.Selector]
+	return ok
+}
+
+// check returns the name of the first rule denying the transaction, if any, and counts the rejection
+func (p *Policy) check(sender common.Address, txn *types.TxSlot) (string, bool) {
+	for _, rule := range p.rules {
+		if rule.denies(sender, txn) {
+			rule.rejected.Inc()
+			return rule.name, false
+		}
+	}
+	return "", true
+}
@@ -27,6 +27,7 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"path/filepath"
 	"runtime"
 	"sort"
 	"sync"
@@ -72,8 +73,41 @@ var (
 	pendingSubCounter       = metrics.GetOrCreateGauge(`txpool_pending`)
 	queuedSubCounter        = metrics.GetOrCreateGauge(`txpool_queued`)
 	basefeeSubCounter       = metrics.GetOrCreateGauge(`txpool_basefee`)
+	blobSubCounter          = metrics.GetOrCreateGauge(`txpool_blob`)
+	blobSubSizeCounter      = metrics.GetOrCreateGauge(`txpool_blob_size`)
 )
 
+type discardCounterKey struct {
+	reason txpoolcfg.DiscardReason
+	local  bool
+}
+
+var (
+	discardCountersLock sync.Mutex
+	discardCounters     = map[discardCounterKey]metrics.Counter{}
+)
+
+func discardCounter(reason txpoolcfg.DiscardReason, local bool) metrics.Counter {
+	key := discardCounterKey{reason: reason, local: local}
+	discardCountersLock.Lock()
+	defer discardCountersLock.Unlock()
+	counter, ok := discardCounters[key]
+	if !ok {
+		origin := "remote"
+		if local {
+			origin = "local"
+		}
+		counter = metrics.GetOrCreateCounter(fmt.Sprintf(`txpool_discarded{reason="%s",origin="%s"}`, reason, origin))
+		discardCounters[key] = counter
+	}
+	return counter
+}
+
+// countDiscard counts the transactions rejected or dropped by the pool for each reason, local and remote ones apart
+func countDiscard(reason txpoolcfg.DiscardReason, local bool) {
+	discardCounter(reason, local).Inc()
+}
+
 // Pool is interface for the transaction pool
 // This interface exists for the convenience of testing, and not yet because
 // there are multiple implementations
@@ -83,6 +117,7 @@ type Pool interface {
 	// Handle 3 main events - new remote txs from p2p, new local txs from RPC, new blocks from execution layer
 	AddRemoteTxs(ctx context.Context, newTxs types.TxSlots)
 	AddLocalTxs(ctx context.Context, newTxs types.TxSlots, tx kv.Tx) ([]txpoolcfg.DiscardReason, error)
+	AddLocalTxsWithOptions(ctx context.Context, newTxs types.TxSlots, options []LocalTxOptions, tx kv.Tx) ([]txpoolcfg.DiscardReason, error)
 	OnNewBlock(ctx context.Context, stateChanges *remote.StateChangeBatch, unwindTxs, minedTxs types.TxSlots, tx kv.Tx) error
 	// IdHashKnown check whether transaction with given Id hash is known to the pool
 	IdHashKnown(tx kv.Tx, hash []byte) (bool, error)
@@ -90,12 +125,25 @@ type Pool interface {
 	Started() bool
 	GetRlp(tx kv.Tx, hash []byte) ([]byte, error)
 	GetKnownBlobTxn(tx kv.Tx, hash []byte) (*metaTx, error)
+	// IsPrivate check whether transaction with given Id hash must not be propagated to peers
+	IsPrivate(idHash []byte) bool
 
 	AddNewGoodPeer(peerID types.PeerID)
 }
 
 var _ Pool = (*TxPool)(nil) // compile-time interface check
 
+// LocalTxOptions are the per-transaction options of local transactions
+type LocalTxOptions struct {
+	// Private transactions are kept for local block building only: they are never announced nor sent to peers
+	Private bool
+	// PrivateExpiryBlocks is the number of blocks after which a private transaction which is still in the pool is
+	// dropped. 0 means that it never expires.
+	PrivateExpiryBlocks uint64
+	// Conditions of conditional transactions, which are dropped as soon as their conditions are not met anymore
+	Conditions *TxConditions
+}
+
 // SubPoolMarker is an ordered bitset of five bits that's used to sort transactions into sub-pools. Bits meaning:
 // 1. Absence of nonce gaps. Set to 1 for transactions whose nonce is N, state nonce for the sender is M, and there are transactions for all nonces between M and N from the same sender. Set to 0 is the transaction's nonce is divided from the state nonce by one or more nonce gaps.
 // 2. Sufficient balance for gas. Set to 1 if the balance of sender's account in the state is B, nonce of the sender in the state is M, nonce of the transaction is N, and the sum of feeCap x gasLimit + transferred_value of all transactions from this sender with nonces N+1 ... M is no more than B. Set to 0 otherwise. In other words, this bit is set if there is currently a guarantee that the transaction and all its required prior transactions will be able to pay for gas.
@@ -128,6 +176,8 @@ type metaTx struct {
 	currentSubPool            SubPoolType
 	alreadyYielded            bool
 	minedBlockNum             uint64
+	private                   bool   // never announced nor sent to peers
+	privateExpiry             uint64 // block from which a private tx still in the pool is dropped, 0 - never
 }
 
 func newMetaTx(slot *types.TxSlot, isLocal bool, timestamp uint64) *metaTx {
@@ -203,7 +253,17 @@ type TxPool struct {
 	queued                  *SubPool
 	minedBlobTxsByBlock     map[uint64][]*metaTx             // (blockNum => slice): cache of recently mined blobs
 	minedBlobTxsByHash      map[string]*metaTx               // (hash => mt): map of recently mined blobs
+	blobTxs                 map[string]*metaTx               // blob sub-pool: the blob txs of all sub-pools, limited by size rather than count
+	blobPoolSize            uint64                           // total size of blobTxs, sidecars included
+	blobStore               *blobStore                       // sidecars of blobTxs and of recently mined blob txs, nil keeps them in memory
+	unstoredBlobTxs         []*metaTx                        // blob txs whose sidecars are still in memory, written to blobStore by flushBlobs
 	isLocalLRU              *simplelru.LRU[string, struct{}] // tx_hash => is_local : to restore isLocal flag of unwinded transactions
+	privateLRU              *simplelru.LRU[string, uint64]   // tx_hash => expiry_block : to restore private flag of unwinded transactions
+	conditions              map[string]*TxConditions         // tx_hash => conditions : conditional txs, kept after mining until finalized
+	minedConditionalTxs     map[uint64][]string              // (blockNum => tx_hashes): recently mined conditional txs
+	bundles                 []*Bundle                        // bundles waiting for their target block, in arrival order
+	policy                  *Policy                          // admission rules, nil if there is no policy file
+	journal                 *localJournal                    // local txs to add again and rebroadcast until mined, nil if disabled
 	newPendingTxs           chan types.Announcements         // notifications about new txs in Pending sub-pool
 	all                     *BySenderAndNonce                // senderID => (sorted map of tx nonce => *metaTx)
 	deletedTxs              []*metaTx                        // list of discarded txs since last db commit
@@ -237,6 +297,10 @@ func New(newTxs chan types.Announcements, coreDB kv.RoDB, cfg txpoolcfg.Config,
 	if err != nil {
 		return nil, err
 	}
+	privateHistory, err := simplelru.NewLRU[string, uint64](10_000, nil)
+	if err != nil {
+		return nil, err
+	}
 
 	byNonce := &BySenderAndNonce{
 		tree:              btree.NewG[*metaTx](32, SortByNonceLess),
@@ -253,6 +317,9 @@ func New(newTxs chan types.Announcements, coreDB kv.RoDB, cfg txpoolcfg.Config,
 		lock:                    &sync.Mutex{},
 		byHash:                  map[string]*metaTx{},
 		isLocalLRU:              localsHistory,
+		privateLRU:              privateHistory,
+		conditions:              map[string]*TxConditions{},
+		minedConditionalTxs:     map[uint64][]string{},
 		discardReasonsLRU:       discardHistory,
 		all:                     byNonce,
 		recentlyConnectedPeers:  &recentlyConnectedPeers{},
@@ -269,10 +336,17 @@ func New(newTxs chan types.Announcements, coreDB kv.RoDB, cfg txpoolcfg.Config,
 		unprocessedRemoteByHash: map[string]int{},
 		minedBlobTxsByBlock:     map[uint64][]*metaTx{},
 		minedBlobTxsByHash:      map[string]*metaTx{},
+		blobTxs:                 map[string]*metaTx{},
 		maxBlobsPerBlock:        maxBlobsPerBlock,
 		logger:                  logger,
 	}
 
+	if cfg.DBDir != "" {
+		if res.blobStore, err = openBlobStore(filepath.Join(cfg.DBDir, "blobs")); err != nil {
+			return nil, err
+		}
+	}
+
 	if shanghaiTime != nil {
 		if !shanghaiTime.IsUint64() {
 			return nil, errors.New("shanghaiTime overflow")
@@ -294,6 +368,14 @@ func New(newTxs chan types.Announcements, coreDB kv.RoDB, cfg txpoolcfg.Config,
 		cancunTimeU64 := cancunTime.Uint64()
 		res.cancunTime = &cancunTimeU64
 	}
+	if cfg.PolicyFile != "" {
+		if res.policy, err = LoadPolicy(cfg.PolicyFile); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.LocalJournalLifetime > 0 {
+		res.journal = newLocalJournal()
+	}
 
 	return res, nil
 }
@@ -377,6 +459,9 @@ func (p *TxPool) OnNewBlock(ctx context.Context, stateChanges *remote.StateChang
 	if err := removeMined(p.all, minedTxs.Txs, p.pending, p.baseFee, p.queued, p.discardLocked, p.logger); err != nil {
 		return err
 	}
+	if p.journal != nil {
+		p.journal.onFinalized(stateChanges.FinalizedBlock)
+	}
 
 	//p.logger.Debug("[txpool] new block", "unwinded", len(unwindTxs.txs), "mined", len(minedTxs.txs), "baseFee", baseFee, "blockHeight", blockHeight)
 
@@ -386,6 +471,9 @@ func (p *TxPool) OnNewBlock(ctx context.Context, stateChanges *remote.StateChang
 	if err != nil {
 		return err
 	}
+	p.discardExpiredPrivateLocked(p.lastSeenBlock.Load())
+	p.discardUnmetConditionsLocked(stateChanges)
+	p.discardPastBundlesLocked(p.lastSeenBlock.Load())
 	p.pending.EnforceWorstInvariants()
 	p.baseFee.EnforceInvariants()
 	p.queued.EnforceInvariants()
@@ -473,6 +561,12 @@ func (p *TxPool) getRlpLocked(tx kv.Tx, hash []byte) (rlpTxn []byte, sender comm
 	if ok && txn.Tx.Rlp != nil {
 		return txn.Tx.Rlp, p.senders.senderID2Addr[txn.Tx.SenderID], txn.subPool&IsLocal > 0, nil
 	}
+	if _, mined := p.minedBlobTxsByHash[string(hash)]; p.blobStore != nil && ((ok && txn.Tx.Type == types.BlobTxType) || mined) {
+		rlpTxn, sender, err = p.blobStore.get(hash)
+		if err != nil || rlpTxn != nil {
+			return rlpTxn, sender, ok && txn.subPool&IsLocal > 0, err
+		}
+	}
 	v, err := tx.GetOne(kv.PoolTransaction, hash)
 	if err != nil {
 		return nil, common.Address{}, false, err
@@ -492,7 +586,7 @@ func (p *TxPool) AppendLocalAnnouncements(types []byte, sizes []uint32, hashes [
 	p.lock.Lock()
 	defer p.lock.Unlock()
 	for hash, txn := range p.byHash {
-		if txn.subPool&IsLocal == 0 {
+		if txn.subPool&IsLocal == 0 || txn.private {
 			continue
 		}
 		types = append(types, txn.Tx.Type)
@@ -573,31 +667,35 @@ func (p *TxPool) GetKnownBlobTxn(tx kv.Tx, hash []byte) (*metaTx, error) {
 	hashS := string(hash)
 	p.lock.Lock()
 	defer p.lock.Unlock()
-	if mt, ok := p.minedBlobTxsByHash[hashS]; ok {
-		return mt, nil
-	}
 	if txn, ok := p.getUnprocessedTxn(hashS); ok {
 		return newMetaTx(txn, false, 0), nil
 	}
-	if mt, ok := p.byHash[hashS]; ok {
+	mt, inPool := p.byHash[hashS]
+	if _, mined := p.minedBlobTxsByHash[hashS]; !inPool && !mined {
+		if has, err := tx.Has(kv.PoolTransaction, hash); err != nil || !has {
+			return nil, err
+		}
+	}
+	if inPool && mt.Tx.Blobs != nil {
 		return mt, nil
 	}
-	has, err := tx.Has(kv.PoolTransaction, hash)
+	// the sidecar is not kept in memory, parse the transaction again with it
+	txn, _, _, err := p.getRlpLocked(tx, hash)
 	if err != nil {
 		return nil, err
 	}
-	if !has {
+	if txn == nil {
+		if mt, mined := p.minedBlobTxsByHash[hashS]; mined {
+			return mt, nil
+		}
 		return nil, nil
 	}
-
-	txn, err := tx.GetOne(kv.PoolTransaction, hash)
-	if err != nil {
-		return nil, err
-	}
 	parseCtx := types.NewTxParseContext(p.chainID)
 	parseCtx.WithSender(false)
 	txSlot := &types.TxSlot{}
-	parseCtx.ParseTransaction(txn, 0, txSlot, nil, false, true, nil)
+	if _, err := parseCtx.ParseTransaction(txn, 0, txSlot, nil, false, true, nil); err != nil {
+		return nil, err
+	}
 	return newMetaTx(txSlot, false, 0), nil
 }
 
@@ -607,6 +705,22 @@ func (p *TxPool) IsLocal(idHash []byte) bool {
 	defer p.lock.Unlock()
 	return p.isLocalLRU.Contains(hashS)
 }
+
+// TxConditions returns the conditions of a conditional transaction, nil for other transactions
+func (p *TxPool) TxConditions(idHash [32]byte) *TxConditions {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.conditions[string(idHash[:])]
+}
+func (p *TxPool) IsPrivate(idHash []byte) bool {
+	hashS := string(idHash)
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if mt, ok := p.byHash[hashS]; ok {
+		return mt.private
+	}
+	return p.privateLRU.Contains(hashS)
+}
 func (p *TxPool) AddNewGoodPeer(peerID types.PeerID) { p.recentlyConnectedPeers.AddPeer(peerID) }
 func (p *TxPool) Started() bool                      { return p.started.Load() }
 
@@ -640,6 +754,12 @@ func (p *TxPool) best(n uint16, txs *types.TxsRlp, tx kv.Tx, onTopOf, availableG
 			continue
 		}
 
+		// Skip transactions that require more blob gas than is available
+		blobCount := uint64(len(mt.Tx.BlobHashes))
+		if blobCount*fixedgas.BlobGasPerBlob > availableBlobGas {
+			continue
+		}
+
 		rlpTx, sender, isLocal, err := p.getRlpLocked(tx, mt.Tx.IDHash[:])
 		if err != nil {
 			return false, count, err
@@ -648,12 +768,6 @@ func (p *TxPool) best(n uint16, txs *types.TxsRlp, tx kv.Tx, onTopOf, availableG
 			toRemove = append(toRemove, mt)
 			continue
 		}
-
-		// Skip transactions that require more blob gas than is available
-		blobCount := uint64(len(mt.Tx.BlobHashes))
-		if blobCount*fixedgas.BlobGasPerBlob > availableBlobGas {
-			continue
-		}
 		availableBlobGas -= blobCount * fixedgas.BlobGasPerBlob
 
 		// make sure we have enough gas in the caller to add this transaction.
@@ -743,6 +857,14 @@ func toBlobs(_blobs [][]byte) []gokzg4844.Blob {
 }
 
 func (p *TxPool) validateTx(txn *types.TxSlot, isLocal bool, stateCache kvcache.CacheView) txpoolcfg.DiscardReason {
+	if p.policy != nil {
+		if rule, ok := p.policy.check(p.senders.senderID2Addr[txn.SenderID], txn); !ok {
+			if txn.Traced {
+				p.logger.Info(fmt.Sprintf("TX TRACING: validateTx rejected by policy idHash=%x rule=%s", txn.IDHash, rule))
+			}
+			return txpoolcfg.PolicyRejected
+		}
+	}
 	isShanghai := p.isShanghai() || p.isAgra()
 	if isShanghai {
 		if txn.DataLen > fixedgas.MaxInitCodeSize {
@@ -1012,6 +1134,7 @@ func (p *TxPool) validateTxs(txs *types.TxSlots, stateCache kvcache.CacheView) (
 			p.punishSpammer(txn.SenderID)
 		}
 		reasons[i] = reason
+		countDiscard(reason, txs.IsLocal[i])
 	}
 
 	goodTxs.Resize(uint(goodCount))
@@ -1060,6 +1183,12 @@ func fillDiscardReasons(reasons []txpoolcfg.DiscardReason, newTxs types.TxSlots,
 }
 
 func (p *TxPool) AddLocalTxs(ctx context.Context, newTransactions types.TxSlots, tx kv.Tx) ([]txpoolcfg.DiscardReason, error) {
+	return p.AddLocalTxsWithOptions(ctx, newTransactions, nil, tx)
+}
+
+// AddLocalTxsWithOptions adds local transactions along with their options, options[i] applying to newTransactions.Txs[i].
+// options may be shorter than the transactions, the missing ones being the default options.
+func (p *TxPool) AddLocalTxsWithOptions(ctx context.Context, newTransactions types.TxSlots, options []LocalTxOptions, tx kv.Tx) ([]txpoolcfg.DiscardReason, error) {
 	coreDb, cache := p.coreDBWithCache()
 	coreTx, err := coreDb.BeginRo(ctx)
 	if err != nil {
@@ -1088,11 +1217,66 @@ func (p *TxPool) AddLocalTxs(ctx context.Context, newTransactions types.TxSlots,
 		return nil, err
 	}
 
+	newPrivate := map[string]uint64{} // tx_hash => expiry_block
+	newConditional := map[string]struct{}{}
+	var unmet []bool // transactions whose conditions already do not hold, nil if there are none
+	for i, opts := range options {
+		if i >= len(newTransactions.Txs) {
+			break
+		}
+		hashStr := string(newTransactions.Txs[i].IDHash[:])
+		if opts.Conditions != nil {
+			if opts.Conditions.Expired(p.lastSeenBlock.Load()+1, uint64(time.Now().Unix())) {
+				if unmet == nil {
+					unmet = make([]bool, len(newTransactions.Txs))
+				}
+				unmet[i] = true
+				continue
+			}
+			if _, ok := p.byHash[hashStr]; !ok {
+				if _, ok := p.conditions[hashStr]; !ok {
+					p.conditions[hashStr] = opts.Conditions
+					newConditional[hashStr] = struct{}{}
+				}
+			}
+		}
+		if !opts.Private {
+			continue
+		}
+		if _, ok := p.byHash[hashStr]; ok || p.privateLRU.Contains(hashStr) {
+			continue // already known to the pool, and possibly to peers
+		}
+		var expiry uint64
+		if opts.PrivateExpiryBlocks > 0 {
+			expiry = p.lastSeenBlock.Load() + opts.PrivateExpiryBlocks
+		}
+		newPrivate[hashStr] = expiry
+	}
+	defer func() {
+		for hashStr := range newConditional {
+			if _, ok := p.byHash[hashStr]; !ok {
+				delete(p.conditions, hashStr)
+			}
+		}
+	}()
+	if unmet != nil {
+		newTransactions = withoutUnmet(newTransactions, unmet)
+	}
+
 	reasons, newTxs, err := p.validateTxs(&newTransactions, cacheView)
 	if err != nil {
 		return nil, err
 	}
 
+	// the sidecars of blob txs are moved out of their slots when they are added
+	var journalRlps [][]byte
+	if p.journal != nil {
+		journalRlps = make([][]byte, len(newTransactions.Txs))
+		for i, txn := range newTransactions.Txs {
+			journalRlps[i] = txn.Rlp
+		}
+	}
+
 	announcements, addReasons, err := addTxs(p.lastSeenBlock.Load(), cacheView, p.senders, newTxs,
 		p.pendingBaseFee.Load(), p.pendingBlobFee.Load(), p.blockGasLimit.Load(), p.pending, p.baseFee, p.queued, p.all, p.byHash, p.addLocked, p.discardLocked, true, p.logger)
 	if err == nil {
@@ -1104,6 +1288,12 @@ func (p *TxPool) AddLocalTxs(ctx context.Context, newTransactions types.TxSlots,
 	} else {
 		return nil, err
 	}
+	// the announcements are sent after the lock is released, and skip the private transactions
+	for hashStr, expiry := range newPrivate {
+		if mt, ok := p.byHash[hashStr]; ok {
+			mt.private, mt.privateExpiry = true, expiry
+		}
+	}
 	p.promoted.Reset()
 	p.promoted.AppendOther(announcements)
 
@@ -1117,14 +1307,59 @@ func (p *TxPool) AddLocalTxs(ctx context.Context, newTransactions types.TxSlots,
 			p.promoted.Append(txn.Type, txn.Size, txn.IDHash[:])
 		}
 	}
+	if p.journal != nil {
+		now := time.Now()
+		for i, txn := range newTransactions.Txs {
+			hashStr := string(txn.IDHash[:])
+			mt, ok := p.byHash[hashStr]
+			if !ok || journalRlps[i] == nil {
+				continue
+			}
+			if mt.private || p.conditions[hashStr] != nil {
+				continue
+			}
+			p.journal.add(hashStr, newTransactions.Senders.AddressAt(i), txn.Nonce, journalRlps[i], now)
+		}
+	}
 	if p.promoted.Len() > 0 {
 		select {
 		case p.newPendingTxs <- p.promoted.Copy():
 		default:
 		}
 	}
+	if unmet != nil {
+		allReasons := make([]txpoolcfg.DiscardReason, len(unmet))
+		j := 0
+		for i := range allReasons {
+			if unmet[i] {
+				allReasons[i] = txpoolcfg.ConditionsNotMet
+				countDiscard(txpoolcfg.ConditionsNotMet, true)
+				continue
+			}
+			allReasons[i] = reasons[j]
+			j++
+		}
+		return allReasons, nil
+	}
 	return reasons, nil
 }
+
+// withoutUnmet drops the transactions whose conditions do not hold
+func withoutUnmet(txs types.TxSlots, unmet []bool) types.TxSlots {
+	var kept types.TxSlots
+	j := 0
+	for i, txn := range txs.Txs {
+		if unmet[i] {
+			continue
+		}
+		kept.Resize(uint(j + 1))
+		kept.Txs[j] = txn
+		kept.IsLocal[j] = txs.IsLocal[i]
+		copy(kept.Senders.At(j), txs.Senders.At(i))
+		j++
+	}
+	return kept
+}
 func (p *TxPool) coreDBWithCache() (kv.RoDB, kvcache.Cache) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
@@ -1157,6 +1392,7 @@ func addTxs(blockNum uint64, cacheView kvcache.CacheView, senders *sendersBatch,
 	for i, txn := range newTxs.Txs {
 		if found, ok := byHash[string(txn.IDHash[:])]; ok {
 			discardReasons[i] = txpoolcfg.DuplicateHash
+			countDiscard(txpoolcfg.DuplicateHash, newTxs.IsLocal[i])
 			// In case if the transition is stuck, "poke" it to rebroadcast
 			if collect && newTxs.IsLocal[i] && (found.currentSubPool == PendingSubPool || found.currentSubPool == BaseFeeSubPool) {
 				announcements.Append(found.Tx.Type, found.Tx.Size, found.Tx.IDHash[:])
@@ -1166,6 +1402,7 @@ func addTxs(blockNum uint64, cacheView kvcache.CacheView, senders *sendersBatch,
 		mt := newMetaTx(txn, newTxs.IsLocal[i], blockNum)
 		if reason := add(mt, &announcements); reason != txpoolcfg.NotSet {
 			discardReasons[i] = reason
+			countDiscard(reason, newTxs.IsLocal[i])
 			continue
 		}
 		discardReasons[i] = txpoolcfg.NotSet // unnecessary
@@ -1266,13 +1503,20 @@ func (p *TxPool) setBaseFee(baseFee uint64) (uint64, bool) {
 
 func (p *TxPool) setBlobFee(blobFee uint64) {
 	if blobFee > 0 {
-		p.pendingBaseFee.Store(blobFee)
+		p.pendingBlobFee.Store(blobFee)
 	}
 }
 
 func (p *TxPool) addLocked(mt *metaTx, announcements *types.Announcements) txpoolcfg.DiscardReason {
 	// Insert to pending pool, if pool doesn't have txn with same Nonce and bigger Tip
 	found := p.all.get(mt.Tx.SenderID, mt.Tx.Nonce)
+	var blobEvictions []*metaTx
+	if mt.Tx.Type == types.BlobTxType {
+		var reason txpoolcfg.DiscardReason
+		if blobEvictions, reason = p.blobEvictionsLocked(mt, found); reason != txpoolcfg.NotSet {
+			return reason
+		}
+	}
 	if found != nil {
 		if found.Tx.Type == types.BlobTxType && mt.Tx.Type != types.BlobTxType {
 			return txpoolcfg.BlobTxReplace
@@ -1332,9 +1576,18 @@ func (p *TxPool) addLocked(mt *metaTx, announcements *types.Announcements) txpoo
 	if mt.Tx.Type == types.BlobTxType && mt.Tx.BlobFeeCap.LtUint64(p.pendingBlobFee.Load()) {
 		return txpoolcfg.FeeTooLow
 	}
+	for _, evicted := range blobEvictions {
+		if evicted.Tx.Traced {
+			p.logger.Info(fmt.Sprintf("TX TRACING: addLocked evicted from the blob sub-pool idHash=%x senderId=%d, by idHash=%x", evicted.Tx.IDHash, evicted.Tx.SenderID, mt.Tx.IDHash))
+		}
+		p.removeLocked(evicted, txpoolcfg.BlobPoolOverflow)
+	}
 
 	hashStr := string(mt.Tx.IDHash[:])
 	p.byHash[hashStr] = mt
+	if mt.Tx.Type == types.BlobTxType {
+		p.addBlobTxLocked(mt)
+	}
 
 	if replaced := p.all.replaceOrInsert(mt); replaced != nil {
 		if assert.Enable {
@@ -1345,6 +1598,13 @@ func (p *TxPool) addLocked(mt *metaTx, announcements *types.Announcements) txpoo
 	if mt.subPool&IsLocal != 0 {
 		p.isLocalLRU.Add(hashStr, struct{}{})
 	}
+	if expiry, ok := p.privateLRU.Peek(hashStr); ok {
+		mt.private, mt.privateExpiry = true, expiry
+		p.privateLRU.Remove(hashStr)
+	}
+	if p.journal != nil {
+		p.journal.onAdd(hashStr)
+	}
 	// All transactions are first added to the queued pool and then immediately promoted from there if required
 	p.queued.Add(mt, p.logger)
 	// Remove from mined cache as we are now "resurrecting" it to a sub-pool
@@ -1352,6 +1612,96 @@ func (p *TxPool) addLocked(mt *metaTx, announcements *types.Announcements) txpoo
 	return txpoolcfg.NotSet
 }
 
+// discardExpiredPrivateLocked drops the private transactions which were not included up to their expiry block
+func (p *TxPool) discardExpiredPrivateLocked(blockNum uint64) {
+	var expired []*metaTx // can't delete items while iterate them
+	for _, mt := range p.byHash {
+		if mt.private && mt.privateExpiry != 0 && mt.privateExpiry <= blockNum {
+			expired = append(expired, mt)
+		}
+	}
+	for _, mt := range expired {
+		if mt.Tx.Traced {
+			p.logger.Info(fmt.Sprintf("TX TRACING: discardExpiredPrivate idHash=%x senderId=%d, expiry=%d", mt.Tx.IDHash, mt.Tx.SenderID, mt.privateExpiry))
+		}
+		p.removeLocked(mt, txpoolcfg.PrivateTxExpired)
+	}
+}
+
+// discardUnmetConditionsLocked drops the conditional transactions whose conditions do not hold after the new block,
+// and forgets the conditions of the finalized ones
+func (p *TxPool) discardUnmetConditionsLocked(stateChanges *remote.StateChangeBatch) {
+	for blockNum, hashes := range p.minedConditionalTxs {
+		if blockNum > stateChanges.FinalizedBlock {
+			continue
+		}
+		for _, hashStr := range hashes {
+			if _, ok := p.byHash[hashStr]; !ok {
+				delete(p.conditions, hashStr)
+			}
+		}
+		delete(p.minedConditionalTxs, blockNum)
+	}
+
+	nextBlock, now := p.lastSeenBlock.Load()+1, uint64(time.Now().Unix())
+	for hashStr, conditions := range p.conditions {
+		mt, ok := p.byHash[hashStr]
+		if !ok {
+			continue
+		}
+		hold := !conditions.Expired(nextBlock, now)
+		for _, stateChange := range stateChanges.ChangeBatch {
+			hold = hold && conditions.holdAfter(stateChange)
+		}
+		if hold {
+			continue
+		}
+		if mt.Tx.Traced {
+			p.logger.Info(fmt.Sprintf("TX TRACING: discardUnmetConditions idHash=%x senderId=%d", mt.Tx.IDHash, mt.Tx.SenderID))
+		}
+		p.removeLocked(mt, txpoolcfg.ConditionsNotMet)
+	}
+}
+
+// reloadPolicy reloads the policy file if it changed, and drops the transactions and bundles the new rules deny
+func (p *TxPool) reloadPolicy() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	changed, err := p.policy.reload()
+	if err != nil {
+		p.logger.Warn("[txpool] could not reload policy, keeping the current rules", "err", err)
+		return
+	}
+	if !changed {
+		return
+	}
+	p.logger.Info("[txpool] policy reloaded", "rules", len(p.policy.rules))
+	for _, mt := range p.byHash {
+		if rule, ok := p.policy.check(p.senders.senderID2Addr[mt.Tx.SenderID], mt.Tx); !ok {
+			if mt.Tx.Traced {
+				p.logger.Info(fmt.Sprintf("TX TRACING: reloadPolicy idHash=%x senderId=%d rule=%s", mt.Tx.IDHash, mt.Tx.SenderID, rule))
+			}
+			p.removeLocked(mt, txpoolcfg.PolicyRejected)
+		}
+	}
+	p.discardDeniedBundlesLocked()
+}
+
+// removeLocked drops a transaction from its sub-pool, then from all sub-structures and from db
+func (p *TxPool) removeLocked(mt *metaTx, reason txpoolcfg.DiscardReason) {
+	switch mt.currentSubPool {
+	case PendingSubPool:
+		p.pending.Remove(mt)
+	case BaseFeeSubPool:
+		p.baseFee.Remove(mt)
+	case QueuedSubPool:
+		p.queued.Remove(mt)
+	default:
+		//already removed
+	}
+	p.discardLocked(mt, reason)
+}
+
 // dropping transaction from all sub-structures and from db
 // Important: don't call it while iterating by all
 func (p *TxPool) discardLocked(mt *metaTx, reason txpoolcfg.DiscardReason) {
@@ -1360,6 +1710,33 @@ func (p *TxPool) discardLocked(mt *metaTx, reason txpoolcfg.DiscardReason) {
 	p.deletedTxs = append(p.deletedTxs, mt)
 	p.all.delete(mt)
 	p.discardReasonsLRU.Add(hashStr, reason)
+	countDiscard(reason, mt.subPool&IsLocal > 0)
+	if mt.private && reason == txpoolcfg.Mined {
+		// not to gossip it if the block is unwound
+		p.privateLRU.Add(hashStr, mt.privateExpiry)
+	}
+	if p.journal != nil {
+		p.journal.onDiscard(hashStr, reason, p.lastSeenBlock.Load())
+	}
+	if _, ok := p.blobTxs[hashStr]; ok {
+		delete(p.blobTxs, hashStr)
+		p.blobPoolSize -= uint64(mt.Tx.Size)
+		// the sidecars of mined txs are kept until finalization, in case of unwind
+		if p.blobStore != nil && reason != txpoolcfg.Mined {
+			if err := p.blobStore.delete(mt.Tx.IDHash[:]); err != nil {
+				p.logger.Warn("[txpool] delete blob tx", "idHash", fmt.Sprintf("%x", mt.Tx.IDHash), "err", err)
+			}
+		}
+	}
+	if _, ok := p.conditions[hashStr]; ok {
+		if reason == txpoolcfg.Mined {
+			// the conditions are needed again if the block is unwound
+			blockNum := p.lastSeenBlock.Load()
+			p.minedConditionalTxs[blockNum] = append(p.minedConditionalTxs[blockNum], hashStr)
+		} else {
+			delete(p.conditions, hashStr)
+		}
+	}
 }
 
 // Cache recently mined blobs in anticipation of reorg, delete finalized ones
@@ -1370,6 +1747,11 @@ func (p *TxPool) processMinedFinalizedBlobs(coreTx kv.Tx, minedTxs []*types.TxSl
 		// delete individual hashes
 		for _, mt := range p.minedBlobTxsByBlock[finalizedBlock] {
 			delete(p.minedBlobTxsByHash, string(mt.Tx.IDHash[:]))
+			if _, ok := p.byHash[string(mt.Tx.IDHash[:])]; !ok && p.blobStore != nil {
+				if err := p.blobStore.delete(mt.Tx.IDHash[:]); err != nil {
+					return err
+				}
+			}
 		}
 		// delete the map entry for this block num
 		delete(p.minedBlobTxsByBlock, finalizedBlock)
@@ -1405,6 +1787,119 @@ func (p *TxPool) deleteMinedBlobTxn(hash string) {
 	delete(p.minedBlobTxsByHash, hash)
 }
 
+// blobEvictionsLocked returns the blob txs to drop so that mt fits into the blob sub-pool: the ones paying the least blob
+// fee, other than txs of the same sender. mt is rejected if it doesn't pay more than all of them. found is the tx which
+// mt replaces, if any.
+func (p *TxPool) blobEvictionsLocked(mt, found *metaTx) ([]*metaTx, txpoolcfg.DiscardReason) {
+	size, limit := p.blobPoolSize+uint64(mt.Tx.Size), p.cfg.BlobPoolLimit.Bytes()
+	if found != nil && found.Tx.Type == types.BlobTxType {
+		size -= uint64(found.Tx.Size)
+	}
+	if size <= limit {
+		return nil, txpoolcfg.NotSet
+	}
+	candidates := make([]*metaTx, 0, len(p.blobTxs))
+	for _, blobTx := range p.blobTxs {
+		if blobTx.Tx.SenderID != mt.Tx.SenderID {
+			candidates = append(candidates, blobTx)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if c := candidates[i].Tx.BlobFeeCap.Cmp(&candidates[j].Tx.BlobFeeCap); c != 0 {
+			return c < 0
+		}
+		return candidates[i].Tx.FeeCap.Lt(&candidates[j].Tx.FeeCap)
+	})
+	var evictions []*metaTx
+	for _, worst := range candidates {
+		if size <= limit {
+			break
+		}
+		if !mt.Tx.BlobFeeCap.Gt(&worst.Tx.BlobFeeCap) {
+			return nil, txpoolcfg.BlobPoolOverflow
+		}
+		evictions = append(evictions, worst)
+		size -= uint64(worst.Tx.Size)
+	}
+	if size > limit {
+		return nil, txpoolcfg.BlobPoolOverflow
+	}
+	return evictions, txpoolcfg.NotSet
+}
+
+// addBlobTxLocked accounts mt in the blob sub-pool, and queues its sidecar to be moved from memory to the blob store
+func (p *TxPool) addBlobTxLocked(mt *metaTx) {
+	p.blobTxs[string(mt.Tx.IDHash[:])] = mt
+	p.blobPoolSize += uint64(mt.Tx.Size)
+	if p.blobStore == nil {
+		return
+	}
+	if mt.Tx.Rlp == nil {
+		// already in the blob store or in kv.PoolTransaction
+		mt.Tx.Blobs, mt.Tx.Commitments, mt.Tx.Proofs = nil, nil, nil
+		return
+	}
+	p.unstoredBlobTxs = append(p.unstoredBlobTxs, mt)
+}
+
+// flushBlobs writes the queued sidecars to the blob store. The files are written without the pool lock, which is only
+// taken to pick the txs still worth storing and then to drop their sidecars from memory.
+func (p *TxPool) flushBlobs() {
+	if p.blobStore == nil {
+		return
+	}
+	type blobWrite struct {
+		mt     *metaTx
+		sender common.Address
+		rlpTx  []byte
+	}
+	p.lock.Lock()
+	writes := make([]blobWrite, 0, len(p.unstoredBlobTxs))
+	for i, mt := range p.unstoredBlobTxs {
+		p.unstoredBlobTxs[i] = nil // for gc
+		hashStr := string(mt.Tx.IDHash[:])
+		// the sidecars of mined txs are needed in case of unwind
+		_, mined := p.minedBlobTxsByHash[hashStr]
+		if mt.Tx.Rlp == nil || (p.blobTxs[hashStr] != mt && !mined) {
+			continue
+		}
+		sender, ok := p.senders.senderID2Addr[mt.Tx.SenderID]
+		if !ok {
+			continue
+		}
+		writes = append(writes, blobWrite{mt: mt, sender: sender, rlpTx: mt.Tx.Rlp})
+	}
+	p.unstoredBlobTxs = p.unstoredBlobTxs[:0]
+	p.lock.Unlock()
+
+	stored := writes[:0]
+	for _, w := range writes {
+		if err := p.blobStore.put(w.mt.Tx.IDHash[:], w.sender, w.rlpTx); err != nil {
+			p.logger.Warn("[txpool] store blob tx", "idHash", fmt.Sprintf("%x", w.mt.Tx.IDHash), "err", err)
+			continue
+		}
+		stored = append(stored, w)
+	}
+
+	var discarded [][]byte
+	p.lock.Lock()
+	for _, w := range stored {
+		hashStr := string(w.mt.Tx.IDHash[:])
+		if _, mined := p.minedBlobTxsByHash[hashStr]; p.blobTxs[hashStr] != w.mt && !mined {
+			// discarded while being written
+			discarded = append(discarded, w.mt.Tx.IDHash[:])
+			continue
+		}
+		w.mt.Tx.Rlp, w.mt.Tx.Blobs, w.mt.Tx.Commitments, w.mt.Tx.Proofs = nil, nil, nil, nil
+	}
+	p.lock.Unlock()
+	for _, hash := range discarded {
+		if err := p.blobStore.delete(hash); err != nil {
+			p.logger.Warn("[txpool] delete blob tx", "idHash", fmt.Sprintf("%x", hash), "err", err)
+		}
+	}
+}
+
 func (p *TxPool) NonceFromAddress(addr [20]byte) (nonce uint64, inPool bool) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
@@ -1415,6 +1910,34 @@ func (p *TxPool) NonceFromAddress(addr [20]byte) (nonce uint64, inPool bool) {
 	return p.all.nonce(senderID)
 }
 
+// TxStatus tells in which sub-pool a transaction is, and its position there (0 being the best), or why it was
+// discarded if it is not in the pool anymore. The position is computed against the whole sub-pool.
+func (p *TxPool) TxStatus(idHash []byte) (subPool SubPoolType, position int, reason txpoolcfg.DiscardReason) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	mt, ok := p.byHash[string(idHash)]
+	if !ok {
+		reason, _ = p.discardReasonsLRU.Get(string(idHash))
+		return 0, 0, reason
+	}
+	var ms []*metaTx
+	switch mt.currentSubPool {
+	case PendingSubPool:
+		ms = p.pending.best.ms
+	case BaseFeeSubPool:
+		ms = p.baseFee.best.ms
+	case QueuedSubPool:
+		ms = p.queued.best.ms
+	}
+	pendingBaseFee := *uint256.NewInt(p.pendingBaseFee.Load())
+	for _, other := range ms {
+		if other != mt && other.better(mt, pendingBaseFee) {
+			position++
+		}
+	}
+	return mt.currentSubPool, position, txpoolcfg.NotSet
+}
+
 // removeMined - apply new highest block (or batch of blocks)
 //
 // 1. New best block arrives, which potentially changes the balance and the nonce of some senders.
@@ -1656,6 +2179,18 @@ func MainLoop(ctx context.Context, db kv.RwDB, coreDB kv.RoDB, p *TxPool, newTxs
 	defer commitEvery.Stop()
 	logEvery := time.NewTicker(p.cfg.LogEvery)
 	defer logEvery.Stop()
+	var reloadPolicyEvery <-chan time.Time
+	if p.policy != nil {
+		ticker := time.NewTicker(p.cfg.PolicyReloadEvery)
+		defer ticker.Stop()
+		reloadPolicyEvery = ticker.C
+	}
+	var processLocalJournalEvery <-chan time.Time
+	if p.journal != nil && p.cfg.RebroadcastEvery > 0 {
+		ticker := time.NewTicker(p.cfg.RebroadcastEvery)
+		defer ticker.Stop()
+		processLocalJournalEvery = ticker.C
+	}
 
 	for {
 		select {
@@ -1664,6 +2199,16 @@ func MainLoop(ctx context.Context, db kv.RwDB, coreDB kv.RoDB, p *TxPool, newTxs
 			return
 		case <-logEvery.C:
 			p.logStats()
+		case <-reloadPolicyEvery:
+			p.reloadPolicy()
+		case <-processLocalJournalEvery:
+			rebroadcasts, err := p.processLocalJournal(ctx, db)
+			if err != nil {
+				p.logger.Error("[txpool] process local tx journal", "err", err)
+			}
+			if rebroadcasts.Len() > 0 && !p.cfg.NoGossip {
+				go p.rebroadcastLocalTxs(ctx, db, send, rebroadcasts)
+			}
 		case <-processRemoteTxsEvery.C:
 			if !p.Started() {
 				continue
@@ -1730,6 +2275,10 @@ func MainLoop(ctx context.Context, db kv.RwDB, coreDB kv.RoDB, p *TxPool, newTxs
 				if err := db.View(ctx, func(tx kv.Tx) error {
 					for i := 0; i < announcements.Len(); i++ {
 						t, size, hash := announcements.At(i)
+						if p.IsPrivate(hash) {
+							// private transactions are kept for local block building only
+							continue
+						}
 						slotRlp, err := p.GetRlp(tx, hash)
 						if err != nil {
 							return err
@@ -1831,8 +2380,10 @@ func (p *TxPool) flushNoFsync(ctx context.Context, db kv.RwDB) (written uint64,
 
 func (p *TxPool) flush(ctx context.Context, db kv.RwDB) (written uint64, err error) {
 	defer writeToDBTimer.ObserveDuration(time.Now())
-	// 1. get global lock on txpool and flush it to db, without fsync (to release lock asap)
-	// 2. then fsync db without txpool lock
+	// 1. write the blob sidecars to the blob store, mostly without txpool lock
+	// 2. get global lock on txpool and flush it to db, without fsync (to release lock asap)
+	// 3. then fsync db without txpool lock
+	p.flushBlobs()
 	written, err = p.flushNoFsync(ctx, db)
 	if err != nil {
 		return 0, err
@@ -1880,11 +2431,54 @@ func (p *TxPool) flushLocked(tx kv.RwTx) (err error) {
 		}
 	}
 
+	if err := tx.ClearBucket(kv.PrivateTransaction); err != nil {
+		return err
+	}
+	for txHash, metaTx := range p.byHash {
+		if !metaTx.private {
+			continue
+		}
+		binary.BigEndian.PutUint64(encID, metaTx.privateExpiry)
+		if err := tx.Put(kv.PrivateTransaction, []byte(txHash), encID); err != nil {
+			return err
+		}
+	}
+	for _, txHash := range p.privateLRU.Keys() {
+		expiry, _ := p.privateLRU.Peek(txHash)
+		binary.BigEndian.PutUint64(encID, expiry)
+		if err := tx.Put(kv.PrivateTransaction, []byte(txHash), encID); err != nil {
+			return err
+		}
+	}
+
+	if p.journal != nil {
+		if err := p.journal.flush(tx); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.ClearBucket(kv.ConditionalTransaction); err != nil {
+		return err
+	}
+	for txHash, conditions := range p.conditions {
+		encoded, err := json.Marshal(conditions)
+		if err != nil {
+			return err
+		}
+		if err := tx.Put(kv.ConditionalTransaction, []byte(txHash), encoded); err != nil {
+			return err
+		}
+	}
+
 	v := make([]byte, 0, 1024)
 	for txHash, metaTx := range p.byHash {
 		if metaTx.Tx.Rlp == nil {
 			continue
 		}
+		if p.blobStore != nil && metaTx.Tx.Type == types.BlobTxType {
+			// left to flushBlobs
+			continue
+		}
 		v = common.EnsureEnoughSize(v, 20+len(metaTx.Tx.Rlp))
 
 		addr, ok := p.senders.senderID2Addr[metaTx.Tx.SenderID]
@@ -1905,7 +2499,7 @@ func (p *TxPool) flushLocked(tx kv.RwTx) (err error) {
 				return err
 			}
 		}
-		metaTx.Tx.Rlp = nil
+		metaTx.Tx.Rlp, metaTx.Tx.Blobs, metaTx.Tx.Commitments, metaTx.Tx.Proofs = nil, nil, nil, nil
 	}
 
 	binary.BigEndian.PutUint64(encID, p.pendingBaseFee.Load())
@@ -1956,6 +2550,38 @@ func (p *TxPool) fromDB(ctx context.Context, tx kv.Tx, coreTx kv.Tx) error {
 		}
 		p.isLocalLRU.Add(string(v), struct{}{})
 	}
+	it, err = tx.Range(kv.PrivateTransaction, nil, nil)
+	if err != nil {
+		return err
+	}
+	private := map[string]uint64{} // tx_hash => expiry_block
+	for it.HasNext() {
+		k, v, err := it.Next()
+		if err != nil {
+			return err
+		}
+		private[string(k)] = binary.BigEndian.Uint64(v)
+	}
+	it, err = tx.Range(kv.ConditionalTransaction, nil, nil)
+	if err != nil {
+		return err
+	}
+	for it.HasNext() {
+		k, v, err := it.Next()
+		if err != nil {
+			return err
+		}
+		conditions := &TxConditions{}
+		if err := json.Unmarshal(v, conditions); err != nil {
+			return err
+		}
+		p.conditions[string(k)] = conditions
+	}
+	if p.journal != nil {
+		if err := p.journal.load(tx); err != nil {
+			return err
+		}
+	}
 
 	txs := types.TxSlots{}
 	parseCtx := types.NewTxParseContext(p.chainID)
@@ -1997,6 +2623,30 @@ func (p *TxPool) fromDB(ctx context.Context, tx kv.Tx, coreTx kv.Tx) error {
 		copy(txs.Senders.At(i), addr[:])
 		i++
 	}
+	if p.blobStore != nil {
+		if err := p.blobStore.forEach(func(hash []byte, addr common.Address, txRlp []byte) error {
+			txn := &types.TxSlot{}
+			if _, err := parseCtx.ParseTransaction(txRlp, 0, txn, nil, false /* hasEnvelope */, true /*wrappedWithBlobs*/, nil); err != nil {
+				p.logger.Warn("[txpool] fromDB: parse blob tx", "idHash", fmt.Sprintf("%x", hash), "err", err)
+				return p.blobStore.delete(hash)
+			}
+			txn.Rlp = nil // already in the blob store
+			txn.SenderID, txn.Traced = p.senders.getOrCreateID(addr, p.logger)
+			isLocalTx := p.isLocalLRU.Contains(string(hash))
+			// also drops the sidecars of mined txs, kept for unwinds before the restart
+			if reason := p.validateTx(txn, isLocalTx, cacheView); reason != txpoolcfg.NotSet && reason != txpoolcfg.Success {
+				return p.blobStore.delete(hash)
+			}
+			txs.Resize(uint(i + 1))
+			txs.Txs[i] = txn
+			txs.IsLocal[i] = isLocalTx
+			copy(txs.Senders.At(i), addr[:])
+			i++
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
 
 	var pendingBaseFee uint64
 	{
@@ -2027,6 +2677,13 @@ func (p *TxPool) fromDB(ctx context.Context, tx kv.Tx, coreTx kv.Tx) error {
 		pendingBaseFee, pendingBlobFee, math.MaxUint64 /* blockGasLimit */, p.pending, p.baseFee, p.queued, p.all, p.byHash, p.addLocked, p.discardLocked, false, p.logger); err != nil {
 		return err
 	}
+	for hashStr, expiry := range private {
+		if mt, ok := p.byHash[hashStr]; ok {
+			mt.private, mt.privateExpiry = true, expiry
+		} else {
+			p.privateLRU.Add(hashStr, expiry)
+		}
+	}
 	p.pendingBaseFee.Store(pendingBaseFee)
 	p.pendingBlobFee.Store(pendingBlobFee)
 	return nil
@@ -2108,6 +2765,8 @@ func (p *TxPool) logStats() {
 		"pending", p.pending.Len(),
 		"baseFee", p.baseFee.Len(),
 		"queued", p.queued.Len(),
+		"blob", len(p.blobTxs),
+		"blobSize", common.ByteCount(p.blobPoolSize),
 	}
 	cacheKeys := p._stateCache.Len()
 	if cacheKeys > 0 {
@@ -2118,6 +2777,8 @@ func (p *TxPool) logStats() {
 	pendingSubCounter.SetInt(p.pending.Len())
 	basefeeSubCounter.SetInt(p.baseFee.Len())
 	queuedSubCounter.SetInt(p.queued.Len())
+	blobSubCounter.SetInt(len(p.blobTxs))
+	blobSubSizeCounter.SetUint64(p.blobPoolSize)
 }
 
 // Deprecated need switch to streaming-like
@@ -2126,18 +2787,14 @@ func (p *TxPool) deprecatedForEach(_ context.Context, f func(rlp []byte, sender
 	defer p.lock.Unlock()
 	p.all.ascendAll(func(mt *metaTx) bool {
 		slot := mt.Tx
-		slotRlp := slot.Rlp
-		if slot.Rlp == nil {
-			v, err := tx.GetOne(kv.PoolTransaction, slot.IDHash[:])
-			if err != nil {
-				p.logger.Warn("[txpool] foreach: get tx from db", "err", err)
-				return true
-			}
-			if v == nil {
-				p.logger.Warn("[txpool] foreach: tx not found in db")
-				return true
-			}
-			slotRlp = v[20:]
+		slotRlp, _, _, err := p.getRlpLocked(tx, slot.IDHash[:])
+		if err != nil {
+			p.logger.Warn("[txpool] foreach: get tx from db", "err", err)
+			return true
+		}
+		if slotRlp == nil {
+			p.logger.Warn("[txpool] foreach: tx not found in db")
+			return true
 		}
 		if sender, found := p.senders.senderID2Addr[slot.SenderID]; found {
 			f(slotRlp, sender, mt.currentSubPool)
@@ -2350,11 +3007,11 @@ func (b *BySenderAndNonce) delete(mt *metaTx) {
 			delete(b.senderIDTxnCount, senderID)
 		}
 
-		if mt.Tx.Type == types.BlobTxType && mt.Tx.Blobs != nil {
+		if mt.Tx.Type == types.BlobTxType {
 			accBlobCount := b.senderIDBlobCount[senderID]
-			txnBlobCount := len(mt.Tx.Blobs)
-			if txnBlobCount > 1 {
-				b.senderIDBlobCount[senderID] = accBlobCount - uint64(txnBlobCount)
+			txnBlobCount := uint64(len(mt.Tx.BlobHashes))
+			if accBlobCount > txnBlobCount {
+				b.senderIDBlobCount[senderID] = accBlobCount - txnBlobCount
 			} else {
 				delete(b.senderIDBlobCount, senderID)
 			}
@@ -2367,8 +3024,8 @@ func (b *BySenderAndNonce) replaceOrInsert(mt *metaTx) *metaTx {
 		return it
 	}
 	b.senderIDTxnCount[mt.Tx.SenderID]++
-	if mt.Tx.Type == types.BlobTxType && mt.Tx.Blobs != nil {
-		b.senderIDBlobCount[mt.Tx.SenderID] += uint64(len(mt.Tx.Blobs))
+	if mt.Tx.Type == types.BlobTxType {
+		b.senderIDBlobCount[mt.Tx.SenderID] += uint64(len(mt.Tx.BlobHashes))
 	}
 	return nil
 }
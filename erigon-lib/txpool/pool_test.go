@@ -23,8 +23,12 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/c2h5oh/datasize"
 	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
 	"github.com/holiman/uint256"
 	"github.com/ledgerwatch/log/v3"
@@ -165,6 +169,87 @@ func TestNonceFromAddress(t *testing.T) {
 	}
 }
 
+func TestTxStatus(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ch := make(chan types.Announcements, 100)
+	db, coreDB := memdb.NewTestPoolDB(t), memdb.NewTestDB(t)
+
+	cfg := txpoolcfg.DefaultConfig
+	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, log.New())
+	assert.NoError(err)
+	require.True(pool != nil)
+	ctx := context.Background()
+	h1 := gointerfaces.ConvertHashToH256([32]byte{})
+	change := &remote.StateChangeBatch{
+		StateVersionId:      0,
+		PendingBlockBaseFee: 200000,
+		BlockGasLimit:       1000000,
+		ChangeBatch: []*remote.StateChange{
+			{BlockHeight: 0, BlockHash: h1},
+		},
+	}
+	var addr [20]byte
+	addr[0] = 1
+	v := make([]byte, types.EncodeSenderLengthForStorage(2, *uint256.NewInt(1 * common.Ether)))
+	types.EncodeSender(2, *uint256.NewInt(1 * common.Ether), v)
+	change.ChangeBatch[0].Changes = append(change.ChangeBatch[0].Changes, &remote.AccountChange{
+		Action:  remote.Action_UPSERT,
+		Address: gointerfaces.ConvertAddressToH160(addr),
+		Data:    v,
+	})
+	tx, err := db.BeginRw(ctx)
+	require.NoError(err)
+	defer tx.Rollback()
+	err = pool.OnNewBlock(ctx, change, types.TxSlots{}, types.TxSlots{}, tx)
+	assert.NoError(err)
+
+	newTx := func(nonce uint64, tip uint64, id byte) *types.TxSlot {
+		txSlot := &types.TxSlot{
+			Tip:    *uint256.NewInt(tip),
+			FeeCap: *uint256.NewInt(tip),
+			Gas:    100000,
+			Nonce:  nonce,
+		}
+		txSlot.IDHash[0] = id
+		return txSlot
+	}
+	var txSlots types.TxSlots
+	txSlots.Append(newTx(2, 300000, 1), addr[:], true)
+	txSlots.Append(newTx(3, 300000, 2), addr[:], true)
+	txSlots.Append(newTx(5, 300000, 3), addr[:], true)
+	reasons, err := pool.AddLocalTxs(ctx, txSlots, tx)
+	assert.NoError(err)
+	for _, reason := range reasons {
+		assert.Equal(txpoolcfg.Success, reason, reason.String())
+	}
+
+	subPool, position, reason := pool.TxStatus(txSlots.Txs[0].IDHash[:])
+	assert.Equal(PendingSubPool, subPool)
+	assert.Equal(0, position)
+	assert.Equal(txpoolcfg.NotSet, reason)
+	subPool, position, _ = pool.TxStatus(txSlots.Txs[1].IDHash[:])
+	assert.Equal(PendingSubPool, subPool)
+	assert.Equal(1, position)
+	subPool, _, _ = pool.TxStatus(txSlots.Txs[2].IDHash[:])
+	assert.Equal(QueuedSubPool, subPool)
+
+	replaced := discardCounter(txpoolcfg.ReplacedByHigherTip, true).GetValue()
+	var replacements types.TxSlots
+	replacements.Append(newTx(3, 3000000, 4), addr[:], true)
+	reasons, err = pool.AddLocalTxs(ctx, replacements, tx)
+	assert.NoError(err)
+	assert.Equal(txpoolcfg.Success, reasons[0], reasons[0].String())
+
+	subPool, _, reason = pool.TxStatus(txSlots.Txs[1].IDHash[:])
+	assert.Equal(SubPoolType(0), subPool)
+	assert.Equal(txpoolcfg.ReplacedByHigherTip, reason)
+	assert.Equal(replaced+1, discardCounter(txpoolcfg.ReplacedByHigherTip, true).GetValue())
+
+	_, _, reason = pool.TxStatus([]byte{0xff})
+	assert.Equal(txpoolcfg.NotSet, reason)
+}
+
 func TestReplaceWithHigherFee(t *testing.T) {
 	assert, require := assert.New(t), require.New(t)
 	ch := make(chan types.Announcements, 100)
@@ -873,6 +958,107 @@ func TestBlobTxReplacement(t *testing.T) {
 	}
 }
 
+func TestBlobPool(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ch := make(chan types.Announcements, 5)
+	db, coreDB := memdb.NewTestPoolDB(t), memdb.NewTestDB(t)
+	cfg := txpoolcfg.DefaultConfig
+	cfg.DBDir = t.TempDir()
+	blobTxSize := makeBlobTx().Size
+	cfg.BlobPoolLimit = datasize.ByteSize(2*blobTxSize + blobTxSize/2) // room for 2 txs
+	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	pool, err := New(ch, coreDB, cfg, sendersCache, *uint256.NewInt(5), common.Big0, nil, common.Big0, fixedgas.DefaultMaxBlobsPerBlock, log.New())
+	assert.NoError(err)
+	require.True(pool != nil)
+	ctx := context.Background()
+
+	h1 := gointerfaces.ConvertHashToH256([32]byte{})
+	change := &remote.StateChangeBatch{
+		StateVersionId:       0,
+		PendingBlockBaseFee:  200_000,
+		BlockGasLimit:        1000000,
+		PendingBlobFeePerGas: 100_000,
+		ChangeBatch: []*remote.StateChange{
+			{BlockHeight: 0, BlockHash: h1},
+		},
+	}
+	addrs := make([][20]byte, 3)
+	for i := range addrs {
+		addrs[i][0] = byte(i + 1)
+		v := make([]byte, types.EncodeSenderLengthForStorage(2, *uint256.NewInt(1 * common.Ether)))
+		types.EncodeSender(2, *uint256.NewInt(1 * common.Ether), v)
+		change.ChangeBatch[0].Changes = append(change.ChangeBatch[0].Changes, &remote.AccountChange{
+			Action:  remote.Action_UPSERT,
+			Address: gointerfaces.ConvertAddressToH160(addrs[i]),
+			Data:    v,
+		})
+	}
+	tx, err := db.BeginRw(ctx)
+	require.NoError(err)
+	defer tx.Rollback()
+	err = pool.OnNewBlock(ctx, change, types.TxSlots{}, types.TxSlots{}, tx)
+	assert.NoError(err)
+
+	addBlobTx := func(sender [20]byte, blobFeeCap uint64, id byte) (*types.TxSlot, txpoolcfg.DiscardReason) {
+		blobTxn := makeBlobTx()
+		blobTxn.Nonce = 0x2
+		blobTxn.BlobFeeCap = *uint256.NewInt(blobFeeCap)
+		blobTxn.IDHash[0] = id
+		var txSlots types.TxSlots
+		txSlots.Append(&blobTxn, sender[:], true)
+		reasons, err := pool.AddLocalTxs(ctx, txSlots, tx)
+		assert.NoError(err)
+		return &blobTxn, reasons[0]
+	}
+
+	wrappedRlp := common.Copy(makeBlobTx().Rlp)
+	txA, reason := addBlobTx(addrs[0], 200_000, 0xa)
+	assert.Equal(txpoolcfg.Success, reason, reason.String())
+	txB, reason := addBlobTx(addrs[1], 300_000, 0xb)
+	assert.Equal(txpoolcfg.Success, reason, reason.String())
+
+	// the sidecars stay in memory until flushed
+	assert.NotNil(txA.Blobs)
+	assert.NotNil(txA.Rlp)
+	assert.Equal(2, len(pool.unstoredBlobTxs))
+
+	// then they are on disk only
+	pool.flushBlobs()
+	assert.Nil(txA.Blobs)
+	assert.Nil(txA.Rlp)
+	assert.Equal(0, len(pool.unstoredBlobTxs))
+	rlpA, err := pool.GetRlp(tx, txA.IDHash[:])
+	require.NoError(err)
+	assert.Equal(wrappedRlp, rlpA)
+	known, err := pool.GetKnownBlobTxn(tx, txB.IDHash[:])
+	require.NoError(err)
+	assert.Equal(2, len(known.Tx.Blobs))
+
+	// the blob sub-pool is full, and the tx doesn't pay more than the others
+	_, reason = addBlobTx(addrs[2], 150_000, 0xc)
+	assert.Equal(txpoolcfg.BlobPoolOverflow, reason, reason.String())
+
+	// the tx paying the least blob fee is evicted
+	_, reason = addBlobTx(addrs[2], 400_000, 0xd)
+	assert.Equal(txpoolcfg.Success, reason, reason.String())
+	_, _, reason = pool.TxStatus(txA.IDHash[:])
+	assert.Equal(txpoolcfg.BlobPoolOverflow, reason)
+	rlpA, err = pool.GetRlp(tx, txA.IDHash[:])
+	require.NoError(err)
+	assert.Nil(rlpA)
+	assert.Equal(2, len(pool.blobTxs))
+	assert.Equal(2*uint64(blobTxSize), pool.blobPoolSize)
+
+	// the store keeps the remaining txs only
+	pool.flushBlobs()
+	var stored [][]byte
+	require.NoError(pool.blobStore.forEach(func(hash []byte, sender common.Address, rlpTx []byte) error {
+		stored = append(stored, hash)
+		return nil
+	}))
+	assert.Equal(2, len(stored))
+}
+
 // Todo, make the tx more realistic with good values
 func makeBlobTx() types.TxSlot {
 	// Some arbitrary hardcoded example
@@ -1043,3 +1229,556 @@ func TestDropRemoteAtNoGossip(t *testing.T) {
 	// no announcement because unprocessedRemoteTxs is already empty
 	assert.True(checkAnnouncementEmpty())
 }
+
+func TestPrivateTx(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ch := make(chan types.Announcements, 100)
+	db, coreDB := memdb.NewTestPoolDB(t), memdb.NewTestDB(t)
+
+	cfg := txpoolcfg.DefaultConfig
+	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, log.New())
+	assert.NoError(err)
+	require.True(pool != nil)
+	ctx := context.Background()
+	var stateVersionID uint64 = 0
+	pendingBaseFee := uint64(200000)
+	// start blocks from 0, set empty hash - then kvcache will also work on this
+	h1 := gointerfaces.ConvertHashToH256([32]byte{})
+	change := &remote.StateChangeBatch{
+		StateVersionId:      stateVersionID,
+		PendingBlockBaseFee: pendingBaseFee,
+		BlockGasLimit:       1000000,
+		ChangeBatch: []*remote.StateChange{
+			{BlockHeight: 0, BlockHash: h1},
+		},
+	}
+	var addr [20]byte
+	addr[0] = 1
+	v := make([]byte, types.EncodeSenderLengthForStorage(2, *uint256.NewInt(1 * common.Ether)))
+	types.EncodeSender(2, *uint256.NewInt(1 * common.Ether), v)
+	change.ChangeBatch[0].Changes = append(change.ChangeBatch[0].Changes, &remote.AccountChange{
+		Action:  remote.Action_UPSERT,
+		Address: gointerfaces.ConvertAddressToH160(addr),
+		Data:    v,
+	})
+	tx, err := db.BeginRw(ctx)
+	require.NoError(err)
+	defer tx.Rollback()
+	err = pool.OnNewBlock(ctx, change, types.TxSlots{}, types.TxSlots{}, tx)
+	assert.NoError(err)
+
+	var txSlots types.TxSlots
+	txSlot := &types.TxSlot{
+		Tip:    *uint256.NewInt(300000),
+		FeeCap: *uint256.NewInt(300000),
+		Gas:    100000,
+		Nonce:  2,
+	}
+	txSlot.IDHash[0] = 1
+	txSlots.Append(txSlot, addr[:], true)
+	reasons, err := pool.AddLocalTxsWithOptions(ctx, txSlots, []LocalTxOptions{{Private: true, PrivateExpiryBlocks: 2}}, tx)
+	assert.NoError(err)
+	for _, reason := range reasons {
+		assert.Equal(txpoolcfg.Success, reason, reason.String())
+	}
+	assert.True(pool.IsPrivate(txSlot.IDHash[:]))
+	// the pool keeps it for block building, but it is not announced to new peers
+	assert.Equal(1, pool.pending.Len())
+	_, _, hashes := pool.AppendLocalAnnouncements(nil, nil, nil)
+	assert.Equal(0, len(hashes))
+	// the flag stays with the transaction, however many private transactions were mined meanwhile
+	for i := 0; i < 10_000; i++ {
+		pool.privateLRU.Add(fmt.Sprintf("mined %d", i), 0)
+	}
+	assert.True(pool.IsPrivate(txSlot.IDHash[:]))
+
+	for _, blockNum := range []uint64{1, 2} {
+		stateVersionID++
+		change = &remote.StateChangeBatch{
+			StateVersionId:      stateVersionID,
+			PendingBlockBaseFee: pendingBaseFee,
+			BlockGasLimit:       1000000,
+			ChangeBatch: []*remote.StateChange{
+				{BlockHeight: blockNum, BlockHash: gointerfaces.ConvertHashToH256([32]byte{byte(blockNum)})},
+			},
+		}
+		err = pool.OnNewBlock(ctx, change, types.TxSlots{}, types.TxSlots{}, tx)
+		assert.NoError(err)
+		if blockNum < 2 {
+			assert.Equal(1, pool.pending.Len())
+		}
+	}
+	// not included up to its expiry block
+	assert.Equal(0, pool.pending.Len())
+	assert.False(pool.IsPrivate(txSlot.IDHash[:]))
+	reason, ok := pool.discardReasonsLRU.Get(string(txSlot.IDHash[:]))
+	assert.True(ok)
+	assert.Equal(txpoolcfg.PrivateTxExpired, reason)
+}
+
+func TestPrivateTxUnwind(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ch := make(chan types.Announcements, 100)
+	db, coreDB := memdb.NewTestPoolDB(t), memdb.NewTestDB(t)
+
+	cfg := txpoolcfg.DefaultConfig
+	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, log.New())
+	assert.NoError(err)
+	require.True(pool != nil)
+	ctx := context.Background()
+	pendingBaseFee := uint64(200000)
+	var addr [20]byte
+	addr[0] = 1
+	newBlock := func(stateVersionID, blockNum, nonce uint64) *remote.StateChangeBatch {
+		v := make([]byte, types.EncodeSenderLengthForStorage(nonce, *uint256.NewInt(1 * common.Ether)))
+		types.EncodeSender(nonce, *uint256.NewInt(1 * common.Ether), v)
+		return &remote.StateChangeBatch{
+			StateVersionId:      stateVersionID,
+			PendingBlockBaseFee: pendingBaseFee,
+			BlockGasLimit:       1000000,
+			ChangeBatch: []*remote.StateChange{{
+				BlockHeight: blockNum,
+				BlockHash:   gointerfaces.ConvertHashToH256([32]byte{byte(stateVersionID)}),
+				Changes: []*remote.AccountChange{{
+					Action:  remote.Action_UPSERT,
+					Address: gointerfaces.ConvertAddressToH160(addr),
+					Data:    v,
+				}},
+			}},
+		}
+	}
+	tx, err := db.BeginRw(ctx)
+	require.NoError(err)
+	defer tx.Rollback()
+	err = pool.OnNewBlock(ctx, newBlock(0, 0, 2), types.TxSlots{}, types.TxSlots{}, tx)
+	assert.NoError(err)
+
+	newTxSlots := func() types.TxSlots {
+		var txSlots types.TxSlots
+		txSlot := &types.TxSlot{
+			Tip:    *uint256.NewInt(300000),
+			FeeCap: *uint256.NewInt(300000),
+			Gas:    100000,
+			Nonce:  2,
+		}
+		txSlot.IDHash[0] = 1
+		txSlots.Append(txSlot, addr[:], true)
+		return txSlots
+	}
+	txSlots := newTxSlots()
+	idHash := txSlots.Txs[0].IDHash
+	reasons, err := pool.AddLocalTxsWithOptions(ctx, txSlots, []LocalTxOptions{{Private: true}}, tx)
+	assert.NoError(err)
+	assert.Equal([]txpoolcfg.DiscardReason{txpoolcfg.Success}, reasons)
+	assert.True(pool.byHash[string(idHash[:])].private)
+	assert.False(pool.privateLRU.Contains(string(idHash[:])))
+
+	// once mined, it is remembered until it is unwound
+	err = pool.OnNewBlock(ctx, newBlock(1, 1, 3), types.TxSlots{}, newTxSlots(), tx)
+	assert.NoError(err)
+	assert.Equal(0, pool.pending.Len())
+	assert.True(pool.IsPrivate(idHash[:]))
+	assert.True(pool.privateLRU.Contains(string(idHash[:])))
+
+	err = pool.OnNewBlock(ctx, newBlock(2, 1, 2), newTxSlots(), types.TxSlots{}, tx)
+	assert.NoError(err)
+	assert.Equal(1, pool.pending.Len())
+	assert.True(pool.byHash[string(idHash[:])].private)
+	assert.False(pool.privateLRU.Contains(string(idHash[:])))
+}
+
+func TestConditionalTx(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ch := make(chan types.Announcements, 100)
+	db, coreDB := memdb.NewTestPoolDB(t), memdb.NewTestDB(t)
+
+	cfg := txpoolcfg.DefaultConfig
+	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, log.New())
+	assert.NoError(err)
+	require.True(pool != nil)
+	ctx := context.Background()
+	var stateVersionID uint64 = 0
+	pendingBaseFee := uint64(200000)
+	// start blocks from 0, set empty hash - then kvcache will also work on this
+	h1 := gointerfaces.ConvertHashToH256([32]byte{})
+	change := &remote.StateChangeBatch{
+		StateVersionId:      stateVersionID,
+		PendingBlockBaseFee: pendingBaseFee,
+		BlockGasLimit:       1000000,
+		ChangeBatch: []*remote.StateChange{
+			{BlockHeight: 0, BlockHash: h1},
+		},
+	}
+	var addr [20]byte
+	addr[0] = 1
+	v := make([]byte, types.EncodeSenderLengthForStorage(2, *uint256.NewInt(1 * common.Ether)))
+	types.EncodeSender(2, *uint256.NewInt(1 * common.Ether), v)
+	change.ChangeBatch[0].Changes = append(change.ChangeBatch[0].Changes, &remote.AccountChange{
+		Action:  remote.Action_UPSERT,
+		Address: gointerfaces.ConvertAddressToH160(addr),
+		Data:    v,
+	})
+	tx, err := db.BeginRw(ctx)
+	require.NoError(err)
+	defer tx.Rollback()
+	err = pool.OnNewBlock(ctx, change, types.TxSlots{}, types.TxSlots{}, tx)
+	assert.NoError(err)
+
+	var contract [20]byte
+	contract[0] = 2
+	slot, value := common.Hash{1}, common.Hash{2}
+	conditions := &TxConditions{
+		BlockNumberMax: 10,
+		KnownAccounts:  map[common.Address]*KnownAccount{contract: {Slots: map[common.Hash]common.Hash{slot: value}}},
+	}
+	var txSlots types.TxSlots
+	for nonce := uint64(2); nonce <= 3; nonce++ {
+		txSlot := &types.TxSlot{
+			Tip:    *uint256.NewInt(300000),
+			FeeCap: *uint256.NewInt(300000),
+			Gas:    100000,
+			Nonce:  nonce,
+		}
+		txSlot.IDHash[0] = byte(nonce)
+		txSlots.Append(txSlot, addr[:], true)
+	}
+	// the second transaction is already out of its time bounds
+	reasons, err := pool.AddLocalTxsWithOptions(ctx, txSlots, []LocalTxOptions{{Conditions: conditions}, {Conditions: &TxConditions{TimestampMax: 1}}}, tx)
+	assert.NoError(err)
+	assert.Equal([]txpoolcfg.DiscardReason{txpoolcfg.Success, txpoolcfg.ConditionsNotMet}, reasons)
+	assert.Equal(1, pool.pending.Len())
+	assert.Equal(conditions, pool.TxConditions(txSlots.Txs[0].IDHash))
+	assert.Nil(pool.TxConditions(txSlots.Txs[1].IDHash))
+
+	// a block which does not touch the known slot
+	stateVersionID++
+	change = &remote.StateChangeBatch{
+		StateVersionId:      stateVersionID,
+		PendingBlockBaseFee: pendingBaseFee,
+		BlockGasLimit:       1000000,
+		ChangeBatch: []*remote.StateChange{
+			{BlockHeight: 1, BlockHash: gointerfaces.ConvertHashToH256([32]byte{1}), Changes: []*remote.AccountChange{{
+				Action:         remote.Action_STORAGE,
+				Address:        gointerfaces.ConvertAddressToH160(contract),
+				StorageChanges: []*remote.StorageChange{{Location: gointerfaces.ConvertHashToH256(common.Hash{3}), Data: []byte{1}}},
+			}}},
+		},
+	}
+	err = pool.OnNewBlock(ctx, change, types.TxSlots{}, types.TxSlots{}, tx)
+	assert.NoError(err)
+	assert.Equal(1, pool.pending.Len())
+
+	// a block which changes it
+	stateVersionID++
+	change = &remote.StateChangeBatch{
+		StateVersionId:      stateVersionID,
+		PendingBlockBaseFee: pendingBaseFee,
+		BlockGasLimit:       1000000,
+		ChangeBatch: []*remote.StateChange{
+			{BlockHeight: 2, BlockHash: gointerfaces.ConvertHashToH256([32]byte{2}), Changes: []*remote.AccountChange{{
+				Action:         remote.Action_STORAGE,
+				Address:        gointerfaces.ConvertAddressToH160(contract),
+				StorageChanges: []*remote.StorageChange{{Location: gointerfaces.ConvertHashToH256(slot), Data: []byte{3}}},
+			}}},
+		},
+	}
+	err = pool.OnNewBlock(ctx, change, types.TxSlots{}, types.TxSlots{}, tx)
+	assert.NoError(err)
+	assert.Equal(0, pool.pending.Len())
+	assert.Nil(pool.TxConditions(txSlots.Txs[0].IDHash))
+	reason, ok := pool.discardReasonsLRU.Get(string(txSlots.Txs[0].IDHash[:]))
+	assert.True(ok)
+	assert.Equal(txpoolcfg.ConditionsNotMet, reason)
+}
+
+func TestPolicy(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ch := make(chan types.Announcements, 100)
+	db, coreDB := memdb.NewTestPoolDB(t), memdb.NewTestDB(t)
+
+	var addr, recipient, sanctioned [20]byte
+	addr[0], recipient[0], sanctioned[0] = 1, 2, 3
+	policyFile := filepath.Join(t.TempDir(), "policy.json")
+	writePolicy := func(rules string, modTime time.Time) {
+		require.NoError(os.WriteFile(policyFile, []byte(rules), 0o600))
+		require.NoError(os.Chtimes(policyFile, modTime, modTime))
+	}
+	writePolicy(fmt.Sprintf(`{"rules": [{"name": "sanctions", "recipients": ["0x%x"]}, {"name": "approvals", "selectors": ["0x095ea7b3"]}]}`, sanctioned), time.Unix(1, 0))
+
+	cfg := txpoolcfg.DefaultConfig
+	cfg.PolicyFile = policyFile
+	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, log.New())
+	assert.NoError(err)
+	require.True(pool != nil)
+	ctx := context.Background()
+	var stateVersionID uint64 = 0
+	pendingBaseFee := uint64(200000)
+	// start blocks from 0, set empty hash - then kvcache will also work on this
+	h1 := gointerfaces.ConvertHashToH256([32]byte{})
+	change := &remote.StateChangeBatch{
+		StateVersionId:      stateVersionID,
+		PendingBlockBaseFee: pendingBaseFee,
+		BlockGasLimit:       1000000,
+		ChangeBatch: []*remote.StateChange{
+			{BlockHeight: 0, BlockHash: h1},
+		},
+	}
+	v := make([]byte, types.EncodeSenderLengthForStorage(2, *uint256.NewInt(1 * common.Ether)))
+	types.EncodeSender(2, *uint256.NewInt(1 * common.Ether), v)
+	change.ChangeBatch[0].Changes = append(change.ChangeBatch[0].Changes, &remote.AccountChange{
+		Action:  remote.Action_UPSERT,
+		Address: gointerfaces.ConvertAddressToH160(addr),
+		Data:    v,
+	})
+	tx, err := db.BeginRw(ctx)
+	require.NoError(err)
+	defer tx.Rollback()
+	err = pool.OnNewBlock(ctx, change, types.TxSlots{}, types.TxSlots{}, tx)
+	assert.NoError(err)
+
+	var txSlots types.TxSlots
+	for i, to := range [][20]byte{recipient, sanctioned, recipient} {
+		txSlot := &types.TxSlot{
+			Tip:    *uint256.NewInt(300000),
+			FeeCap: *uint256.NewInt(300000),
+			Gas:    100000,
+			Nonce:  uint64(2 + i),
+			To:     to,
+		}
+		if i == 2 {
+			txSlot.DataLen, txSlot.Selector = 4+32+32, [4]byte{0x09, 0x5e, 0xa7, 0xb3}
+			txSlot.Gas = 200000
+		}
+		txSlot.IDHash[0] = byte(i + 1)
+		txSlots.Append(txSlot, addr[:], true)
+	}
+	reasons, err := pool.AddLocalTxs(ctx, txSlots, tx)
+	assert.NoError(err)
+	assert.Equal([]txpoolcfg.DiscardReason{txpoolcfg.Success, txpoolcfg.PolicyRejected, txpoolcfg.PolicyRejected}, reasons)
+	assert.Equal(1, pool.pending.Len())
+
+	// the sender gets sanctioned: its transactions already in the pool are dropped on reload
+	writePolicy(fmt.Sprintf(`{"rules": [{"name": "sanctions", "senders": ["0x%x"]}]}`, addr), time.Unix(2, 0))
+	pool.reloadPolicy()
+	assert.Equal(0, pool.pending.Len())
+	reason, ok := pool.discardReasonsLRU.Get(string(txSlots.Txs[0].IDHash[:]))
+	assert.True(ok)
+	assert.Equal(txpoolcfg.PolicyRejected, reason)
+
+	// invalid rules are not applied
+	writePolicy(`{"rules": [{"name": "no selector", "selectors": ["0x01"]}]}`, time.Unix(3, 0))
+	pool.reloadPolicy()
+	assert.Equal(1, len(pool.policy.rules))
+}
+
+func TestLocalJournal(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ch := make(chan types.Announcements, 100)
+	db, coreDB := memdb.NewTestPoolDB(t), memdb.NewTestDB(t)
+
+	cfg := txpoolcfg.DefaultConfig
+	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, log.New())
+	assert.NoError(err)
+	require.True(pool != nil)
+	ctx := context.Background()
+	var addr [20]byte
+	addr[0] = 1
+	newBlock := func(blockNum, senderNonce, finalized uint64, minedTxs types.TxSlots, tx kv.Tx) {
+		change := &remote.StateChangeBatch{
+			StateVersionId:      blockNum,
+			PendingBlockBaseFee: 200000,
+			BlockGasLimit:       1000000,
+			FinalizedBlock:      finalized,
+			ChangeBatch: []*remote.StateChange{
+				{BlockHeight: blockNum, BlockHash: gointerfaces.ConvertHashToH256([32]byte{byte(blockNum)})},
+			},
+		}
+		v := make([]byte, types.EncodeSenderLengthForStorage(senderNonce, *uint256.NewInt(1 * common.Ether)))
+		types.EncodeSender(senderNonce, *uint256.NewInt(1 * common.Ether), v)
+		change.ChangeBatch[0].Changes = append(change.ChangeBatch[0].Changes, &remote.AccountChange{
+			Action:  remote.Action_UPSERT,
+			Address: gointerfaces.ConvertAddressToH160(addr),
+			Data:    v,
+		})
+		assert.NoError(pool.OnNewBlock(ctx, change, types.TxSlots{}, minedTxs, tx))
+	}
+	// the journal is read by another db tx, so the test doesn't keep one open
+	tx, err := db.BeginRo(ctx)
+	require.NoError(err)
+	newBlock(0, 2, 0, types.TxSlots{}, tx)
+	tx.Rollback()
+
+	// legacy txs which parse again from their rlp, signatures aside
+	parseCtx := types.NewTxParseContext(*u256.N1)
+	parseCtx.WithSender(false)
+	newTx := func(nonce byte) *types.TxSlot {
+		txRlp := []byte{0xe3, nonce, 0x83, 0x04, 0x93, 0xe0, 0x83, 0x01, 0x86, 0xa0, 0x94}
+		txRlp = append(txRlp, make([]byte, 20)...)
+		txRlp = append(txRlp, 0x80, 0x80, 0x1b, 0x01, 0x01)
+		txSlot := &types.TxSlot{}
+		_, err := parseCtx.ParseTransaction(txRlp, 0, txSlot, nil, false /* hasEnvelope */, true /* wrappedWithBlobs */, nil)
+		require.NoError(err)
+		return txSlot
+	}
+	var txSlots types.TxSlots
+	for _, nonce := range []byte{2, 3, 5} {
+		txSlots.Append(newTx(nonce), addr[:], true)
+	}
+	reasons, err := pool.AddLocalTxs(ctx, txSlots, nil)
+	assert.NoError(err)
+	for _, reason := range reasons {
+		assert.Equal(txpoolcfg.Success, reason, reason.String())
+	}
+	localTxs := pool.LocalTxs()
+	require.Equal(3, len(localTxs))
+	assert.Equal(PendingSubPool, localTxs[0].SubPool)
+	assert.Equal(PendingSubPool, localTxs[1].SubPool)
+	assert.Equal(QueuedSubPool, localTxs[2].SubPool) // nonce gap
+	assert.Equal(uint64(5), localTxs[2].Nonce)
+
+	require.NoError(db.Update(ctx, pool.flushLocked))
+	journal := newLocalJournal()
+	require.NoError(db.View(ctx, journal.load))
+	assert.Equal(3, len(journal.txs))
+
+	// dropped, then added again from the rlp in the db
+	hash1 := string(txSlots.Txs[1].IDHash[:])
+	pool.lock.Lock()
+	pool.removeLocked(pool.byHash[hash1], txpoolcfg.PendingPoolOverflow)
+	pool.lock.Unlock()
+	localTxs = pool.LocalTxs()
+	assert.Equal(SubPoolType(0), localTxs[1].SubPool)
+	assert.Equal(txpoolcfg.PendingPoolOverflow, localTxs[1].DropReason)
+	rebroadcasts, err := pool.processLocalJournal(ctx, db)
+	require.NoError(err)
+	assert.Equal(0, rebroadcasts.Len())
+	localTxs = pool.LocalTxs()
+	assert.Equal(PendingSubPool, localTxs[1].SubPool)
+	assert.Equal(txpoolcfg.NotSet, localTxs[1].DropReason)
+
+	// rebroadcast after the interval, then after twice the interval
+	hash0 := string(txSlots.Txs[0].IDHash[:])
+	pool.journal.txs[hash0].lastBroadcast = time.Now().Add(-cfg.RebroadcastEvery)
+	rebroadcasts, err = pool.processLocalJournal(ctx, db)
+	require.NoError(err)
+	require.Equal(1, rebroadcasts.Len())
+	_, _, hash := rebroadcasts.At(0)
+	assert.Equal(txSlots.Txs[0].IDHash[:], hash)
+	pool.journal.txs[hash0].lastBroadcast = time.Now().Add(-cfg.RebroadcastEvery)
+	rebroadcasts, err = pool.processLocalJournal(ctx, db)
+	require.NoError(err)
+	assert.Equal(0, rebroadcasts.Len())
+	assert.Equal(1, pool.LocalTxs()[0].Broadcasts)
+
+	// mined, then forgotten once finalized
+	var minedTxs types.TxSlots
+	minedTxs.Append(txSlots.Txs[0], addr[:], false)
+	newBlock(1, 3, 0, minedTxs, nil)
+	localTxs = pool.LocalTxs()
+	require.Equal(3, len(localTxs))
+	assert.Equal(uint64(1), localTxs[0].MinedBlock)
+	newBlock(2, 3, 1, types.TxSlots{}, nil)
+	assert.Equal(2, len(pool.LocalTxs()))
+
+	// another tx with the same nonce is mined: this one is forgotten, the one after the gap is kept
+	pool.lock.Lock()
+	pool.removeLocked(pool.byHash[hash1], txpoolcfg.NonceTooLow)
+	pool.lock.Unlock()
+	localTxs = pool.LocalTxs()
+	require.Equal(1, len(localTxs))
+	assert.Equal(uint64(5), localTxs[0].Nonce)
+	assert.Equal(QueuedSubPool, localTxs[0].SubPool)
+}
+
+func TestBundles(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ch := make(chan types.Announcements, 100)
+	db, coreDB := memdb.NewTestPoolDB(t), memdb.NewTestDB(t)
+
+	policyFile := filepath.Join(t.TempDir(), "policy.json")
+	writePolicy := func(rules string, modTime time.Time) {
+		require.NoError(os.WriteFile(policyFile, []byte(rules), 0o600))
+		require.NoError(os.Chtimes(policyFile, modTime, modTime))
+	}
+	writePolicy(`{"rules": []}`, time.Unix(1, 0))
+
+	cfg := txpoolcfg.DefaultConfig
+	cfg.PolicyFile = policyFile
+	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, log.New())
+	assert.NoError(err)
+	require.True(pool != nil)
+	ctx := context.Background()
+	pendingBaseFee := uint64(200000)
+	// mainnet transactions: the sender of the first one can pay for it, the one of the second one is unknown
+	funded, unfunded := types.TxParseMainnetTests[1], types.TxParseMainnetTests[2]
+	sender := hexutility.MustDecodeHex(funded.SenderStr)
+	change := &remote.StateChangeBatch{
+		PendingBlockBaseFee: pendingBaseFee,
+		BlockGasLimit:       1000000,
+		ChangeBatch: []*remote.StateChange{
+			{BlockHeight: 0, BlockHash: gointerfaces.ConvertHashToH256([32]byte{})},
+		},
+	}
+	v := make([]byte, types.EncodeSenderLengthForStorage(0, *uint256.NewInt(1 * common.Ether)))
+	types.EncodeSender(0, *uint256.NewInt(1 * common.Ether), v)
+	change.ChangeBatch[0].Changes = append(change.ChangeBatch[0].Changes, &remote.AccountChange{
+		Action:  remote.Action_UPSERT,
+		Address: gointerfaces.ConvertAddressToH160([20]byte(sender)),
+		Data:    v,
+	})
+	tx, err := db.BeginRw(ctx)
+	require.NoError(err)
+	defer tx.Rollback()
+	err = pool.OnNewBlock(ctx, change, types.TxSlots{}, types.TxSlots{}, tx)
+	assert.NoError(err)
+
+	txRlp := hexutility.MustDecodeHex(funded.PayloadStr)
+	txHashes := []common.Hash{common.HexToHash(funded.IdHashStr)}
+	next := &Bundle{Txs: [][]byte{txRlp}, MinTimestamp: 10, MaxTimestamp: 20}
+	require.NoError(pool.AddBundle(ctx, next))
+	assert.Equal(uint64(1), next.BlockNumber)
+	assert.Equal(txHashes, next.TxHashes)
+	assert.Equal(BundleHash(txHashes), next.Hash)
+	require.NoError(pool.AddBundle(ctx, next))
+	later := &Bundle{Txs: [][]byte{txRlp}, BlockNumber: 2}
+	require.NoError(pool.AddBundle(ctx, later))
+	assert.Error(pool.AddBundle(ctx, &Bundle{Txs: [][]byte{txRlp}, MinTimestamp: 2, MaxTimestamp: 1}))
+	assert.Error(pool.AddBundle(ctx, &Bundle{Txs: [][]byte{{1}}}))
+	// the transactions are checked as the local ones
+	err = pool.AddBundle(ctx, &Bundle{Txs: [][]byte{txRlp, hexutility.MustDecodeHex(unfunded.PayloadStr)}})
+	assert.ErrorContains(err, txpoolcfg.InsufficientFunds.String())
+
+	assert.Equal([]*Bundle{next}, pool.Bundles(1, 15))
+	assert.Empty(pool.Bundles(1, 21))
+	assert.Empty(pool.Bundles(1, 9))
+	assert.Equal([]*Bundle{later}, pool.Bundles(2, 100))
+
+	change = &remote.StateChangeBatch{
+		StateVersionId:      1,
+		PendingBlockBaseFee: pendingBaseFee,
+		BlockGasLimit:       1000000,
+		ChangeBatch: []*remote.StateChange{
+			{BlockHeight: 1, BlockHash: gointerfaces.ConvertHashToH256([32]byte{1})},
+		},
+	}
+	err = pool.OnNewBlock(ctx, change, types.TxSlots{}, types.TxSlots{}, tx)
+	assert.NoError(err)
+	// bundles for mined blocks are dropped, and not accepted anymore
+	assert.Empty(pool.Bundles(1, 15))
+	assert.Equal([]*Bundle{later}, pool.Bundles(2, 100))
+	assert.ErrorIs(pool.AddBundle(ctx, &Bundle{Txs: [][]byte{txRlp}, BlockNumber: 1}), ErrBundleTooLate)
+
+	// the sender gets sanctioned: its bundles are dropped on reload, and not accepted anymore
+	require.NoError(pool.CheckBundlePolicy(later))
+	writePolicy(fmt.Sprintf(`{"rules": [{"name": "sanctions", "senders": ["0x%x"]}]}`, sender), time.Unix(2, 0))
+	pool.reloadPolicy()
+	assert.ErrorContains(pool.CheckBundlePolicy(later), "sanctions")
+	assert.Empty(pool.Bundles(2, 100))
+	assert.ErrorContains(pool.AddBundle(ctx, &Bundle{Txs: [][]byte{txRlp}}), txpoolcfg.PolicyRejected.String())
+}
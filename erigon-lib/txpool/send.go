@@ -114,8 +114,53 @@ func (f *Send) BroadcastPooledTxs(rlps [][]byte, maxPeers uint64) (txSentTo []in
 	return
 }
 
+// withoutPrivate drops the private transactions from announcements, along with the positions of the remaining ones in
+// the original announcements. Positions are nil if nothing was dropped.
+func (f *Send) withoutPrivate(types []byte, sizes []uint32, hashes []byte) ([]byte, []uint32, []byte, []int) {
+	if f.pool == nil {
+		return types, sizes, hashes, nil
+	}
+	hasPrivate := false
+	for i := 0; i < len(types) && !hasPrivate; i++ {
+		hasPrivate = f.pool.IsPrivate(hashes[32*i : 32*i+32])
+	}
+	if !hasPrivate {
+		return types, sizes, hashes, nil
+	}
+	keptTypes := make([]byte, 0, len(types))
+	keptSizes := make([]uint32, 0, len(sizes))
+	keptHashes := make([]byte, 0, len(hashes))
+	positions := make([]int, 0, len(types))
+	for i := 0; i < len(types); i++ {
+		if f.pool.IsPrivate(hashes[32*i : 32*i+32]) {
+			continue
+		}
+		keptTypes = append(keptTypes, types[i])
+		keptSizes = append(keptSizes, sizes[i])
+		keptHashes = append(keptHashes, hashes[32*i:32*i+32]...)
+		positions = append(positions, i)
+	}
+	return keptTypes, keptSizes, keptHashes, positions
+}
+
+// AnnouncePooledTxs announces the given transactions to random peers, except the private ones
 func (f *Send) AnnouncePooledTxs(types []byte, sizes []uint32, hashes types2.Hashes, maxPeers uint64) (hashSentTo []int) {
 	defer f.notifyTests()
+	hashSentTo = make([]int, len(types))
+	if len(types) == 0 {
+		return
+	}
+	keptTypes, keptSizes, keptHashes, positions := f.withoutPrivate(types, sizes, hashes)
+	if positions == nil {
+		return f.announcePooledTxs(types, sizes, hashes, maxPeers)
+	}
+	for i, sentTo := range f.announcePooledTxs(keptTypes, keptSizes, keptHashes, maxPeers) {
+		hashSentTo[positions[i]] = sentTo
+	}
+	return
+}
+
+func (f *Send) announcePooledTxs(types []byte, sizes []uint32, hashes types2.Hashes, maxPeers uint64) (hashSentTo []int) {
 	hashSentTo = make([]int, len(types))
 	if len(types) == 0 {
 		return
@@ -195,9 +240,11 @@ func (f *Send) AnnouncePooledTxs(types []byte, sizes []uint32, hashes types2.Has
 	return
 }
 
+// PropagatePooledTxsToPeersList announces the given transactions to the given peers, except the private ones
 func (f *Send) PropagatePooledTxsToPeersList(peers []types2.PeerID, types []byte, sizes []uint32, hashes []byte) {
 	defer f.notifyTests()
 
+	types, sizes, hashes, _ = f.withoutPrivate(types, sizes, hashes)
 	if len(types) == 0 {
 		return
 	}
@@ -54,11 +54,14 @@ type txPool interface {
 
 	PeekBest(n uint16, txs *types.TxsRlp, tx kv.Tx, onTopOf, availableGas, availableBlobGas uint64) (bool, error)
 	GetRlp(tx kv.Tx, hash []byte) ([]byte, error)
-	AddLocalTxs(ctx context.Context, newTxs types.TxSlots, tx kv.Tx) ([]txpoolcfg.DiscardReason, error)
+	AddLocalTxsWithOptions(ctx context.Context, newTxs types.TxSlots, options []LocalTxOptions, tx kv.Tx) ([]txpoolcfg.DiscardReason, error)
 	deprecatedForEach(_ context.Context, f func(rlp []byte, sender common.Address, t SubPoolType), tx kv.Tx)
 	CountContent() (int, int, int)
 	IdHashKnown(tx kv.Tx, hash []byte) (bool, error)
 	NonceFromAddress(addr [20]byte) (nonce uint64, inPool bool)
+	TxStatus(idHash []byte) (subPool SubPoolType, position int, reason txpoolcfg.DiscardReason)
+	LocalTxs() []LocalTxStatus
+	AddBundle(ctx context.Context, bundle *Bundle) error
 }
 
 var _ txpool_proto.TxpoolServer = (*GrpcServer)(nil)   // compile-time interface check
@@ -97,6 +100,15 @@ func (*GrpcDisabled) Status(ctx context.Context, request *txpool_proto.StatusReq
 func (*GrpcDisabled) Nonce(ctx context.Context, request *txpool_proto.NonceRequest) (*txpool_proto.NonceReply, error) {
 	return nil, ErrPoolDisabled
 }
+func (*GrpcDisabled) TransactionStatus(ctx context.Context, request *txpool_proto.TransactionStatusRequest) (*txpool_proto.TransactionStatusReply, error) {
+	return nil, ErrPoolDisabled
+}
+func (*GrpcDisabled) LocalTransactions(ctx context.Context, request *txpool_proto.LocalTransactionsRequest) (*txpool_proto.LocalTransactionsReply, error) {
+	return nil, ErrPoolDisabled
+}
+func (*GrpcDisabled) AddBundle(ctx context.Context, request *txpool_proto.AddBundleRequest) (*txpool_proto.AddBundleReply, error) {
+	return nil, ErrPoolDisabled
+}
 
 type GrpcServer struct {
 	txpool_proto.UnimplementedTxpoolServer
@@ -187,6 +199,7 @@ func (s *GrpcServer) Add(ctx context.Context, in *txpool_proto.AddRequest) (*txp
 
 	reply := &txpool_proto.AddReply{Imported: make([]txpool_proto.ImportResult, len(in.RlpTxs)), Errors: make([]string, len(in.RlpTxs))}
 
+	var options []LocalTxOptions
 	j := 0
 	for i := 0; i < len(in.RlpTxs); i++ { // some incoming txs may be rejected, so - need second index
 		slots.Resize(uint(j + 1))
@@ -210,10 +223,17 @@ func (s *GrpcServer) Add(ctx context.Context, in *txpool_proto.AddRequest) (*txp
 			}
 			continue
 		}
+		if i < len(in.Options) {
+			options = append(options, LocalTxOptions{
+				Private:             in.Options[i].GetPrivate(),
+				PrivateExpiryBlocks: in.Options[i].GetPrivateExpiryBlocks(),
+				Conditions:          ConditionsFromProto(in.Options[i].GetConditions()),
+			})
+		}
 		j++
 	}
 
-	discardReasons, err := s.txPool.AddLocalTxs(ctx, slots, tx)
+	discardReasons, err := s.txPool.AddLocalTxsWithOptions(ctx, slots, options, tx)
 	if err != nil {
 		return nil, err
 	}
@@ -238,9 +258,9 @@ func mapDiscardReasonToProto(reason txpoolcfg.DiscardReason) txpool_proto.Import
 		return txpool_proto.ImportResult_SUCCESS
 	case txpoolcfg.AlreadyKnown:
 		return txpool_proto.ImportResult_ALREADY_EXISTS
-	case txpoolcfg.UnderPriced, txpoolcfg.ReplaceUnderpriced, txpoolcfg.FeeTooLow:
+	case txpoolcfg.UnderPriced, txpoolcfg.ReplaceUnderpriced, txpoolcfg.FeeTooLow, txpoolcfg.BlobPoolOverflow:
 		return txpool_proto.ImportResult_FEE_TOO_LOW
-	case txpoolcfg.InvalidSender, txpoolcfg.NegativeValue, txpoolcfg.OversizedData, txpoolcfg.InitCodeTooLarge, txpoolcfg.RLPTooLong, txpoolcfg.CreateBlobTxn, txpoolcfg.NoBlobs, txpoolcfg.TooManyBlobs, txpoolcfg.TypeNotActivated, txpoolcfg.UnequalBlobTxExt, txpoolcfg.BlobHashCheckFail, txpoolcfg.UnmatchedBlobTxExt:
+	case txpoolcfg.InvalidSender, txpoolcfg.NegativeValue, txpoolcfg.OversizedData, txpoolcfg.InitCodeTooLarge, txpoolcfg.RLPTooLong, txpoolcfg.CreateBlobTxn, txpoolcfg.NoBlobs, txpoolcfg.TooManyBlobs, txpoolcfg.TypeNotActivated, txpoolcfg.UnequalBlobTxExt, txpoolcfg.BlobHashCheckFail, txpoolcfg.UnmatchedBlobTxExt, txpoolcfg.ConditionsNotMet, txpoolcfg.PolicyRejected:
 		// TODO(eip-4844) TypeNotActivated may be transient (e.g. a blob transaction is submitted 1 sec prior to Cancun activation)
 		return txpool_proto.ImportResult_INVALID
 	default:
@@ -304,6 +324,70 @@ func (s *GrpcServer) Nonce(ctx context.Context, in *txpool_proto.NonceRequest) (
 	}, nil
 }
 
+func (s *GrpcServer) TransactionStatus(ctx context.Context, in *txpool_proto.TransactionStatusRequest) (*txpool_proto.TransactionStatusReply, error) {
+	hash := gointerfaces.ConvertH256ToHash(in.Hash)
+	subPool, position, reason := s.txPool.TxStatus(hash[:])
+	reply := &txpool_proto.TransactionStatusReply{Position: uint32(position)}
+	switch subPool {
+	case PendingSubPool:
+		reply.Status = txpool_proto.TransactionStatusReply_PENDING
+	case BaseFeeSubPool:
+		reply.Status = txpool_proto.TransactionStatusReply_BASE_FEE
+	case QueuedSubPool:
+		reply.Status = txpool_proto.TransactionStatusReply_QUEUED
+	default:
+		if reason != txpoolcfg.NotSet {
+			reply.Status = txpool_proto.TransactionStatusReply_DISCARDED
+			reply.DiscardReason = reason.String()
+		}
+	}
+	return reply, nil
+}
+
+// returns the journaled local transactions, until they are mined in a finalized block
+func (s *GrpcServer) LocalTransactions(ctx context.Context, _ *txpool_proto.LocalTransactionsRequest) (*txpool_proto.LocalTransactionsReply, error) {
+	localTxs := s.txPool.LocalTxs()
+	reply := &txpool_proto.LocalTransactionsReply{Txs: make([]*txpool_proto.LocalTransactionsReply_Tx, len(localTxs))}
+	for i, localTx := range localTxs {
+		tx := &txpool_proto.LocalTransactionsReply_Tx{
+			Hash:       gointerfaces.ConvertHashToH256(localTx.IDHash),
+			Sender:     gointerfaces.ConvertAddressToH160(localTx.Sender),
+			Nonce:      localTx.Nonce,
+			MinedBlock: localTx.MinedBlock,
+			Broadcasts: uint32(localTx.Broadcasts),
+		}
+		if localTx.Broadcasts > 0 {
+			tx.LastBroadcast = uint64(localTx.LastBroadcast.Unix())
+		}
+		switch {
+		case localTx.SubPool == PendingSubPool:
+			tx.Status = txpool_proto.LocalTransactionsReply_PENDING
+		case localTx.SubPool == BaseFeeSubPool:
+			tx.Status = txpool_proto.LocalTransactionsReply_BASE_FEE
+		case localTx.SubPool == QueuedSubPool:
+			tx.Status = txpool_proto.LocalTransactionsReply_QUEUED
+		case localTx.MinedBlock != 0:
+			tx.Status = txpool_proto.LocalTransactionsReply_MINED
+		default:
+			tx.Status = txpool_proto.LocalTransactionsReply_DROPPED
+			if localTx.DropReason != txpoolcfg.NotSet {
+				tx.DiscardReason = localTx.DropReason.String()
+			}
+		}
+		reply.Txs[i] = tx
+	}
+	return reply, nil
+}
+
+// adds a bundle of transactions, to be included atomically at the top of its target block
+func (s *GrpcServer) AddBundle(ctx context.Context, in *txpool_proto.AddBundleRequest) (*txpool_proto.AddBundleReply, error) {
+	bundle := BundleFromProto(in)
+	if err := s.txPool.AddBundle(ctx, bundle); err != nil {
+		return nil, err
+	}
+	return &txpool_proto.AddBundleReply{BundleHash: gointerfaces.ConvertHashToH256(bundle.Hash)}, nil
+}
+
 // NewSlotsStreams - it's safe to use this class as non-pointer
 type NewSlotsStreams struct {
 	chans map[uint]txpool_proto.Txpool_OnAddServer
@@ -35,10 +35,11 @@ type Config struct {
 	BaseFeeSubPoolLimit int
 	QueuedSubPoolLimit  int
 	MinFeeCap           uint64
-	AccountSlots        uint64 // Number of executable transaction slots guaranteed per account
-	BlobSlots           uint64 // Total number of blobs (not txs) allowed per account
-	PriceBump           uint64 // Price bump percentage to replace an already existing transaction
-	BlobPriceBump       uint64 //Price bump percentage to replace an existing 4844 blob tx (type-3)
+	AccountSlots        uint64            // Number of executable transaction slots guaranteed per account
+	BlobSlots           uint64            // Total number of blobs (not txs) allowed per account
+	BlobPoolLimit       datasize.ByteSize // Total size of the blob transactions of the pool, sidecars included
+	PriceBump           uint64            // Price bump percentage to replace an already existing transaction
+	BlobPriceBump       uint64            //Price bump percentage to replace an existing 4844 blob tx (type-3)
 	OverrideCancunTime  *big.Int
 
 	// regular batch tasks processing
@@ -53,6 +54,12 @@ type Config struct {
 	MdbxGrowthStep  datasize.ByteSize
 
 	NoGossip bool // this mode doesn't broadcast any txs, and if receive remote-txn - skip it
+
+	PolicyFile        string        // JSON file with the admission rules of the pool, see txpool.Policy
+	PolicyReloadEvery time.Duration // How often the policy file is checked for changes
+
+	LocalJournalLifetime time.Duration // How long local txs are journaled to be added again if dropped, 0 disables the journal
+	RebroadcastEvery     time.Duration // Delay before the first rebroadcast of pending local txs, doubled after each one
 }
 
 var DefaultConfig = Config{
@@ -66,12 +73,18 @@ var DefaultConfig = Config{
 	QueuedSubPoolLimit:  10_000,
 
 	MinFeeCap:     1,
-	AccountSlots:  16, //TODO: to choose right value (16 to be compatible with Geth)
-	BlobSlots:     48, // Default for a total of 8 txs for 6 blobs each - for hive tests
-	PriceBump:     10, // Price bump percentage to replace an already existing transaction
+	AccountSlots:  16,                 //TODO: to choose right value (16 to be compatible with Geth)
+	BlobSlots:     48,                 // Default for a total of 8 txs for 6 blobs each - for hive tests
+	BlobPoolLimit: 2560 * datasize.MB, // sidecars are on disk, so this is mostly a disk limit
+	PriceBump:     10,                 // Price bump percentage to replace an already existing transaction
 	BlobPriceBump: 100,
 
 	NoGossip: false,
+
+	PolicyReloadEvery: 10 * time.Second,
+
+	LocalJournalLifetime: 24 * time.Hour,
+	RebroadcastEvery:     time.Minute,
 }
 
 type DiscardReason uint8
@@ -108,6 +121,10 @@ const (
 	BlobHashCheckFail   DiscardReason = 28 // KZGcommitment's versioned hash has to be equal to blob_versioned_hash at the same index
 	UnmatchedBlobTxExt  DiscardReason = 29 // KZGcommitments must match the corresponding blobs and proofs
 	BlobTxReplace       DiscardReason = 30 // Cannot replace type-3 blob txn with another type of txn
+	PrivateTxExpired    DiscardReason = 31 // Private transaction was not included before its expiry block
+	ConditionsNotMet    DiscardReason = 32 // Preconditions of a conditional transaction do not hold anymore
+	PolicyRejected      DiscardReason = 33 // Sender, recipient or method selector is denied by the pool policy
+	BlobPoolOverflow    DiscardReason = 34 // The blob transactions of the pool exceed BlobPoolLimit, and this one pays the least blob fee
 )
 
 func (r DiscardReason) String() string {
@@ -166,8 +183,22 @@ func (r DiscardReason) String() string {
 		return "blob transactions must have at least one blob"
 	case TooManyBlobs:
 		return "max number of blobs exceeded"
+	case UnequalBlobTxExt:
+		return "blob_versioned_hashes, blobs, commitments and proofs must have equal number"
+	case BlobHashCheckFail:
+		return "KZGcommitment's versioned hash has to be equal to blob_versioned_hash at the same index"
+	case UnmatchedBlobTxExt:
+		return "KZGcommitments must match the corresponding blobs and proofs"
 	case BlobTxReplace:
 		return "can't replace blob-txn with a non-blob-txn"
+	case PrivateTxExpired:
+		return "private transaction expired"
+	case ConditionsNotMet:
+		return "transaction conditions not met"
+	case PolicyRejected:
+		return "rejected by txpool policy"
+	case BlobPoolOverflow:
+		return "blob sub-pool is full"
 	default:
 		panic(fmt.Sprintf("discard reason: %d", r))
 	}
@@ -93,14 +93,16 @@ type TxSlot struct {
 	Nonce          uint64      // Nonce of the transaction
 	DataLen        int         // Length of transaction's data (for calculation of intrinsic gas)
 	DataNonZeroLen int
-	AlAddrCount    int      // Number of addresses in the access list
-	AlStorCount    int      // Number of storage keys in the access list
-	Gas            uint64   // Gas limit of the transaction
-	IDHash         [32]byte // Transaction hash for the purposes of using it as a transaction Id
-	Traced         bool     // Whether transaction needs to be traced throughout transaction pool code and generate debug printing
-	Creation       bool     // Set to true if "To" field of the transaction is not set
-	Type           byte     // Transaction type
-	Size           uint32   // Size of the payload (without the RLP string envelope for typed transactions)
+	AlAddrCount    int            // Number of addresses in the access list
+	AlStorCount    int            // Number of storage keys in the access list
+	Gas            uint64         // Gas limit of the transaction
+	IDHash         [32]byte       // Transaction hash for the purposes of using it as a transaction Id
+	Traced         bool           // Whether transaction needs to be traced throughout transaction pool code and generate debug printing
+	Creation       bool           // Set to true if "To" field of the transaction is not set
+	To             common.Address // Destination of the transaction, zero for contract creations
+	Selector       [4]byte        // First 4 bytes of the transaction's data: method selector of contract calls
+	Type           byte           // Transaction type
+	Size           uint32         // Size of the payload (without the RLP string envelope for typed transactions)
 
 	// EIP-4844: Shard Blob Transactions
 	BlobFeeCap  uint256.Int // max_fee_per_blob_gas
@@ -374,8 +376,9 @@ func (ctx *TxParseContext) parseTransactionBody(payload []byte, pos, p0 int, slo
 		return 0, fmt.Errorf("%w: unexpected length of to field: %d", ErrParseTxn, dataLen)
 	}
 
-	// Only note if To field is empty or not
 	slot.Creation = dataLen == 0
+	slot.To = common.Address{}
+	copy(slot.To[:], payload[dataPos:dataPos+dataLen])
 	p = dataPos + dataLen
 	// Next follows value
 	p, err = rlp.U256(payload, p, &slot.Value)
@@ -388,6 +391,10 @@ func (ctx *TxParseContext) parseTransactionBody(payload []byte, pos, p0 int, slo
 		return 0, fmt.Errorf("%w: data len: %s", ErrParseTxn, err) //nolint
 	}
 	slot.DataLen = dataLen
+	slot.Selector = [4]byte{}
+	if dataLen >= 4 {
+		copy(slot.Selector[:], payload[dataPos:dataPos+4])
+	}
 
 	// Zero and non-zero bytes are priced differently
 	slot.DataNonZeroLen = 0
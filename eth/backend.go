@@ -39,6 +39,7 @@ import (
 	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
 	"github.com/ledgerwatch/erigon/cl/fork"
+	"github.com/ledgerwatch/erigon/cl/light_client"
 	"github.com/ledgerwatch/erigon/cl/persistence"
 	"github.com/ledgerwatch/erigon/cl/persistence/format/snapshot_format/getters"
 	clcore "github.com/ledgerwatch/erigon/cl/phase1/core"
@@ -666,6 +667,27 @@ func New(ctx context.Context, stack *node.Node, config *ethconfig.Config, logger
 		return block, nil
 	}
 
+	// builder API: the blocks built for the payload attributes of the beacon node are submitted to relays
+	if len(config.Builder.Relays) > 0 {
+		beaconCfg, ok := clparams.BeaconConfigs[clparams.NetworkType(config.NetworkID)]
+		if !ok {
+			beaconCfg = clparams.MainnetBeaconConfig
+		}
+		signer, err := builder.NewSigner(config.Builder.SecretKey, &beaconCfg)
+		if err != nil {
+			return nil, err
+		}
+		relays := make([]*builder.RelayClient, len(config.Builder.Relays))
+		for i, url := range config.Builder.Relays {
+			relays[i] = builder.NewRelayClient(url, nil)
+		}
+		builderService := builder.NewService(assembleBlockPOS, relays, signer, config.Builder.BuildTime, logger)
+		payloadAttributes := make(chan *builder.PayloadAttributesEvent, 4)
+		go builder.SubscribePayloadAttributes(backend.sentryCtx, config.Builder.BeaconURL, payloadAttributes, logger)
+		go builderService.Run(backend.sentryCtx, payloadAttributes)
+		logger.Info("Builder API enabled", "relays", config.Builder.Relays, "pubkey", signer.PublicKey())
+	}
+
 	// Initialize ethbackend
 	ethBackendRPC := privateapi.NewEthBackendServer(ctx, backend, backend.chainDB, backend.notifications.Events, blockReader, logger, latestBlockBuiltStore)
 	// intiialize engine backend
@@ -831,15 +853,20 @@ func New(ctx context.Context, stack *node.Node, config *ethconfig.Config, logger
 		}
 
 		rawBeaconBlockChainDb, _ := persistence.AferoRawBeaconBlockChainFromOsPath(beaconCfg, dirs.CaplinHistory)
+		lightClientStore, err := light_client.NewStore(beaconCfg)
+		if err != nil {
+			return nil, err
+		}
 
 		client, err := service.StartSentinelService(&sentinel.SentinelConfig{
-			IpAddr:        config.LightClientDiscoveryAddr,
-			Port:          int(config.LightClientDiscoveryPort),
-			TCPPort:       uint(config.LightClientDiscoveryTCPPort),
-			GenesisConfig: genesisCfg,
-			NetworkConfig: networkCfg,
-			BeaconConfig:  beaconCfg,
-			TmpDir:        tmpdir,
+			IpAddr:           config.LightClientDiscoveryAddr,
+			Port:             int(config.LightClientDiscoveryPort),
+			TCPPort:          uint(config.LightClientDiscoveryTCPPort),
+			GenesisConfig:    genesisCfg,
+			NetworkConfig:    networkCfg,
+			BeaconConfig:     beaconCfg,
+			TmpDir:           tmpdir,
+			LightClientStore: lightClientStore,
 		}, rawBeaconBlockChainDb, &service.ServerConfig{Network: "tcp", Addr: fmt.Sprintf("%s:%d", config.SentinelAddr, config.SentinelPort)}, creds, &cltypes.Status{
 			ForkDigest:     forkDigest,
 			FinalizedRoot:  state.FinalizedCheckpoint().BlockRoot(),
@@ -855,7 +882,7 @@ func New(ctx context.Context, stack *node.Node, config *ethconfig.Config, logger
 
 		go func() {
 			eth1Getter := getters.NewExecutionSnapshotReader(ctx, blockReader, backend.chainDB)
-			if err := caplin1.RunCaplinPhase1(ctx, client, engine, beaconCfg, genesisCfg, state, nil, dirs, config.BeaconRouter, eth1Getter, backend.downloaderClient, config.CaplinConfig.Backfilling, config.CaplinConfig.Archive); err != nil {
+			if err := caplin1.RunCaplinPhase1(ctx, client, engine, beaconCfg, genesisCfg, state, nil, dirs, config.BeaconRouter, eth1Getter, backend.downloaderClient, config.CaplinConfig, lightClientStore); err != nil {
 				logger.Error("could not start caplin", "err", err)
 			}
 			ctxCancel()
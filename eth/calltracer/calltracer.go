@@ -51,6 +51,7 @@ func (ct *CallTracer) CaptureStart(env *vm.EVM, from libcommon.Address, to libco
 func (ct *CallTracer) CaptureEnter(typ vm.OpCode, from libcommon.Address, to libcommon.Address, precompile bool, create bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
 	ct.captureStartOrEnter(from, to, create, code)
 }
+func (ct *CallTracer) IgnoresOpcodes() bool { return true }
 func (ct *CallTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
 }
 func (ct *CallTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
@@ -60,6 +61,22 @@ func (ct *CallTracer) CaptureEnd(output []byte, usedGas uint64, err error) {
 func (ct *CallTracer) CaptureExit(output []byte, usedGas uint64, err error) {
 }
 
+// NewChild returns a tracer for a speculative execution of a transaction, see core.ParallelLogger.
+func (ct *CallTracer) NewChild() vm.EVMLogger {
+	return NewCallTracer()
+}
+
+// MergeChild adds the addresses collected by a child tracer.
+func (ct *CallTracer) MergeChild(child vm.EVMLogger) {
+	c := child.(*CallTracer)
+	for addr := range c.froms {
+		ct.froms[addr] = struct{}{}
+	}
+	for addr, created := range c.tos {
+		ct.tos[addr] = ct.tos[addr] || created
+	}
+}
+
 func (ct *CallTracer) WriteToDb(tx kv.StatelessWriteTx, block *types.Block, vmConfig vm.Config) error {
 	ct.tos[block.Coinbase()] = false
 	for _, uncle := range block.Uncles() {
@@ -0,0 +1,15 @@
+package ethconfig
+
+import "time"
+
+// BuilderConfig are the configuration parameters of the builder API, to submit the blocks built to relays
+type BuilderConfig struct {
+	Relays    []string      // URLs of the relays to submit blocks to, the builder API is disabled if empty
+	BeaconURL string        // URL of the beacon API, for the payload attributes of the next slots
+	SecretKey []byte        `toml:"-"` // BLS secret key signing the bids
+	BuildTime time.Duration // time spent building each block before submitting it
+}
+
+var DefaultBuilderConfig = BuilderConfig{
+	BuildTime: 2 * time.Second,
+}
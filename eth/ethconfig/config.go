@@ -93,6 +93,7 @@ var Defaults = Config{
 		Recommit: 3 * time.Second,
 	},
 	DeprecatedTxPool: DeprecatedDefaultTxPoolConfig,
+	Builder:          DefaultBuilderConfig,
 	RPCGasCap:        50000000,
 	GPO:              FullNodeGPO,
 	RPCTxFeeCap:      1, // 1 ether
@@ -213,6 +214,9 @@ type Config struct {
 	DeprecatedTxPool DeprecatedTxPoolConfig
 	TxPool           txpoolcfg.Config
 
+	// Builder API options
+	Builder BuilderConfig
+
 	// Gas Price Oracle options
 	GPO gaspricecfg.Config
 
@@ -266,6 +270,8 @@ type Sync struct {
 	LoopThrottle     time.Duration
 	ExecWorkerCount  int
 	ReconWorkerCount int
+	// ParallelExecution executes the transactions of a block optimistically in parallel, with ExecWorkerCount workers
+	ParallelExecution bool
 
 	BodyCacheLimit             datasize.ByteSize
 	BodyDownloadTimeoutSeconds int // TODO: change to duration
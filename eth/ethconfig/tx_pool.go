@@ -71,6 +71,10 @@ var DefaultTxPool2Config = func(fullCfg *Config) txpoolcfg.Config {
 	cfg.MinFeeCap = pool1Cfg.PriceLimit
 	cfg.AccountSlots = pool1Cfg.AccountSlots
 	cfg.BlobSlots = fullCfg.TxPool.BlobSlots
+	cfg.BlobPoolLimit = fullCfg.TxPool.BlobPoolLimit
+	cfg.PolicyFile = fullCfg.TxPool.PolicyFile
+	cfg.LocalJournalLifetime = fullCfg.TxPool.LocalJournalLifetime
+	cfg.RebroadcastEvery = fullCfg.TxPool.RebroadcastEvery
 	cfg.LogEvery = 3 * time.Minute
 	cfg.CommitEvery = 5 * time.Minute
 	cfg.TracedSenders = pool1Cfg.TracedSenders
@@ -173,13 +173,24 @@ func executeBlock(
 	var execRs *core.EphemeralExecResult
 	getHashFn := core.GetHashFn(block.Header(), getHeader)
 
-	execRs, err = core.ExecuteBlockEphemerally(cfg.chainConfig, &vmConfig, getHashFn, cfg.engine, block, stateReader, stateWriter, NewChainReaderImpl(cfg.chainConfig, tx, cfg.blockReader, logger), getTracer, logger)
+	// a tracer configured for the stage is only honoured by the serial execution
+	if cfg.syncCfg.ParallelExecution && !cfg.vmConfig.Debug && cfg.vmConfig.Tracer == nil {
+		execRs, err = core.ExecuteBlockParallel(cfg.chainConfig, &vmConfig, getHashFn, cfg.engine, block, stateReader, stateWriter, NewChainReaderImpl(cfg.chainConfig, tx, cfg.blockReader, logger), getTracer, cfg.syncCfg.ExecWorkerCount, logger)
+	} else {
+		execRs, err = core.ExecuteBlockEphemerally(cfg.chainConfig, &vmConfig, getHashFn, cfg.engine, block, stateReader, stateWriter, NewChainReaderImpl(cfg.chainConfig, tx, cfg.blockReader, logger), getTracer, logger)
+	}
 	if err != nil {
 		return fmt.Errorf("%w: %v", consensus.ErrInvalidBlock, err)
 	}
 	receipts = execRs.Receipts
 	stateSyncReceipt = execRs.StateSyncReceipt
 
+	if execRs.Witness != nil {
+		if err = writeVerkleWitness(tx, blockNum, execRs.Witness.Keys(), logger); err != nil {
+			return err
+		}
+	}
+
 	if writeReceipts {
 		if err = rawdb.AppendReceipts(tx, blockNum, receipts); err != nil {
 			return err
@@ -817,6 +828,9 @@ func unwindExecutionStage(u *UnwindState, s *StageState, tx kv.RwTx, ctx context
 	if err := rawdb.TruncateBorReceipts(tx, u.UnwindPoint+1); err != nil {
 		return fmt.Errorf("truncate bor receipts: %w", err)
 	}
+	if err := rawdb.TruncateVerkleWitnesses(tx, u.UnwindPoint+1); err != nil {
+		return fmt.Errorf("truncate verkle witnesses: %w", err)
+	}
 	if err := rawdb.DeleteNewerEpochs(tx, u.UnwindPoint+1); err != nil {
 		return fmt.Errorf("delete newer epochs: %w", err)
 	}
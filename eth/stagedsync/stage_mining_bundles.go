@@ -0,0 +1,229 @@
+package stagedsync
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/log/v3"
+	"golang.org/x/exp/maps"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/txpool"
+
+	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+	"github.com/ledgerwatch/erigon/core/vm"
+)
+
+// simulatedBundle is a bundle of the pool, decoded and simulated on top of the block being built
+type simulatedBundle struct {
+	bundle  *txpool.Bundle
+	txs     []types.Transaction
+	gasUsed uint64
+	profit  uint256.Int // paid to the coinbase
+}
+
+// addBundlesToMiningBlock adds the bundles targeting the block being built, before any transaction of the pool. Each
+// bundle is simulated on top of the block, and dropped if any of its transactions fails, or reverts without being
+// allowed to. The others are added by decreasing profit per gas, as long as they still succeed after the bundles
+// already added. It returns the state after the bundles, the logs and the hashes of the transactions added.
+func addBundlesToMiningBlock(logPrefix string, current *MiningBlock, chainConfig chain.Config, vmConfig *vm.Config, getHeader func(hash libcommon.Hash, number uint64) *types.Header,
+	engine consensus.Engine, txPool TxPoolForMining, coinbase libcommon.Address, stateReader state.StateReader, ibs *state.IntraBlockState, conditions *conditionsChecker,
+	quit <-chan struct{}, logger log.Logger) (*state.IntraBlockState, types.Logs, []libcommon.Hash, error) {
+	if txPool == nil {
+		return ibs, nil, nil, nil
+	}
+	header := current.Header
+	bundles := txPool.Bundles(header.Number.Uint64(), header.Time)
+	if len(bundles) == 0 {
+		return ibs, nil, nil, nil
+	}
+	chainID, _ := uint256.FromBig(chainConfig.ChainID)
+	signer := types.MakeSigner(&chainConfig, header.Number.Uint64(), header.Time)
+
+	var simulated []*simulatedBundle
+	for _, bundle := range bundles {
+		// the policy may have been reloaded since the bundle was added to the pool
+		if err := txPool.CheckBundlePolicy(bundle); err != nil {
+			logger.Debug(fmt.Sprintf("[%s] Skipping bundle", logPrefix), "bundle", bundle.Hash, "err", err)
+			continue
+		}
+		sim, err := decodeBundle(bundle, chainID, signer)
+		if err != nil {
+			logger.Debug(fmt.Sprintf("[%s] Skipping bundle", logPrefix), "bundle", bundle.Hash, "err", err)
+			continue
+		}
+		if err := simulateBundle(sim, chainConfig, vmConfig, getHeader, engine, header, coinbase, stateReader, ibs); err != nil {
+			logger.Debug(fmt.Sprintf("[%s] Skipping bundle", logPrefix), "bundle", bundle.Hash, "err", err)
+			continue
+		}
+		simulated = append(simulated, sim)
+	}
+	sort.SliceStable(simulated, func(i, j int) bool {
+		// profit_i/gas_i > profit_j/gas_j
+		var a, b uint256.Int
+		a.Mul(&simulated[i].profit, uint256.NewInt(simulated[j].gasUsed))
+		b.Mul(&simulated[j].profit, uint256.NewInt(simulated[i].gasUsed))
+		return a.Gt(&b)
+	})
+
+	gasPool := new(core.GasPool).AddGas(header.GasLimit - header.GasUsed)
+	if header.BlobGasUsed != nil {
+		gasPool.AddBlobGas(chainConfig.GetMaxBlobGasPerBlock() - *header.BlobGasUsed)
+	}
+	var logs types.Logs
+	var included []libcommon.Hash
+	for _, sim := range simulated {
+		if err := libcommon.Stopped(quit); err != nil {
+			return nil, nil, nil, err
+		}
+		// the state changed with the bundles already added, so the bundle is applied on a copy of the state, which
+		// replaces it only if all of the transactions still succeed. A snapshot of the state can't be used instead,
+		// as it is not valid anymore after the first transaction.
+		bundleState, bundleGasPool, storageWritten := ibs.Copy(), *gasPool, maps.Clone(conditions.storageWritten)
+		receipts, gasUsed, blobGasUsed, err := applyBundle(sim, len(current.Txs), chainConfig, vmConfig, getHeader, engine, header, coinbase, &bundleGasPool, bundleState, conditions)
+		if err != nil {
+			conditions.storageWritten = storageWritten
+			logger.Debug(fmt.Sprintf("[%s] Skipping bundle", logPrefix), "bundle", sim.bundle.Hash, "err", err)
+			continue
+		}
+		ibs, *gasPool, header.GasUsed = bundleState, bundleGasPool, gasUsed
+		if header.BlobGasUsed != nil {
+			*header.BlobGasUsed = blobGasUsed
+		}
+		for i, txn := range sim.txs {
+			current.Txs = append(current.Txs, txn)
+			current.Receipts = append(current.Receipts, receipts[i])
+			logs = append(logs, receipts[i].Logs...)
+			included = append(included, txn.Hash())
+		}
+		logger.Debug(fmt.Sprintf("[%s] Added bundle", logPrefix), "bundle", sim.bundle.Hash, "txs", len(sim.txs), "gas", sim.gasUsed, "profit", &sim.profit)
+	}
+	return ibs, logs, included, nil
+}
+
+// decodeBundle decodes the transactions of the bundle, which must be for the chain, and neither from nor to an
+// address of types.NanoBlackList
+func decodeBundle(bundle *txpool.Bundle, chainID *uint256.Int, signer *types.Signer) (*simulatedBundle, error) {
+	sim := &simulatedBundle{bundle: bundle}
+	for _, enc := range bundle.Txs {
+		txn, err := types.DecodeWrappedTransaction(enc)
+		if err != nil {
+			return nil, err
+		}
+		if !txn.GetChainID().IsZero() && txn.GetChainID().Cmp(chainID) != 0 {
+			return nil, fmt.Errorf("transaction %x: invalid chain id %d", txn.Hash(), txn.GetChainID())
+		}
+		sender, err := txn.Sender(*signer)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %x: %w", txn.Hash(), err)
+		}
+		for _, blackListed := range types.NanoBlackList {
+			if sender == blackListed || (txn.GetTo() != nil && *txn.GetTo() == blackListed) {
+				return nil, fmt.Errorf("transaction %x: blacklisted address %x", txn.Hash(), blackListed)
+			}
+		}
+		sim.txs = append(sim.txs, txn)
+	}
+	return sim, nil
+}
+
+// applyBundle executes the transactions of the bundle in the block being built, on the given state and gas pool.
+// It fails if any of the transactions fails, or reverts without being allowed to, and then the state and the gas
+// pool must be discarded. It returns the receipts, and the gas and blob gas used by the block with the bundle.
+func applyBundle(sim *simulatedBundle, txIndex int, chainConfig chain.Config, vmConfig *vm.Config, getHeader func(hash libcommon.Hash, number uint64) *types.Header,
+	engine consensus.Engine, header *types.Header, coinbase libcommon.Address, gasPool *core.GasPool, ibs *state.IntraBlockState, conditions *conditionsChecker) (types.Receipts, uint64, uint64, error) {
+	gasUsed := header.GasUsed
+	var blobGasUsed uint64
+	if header.BlobGasUsed != nil {
+		blobGasUsed = *header.BlobGasUsed
+	}
+	receipts := make(types.Receipts, 0, len(sim.txs))
+	for i, txn := range sim.txs {
+		ibs.SetTxContext(txn.Hash(), libcommon.Hash{}, txIndex+i)
+		receipt, _, err := core.ApplyTransaction(&chainConfig, core.GetHashFn(header, getHeader), engine, &coinbase, gasPool, ibs, conditions, header, txn, &gasUsed, &blobGasUsed, *vmConfig)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("transaction %x: %w", txn.Hash(), err)
+		}
+		if receipt.Status == types.ReceiptStatusFailed && !sim.bundle.CanRevert(txn.Hash()) {
+			return nil, 0, 0, fmt.Errorf("transaction %x reverted", txn.Hash())
+		}
+		receipts = append(receipts, receipt)
+	}
+	return receipts, gasUsed, blobGasUsed, nil
+}
+
+// simulateBundle executes the transactions of the bundle on top of the block being built, without changing it
+func simulateBundle(sim *simulatedBundle, chainConfig chain.Config, vmConfig *vm.Config, getHeader func(hash libcommon.Hash, number uint64) *types.Header,
+	engine consensus.Engine, header *types.Header, coinbase libcommon.Address, stateReader state.StateReader, ibs *state.IntraBlockState) error {
+	simState := state.New(&ibsReader{ibs: ibs, reader: stateReader})
+	gasPool := new(core.GasPool).AddGas(header.GasLimit - header.GasUsed)
+	usedGas := header.GasUsed
+	var usedBlobGas uint64
+	if header.BlobGasUsed != nil {
+		gasPool.AddBlobGas(chainConfig.GetMaxBlobGasPerBlock() - *header.BlobGasUsed)
+		usedBlobGas = *header.BlobGasUsed
+	}
+	balanceBefore := simState.GetBalance(coinbase).Clone()
+	for i, txn := range sim.txs {
+		simState.SetTxContext(txn.Hash(), libcommon.Hash{}, i)
+		receipt, _, err := core.ApplyTransaction(&chainConfig, core.GetHashFn(header, getHeader), engine, &coinbase, gasPool, simState, state.NewNoopWriter(), header, txn, &usedGas, &usedBlobGas, *vmConfig)
+		if err != nil {
+			return fmt.Errorf("transaction %x: %w", txn.Hash(), err)
+		}
+		if receipt.Status == types.ReceiptStatusFailed && !sim.bundle.CanRevert(txn.Hash()) {
+			return fmt.Errorf("transaction %x reverted", txn.Hash())
+		}
+	}
+	sim.gasUsed = usedGas - header.GasUsed
+	if balanceAfter := simState.GetBalance(coinbase); balanceAfter.Gt(balanceBefore) {
+		sim.profit.Sub(balanceAfter, balanceBefore)
+	} else {
+		sim.profit.Clear()
+	}
+	return nil
+}
+
+// ibsReader reads the state of an IntraBlockState, to execute transactions on top of it without changing it
+type ibsReader struct {
+	ibs    *state.IntraBlockState
+	reader state.StateReader // state under the IntraBlockState, for the incarnations of the deleted contracts
+}
+
+func (r *ibsReader) ReadAccountData(address libcommon.Address) (*accounts.Account, error) {
+	if !r.ibs.Exist(address) {
+		return nil, nil
+	}
+	acc := accounts.NewAccount()
+	acc.Nonce = r.ibs.GetNonce(address)
+	acc.Balance.Set(r.ibs.GetBalance(address))
+	acc.CodeHash = r.ibs.GetCodeHash(address)
+	acc.Incarnation = r.ibs.GetIncarnation(address)
+	return &acc, nil
+}
+
+func (r *ibsReader) ReadAccountStorage(address libcommon.Address, incarnation uint64, key *libcommon.Hash) ([]byte, error) {
+	var value uint256.Int
+	r.ibs.GetState(address, key, &value)
+	return value.Bytes(), nil
+}
+
+func (r *ibsReader) ReadAccountCode(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash) ([]byte, error) {
+	return r.ibs.GetCode(address), nil
+}
+
+func (r *ibsReader) ReadAccountCodeSize(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash) (int, error) {
+	return r.ibs.GetCodeSize(address), nil
+}
+
+func (r *ibsReader) ReadAccountIncarnation(address libcommon.Address) (uint64, error) {
+	if incarnation := r.ibs.GetIncarnation(address); incarnation != 0 {
+		return incarnation, nil
+	}
+	return r.reader.ReadAccountIncarnation(address)
+}
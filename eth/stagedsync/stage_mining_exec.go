@@ -17,6 +17,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/chain"
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/txpool"
 	types2 "github.com/ledgerwatch/erigon-lib/types"
 
 	"github.com/ledgerwatch/erigon/consensus"
@@ -48,6 +49,9 @@ type MiningExecCfg struct {
 
 type TxPoolForMining interface {
 	YieldBest(n uint16, txs *types2.TxsRlp, tx kv.Tx, onTopOf, availableGas, availableBlobGas uint64, toSkip mapset.Set[[32]byte]) (bool, int, error)
+	TxConditions(idHash [32]byte) *txpool.TxConditions
+	Bundles(blockNum, timestamp uint64) []*txpool.Bundle
+	CheckBundlePolicy(bundle *txpool.Bundle) error
 }
 
 func StageMiningExecCfg(
@@ -91,6 +95,7 @@ func SpawnMiningExecStage(s *StageState, tx kv.RwTx, cfg MiningExecCfg, quit <-c
 
 	chainReader := ChainReader{Cfg: cfg.chainConfig, Db: tx, BlockReader: cfg.blockReader}
 	core.InitializeBlockExecution(cfg.engine, chainReader, current.Header, &cfg.chainConfig, ibs, logger)
+	conditions := newConditionsChecker(cfg.txPool2)
 
 	// Create an empty block based on temporary copied state for
 	// sealing in advance without waiting block execution finished.
@@ -101,12 +106,19 @@ func SpawnMiningExecStage(s *StageState, tx kv.RwTx, cfg MiningExecCfg, quit <-c
 
 	getHeader := func(hash libcommon.Hash, number uint64) *types.Header { return rawdb.ReadHeader(tx, hash, number) }
 
+	// Bundles go first, at the top of the block
+	ibs, logs, bundleTxs, err := addBundlesToMiningBlock(logPrefix, current, cfg.chainConfig, cfg.vmConfig, getHeader, cfg.engine, cfg.txPool2, cfg.miningState.MiningConfig.Etherbase, stateReader, ibs, conditions, quit, logger)
+	if err != nil {
+		return err
+	}
+	NotifyPendingLogs(logPrefix, cfg.notifier, logs, logger)
+
 	// Short circuit if there is no available pending transactions.
 	// But if we disable empty precommit already, ignore it. Since
 	// empty block is necessary to keep the liveness of the network.
 	if noempty {
 		if txs != nil && !txs.Empty() {
-			logs, _, err := addTransactionsToMiningBlock(logPrefix, current, cfg.chainConfig, cfg.vmConfig, getHeader, cfg.engine, txs, cfg.miningState.MiningConfig.Etherbase, ibs, quit, cfg.interrupt, cfg.payloadId, logger)
+			logs, _, err := addTransactionsToMiningBlock(logPrefix, current, cfg.chainConfig, cfg.vmConfig, getHeader, cfg.engine, txs, cfg.miningState.MiningConfig.Etherbase, ibs, conditions, quit, cfg.interrupt, cfg.payloadId, logger)
 			if err != nil {
 				return err
 			}
@@ -114,6 +126,9 @@ func SpawnMiningExecStage(s *StageState, tx kv.RwTx, cfg MiningExecCfg, quit <-c
 		} else {
 
 			yielded := mapset.NewSet[[32]byte]()
+			for _, txHash := range bundleTxs {
+				yielded.Add(txHash)
+			}
 			var simulationTx kv.StatelessRwTx
 			m := membatch.NewHashBatch(tx, quit, cfg.tmpdir, logger)
 			defer m.Close()
@@ -131,7 +146,7 @@ func SpawnMiningExecStage(s *StageState, tx kv.RwTx, cfg MiningExecCfg, quit <-c
 				}
 
 				if !txs.Empty() {
-					logs, stop, err := addTransactionsToMiningBlock(logPrefix, current, cfg.chainConfig, cfg.vmConfig, getHeader, cfg.engine, txs, cfg.miningState.MiningConfig.Etherbase, ibs, quit, cfg.interrupt, cfg.payloadId, logger)
+					logs, stop, err := addTransactionsToMiningBlock(logPrefix, current, cfg.chainConfig, cfg.vmConfig, getHeader, cfg.engine, txs, cfg.miningState.MiningConfig.Etherbase, ibs, conditions, quit, cfg.interrupt, cfg.payloadId, logger)
 					if err != nil {
 						return err
 					}
@@ -162,7 +177,6 @@ func SpawnMiningExecStage(s *StageState, tx kv.RwTx, cfg MiningExecCfg, quit <-c
 		current.Receipts = types.Receipts{}
 	}
 
-	var err error
 	_, current.Txs, current.Receipts, err = core.FinalizeBlockExecution(cfg.engine, stateReader, current.Header, current.Txs, current.Uncles, stateWriter, &cfg.chainConfig, ibs, current.Receipts, current.Withdrawals, ChainReaderImpl{config: &cfg.chainConfig, tx: tx, blockReader: cfg.blockReader}, true, logger)
 	if err != nil {
 		return err
@@ -358,8 +372,8 @@ func filterBadTransactions(transactions []types.Transaction, config chain.Config
 }
 
 func addTransactionsToMiningBlock(logPrefix string, current *MiningBlock, chainConfig chain.Config, vmConfig *vm.Config, getHeader func(hash libcommon.Hash, number uint64) *types.Header,
-	engine consensus.Engine, txs types.TransactionsStream, coinbase libcommon.Address, ibs *state.IntraBlockState, quit <-chan struct{},
-	interrupt *int32, payloadId uint64, logger log.Logger) (types.Logs, bool, error) {
+	engine consensus.Engine, txs types.TransactionsStream, coinbase libcommon.Address, ibs *state.IntraBlockState, conditions *conditionsChecker,
+	quit <-chan struct{}, interrupt *int32, payloadId uint64, logger log.Logger) (types.Logs, bool, error) {
 	header := current.Header
 	tcount := 0
 	gasPool := new(core.GasPool).AddGas(header.GasLimit - header.GasUsed)
@@ -369,7 +383,6 @@ func addTransactionsToMiningBlock(logPrefix string, current *MiningBlock, chainC
 	signer := types.MakeSigner(&chainConfig, header.Number.Uint64(), header.Time)
 
 	var coalescedLogs types.Logs
-	noop := state.NewNoopWriter()
 
 	var miningCommitTx = func(txn types.Transaction, coinbase libcommon.Address, vmConfig *vm.Config, chainConfig chain.Config, ibs *state.IntraBlockState, current *MiningBlock) ([]*types.Log, error) {
 		ibs.SetTxContext(txn.Hash(), libcommon.Hash{}, tcount)
@@ -377,7 +390,7 @@ func addTransactionsToMiningBlock(logPrefix string, current *MiningBlock, chainC
 		blobGasSnap := gasPool.BlobGas()
 		snap := ibs.Snapshot()
 		logger.Debug("addTransactionsToMiningBlock", "txn hash", txn.Hash())
-		receipt, _, err := core.ApplyTransaction(&chainConfig, core.GetHashFn(header, getHeader), engine, &coinbase, gasPool, ibs, noop, header, txn, &header.GasUsed, header.BlobGasUsed, *vmConfig)
+		receipt, _, err := core.ApplyTransaction(&chainConfig, core.GetHashFn(header, getHeader), engine, &coinbase, gasPool, ibs, conditions, header, txn, &header.GasUsed, header.BlobGasUsed, *vmConfig)
 		if err != nil {
 			ibs.RevertToSnapshot(snap)
 			gasPool = new(core.GasPool).AddGas(gasSnap).AddBlobGas(blobGasSnap) // restore gasPool as well as ibs
@@ -448,6 +461,13 @@ LOOP:
 			continue
 		}
 
+		// Conditional transactions are only included while their conditions hold
+		if !conditions.hold(txn, header, ibs) {
+			logger.Debug(fmt.Sprintf("[%s] Skipping transaction with unmet conditions", logPrefix), "hash", txn.Hash(), "sender", from)
+			txs.Pop()
+			continue
+		}
+
 		// Start executing the transaction
 		logs, err := miningCommitTx(txn, coinbase, vmConfig, chainConfig, ibs, current)
 
@@ -499,3 +519,68 @@ func NotifyPendingLogs(logPrefix string, notifier ChainEventNotifier, logs types
 	}
 	notifier.OnNewPendingLogs(logs)
 }
+
+// conditionsChecker checks the conditions of conditional transactions against the block being built. It is also its
+// state writer, to know which storage the transactions already included have changed.
+type conditionsChecker struct {
+	*state.NoopWriter
+	txConditions   func(idHash [32]byte) *txpool.TxConditions
+	storageWritten map[libcommon.Address]struct{}
+}
+
+func newConditionsChecker(txPool TxPoolForMining) *conditionsChecker {
+	c := &conditionsChecker{NoopWriter: state.NewNoopWriter(), storageWritten: map[libcommon.Address]struct{}{}}
+	if txPool != nil {
+		c.txConditions = txPool.TxConditions
+	}
+	return c
+}
+
+func (c *conditionsChecker) WriteAccountStorage(address libcommon.Address, incarnation uint64, key *libcommon.Hash, original, value *uint256.Int) error {
+	if !original.Eq(value) {
+		c.storageWritten[address] = struct{}{}
+	}
+	return nil
+}
+
+func (c *conditionsChecker) DeleteAccount(address libcommon.Address, original *accounts.Account) error {
+	c.storageWritten[address] = struct{}{}
+	return nil
+}
+
+// hold tells whether the conditions of the transaction, if any, hold at this point of the block
+func (c *conditionsChecker) hold(txn types.Transaction, header *types.Header, ibs *state.IntraBlockState) bool {
+	if c.txConditions == nil {
+		return true
+	}
+	conditions := c.txConditions(txn.Hash())
+	if conditions == nil {
+		return true
+	}
+	if !conditions.CheckBlock(header.Number.Uint64(), header.Time) {
+		return false
+	}
+	var value uint256.Int
+	for address, known := range conditions.KnownAccounts {
+		if known.StorageRoot != nil {
+			// the storage root is not computed while building the block. It was checked against the latest state when
+			// the transaction was submitted (see checkKnownAccounts of eth_sendRawTransactionConditional), and the pool
+			// dropped the transaction if a later block changed the storage of the account, so the root is still the
+			// expected one unless a transaction of this block changed the storage. This is conservative: once a slot of
+			// the account was changed, the transaction is dropped even if the slot was set back since, as the root can't
+			// be compared.
+			if _, ok := c.storageWritten[address]; ok {
+				return false
+			}
+			continue
+		}
+		for key, expected := range known.Slots {
+			key := key
+			ibs.GetState(address, &key, &value)
+			if libcommon.Hash(value.Bytes32()) != expected {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -108,3 +108,25 @@ func PruneVerkleTries(s *PruneState, tx kv.RwTx, cfg TrieCfg, ctx context.Contex
 	}
 	return nil
 }
+
+// writeVerkleWitness generates the execution witness of a block against the verkle tree of its
+// parent. That tree is only available once the VerkleTrie stage has processed the parent block,
+// which is the case when following the chain tip; otherwise the witness is skipped.
+func writeVerkleWitness(tx kv.RwTx, blockNum uint64, keys [][]byte, logger log.Logger) error {
+	if blockNum == 0 || len(keys) == 0 {
+		return nil
+	}
+	parentRoot, err := rawdb.ReadVerkleRoot(tx, blockNum-1)
+	if err != nil {
+		return err
+	}
+	if parentRoot == (libcommon.Hash{}) {
+		logger.Debug("[VerkleTrie] parent tree is not available, skipping execution witness", "block", blockNum)
+		return nil
+	}
+	proof, keyVals, err := verkletrie.GenerateWitness(tx, parentRoot, keys)
+	if err != nil {
+		return fmt.Errorf("generating verkle witness for block %d: %w", blockNum, err)
+	}
+	return rawdb.WriteVerkleWitness(tx, blockNum, proof, keyVals)
+}
@@ -71,6 +71,12 @@ const (
 	// Which becomes: 5000 - 2100 + 1900 = 4800
 	SstoreClearsScheduleRefundEIP3529 = SstoreResetGasEIP2200 - ColdSloadCostEIP2929 + TxAccessListStorageKeyGas
 
+	// EIP-4762: Statelessness gas cost changes
+	WitnessBranchReadCost  uint64 = 1900 // WITNESS_BRANCH_READ_COST, first read of a leaf under a given stem
+	WitnessChunkReadCost   uint64 = 200  // WITNESS_CHUNK_READ_COST, first read of a given leaf
+	WitnessBranchWriteCost uint64 = 3000 // WITNESS_BRANCH_WRITE_COST, first write of a leaf under a given stem
+	WitnessChunkWriteCost  uint64 = 500  // WITNESS_CHUNK_WRITE_COST, first write of a given leaf
+
 	JumpdestGas   uint64 = 1     // Once per JUMPDEST operation.
 	EpochDuration uint64 = 30000 // Duration between proof-of-work epochs.
 
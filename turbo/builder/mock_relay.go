@@ -0,0 +1,127 @@
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/Giulio2002/bls"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/fork"
+)
+
+// MockRelay is a minimal relay, to test the builder API without a beacon node: proposers are registered by hand, and
+// the blocks submitted for them are checked and kept, but never delivered
+type MockRelay struct {
+	domain []byte
+
+	lock        sync.Mutex
+	validators  []*ValidatorRegistration
+	submissions []*SubmitBlockRequest
+}
+
+func NewMockRelay(beaconCfg *clparams.BeaconChainConfig) (*MockRelay, error) {
+	domain, err := BuilderDomain(beaconCfg)
+	if err != nil {
+		return nil, err
+	}
+	return &MockRelay{domain: domain}, nil
+}
+
+// Register registers the proposer of a slot
+func (r *MockRelay) Register(slot uint64, pubkey libcommon.Bytes48, feeRecipient libcommon.Address, gasLimit uint64) {
+	v := &ValidatorRegistration{Slot: slot}
+	v.Entry.Message.Pubkey = pubkey
+	v.Entry.Message.FeeRecipient = feeRecipient
+	v.Entry.Message.GasLimit = gasLimit
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.validators = append(r.validators, v)
+}
+
+// Submissions returns the blocks accepted so far
+func (r *MockRelay) Submissions() []*SubmitBlockRequest {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]*SubmitBlockRequest(nil), r.submissions...)
+}
+
+func (r *MockRelay) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch {
+	case req.Method == http.MethodGet && req.URL.Path == ValidatorsPath:
+		r.lock.Lock()
+		validators := append([]*ValidatorRegistration{}, r.validators...)
+		r.lock.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(validators)
+	case req.Method == http.MethodPost && req.URL.Path == SubmitBlockPath:
+		submission := &SubmitBlockRequest{}
+		if err := json.NewDecoder(req.Body).Decode(submission); err != nil {
+			relayError(w, err)
+			return
+		}
+		if err := r.checkSubmission(submission); err != nil {
+			relayError(w, err)
+			return
+		}
+		r.lock.Lock()
+		r.submissions = append(r.submissions, submission)
+		r.lock.Unlock()
+		w.WriteHeader(http.StatusOK)
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+func (r *MockRelay) checkSubmission(submission *SubmitBlockRequest) error {
+	trace, payload := submission.Message, submission.ExecutionPayload
+	if trace == nil || payload == nil {
+		return fmt.Errorf("missing message or execution payload")
+	}
+	root, err := fork.ComputeSigningRoot(trace, r.domain)
+	if err != nil {
+		return err
+	}
+	if ok, err := bls.Verify(submission.Signature[:], root[:], trace.BuilderPubkey[:]); err != nil || !ok {
+		return fmt.Errorf("invalid signature")
+	}
+
+	r.lock.Lock()
+	var registration *ValidatorRegistration
+	for _, v := range r.validators {
+		if v.Slot == trace.Slot {
+			registration = v
+			break
+		}
+	}
+	r.lock.Unlock()
+	if registration == nil {
+		return fmt.Errorf("no proposer registered for slot %d", trace.Slot)
+	}
+	if trace.ProposerPubkey != registration.Entry.Message.Pubkey {
+		return fmt.Errorf("proposer pubkey mismatch: %x, registered: %x", trace.ProposerPubkey, registration.Entry.Message.Pubkey)
+	}
+	if trace.ProposerFeeRecipient != registration.Entry.Message.FeeRecipient || payload.FeeRecipient != trace.ProposerFeeRecipient {
+		return fmt.Errorf("fee recipient mismatch: %x, registered: %x", payload.FeeRecipient, registration.Entry.Message.FeeRecipient)
+	}
+	if trace.BlockHash != payload.BlockHash || trace.ParentHash != payload.ParentHash {
+		return fmt.Errorf("block hash mismatch between the message and the payload")
+	}
+	if trace.GasLimit != payload.GasLimit || trace.GasUsed != payload.GasUsed {
+		return fmt.Errorf("gas mismatch between the message and the payload")
+	}
+	return nil
+}
+
+func relayError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}{http.StatusBadRequest, err.Error()})
+}
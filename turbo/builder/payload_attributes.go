@@ -0,0 +1,125 @@
+package builder
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/types"
+)
+
+// PayloadAttributesPath is the path of the events of the beacon API, for the payload attributes events
+const PayloadAttributesPath = "/eth/v1/events?topics=payload_attributes"
+
+// PayloadAttributesEvent is a payload_attributes event of the beacon API: the attributes of the payload for the
+// next slot, sent by the beacon node as soon as it knows the parent of the block
+type PayloadAttributesEvent struct {
+	Version string                `json:"version"`
+	Data    PayloadAttributesData `json:"data"`
+}
+
+type PayloadAttributesData struct {
+	ProposerIndex     uint64            `json:"proposer_index,string"`
+	ProposalSlot      uint64            `json:"proposal_slot,string"`
+	ParentBlockNumber uint64            `json:"parent_block_number,string"`
+	ParentBlockRoot   libcommon.Hash    `json:"parent_block_root"`
+	ParentBlockHash   libcommon.Hash    `json:"parent_block_hash"`
+	PayloadAttributes PayloadAttributes `json:"payload_attributes"`
+}
+
+type PayloadAttributes struct {
+	Timestamp             uint64            `json:"timestamp,string"`
+	PrevRandao            libcommon.Hash    `json:"prev_randao"`
+	SuggestedFeeRecipient libcommon.Address `json:"suggested_fee_recipient"`
+	Withdrawals           []*Withdrawal     `json:"withdrawals,omitempty"`
+	ParentBeaconBlockRoot *libcommon.Hash   `json:"parent_beacon_block_root,omitempty"`
+}
+
+// BuilderParameters are the parameters to build the block of the event, paying the given fee recipient
+func (e *PayloadAttributesEvent) BuilderParameters(feeRecipient libcommon.Address) *core.BlockBuilderParameters {
+	attributes := e.Data.PayloadAttributes
+	param := &core.BlockBuilderParameters{
+		PayloadId:             e.Data.ProposalSlot,
+		ParentHash:            e.Data.ParentBlockHash,
+		Timestamp:             attributes.Timestamp,
+		PrevRandao:            attributes.PrevRandao,
+		SuggestedFeeRecipient: feeRecipient,
+		ParentBeaconBlockRoot: attributes.ParentBeaconBlockRoot,
+	}
+	if attributes.Withdrawals != nil {
+		param.Withdrawals = make([]*types.Withdrawal, len(attributes.Withdrawals))
+		for i, w := range attributes.Withdrawals {
+			param.Withdrawals[i] = &types.Withdrawal{Index: w.Index, Validator: w.ValidatorIndex, Address: w.Address, Amount: w.Amount}
+		}
+	}
+	return param
+}
+
+// SubscribePayloadAttributes sends the payload_attributes events of the beacon node to the channel, until the
+// context is done. It connects again whenever the stream of events is interrupted.
+func SubscribePayloadAttributes(ctx context.Context, beaconURL string, events chan<- *PayloadAttributesEvent, logger log.Logger) {
+	url := strings.TrimSuffix(beaconURL, "/") + PayloadAttributesPath
+	for {
+		if err := readPayloadAttributes(ctx, url, events); err != nil && ctx.Err() == nil {
+			logger.Warn("[builder] Payload attributes stream interrupted", "url", url, "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// readPayloadAttributes reads the server-sent events of the beacon node, until the stream ends
+func readPayloadAttributes(ctx context.Context, url string, events chan<- *PayloadAttributesEvent) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s", resp.Status)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	var eventType string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			eventType = ""
+		case strings.HasPrefix(line, "event:"):
+			eventType = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:") && eventType == "payload_attributes":
+			event := &PayloadAttributesEvent{}
+			if err := json.Unmarshal([]byte(strings.TrimSpace(strings.TrimPrefix(line, "data:"))), event); err != nil {
+				return fmt.Errorf("payload attributes event: %w", err)
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("end of stream")
+}
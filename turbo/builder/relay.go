@@ -0,0 +1,289 @@
+package builder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/holiman/uint256"
+	blst "github.com/supranational/blst/bindings/go"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/fork"
+	"github.com/ledgerwatch/erigon/cl/merkle_tree"
+	"github.com/ledgerwatch/erigon/cl/utils"
+	"github.com/ledgerwatch/erigon/core/types"
+)
+
+// Relay API paths, see https://flashbots.github.io/relay-specs/
+const (
+	ValidatorsPath  = "/relay/v1/builder/validators"
+	SubmitBlockPath = "/relay/v1/builder/blocks"
+)
+
+// blsDST is the domain separation tag of the BLS signatures of the consensus layer
+var blsDST = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
+
+// Decimal is a uint256 encoded as a decimal string, as in the builder API
+type Decimal uint256.Int
+
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte((*uint256.Int)(&d).Dec()), nil
+}
+
+func (d *Decimal) UnmarshalText(input []byte) error {
+	return (*uint256.Int)(d).SetFromDecimal(string(input))
+}
+
+// BidTrace is the message signed by the builder for a block submitted to a relay
+type BidTrace struct {
+	Slot                 uint64            `json:"slot,string"`
+	ParentHash           libcommon.Hash    `json:"parent_hash"`
+	BlockHash            libcommon.Hash    `json:"block_hash"`
+	BuilderPubkey        libcommon.Bytes48 `json:"builder_pubkey"`
+	ProposerPubkey       libcommon.Bytes48 `json:"proposer_pubkey"`
+	ProposerFeeRecipient libcommon.Address `json:"proposer_fee_recipient"`
+	GasLimit             uint64            `json:"gas_limit,string"`
+	GasUsed              uint64            `json:"gas_used,string"`
+	Value                Decimal           `json:"value"`
+}
+
+// HashSSZ is the SSZ hash tree root of the bid trace, which is signed
+func (b *BidTrace) HashSSZ() ([32]byte, error) {
+	value := (*uint256.Int)(&b.Value).Bytes32()
+	// uint256 are little endian in SSZ
+	for i, j := 0, len(value)-1; i < j; i, j = i+1, j-1 {
+		value[i], value[j] = value[j], value[i]
+	}
+	return merkle_tree.HashTreeRoot(b.Slot, b.ParentHash[:], b.BlockHash[:], b.BuilderPubkey[:], b.ProposerPubkey[:],
+		b.ProposerFeeRecipient[:], b.GasLimit, b.GasUsed, value[:])
+}
+
+// Withdrawal is a withdrawal of an execution payload, in the format of the builder API
+type Withdrawal struct {
+	Index          uint64            `json:"index,string"`
+	ValidatorIndex uint64            `json:"validator_index,string"`
+	Address        libcommon.Address `json:"address"`
+	Amount         uint64            `json:"amount,string"`
+}
+
+// ExecutionPayload is a block, in the format of the builder API
+type ExecutionPayload struct {
+	ParentHash    libcommon.Hash     `json:"parent_hash"`
+	FeeRecipient  libcommon.Address  `json:"fee_recipient"`
+	StateRoot     libcommon.Hash     `json:"state_root"`
+	ReceiptsRoot  libcommon.Hash     `json:"receipts_root"`
+	LogsBloom     types.Bloom        `json:"logs_bloom"`
+	PrevRandao    libcommon.Hash     `json:"prev_randao"`
+	BlockNumber   uint64             `json:"block_number,string"`
+	GasLimit      uint64             `json:"gas_limit,string"`
+	GasUsed       uint64             `json:"gas_used,string"`
+	Timestamp     uint64             `json:"timestamp,string"`
+	ExtraData     hexutility.Bytes   `json:"extra_data"`
+	BaseFeePerGas Decimal            `json:"base_fee_per_gas"`
+	BlockHash     libcommon.Hash     `json:"block_hash"`
+	Transactions  []hexutility.Bytes `json:"transactions"`
+	Withdrawals   []*Withdrawal      `json:"withdrawals,omitempty"`
+	BlobGasUsed   *uint64            `json:"blob_gas_used,string,omitempty"`
+	ExcessBlobGas *uint64            `json:"excess_blob_gas,string,omitempty"`
+}
+
+// NewExecutionPayload converts a block to the format of the builder API
+func NewExecutionPayload(block *types.Block) (*ExecutionPayload, error) {
+	header := block.Header()
+	payload := &ExecutionPayload{
+		ParentHash:    header.ParentHash,
+		FeeRecipient:  header.Coinbase,
+		StateRoot:     header.Root,
+		ReceiptsRoot:  header.ReceiptHash,
+		LogsBloom:     header.Bloom,
+		PrevRandao:    header.MixDigest,
+		BlockNumber:   header.Number.Uint64(),
+		GasLimit:      header.GasLimit,
+		GasUsed:       header.GasUsed,
+		Timestamp:     header.Time,
+		ExtraData:     header.Extra,
+		BlockHash:     block.Hash(),
+		BlobGasUsed:   header.BlobGasUsed,
+		ExcessBlobGas: header.ExcessBlobGas,
+	}
+	if header.BaseFee != nil {
+		(*uint256.Int)(&payload.BaseFeePerGas).SetFromBig(header.BaseFee)
+	}
+	encodedTransactions, err := types.MarshalTransactionsBinary(block.Transactions())
+	if err != nil {
+		return nil, err
+	}
+	payload.Transactions = make([]hexutility.Bytes, len(encodedTransactions))
+	for i, encodedTransaction := range encodedTransactions {
+		payload.Transactions[i] = encodedTransaction
+	}
+	if block.Withdrawals() != nil {
+		payload.Withdrawals = make([]*Withdrawal, len(block.Withdrawals()))
+		for i, w := range block.Withdrawals() {
+			payload.Withdrawals[i] = &Withdrawal{Index: w.Index, ValidatorIndex: w.Validator, Address: w.Address, Amount: w.Amount}
+		}
+	}
+	return payload, nil
+}
+
+// BlobsBundle are the blobs of the blob transactions of a block, in the format of the builder API
+type BlobsBundle struct {
+	Commitments []hexutility.Bytes `json:"commitments"`
+	Proofs      []hexutility.Bytes `json:"proofs"`
+	Blobs       []hexutility.Bytes `json:"blobs"`
+}
+
+// NewBlobsBundle collects the blobs of the blob transactions of a block, nil if there are none
+func NewBlobsBundle(block *types.Block) (*BlobsBundle, error) {
+	if block.Header().BlobGasUsed == nil {
+		return nil, nil
+	}
+	bundle := &BlobsBundle{Commitments: []hexutility.Bytes{}, Proofs: []hexutility.Bytes{}, Blobs: []hexutility.Bytes{}}
+	for _, txn := range block.Transactions() {
+		if txn.Type() != types.BlobTxType {
+			continue
+		}
+		blobTx, ok := txn.(*types.BlobTxWrapper)
+		if !ok {
+			return nil, fmt.Errorf("expected blob transaction %x to be type BlobTxWrapper, got: %T", txn.Hash(), txn)
+		}
+		for i := range blobTx.Commitments {
+			bundle.Commitments = append(bundle.Commitments, libcommon.Copy(blobTx.Commitments[i][:]))
+			bundle.Proofs = append(bundle.Proofs, libcommon.Copy(blobTx.Proofs[i][:]))
+			bundle.Blobs = append(bundle.Blobs, libcommon.Copy(blobTx.Blobs[i][:]))
+		}
+	}
+	return bundle, nil
+}
+
+// SubmitBlockRequest is a signed block submission to a relay
+type SubmitBlockRequest struct {
+	Message          *BidTrace         `json:"message"`
+	ExecutionPayload *ExecutionPayload `json:"execution_payload"`
+	BlobsBundle      *BlobsBundle      `json:"blobs_bundle,omitempty"`
+	Signature        libcommon.Bytes96 `json:"signature"`
+}
+
+// ValidatorRegistration is a proposer registered to a relay for an upcoming slot
+type ValidatorRegistration struct {
+	Slot           uint64 `json:"slot,string"`
+	ValidatorIndex uint64 `json:"validator_index,string"`
+	Entry          struct {
+		Message struct {
+			FeeRecipient libcommon.Address `json:"fee_recipient"`
+			GasLimit     uint64            `json:"gas_limit,string"`
+			Timestamp    uint64            `json:"timestamp,string"`
+			Pubkey       libcommon.Bytes48 `json:"pubkey"`
+		} `json:"message"`
+		Signature libcommon.Bytes96 `json:"signature"`
+	} `json:"entry"`
+}
+
+// Signer signs the bid traces of the builder with its BLS secret key
+type Signer struct {
+	secretKey *blst.SecretKey
+	publicKey libcommon.Bytes48
+	domain    []byte
+}
+
+// NewSigner creates a signer for the given network, with a 32 bytes BLS secret key
+func NewSigner(secretKey []byte, beaconCfg *clparams.BeaconChainConfig) (*Signer, error) {
+	sk := new(blst.SecretKey).Deserialize(secretKey)
+	if sk == nil {
+		return nil, fmt.Errorf("invalid builder secret key")
+	}
+	domain, err := BuilderDomain(beaconCfg)
+	if err != nil {
+		return nil, err
+	}
+	s := &Signer{secretKey: sk, domain: domain}
+	copy(s.publicKey[:], new(blst.P1Affine).From(sk).Compress())
+	return s, nil
+}
+
+// BuilderDomain is the signing domain of the builder API: it does not depend on the fork, nor on the genesis
+// validators root
+func BuilderDomain(beaconCfg *clparams.BeaconChainConfig) ([]byte, error) {
+	return fork.ComputeDomain(beaconCfg.DomainApplicationBuilder[:], utils.Uint32ToBytes4(beaconCfg.GenesisForkVersion), [32]byte{})
+}
+
+func (s *Signer) PublicKey() libcommon.Bytes48 { return s.publicKey }
+
+func (s *Signer) Sign(trace *BidTrace) (libcommon.Bytes96, error) {
+	var signature libcommon.Bytes96
+	root, err := fork.ComputeSigningRoot(trace, s.domain)
+	if err != nil {
+		return signature, err
+	}
+	copy(signature[:], new(blst.P2Affine).Sign(s.secretKey, root[:], blsDST).Compress())
+	return signature, nil
+}
+
+// RelayClient is a client of the builder API of a relay
+type RelayClient struct {
+	url    string
+	client *http.Client
+}
+
+func NewRelayClient(url string, client *http.Client) *RelayClient {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &RelayClient{url: strings.TrimSuffix(url, "/"), client: client}
+}
+
+func (c *RelayClient) URL() string { return c.url }
+
+// Validators returns the proposers registered to the relay for the current and the next epoch
+func (c *RelayClient) Validators(ctx context.Context) ([]*ValidatorRegistration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+ValidatorsPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	var validators []*ValidatorRegistration
+	if err := c.do(req, &validators); err != nil {
+		return nil, err
+	}
+	return validators, nil
+}
+
+// SubmitBlock submits a signed block to the relay
+func (c *RelayClient) SubmitBlock(ctx context.Context, submission *SubmitBlockRequest) error {
+	body, err := json.Marshal(submission)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+SubmitBlockPath, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return c.do(req, nil)
+}
+
+func (c *RelayClient) do(req *http.Request, out interface{}) error {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("relay %s%s: %s: %s", c.url, req.URL.Path, resp.Status, strings.TrimSpace(string(body)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(body, out)
+}
@@ -0,0 +1,139 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon/core/types"
+)
+
+// Service is the builder API: it builds a block for each payload attributes event of the beacon node, and submits it
+// to the relays where the proposer of the slot is registered. The proposer is the fee recipient of the block, and the
+// value of the bid is what the block pays to it in priority fees.
+type Service struct {
+	build     BlockBuilderFunc
+	relays    []*RelayClient
+	signer    *Signer
+	buildTime time.Duration
+	logger    log.Logger
+}
+
+// NewService creates a builder API service, which builds each block for buildTime before submitting it
+func NewService(build BlockBuilderFunc, relays []*RelayClient, signer *Signer, buildTime time.Duration, logger log.Logger) *Service {
+	return &Service{build: build, relays: relays, signer: signer, buildTime: buildTime, logger: logger}
+}
+
+// Run handles the payload attributes events one at a time, until the context is done or the channel is closed
+func (s *Service) Run(ctx context.Context, events <-chan *PayloadAttributesEvent) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := s.OnPayloadAttributes(ctx, event); err != nil {
+				s.logger.Warn("[builder] Failed to build a block for the relays", "slot", event.Data.ProposalSlot, "err", err)
+			}
+		}
+	}
+}
+
+// OnPayloadAttributes builds the block of the event, and submits it to the relays where its proposer is registered
+func (s *Service) OnPayloadAttributes(ctx context.Context, event *PayloadAttributesEvent) error {
+	slot := event.Data.ProposalSlot
+	var relays []*RelayClient
+	var registration *ValidatorRegistration
+	for _, relay := range s.relays {
+		validators, err := relay.Validators(ctx)
+		if err != nil {
+			s.logger.Warn("[builder] Failed to get the registered proposers", "relay", relay.URL(), "err", err)
+			continue
+		}
+		for _, v := range validators {
+			if v.Slot != slot {
+				continue
+			}
+			if registration == nil {
+				registration = v
+			}
+			// the block pays a single fee recipient
+			if v.Entry.Message.Pubkey == registration.Entry.Message.Pubkey && v.Entry.Message.FeeRecipient == registration.Entry.Message.FeeRecipient {
+				relays = append(relays, relay)
+			}
+			break
+		}
+	}
+	if registration == nil {
+		s.logger.Debug("[builder] No proposer registered to the relays", "slot", slot)
+		return nil
+	}
+	proposer := registration.Entry.Message
+
+	builder := NewBlockBuilder(s.build, event.BuilderParameters(proposer.FeeRecipient))
+	select {
+	case <-ctx.Done():
+	case <-time.After(s.buildTime):
+	}
+	result, err := builder.Stop()
+	if err != nil {
+		return err
+	}
+	block := result.Block
+	if block.ParentHash() != event.Data.ParentBlockHash {
+		return fmt.Errorf("block built on top of %x instead of %x", block.ParentHash(), event.Data.ParentBlockHash)
+	}
+
+	baseFee := new(uint256.Int)
+	baseFee.SetFromBig(block.BaseFee())
+	trace := &BidTrace{
+		Slot:                 slot,
+		ParentHash:           block.ParentHash(),
+		BlockHash:            block.Hash(),
+		BuilderPubkey:        s.signer.PublicKey(),
+		ProposerPubkey:       proposer.Pubkey,
+		ProposerFeeRecipient: proposer.FeeRecipient,
+		GasLimit:             block.GasLimit(),
+		GasUsed:              block.GasUsed(),
+		Value:                Decimal(*BlockValue(result, baseFee)),
+	}
+	signature, err := s.signer.Sign(trace)
+	if err != nil {
+		return err
+	}
+	payload, err := NewExecutionPayload(block)
+	if err != nil {
+		return err
+	}
+	blobsBundle, err := NewBlobsBundle(block)
+	if err != nil {
+		return err
+	}
+	submission := &SubmitBlockRequest{Message: trace, ExecutionPayload: payload, BlobsBundle: blobsBundle, Signature: signature}
+	for _, relay := range relays {
+		if err := relay.SubmitBlock(ctx, submission); err != nil {
+			s.logger.Warn("[builder] Failed to submit the block", "relay", relay.URL(), "slot", slot, "hash", block.Hash(), "err", err)
+			continue
+		}
+		s.logger.Info("[builder] Submitted block", "relay", relay.URL(), "slot", slot, "hash", block.Hash(), "txs", len(block.Transactions()), "value", (*uint256.Int)(&trace.Value))
+	}
+	return nil
+}
+
+// BlockValue is the expected value to be received by the feeRecipient in wei
+func BlockValue(br *types.BlockWithReceipts, baseFee *uint256.Int) *uint256.Int {
+	blockValue := uint256.NewInt(0)
+	txs := br.Block.Transactions()
+	for i := range txs {
+		gas := new(uint256.Int).SetUint64(br.Receipts[i].GasUsed)
+		effectiveTip := txs[i].GetEffectiveGasTip(baseFee)
+		txValue := new(uint256.Int).Mul(gas, effectiveTip)
+		blockValue.Add(blockValue, txValue)
+	}
+	return blockValue
+}
@@ -0,0 +1,132 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/types"
+)
+
+func testSigner(t *testing.T) *Signer {
+	secretKey := make([]byte, 32)
+	secretKey[31] = 42
+	signer, err := NewSigner(secretKey, &clparams.MainnetBeaconConfig)
+	require.NoError(t, err)
+	return signer
+}
+
+func testBuild(param *core.BlockBuilderParameters, interrupt *int32) (*types.BlockWithReceipts, error) {
+	header := &types.Header{
+		ParentHash: param.ParentHash,
+		Coinbase:   param.SuggestedFeeRecipient,
+		Number:     big.NewInt(100),
+		GasLimit:   30_000_000,
+		GasUsed:    21_000,
+		Time:       param.Timestamp,
+		MixDigest:  param.PrevRandao,
+		BaseFee:    big.NewInt(10),
+	}
+	to := libcommon.HexToAddress("0x1234")
+	txn := &types.DynamicFeeTransaction{
+		CommonTx: types.CommonTx{Gas: 21_000, To: &to, Value: uint256.NewInt(1)},
+		ChainID:  uint256.NewInt(1),
+		Tip:      uint256.NewInt(2),
+		FeeCap:   uint256.NewInt(100),
+	}
+	receipts := types.Receipts{{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 21_000, GasUsed: 21_000}}
+	block := types.NewBlock(header, []types.Transaction{txn}, nil, receipts, nil)
+	return &types.BlockWithReceipts{Block: block, Receipts: receipts}, nil
+}
+
+func TestSubmitBlock(t *testing.T) {
+	relay, err := NewMockRelay(&clparams.MainnetBeaconConfig)
+	require.NoError(t, err)
+	server := httptest.NewServer(relay)
+	defer server.Close()
+
+	proposer := libcommon.Bytes48{1}
+	feeRecipient := libcommon.HexToAddress("0xfee")
+	relay.Register(10, proposer, feeRecipient, 30_000_000)
+
+	signer := testSigner(t)
+	service := NewService(testBuild, []*RelayClient{NewRelayClient(server.URL, nil)}, signer, time.Millisecond, log.New())
+	event := &PayloadAttributesEvent{Version: "deneb"}
+	event.Data.ProposalSlot = 10
+	event.Data.ParentBlockHash = libcommon.HexToHash("0xabc")
+	event.Data.PayloadAttributes.Timestamp = 1000
+	event.Data.PayloadAttributes.SuggestedFeeRecipient = libcommon.HexToAddress("0x1")
+	require.NoError(t, service.OnPayloadAttributes(context.Background(), event))
+
+	submissions := relay.Submissions()
+	require.Len(t, submissions, 1)
+	trace := submissions[0].Message
+	require.Equal(t, uint64(10), trace.Slot)
+	require.Equal(t, proposer, trace.ProposerPubkey)
+	require.Equal(t, feeRecipient, trace.ProposerFeeRecipient)
+	require.Equal(t, feeRecipient, submissions[0].ExecutionPayload.FeeRecipient)
+	require.Equal(t, signer.PublicKey(), trace.BuilderPubkey)
+	require.Equal(t, event.Data.ParentBlockHash, trace.ParentHash)
+	require.Equal(t, uint64(42_000), (*uint256.Int)(&trace.Value).Uint64()) // 21000 gas * 2 wei tip
+	require.Len(t, submissions[0].ExecutionPayload.Transactions, 1)
+
+	// no proposer registered for the slot: nothing is built
+	event.Data.ProposalSlot = 11
+	require.NoError(t, service.OnPayloadAttributes(context.Background(), event))
+	require.Len(t, relay.Submissions(), 1)
+
+	// a tampered bid is rejected by the relay
+	submission := *submissions[0]
+	tampered := *submission.Message
+	(*uint256.Int)(&tampered.Value).SetUint64(1)
+	submission.Message = &tampered
+	require.ErrorContains(t, NewRelayClient(server.URL, nil).SubmitBlock(context.Background(), &submission), "invalid signature")
+}
+
+func TestSubscribePayloadAttributes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/eth/v1/events", r.URL.Path)
+		require.Equal(t, "payload_attributes", r.URL.Query().Get("topics"))
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: head\ndata: {}\n\n")
+		fmt.Fprint(w, `event: payload_attributes
+data: {"version":"deneb","data":{"proposer_index":"7","proposal_slot":"12","parent_block_number":"99","parent_block_root":"0x0000000000000000000000000000000000000000000000000000000000000001","parent_block_hash":"0x0000000000000000000000000000000000000000000000000000000000000002","payload_attributes":{"timestamp":"1000","prev_randao":"0x0000000000000000000000000000000000000000000000000000000000000003","suggested_fee_recipient":"0x0000000000000000000000000000000000000fee","withdrawals":[{"index":"1","validator_index":"2","address":"0x0000000000000000000000000000000000000001","amount":"3"}],"parent_beacon_block_root":"0x0000000000000000000000000000000000000000000000000000000000000004"}}}
+
+`)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan *PayloadAttributesEvent)
+	go SubscribePayloadAttributes(ctx, server.URL, events, log.New())
+
+	var event *PayloadAttributesEvent
+	select {
+	case event = <-events:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no payload attributes event")
+	}
+	require.Equal(t, uint64(12), event.Data.ProposalSlot)
+	require.Equal(t, uint64(7), event.Data.ProposerIndex)
+	require.Equal(t, libcommon.HexToHash("0x2"), event.Data.ParentBlockHash)
+
+	param := event.BuilderParameters(libcommon.HexToAddress("0xbeef"))
+	require.Equal(t, uint64(12), param.PayloadId)
+	require.Equal(t, uint64(1000), param.Timestamp)
+	require.Equal(t, libcommon.HexToAddress("0xbeef"), param.SuggestedFeeRecipient)
+	require.Equal(t, libcommon.HexToHash("0x4"), *param.ParentBeaconBlockRoot)
+	require.Equal(t, []*types.Withdrawal{{Index: 1, Validator: 2, Address: libcommon.HexToAddress("0x1"), Amount: 3}}, param.Withdrawals)
+}
@@ -20,13 +20,17 @@ var DefaultFlags = []cli.Flag{
 	&utils.TxPoolBlobPriceBumpFlag,
 	&utils.TxPoolAccountSlotsFlag,
 	&utils.TxPoolBlobSlotsFlag,
+	&utils.TxPoolBlobPoolLimitFlag,
 	&utils.TxPoolGlobalSlotsFlag,
 	&utils.TxPoolGlobalBaseFeeSlotsFlag,
 	&utils.TxPoolAccountQueueFlag,
 	&utils.TxPoolGlobalQueueFlag,
 	&utils.TxPoolLifetimeFlag,
 	&utils.TxPoolTraceSendersFlag,
+	&utils.TxPoolJournalLifetimeFlag,
+	&utils.TxPoolRebroadcastEveryFlag,
 	&utils.TxPoolCommitEveryFlag,
+	&utils.TxPoolPolicyFlag,
 	&PruneFlag,
 	&PruneHistoryFlag,
 	&PruneReceiptFlag,
@@ -48,6 +52,7 @@ var DefaultFlags = []cli.Flag{
 	&TLSCACertFlag,
 	&StateStreamDisableFlag,
 	&SyncLoopThrottleFlag,
+	&SyncParallelExecutionFlag,
 	&BadBlockFlag,
 
 	&utils.HTTPEnabledFlag,
@@ -138,6 +143,10 @@ var DefaultFlags = []cli.Flag{
 	&utils.MinerExtraDataFlag,
 	&utils.MinerNoVerfiyFlag,
 	&utils.MinerSigningKeyFileFlag,
+	&utils.BuilderRelaysFlag,
+	&utils.BuilderBeaconURLFlag,
+	&utils.BuilderSecretKeyFileFlag,
+	&utils.BuilderBuildTimeFlag,
 	&utils.SentryAddrFlag,
 	&utils.SentryLogPeerInfoFlag,
 	&utils.DownloaderAddrFlag,
@@ -178,6 +187,9 @@ var DefaultFlags = []cli.Flag{
 
 	&utils.CaplinBackfillingFlag,
 	&utils.CaplinArchiveFlag,
+	&utils.CaplinValidatorKeystoresFlag,
+	&utils.CaplinValidatorPasswordFileFlag,
+	&utils.CaplinValidatorSlashingProtectionImportFlag,
 
 	&utils.TrustedSetupFile,
 	&utils.RPCSlowFlag,
@@ -147,6 +147,10 @@ var (
 		Usage: "Sets the minimum time between sync loop starts (e.g. 1h30m, default is none)",
 		Value: "",
 	}
+	SyncParallelExecutionFlag = cli.BoolFlag{
+		Name:  "sync.parallel-exec",
+		Usage: "Execute the transactions of a block optimistically in parallel, re-executing the conflicting ones (experimental)",
+	}
 
 	BadBlockFlag = cli.StringFlag{
 		Name:  "bad.block",
@@ -254,6 +258,7 @@ func ApplyFlagsForEthConfig(ctx *cli.Context, cfg *ethconfig.Config, logger log.
 		}
 		cfg.Sync.LoopThrottle = syncLoopThrottle
 	}
+	cfg.Sync.ParallelExecution = ctx.Bool(SyncParallelExecutionFlag.Name)
 
 	if ctx.String(BadBlockFlag.Name) != "" {
 		bytes, err := hexutil.Decode(ctx.String(BadBlockFlag.Name))
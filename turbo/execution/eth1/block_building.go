@@ -90,19 +90,6 @@ func (e *EthereumExecutionModule) AssembleBlock(ctx context.Context, req *execut
 	}, nil
 }
 
-// The expected value to be received by the feeRecipient in wei
-func blockValue(br *types.BlockWithReceipts, baseFee *uint256.Int) *uint256.Int {
-	blockValue := uint256.NewInt(0)
-	txs := br.Block.Transactions()
-	for i := range txs {
-		gas := new(uint256.Int).SetUint64(br.Receipts[i].GasUsed)
-		effectiveTip := txs[i].GetEffectiveGasTip(baseFee)
-		txValue := new(uint256.Int).Mul(gas, effectiveTip)
-		blockValue.Add(blockValue, txValue)
-	}
-	return blockValue
-}
-
 func (e *EthereumExecutionModule) GetAssembledBlock(ctx context.Context, req *execution.GetAssembledBlockRequest) (*execution.GetAssembledBlockResponse, error) {
 	if !e.semaphore.TryAcquire(1) {
 		return &execution.GetAssembledBlockResponse{
@@ -111,14 +98,14 @@ func (e *EthereumExecutionModule) GetAssembledBlock(ctx context.Context, req *ex
 	}
 	defer e.semaphore.Release(1)
 	payloadId := req.Id
-	builder, ok := e.builders[payloadId]
+	blockBuilder, ok := e.builders[payloadId]
 	if !ok {
 		return &execution.GetAssembledBlockResponse{
 			Busy: false,
 		}, nil
 	}
 
-	blockWithReceipts, err := builder.Stop()
+	blockWithReceipts, err := blockBuilder.Stop()
 	if err != nil {
 		e.logger.Error("Failed to build PoS block", "err", err)
 		return nil, err
@@ -162,7 +149,7 @@ func (e *EthereumExecutionModule) GetAssembledBlock(ctx context.Context, req *ex
 		payload.ExcessBlobGas = header.ExcessBlobGas
 	}
 
-	blockValue := blockValue(blockWithReceipts, baseFee)
+	blockValue := builder.BlockValue(blockWithReceipts, baseFee)
 
 	blobsBundle := &types2.BlobsBundleV1{}
 	for i, tx := range block.Transactions() {
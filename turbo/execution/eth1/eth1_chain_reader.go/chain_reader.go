@@ -12,6 +12,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/gointerfaces/execution"
 	types2 "github.com/ledgerwatch/erigon-lib/gointerfaces/types"
 	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/turbo/engineapi/engine_types"
 	"github.com/ledgerwatch/erigon/turbo/execution/eth1/eth1_utils"
 	"github.com/ledgerwatch/log/v3"
 	"google.golang.org/protobuf/types/known/emptypb"
@@ -320,3 +321,44 @@ func (c ChainReaderWriterEth1) GetForkchoice() (headHash, finalizedHash, safeHas
 	return gointerfaces.ConvertH256ToHash(resp.HeadBlockHash), gointerfaces.ConvertH256ToHash(resp.FinalizedBlockHash),
 		gointerfaces.ConvertH256ToHash(resp.SafeBlockHash), nil
 }
+
+func (c ChainReaderWriterEth1) AssembleBlock(head libcommon.Hash, attributes *engine_types.PayloadAttributes) (uint64, error) {
+	request := &execution.AssembleBlockRequest{
+		ParentHash:            gointerfaces.ConvertHashToH256(head),
+		Timestamp:             uint64(attributes.Timestamp),
+		PrevRandao:            gointerfaces.ConvertHashToH256(attributes.PrevRandao),
+		SuggestedFeeRecipient: gointerfaces.ConvertAddressToH160(attributes.SuggestedFeeRecipient),
+	}
+	if attributes.Withdrawals != nil {
+		request.Withdrawals = eth1_utils.ConvertWithdrawalsToRpc(attributes.Withdrawals)
+	}
+	if attributes.ParentBeaconBlockRoot != nil {
+		request.ParentBeaconBlockRoot = gointerfaces.ConvertHashToH256(*attributes.ParentBeaconBlockRoot)
+	}
+	resp, err := c.executionModule.AssembleBlock(c.ctx, request)
+	if err != nil {
+		return 0, err
+	}
+	if resp.Busy {
+		return 0, fmt.Errorf("execution module is busy, cannot assemble block")
+	}
+	return resp.Id, nil
+}
+
+func (c ChainReaderWriterEth1) GetAssembledBlock(id uint64) (*engine_types.ExecutionPayload, *engine_types.BlobsBundleV1, *big.Int, error) {
+	resp, err := c.executionModule.GetAssembledBlock(c.ctx, &execution.GetAssembledBlockRequest{
+		Id: id,
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if resp.Busy {
+		return nil, nil, nil, fmt.Errorf("execution module is busy, cannot retrieve assembled block")
+	}
+	if resp.Data == nil {
+		return nil, nil, nil, fmt.Errorf("no assembled block for payload id %d", id)
+	}
+	return engine_types.ConvertPayloadFromRpc(resp.Data.ExecutionPayload),
+		engine_types.ConvertBlobsFromRpc(resp.Data.BlobsBundle),
+		eth1_utils.ConvertBigIntFromRpc(resp.Data.BlockValue), nil
+}
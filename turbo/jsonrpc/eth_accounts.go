@@ -11,8 +11,6 @@ import (
 	"github.com/ledgerwatch/erigon-lib/gointerfaces"
 	"google.golang.org/grpc"
 
-	"github.com/ledgerwatch/erigon/turbo/rpchelper"
-
 	txpool_proto "github.com/ledgerwatch/erigon-lib/gointerfaces/txpool"
 
 	"github.com/ledgerwatch/erigon/common"
@@ -26,10 +24,11 @@ func (api *APIImpl) GetBalance(ctx context.Context, address libcommon.Address, b
 		return nil, fmt.Errorf("getBalance cannot open tx: %w", err1)
 	}
 	defer tx.Rollback()
-	reader, err := rpchelper.CreateStateReader(ctx, tx, blockNrOrHash, 0, api.filters, api.stateCache, api.historyV3(tx), "")
+	reader, release, err := api.createStateReader(ctx, tx, blockNrOrHash, 0, "")
 	if err != nil {
 		return nil, err
 	}
+	defer release()
 
 	acc, err := reader.ReadAccountData(address)
 	if err != nil {
@@ -62,10 +61,11 @@ func (api *APIImpl) GetTransactionCount(ctx context.Context, address libcommon.A
 		return nil, fmt.Errorf("getTransactionCount cannot open tx: %w", err1)
 	}
 	defer tx.Rollback()
-	reader, err := rpchelper.CreateStateReader(ctx, tx, blockNrOrHash, 0, api.filters, api.stateCache, api.historyV3(tx), "")
+	reader, release, err := api.createStateReader(ctx, tx, blockNrOrHash, 0, "")
 	if err != nil {
 		return nil, err
 	}
+	defer release()
 	nonce := hexutil.Uint64(0)
 	acc, err := reader.ReadAccountData(address)
 	if acc == nil || err != nil {
@@ -85,10 +85,11 @@ func (api *APIImpl) GetCode(ctx context.Context, address libcommon.Address, bloc
 	if err != nil {
 		return nil, fmt.Errorf("read chain config: %v", err)
 	}
-	reader, err := rpchelper.CreateStateReader(ctx, tx, blockNrOrHash, 0, api.filters, api.stateCache, api.historyV3(tx), chainConfig.ChainName)
+	reader, release, err := api.createStateReader(ctx, tx, blockNrOrHash, 0, chainConfig.ChainName)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
 
 	acc, err := reader.ReadAccountData(address)
 	if acc == nil || err != nil {
@@ -111,10 +112,11 @@ func (api *APIImpl) GetStorageAt(ctx context.Context, address libcommon.Address,
 	}
 	defer tx.Rollback()
 
-	reader, err := rpchelper.CreateStateReader(ctx, tx, blockNrOrHash, 0, api.filters, api.stateCache, api.historyV3(tx), "")
+	reader, release, err := api.createStateReader(ctx, tx, blockNrOrHash, 0, "")
 	if err != nil {
 		return hexutility.Encode(common.LeftPadBytes(empty, 32)), err
 	}
+	defer release()
 	acc, err := reader.ReadAccountData(address)
 	if acc == nil || err != nil {
 		return hexutility.Encode(common.LeftPadBytes(empty, 32)), err
@@ -136,10 +138,11 @@ func (api *APIImpl) Exist(ctx context.Context, address libcommon.Address, blockN
 	}
 	defer tx.Rollback()
 
-	reader, err := rpchelper.CreateStateReader(ctx, tx, blockNrOrHash, 0, api.filters, api.stateCache, api.historyV3(tx), "")
+	reader, release, err := api.createStateReader(ctx, tx, blockNrOrHash, 0, "")
 	if err != nil {
 		return false, err
 	}
+	defer release()
 	acc, err := reader.ReadAccountData(address)
 	if err != nil {
 		return false, err
@@ -92,7 +92,11 @@ type EthAPI interface {
 	// Sending related (see ./eth_call.go)
 	Call(ctx context.Context, args ethapi2.CallArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *ethapi2.StateOverrides) (hexutility.Bytes, error)
 	EstimateGas(ctx context.Context, argsOrNil *ethapi2.CallArgs, blockNrOrHash *rpc.BlockNumberOrHash) (hexutil.Uint64, error)
+	CallBundleV2(ctx context.Context, encodedTxs []hexutility.Bytes, stateBlockNumberOrHash rpc.BlockNumberOrHash, blockOverrides *BlockOverrides, timeoutMilliSecondsPtr *int64) (*CallBundleResult, error)
 	SendRawTransaction(ctx context.Context, encodedTx hexutility.Bytes) (common.Hash, error)
+	SendPrivateRawTransaction(ctx context.Context, encodedTx hexutility.Bytes, expiryBlocks *hexutil.Uint64) (common.Hash, error)
+	SendRawTransactionConditional(ctx context.Context, encodedTx hexutility.Bytes, options TransactionConditions) (common.Hash, error)
+	SendBundle(ctx context.Context, args SendBundleArgs) (*SendBundleResult, error)
 	SendTransaction(_ context.Context, txObject interface{}) (common.Hash, error)
 	Sign(ctx context.Context, _ common.Address, _ hexutility.Bytes) (hexutility.Bytes, error)
 	SignTransaction(_ context.Context, txObject interface{}) (common.Hash, error)
@@ -124,6 +128,8 @@ type BaseAPI struct {
 
 	evmCallTimeout time.Duration
 	dirs           datadir.Dirs
+
+	pending pendingStateCache
 }
 
 func NewBaseApi(f *rpchelper.Filters, stateCache kvcache.Cache, blockReader services.FullBlockReader, agg *libstate.AggregatorV3, singleNodeMode bool, evmCallTimeout time.Duration, engine consensus.EngineReader, dirs datadir.Dirs) *BaseAPI {
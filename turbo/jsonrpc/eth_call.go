@@ -65,13 +65,19 @@ func (api *APIImpl) Call(ctx context.Context, args ethapi2.CallArgs, blockNrOrHa
 		return nil, err
 	}
 	if block == nil {
-		return nil, nil
+		// the pending block is not in the db
+		pending := api.pendingBlock()
+		if pending == nil || pending.Hash() != hash {
+			return nil, nil
+		}
+		block = pending
 	}
 
-	stateReader, err := rpchelper.CreateStateReader(ctx, tx, blockNrOrHash, 0, api.filters, api.stateCache, api.historyV3(tx), chainConfig.ChainName)
+	stateReader, release, err := api.createStateReader(ctx, tx, blockNrOrHash, 0, chainConfig.ChainName)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
 	header := block.HeaderNoCopy()
 	result, err := transactions.DoCall(ctx, engine, args, tx, blockNrOrHash, header, overrides, api.GasCap, chainConfig, stateReader, api._blockReader, api.evmCallTimeout)
 	if err != nil {
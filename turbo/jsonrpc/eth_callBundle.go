@@ -0,0 +1,250 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+
+	"github.com/ledgerwatch/erigon/accounts/abi"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/common/math"
+	"github.com/ledgerwatch/erigon/consensus/misc"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/crypto/cryptopool"
+	"github.com/ledgerwatch/erigon/eth/tracers"
+	_ "github.com/ledgerwatch/erigon/eth/tracers/native"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
+	"github.com/ledgerwatch/erigon/turbo/transactions"
+)
+
+// CallBundleTxResult is the result of a transaction of eth_callBundleV2
+type CallBundleTxResult struct {
+	TxHash            common.Hash      `json:"txHash"`
+	FromAddress       common.Address   `json:"fromAddress"`
+	ToAddress         *common.Address  `json:"toAddress"`
+	GasUsed           hexutil.Uint64   `json:"gasUsed"`
+	GasPrice          *hexutil.Big     `json:"gasPrice"`          // effective gas price
+	GasFees           *hexutil.Big     `json:"gasFees"`           // priority fees paid to the coinbase
+	EthSentToCoinbase *hexutil.Big     `json:"ethSentToCoinbase"` // paid to the coinbase, other than the fees
+	CoinbaseDiff      *hexutil.Big     `json:"coinbaseDiff"`      // gasFees + ethSentToCoinbase
+	Logs              []*types.Log     `json:"logs"`
+	StateDiff         json.RawMessage  `json:"stateDiff"` // pre and post state of the accounts changed, as the prestate tracer in diff mode
+	Value             hexutility.Bytes `json:"value,omitempty"`
+	Error             string           `json:"error,omitempty"`
+	Revert            hexutility.Bytes `json:"revert,omitempty"`
+	RevertReason      string           `json:"revertReason,omitempty"`
+}
+
+// CallBundleResult is the result of eth_callBundleV2
+type CallBundleResult struct {
+	BundleHash        common.Hash           `json:"bundleHash"`
+	BundleGasPrice    *hexutil.Big          `json:"bundleGasPrice"` // coinbaseDiff / totalGasUsed
+	CoinbaseDiff      *hexutil.Big          `json:"coinbaseDiff"`
+	EthSentToCoinbase *hexutil.Big          `json:"ethSentToCoinbase"`
+	GasFees           *hexutil.Big          `json:"gasFees"`
+	StateBlockNumber  hexutil.Uint64        `json:"stateBlockNumber"`
+	TotalGasUsed      hexutil.Uint64        `json:"totalGasUsed"`
+	Results           []*CallBundleTxResult `json:"results"`
+}
+
+// CallBundleV2 implements eth_callBundleV2. It executes the signed transactions of a bundle, in order, on top of the
+// state of the given block, in a block built by its coinbase after it (unless overridden by blockOverrides). Unlike
+// eth_callBundle, the transactions are not looked up by hash, and the nonces are checked. For each transaction it
+// returns the gas used, what the coinbase is paid, the logs, the revert reason and the state diff.
+func (api *APIImpl) CallBundleV2(ctx context.Context, encodedTxs []hexutility.Bytes, stateBlockNumberOrHash rpc.BlockNumberOrHash, blockOverrides *BlockOverrides, timeoutMilliSecondsPtr *int64) (*CallBundleResult, error) {
+	if len(encodedTxs) == 0 {
+		return nil, errors.New("bundle missing txs")
+	}
+	if len(encodedTxs) > maxBundleTxs {
+		return nil, fmt.Errorf("too many bundle txs: %d, max: %d", len(encodedTxs), maxBundleTxs)
+	}
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	chainConfig, err := api.chainConfig(tx)
+	if err != nil {
+		return nil, err
+	}
+	engine := api.engine()
+
+	txs := make(types.Transactions, len(encodedTxs))
+	for i, encodedTx := range encodedTxs {
+		if txs[i], err = types.DecodeWrappedTransaction(encodedTx); err != nil {
+			return nil, fmt.Errorf("bundle tx %d: %w", i, err)
+		}
+	}
+	defer func(start time.Time) { log.Trace("Executing EVM callBundleV2 finished", "runtime", time.Since(start)) }(time.Now())
+
+	stateBlockNumber, hash, _, err := rpchelper.GetBlockNumber(stateBlockNumberOrHash, tx, api.filters)
+	if err != nil {
+		return nil, err
+	}
+	stateReader, err := rpchelper.CreateStateReader(ctx, tx, stateBlockNumberOrHash, 0, api.filters, api.stateCache, api.historyV3(tx), chainConfig.ChainName)
+	if err != nil {
+		return nil, err
+	}
+	ibs := state.New(stateReader)
+
+	parent, _ := api.headerByRPCNumber(rpc.BlockNumber(stateBlockNumber), tx)
+	if parent == nil {
+		return nil, fmt.Errorf("block %d(%x) not found", stateBlockNumber, hash)
+	}
+	blockNumber := stateBlockNumber + 1
+	timestamp := parent.Time + clparams.MainnetBeaconConfig.SecondsPerSlot
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     big.NewInt(int64(blockNumber)),
+		GasLimit:   parent.GasLimit,
+		Time:       timestamp,
+		Difficulty: parent.Difficulty,
+		Coinbase:   parent.Coinbase,
+	}
+	if chainConfig.IsLondon(blockNumber) {
+		header.BaseFee = misc.CalcBaseFee(chainConfig, parent)
+	}
+	if chainConfig.IsCancun(timestamp) {
+		excessBlobGas := misc.CalcExcessBlobGas(chainConfig, parent)
+		header.ExcessBlobGas = &excessBlobGas
+	}
+
+	blockCtx := transactions.NewEVMBlockContext(engine, header, stateBlockNumberOrHash.RequireCanonical, tx, api._blockReader)
+	if blockOverrides != nil {
+		overrideBlockHash := make(map[uint64]common.Hash)
+		blockHeaderOverride(&blockCtx, *blockOverrides, overrideBlockHash)
+		getHash := blockCtx.GetHash
+		blockCtx.GetHash = func(i uint64) common.Hash {
+			if hash, ok := overrideBlockHash[i]; ok {
+				return hash
+			}
+			return getHash(i)
+		}
+	}
+	coinbase := blockCtx.Coinbase
+	signer := types.MakeSigner(chainConfig, blockCtx.BlockNumber, blockCtx.Time)
+	rules := chainConfig.Rules(blockCtx.BlockNumber, blockCtx.Time)
+	evm := vm.NewEVM(blockCtx, core.NewEVMTxContext(types.Message{}), ibs, chainConfig, vm.Config{})
+
+	timeoutMilliSeconds := int64(5000)
+	if timeoutMilliSecondsPtr != nil {
+		timeoutMilliSeconds = *timeoutMilliSecondsPtr
+	}
+	timeout := time.Millisecond * time.Duration(timeoutMilliSeconds)
+	// Setup context so it may be cancelled the call has completed
+	// or, in case of unmetered gas, setup a context with a timeout.
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	// Make sure the context is cancelled when the call has completed
+	// this makes sure resources are cleaned up.
+	defer cancel()
+
+	// Wait for the context to be done and cancel the evm. Even if the
+	// EVM has finished, cancelling may be done (repeatedly)
+	go func() {
+		<-ctx.Done()
+		evm.Cancel()
+	}()
+
+	gp := new(core.GasPool).AddGas(math.MaxUint64).AddBlobGas(math.MaxUint64)
+
+	bundleHash := cryptopool.NewLegacyKeccak256()
+	defer cryptopool.ReturnToPoolKeccak256(bundleHash)
+
+	ret := &CallBundleResult{StateBlockNumber: hexutil.Uint64(stateBlockNumber)}
+	var totalGasFees, totalCoinbaseDiff, totalGasUsed big.Int
+	for i, txn := range txs {
+		msg, err := txn.AsMessage(*signer, blockCtx.BaseFee.ToBig(), rules)
+		if err != nil {
+			return nil, fmt.Errorf("bundle tx %d: %w", i, err)
+		}
+		tracer, err := tracers.New("prestateTracer", &tracers.Context{TxHash: txn.Hash(), TxIndex: i}, json.RawMessage(`{"diffMode":true}`))
+		if err != nil {
+			return nil, err
+		}
+		ibs.SetTxContext(txn.Hash(), common.Hash{}, i)
+		evm.ResetBetweenBlocks(blockCtx, core.NewEVMTxContext(msg), ibs, vm.Config{Debug: true, Tracer: tracer}, rules)
+
+		coinbaseBefore := ibs.GetBalance(coinbase).Clone()
+		result, err := core.ApplyMessage(evm, msg, gp, true /* refunds */, false /* gasBailout */)
+		if err != nil {
+			return nil, fmt.Errorf("bundle tx %d: %w", i, err)
+		}
+		// If the timer caused an abort, return an appropriate error message
+		if evm.Cancelled() || ctx.Err() != nil {
+			return nil, fmt.Errorf("execution aborted (timeout = %v)", timeout)
+		}
+		if err = ibs.FinalizeTx(rules, state.NewNoopWriter()); err != nil {
+			return nil, err
+		}
+		stateDiff, err := tracer.GetResult()
+		if err != nil {
+			return nil, err
+		}
+
+		gasFees := new(big.Int).SetUint64(result.UsedGas)
+		gasFees.Mul(gasFees, txn.GetEffectiveGasTip(blockCtx.BaseFee).ToBig())
+		coinbaseDiff := new(big.Int).Sub(ibs.GetBalance(coinbase).ToBig(), coinbaseBefore.ToBig())
+		txResult := &CallBundleTxResult{
+			TxHash:            txn.Hash(),
+			FromAddress:       msg.From(),
+			ToAddress:         txn.GetTo(),
+			GasUsed:           hexutil.Uint64(result.UsedGas),
+			GasPrice:          (*hexutil.Big)(msg.GasPrice().ToBig()),
+			GasFees:           (*hexutil.Big)(gasFees),
+			EthSentToCoinbase: (*hexutil.Big)(new(big.Int).Sub(coinbaseDiff, gasFees)),
+			CoinbaseDiff:      (*hexutil.Big)(coinbaseDiff),
+			Logs:              ibs.GetLogs(txn.Hash()),
+			StateDiff:         stateDiff,
+		}
+		if txResult.Logs == nil {
+			txResult.Logs = []*types.Log{}
+		}
+		if result.Err != nil {
+			txResult.Error = result.Err.Error()
+			if len(result.Revert()) > 0 {
+				txResult.Revert = result.Revert()
+				if reason, errUnpack := abi.UnpackRevert(result.Revert()); errUnpack == nil {
+					txResult.RevertReason = reason
+				}
+			}
+		} else {
+			txResult.Value = result.Return()
+		}
+		ret.Results = append(ret.Results, txResult)
+
+		bundleHash.Write(txn.Hash().Bytes())
+		totalGasUsed.Add(&totalGasUsed, new(big.Int).SetUint64(result.UsedGas))
+		totalGasFees.Add(&totalGasFees, gasFees)
+		totalCoinbaseDiff.Add(&totalCoinbaseDiff, coinbaseDiff)
+	}
+
+	ret.BundleHash = common.BytesToHash(bundleHash.Sum(nil))
+	ret.TotalGasUsed = hexutil.Uint64(totalGasUsed.Uint64())
+	ret.GasFees = (*hexutil.Big)(&totalGasFees)
+	ret.CoinbaseDiff = (*hexutil.Big)(&totalCoinbaseDiff)
+	ret.EthSentToCoinbase = (*hexutil.Big)(new(big.Int).Sub(&totalCoinbaseDiff, &totalGasFees))
+	ret.BundleGasPrice = (*hexutil.Big)(new(big.Int))
+	if totalGasUsed.Sign() > 0 {
+		ret.BundleGasPrice = (*hexutil.Big)(new(big.Int).Div(&totalCoinbaseDiff, &totalGasUsed))
+	}
+	return ret, nil
+}
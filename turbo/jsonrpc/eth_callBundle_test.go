@@ -0,0 +1,139 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/datadir"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
+
+	"github.com/ledgerwatch/erigon/accounts/abi"
+	"github.com/ledgerwatch/erigon/accounts/abi/bind"
+	"github.com/ledgerwatch/erigon/accounts/abi/bind/backends"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/rpc/rpccfg"
+	"github.com/ledgerwatch/erigon/turbo/jsonrpc/contracts"
+)
+
+func TestCallBundleV2(t *testing.T) {
+	var (
+		key, _   = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		key1, _  = crypto.HexToECDSA("49a7b37aa6f6645917e7b807e9d1c00d4fa71f18343b0d4122a4d2df64dd6fee")
+		key2, _  = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+		address  = crypto.PubkeyToAddress(key.PublicKey)
+		address1 = crypto.PubkeyToAddress(key1.PublicKey)
+		address2 = crypto.PubkeyToAddress(key2.PublicKey)
+		gspec    = &types.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: types.GenesisAlloc{
+				address:  {Balance: big.NewInt(9000000000000000000)},
+				address1: {Balance: big.NewInt(200000000000000000)},
+				address2: {Balance: big.NewInt(300000000000000000)},
+			},
+			GasLimit: 10000000,
+		}
+		chainID  = big.NewInt(1337)
+		ctx      = context.Background()
+		coinbase = libcommon.HexToAddress("0xc0ffee")
+	)
+
+	// block 1: deploy the token, mint 100 to address 2, and transfer them to address 1
+	transactOpts, _ := bind.NewKeyedTransactorWithChainID(key, chainID)
+	transactOpts1, _ := bind.NewKeyedTransactorWithChainID(key1, chainID)
+	transactOpts2, _ := bind.NewKeyedTransactorWithChainID(key2, chainID)
+	contractBackend := backends.NewTestSimulatedBackendWithConfig(t, gspec.Alloc, gspec.Config, gspec.GasLimit)
+	defer contractBackend.Close()
+	tokenAddr, _, tokenContract, err := contracts.DeployToken(transactOpts, contractBackend, address1)
+	require.NoError(t, err)
+	_, err = tokenContract.Mint(transactOpts1, address2, big.NewInt(100))
+	require.NoError(t, err)
+	_, err = tokenContract.Transfer(transactOpts2, address1, big.NewInt(100))
+	require.NoError(t, err)
+	contractBackend.Commit()
+
+	api := NewEthAPI(NewBaseApi(nil, kvcache.New(kvcache.DefaultCoherentConfig), contractBackend.BlockReader(), contractBackend.Agg(), false, rpccfg.DefaultEvmCallTimeout, contractBackend.Engine(),
+		datadir.New(t.TempDir())), contractBackend.DB(), nil, nil, nil, 5000000, 100_000, false, 100_000, log.New())
+
+	tokenABI, err := abi.JSON(strings.NewReader(contracts.TokenABI))
+	require.NoError(t, err)
+	signer := types.LatestSignerForChainID(chainID)
+	gasPrice := uint256.NewInt(10 * params.GWei)
+	encode := func(txn types.Transaction, key *ecdsa.PrivateKey) hexutility.Bytes {
+		signed, err := types.SignTx(txn, *signer, key)
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		require.NoError(t, signed.MarshalBinary(&buf))
+		return buf.Bytes()
+	}
+	transfer := func(to libcommon.Address, value int64) []byte {
+		data, err := tokenABI.Pack("transfer", to, big.NewInt(value))
+		require.NoError(t, err)
+		return data
+	}
+	txs := []hexutility.Bytes{
+		// address 1 sends 40 tokens back to address 2
+		encode(types.NewTransaction(1, tokenAddr, uint256.NewInt(0), 100_000, gasPrice, transfer(address2, 40)), key1),
+		// address 2 only has 40 tokens
+		encode(types.NewTransaction(1, tokenAddr, uint256.NewInt(0), 100_000, gasPrice, transfer(address1, 100)), key2),
+		// address pays the coinbase directly
+		encode(types.NewTransaction(1, coinbase, uint256.NewInt(params.GWei), 21_000, gasPrice, nil), key),
+	}
+
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	res, err := api.CallBundleV2(ctx, txs, latest, &BlockOverrides{Coinbase: &coinbase}, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), uint64(res.StateBlockNumber))
+	require.Len(t, res.Results, 3)
+
+	totalGasUsed := uint64(0)
+	for _, r := range res.Results {
+		totalGasUsed += uint64(r.GasUsed)
+		require.Equal(t, new(big.Int).Add(r.GasFees.ToInt(), r.EthSentToCoinbase.ToInt()), r.CoinbaseDiff.ToInt())
+	}
+	require.Equal(t, totalGasUsed, uint64(res.TotalGasUsed))
+
+	ok := res.Results[0]
+	require.Equal(t, address1, ok.FromAddress)
+	require.Equal(t, tokenAddr, *ok.ToAddress)
+	require.Empty(t, ok.Error)
+	require.Equal(t, libcommon.BigToHash(big.NewInt(1)).Bytes(), []byte(ok.Value))
+	require.Zero(t, ok.EthSentToCoinbase.ToInt().Sign())
+	var diff struct {
+		Pre  map[libcommon.Address]json.RawMessage `json:"pre"`
+		Post map[libcommon.Address]struct {
+			Storage map[libcommon.Hash]libcommon.Hash `json:"storage"`
+		} `json:"post"`
+	}
+	require.NoError(t, json.Unmarshal(ok.StateDiff, &diff))
+	require.Contains(t, diff.Pre, tokenAddr)
+	require.Contains(t, diff.Post, tokenAddr)
+	require.Len(t, diff.Post[tokenAddr].Storage, 2) // both token balances changed
+
+	reverted := res.Results[1]
+	require.Equal(t, "execution reverted", reverted.Error)
+	require.Empty(t, reverted.Value)
+	require.NotZero(t, reverted.GasFees.ToInt().Sign())
+
+	payment := res.Results[2]
+	require.Equal(t, uint64(21_000), uint64(payment.GasUsed))
+	require.Equal(t, big.NewInt(params.GWei), payment.EthSentToCoinbase.ToInt())
+	require.Equal(t, big.NewInt(params.GWei), res.EthSentToCoinbase.ToInt())
+
+	// the nonces are checked against the state block: at genesis, the bundle is invalid
+	_, err = api.CallBundleV2(ctx, txs, rpc.BlockNumberOrHashWithNumber(0), nil, nil)
+	require.ErrorContains(t, err, "nonce too high")
+}
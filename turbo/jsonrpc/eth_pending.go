@@ -0,0 +1,190 @@
+package jsonrpc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/membatchwithdb"
+
+	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/eth/filters"
+	"github.com/ledgerwatch/erigon/eth/stagedsync"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
+)
+
+// pendingState is the execution result of a pending block: its receipts, and its state changes kept as the diff of a
+// memory batch. The diff applies on top of any later tx, as long as the parent of the block is the latest executed one.
+type pendingState struct {
+	block    *types.Block
+	receipts types.Receipts
+	diff     *membatchwithdb.MemoryDiff
+}
+
+// pendingStateCache keeps the execution result of the last pending block, computed by the first request needing it
+type pendingStateCache struct {
+	lock sync.Mutex
+	last *pendingState
+}
+
+// pendingState returns the execution result of the last pending block, or nil if there is no pending block on top of
+// the latest executed block
+func (api *BaseAPI) pendingState(ctx context.Context, tx kv.Tx) (*pendingState, error) {
+	if api.filters == nil {
+		return nil, nil
+	}
+	block := api.pendingBlock()
+	if block == nil {
+		return nil, nil
+	}
+	executed, err := stages.GetStageProgress(tx, stages.Execution)
+	if err != nil {
+		return nil, err
+	}
+	if block.NumberU64() != executed+1 {
+		return nil, nil
+	}
+	parentHash, err := rawdb.ReadCanonicalHash(tx, executed)
+	if err != nil {
+		return nil, err
+	}
+	if parentHash != block.ParentHash() {
+		return nil, nil
+	}
+
+	api.pending.lock.Lock()
+	defer api.pending.lock.Unlock()
+	if last := api.pending.last; last != nil && last.block.Hash() == block.Hash() {
+		return last, nil
+	}
+	pending, err := api.executePendingBlock(ctx, tx, block)
+	if err != nil {
+		return nil, err
+	}
+	api.pending.last = pending
+	return pending, nil
+}
+
+func (api *BaseAPI) executePendingBlock(ctx context.Context, tx kv.Tx, block *types.Block) (*pendingState, error) {
+	chainConfig, err := api.chainConfig(tx)
+	if err != nil {
+		return nil, err
+	}
+	batch := membatchwithdb.NewMemoryBatch(tx, api.dirs.Tmp)
+	defer batch.Close()
+
+	header := block.HeaderNoCopy()
+	stateReader := rpchelper.NewLatestStateReader(batch)
+	stateWriter := state.NewPlainStateWriterNoHistory(batch)
+	ibs := state.New(stateReader)
+	getHeader := func(hash common.Hash, number uint64) *types.Header {
+		h, e := api._blockReader.Header(ctx, tx, hash, number)
+		if e != nil {
+			log.Error("getHeader error", "number", number, "hash", hash, "err", e)
+		}
+		return h
+	}
+	// block rewards, withdrawals and system calls need the full engine, which a remote rpcdaemon may not have
+	engine, fullEngine := api.engine().(consensus.Engine)
+	chainReader := stagedsync.NewChainReaderImpl(chainConfig, tx, api._blockReader, log.Root())
+	if fullEngine {
+		if err := core.InitializeBlockExecution(engine, chainReader, header, chainConfig, ibs, log.Root()); err != nil {
+			return nil, err
+		}
+	}
+
+	usedGas := new(uint64)
+	usedBlobGas := new(uint64)
+	gp := new(core.GasPool).AddGas(block.GasLimit()).AddBlobGas(chainConfig.GetMaxBlobGasPerBlock())
+	receipts := make(types.Receipts, len(block.Transactions()))
+	for i, txn := range block.Transactions() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		ibs.SetTxContext(txn.Hash(), block.Hash(), i)
+		receipt, _, err := core.ApplyTransaction(chainConfig, core.GetHashFn(header, getHeader), api.engine(), nil, gp, ibs, stateWriter, header, txn, usedGas, usedBlobGas, vm.Config{})
+		if err != nil {
+			return nil, fmt.Errorf("pending transaction %x: %w", txn.Hash(), err)
+		}
+		receipt.BlockHash = block.Hash()
+		receipts[i] = receipt
+	}
+	if fullEngine {
+		if _, _, _, err := core.FinalizeBlockExecution(engine, stateReader, header, block.Transactions(), block.Uncles(), stateWriter, chainConfig, ibs, receipts, block.Withdrawals(), chainReader, false, log.Root()); err != nil {
+			return nil, err
+		}
+	}
+
+	diff, err := batch.Diff()
+	if err != nil {
+		return nil, err
+	}
+	return &pendingState{block: block, receipts: receipts, diff: diff}, nil
+}
+
+// overlay returns a memory batch over tx with the state changes of the pending block applied
+func (s *pendingState) overlay(tx kv.Tx, tmpDir string) (*membatchwithdb.MemoryMutation, error) {
+	batch := membatchwithdb.NewMemoryBatch(tx, tmpDir)
+	if err := s.diff.Flush(batch); err != nil {
+		batch.Close()
+		return nil, err
+	}
+	return batch, nil
+}
+
+// receipt returns the receipt of a transaction of the pending block, with the transaction, or nil if it is not there
+func (s *pendingState) receipt(txnHash common.Hash) (*types.Receipt, types.Transaction) {
+	for i, txn := range s.block.Transactions() {
+		if txn.Hash() == txnHash {
+			return s.receipts[i], txn
+		}
+	}
+	return nil, nil
+}
+
+// logs returns the logs of the pending block matching the criteria
+func (s *pendingState) logs(crit filters.FilterCriteria) types.Logs {
+	addrMap := make(map[common.Address]struct{}, len(crit.Addresses))
+	for _, v := range crit.Addresses {
+		addrMap[v] = struct{}{}
+	}
+	var logs types.Logs
+	for _, receipt := range s.receipts {
+		logs = append(logs, types.Logs(receipt.Logs).Filter(addrMap, crit.Topics)...)
+	}
+	return logs
+}
+
+// createStateReader is rpchelper.CreateStateReader, except that "pending" reads the state after the pending block when
+// it is on top of the latest executed block. The returned function releases the reader, once it is not used anymore.
+func (api *BaseAPI) createStateReader(ctx context.Context, tx kv.Tx, blockNrOrHash rpc.BlockNumberOrHash, txnIndex int, chainName string) (state.StateReader, func(), error) {
+	if blockNrOrHash.BlockNumber != nil && *blockNrOrHash.BlockNumber == rpc.PendingBlockNumber {
+		pending, err := api.pendingState(ctx, tx)
+		if err != nil {
+			return nil, nil, err
+		}
+		if pending != nil {
+			overlay, err := pending.overlay(tx, api.dirs.Tmp)
+			if err != nil {
+				return nil, nil, err
+			}
+			return rpchelper.NewLatestStateReader(overlay), overlay.Close, nil
+		}
+	}
+	reader, err := rpchelper.CreateStateReader(ctx, tx, blockNrOrHash, txnIndex, api.filters, api.stateCache, api.historyV3(tx), chainName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return reader, func() {}, nil
+}
@@ -0,0 +1,97 @@
+package jsonrpc
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/txpool"
+	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/filters"
+	"github.com/ledgerwatch/erigon/rlp"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/rpc/rpccfg"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
+	"github.com/ledgerwatch/erigon/turbo/stages/mock"
+)
+
+func TestPendingState(t *testing.T) {
+	m := mock.Mock(t)
+	ctx := context.Background()
+	ff := rpchelper.New(ctx, nil, nil, nil, func() {}, m.Log)
+	api := NewEthAPI(NewBaseApi(ff, kvcache.New(kvcache.DefaultCoherentConfig), m.BlockReader, m.HistoryV3Components(), false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs), m.DB, nil, nil, nil, 5000000, 100_000, false, 100_000, log.New())
+
+	to := libcommon.HexToAddress("0x0d3ab14bbad3d99f4203bd7a11acb94882050e7e")
+	signer := types.LatestSignerForChainID(m.ChainConfig.ChainID)
+	gasPrice := uint256.NewInt(2 * 1e9)
+	transfer, err := types.SignTx(types.NewTransaction(0, to, uint256.NewInt(1000), 21000, gasPrice, nil), *signer, m.Key)
+	require.NoError(t, err)
+	// PUSH1 0 PUSH1 0 LOG0 STOP
+	creation, err := types.SignTx(types.NewContractCreation(1, uint256.NewInt(0), 100_000, gasPrice, []byte{0x60, 0x00, 0x60, 0x00, 0xa0, 0x00}), *signer, m.Key)
+	require.NoError(t, err)
+
+	// the pending block is built on top of genesis, and not inserted
+	chain, err := core.GenerateChain(m.ChainConfig, m.Genesis, m.Engine, m.DB, 1, func(i int, gen *core.BlockGen) {
+		gen.SetCoinbase(libcommon.Address{1})
+		gen.AddTx(transfer)
+		gen.AddTx(creation)
+	})
+	require.NoError(t, err)
+	rlpBlock, err := rlp.EncodeToBytes(chain.TopBlock)
+	require.NoError(t, err)
+	ff.HandlePendingBlock(&txpool.OnPendingBlockReply{RplBlock: rlpBlock})
+
+	pending := rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber)
+	balance, err := api.GetBalance(ctx, to, pending)
+	require.NoError(t, err)
+	require.Equal(t, (*hexutil.Big)(big.NewInt(1000)), balance)
+	balance, err = api.GetBalance(ctx, to, latestNumOrHash)
+	require.NoError(t, err)
+	require.Equal(t, (*hexutil.Big)(big.NewInt(0)), balance)
+
+	receipt, err := api.GetTransactionReceipt(ctx, creation.Hash())
+	require.NoError(t, err)
+	require.NotNil(t, receipt)
+	require.Equal(t, hexutil.Uint64(types.ReceiptStatusSuccessful), receipt["status"])
+	require.Equal(t, hexutil.Uint64(1), receipt["transactionIndex"])
+	contract := receipt["contractAddress"].(libcommon.Address)
+
+	code, err := api.GetCode(ctx, contract, pending)
+	require.NoError(t, err)
+	require.Empty(t, code)
+	receipts, err := api.GetBlockReceipts(ctx, pending)
+	require.NoError(t, err)
+	require.Len(t, receipts, 2)
+
+	toPending := big.NewInt(PendingBlockNumber)
+	logs, err := api.GetLogs(ctx, filters.FilterCriteria{ToBlock: toPending})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	require.Equal(t, contract, logs[0].Address)
+	require.Equal(t, chain.TopBlock.Hash(), logs[0].BlockHash)
+	logs, err = api.GetLogs(ctx, filters.FilterCriteria{FromBlock: toPending, ToBlock: toPending, Addresses: []libcommon.Address{to}})
+	require.NoError(t, err)
+	require.Empty(t, logs)
+
+	// a pending block which is not on top of the latest executed block is not applied
+	require.NoError(t, m.InsertChain(chain))
+	stale, err := core.GenerateChain(m.ChainConfig, m.Genesis, m.Engine, m.DB, 1, func(i int, gen *core.BlockGen) {
+		gen.SetCoinbase(libcommon.Address{2})
+	})
+	require.NoError(t, err)
+	rlpBlock, err = rlp.EncodeToBytes(stale.TopBlock)
+	require.NoError(t, err)
+	ff.HandlePendingBlock(&txpool.OnPendingBlockReply{RplBlock: rlpBlock})
+	balance, err = api.GetBalance(ctx, to, pending)
+	require.NoError(t, err)
+	require.Equal(t, (*hexutil.Big)(big.NewInt(1000)), balance)
+}
@@ -161,6 +161,29 @@ func (api *APIImpl) GetLogs(ctx context.Context, crit filters.FilterCriteria) (t
 		end = latest
 	}
 
+	if crit.BlockHash == nil && crit.ToBlock != nil && crit.ToBlock.Int64() == PendingBlockNumber {
+		pending, err := api.pendingState(ctx, tx)
+		if err != nil {
+			return nil, err
+		}
+		if pending != nil {
+			// the logs of the pending block are not in the db
+			if begin == end {
+				return pending.logs(crit), nil
+			}
+			logs, err := api.getLogs(ctx, tx, begin, end-1, crit)
+			if err != nil {
+				return nil, err
+			}
+			return append(logs, pending.logs(crit)...), nil
+		}
+	}
+	return api.getLogs(ctx, tx, begin, end, crit)
+}
+
+// getLogs returns the logs of the blocks in [begin, end] matching the criteria
+func (api *APIImpl) getLogs(ctx context.Context, tx kv.Tx, begin, end uint64, crit filters.FilterCriteria) (types.Logs, error) {
+	logs := types.Logs{}
 	if api.historyV3(tx) {
 		return api.getLogsV3(ctx, tx.(kv.TemporalTx), begin, end, crit)
 	}
@@ -636,7 +659,15 @@ func (api *APIImpl) GetTransactionReceipt(ctx context.Context, txnHash common.Ha
 	}
 
 	if !ok {
-		return nil, nil
+		pending, err := api.pendingState(ctx, tx)
+		if err != nil || pending == nil {
+			return nil, err
+		}
+		receipt, txn := pending.receipt(txnHash)
+		if receipt == nil {
+			return nil, nil
+		}
+		return marshalReceipt(receipt, txn, cc, pending.block.HeaderNoCopy(), txnHash, true), nil
 	}
 
 	block, err := api.blockByNumberWithSenders(tx, blockNum)
@@ -703,16 +734,26 @@ func (api *APIImpl) GetBlockReceipts(ctx context.Context, numberOrHash rpc.Block
 	if err != nil {
 		return nil, err
 	}
-	if block == nil {
-		return nil, nil
-	}
 	chainConfig, err := api.chainConfig(tx)
 	if err != nil {
 		return nil, err
 	}
-	receipts, err := api.getReceipts(ctx, tx, chainConfig, block, block.Body().SendersFromTxs())
-	if err != nil {
-		return nil, fmt.Errorf("getReceipts error: %w", err)
+	var receipts types.Receipts
+	if block == nil {
+		// the pending block is not in the db
+		if numberOrHash.BlockNumber == nil || *numberOrHash.BlockNumber != rpc.PendingBlockNumber {
+			return nil, nil
+		}
+		pending, err := api.pendingState(ctx, tx)
+		if err != nil || pending == nil {
+			return nil, err
+		}
+		block, receipts = pending.block, pending.receipts
+	} else {
+		receipts, err = api.getReceipts(ctx, tx, chainConfig, block, block.Body().SendersFromTxs())
+		if err != nil {
+			return nil, fmt.Errorf("getReceipts error: %w", err)
+		}
 	}
 	result := make([]map[string]interface{}, 0, len(receipts))
 	for _, receipt := range receipts {
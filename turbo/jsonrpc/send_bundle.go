@@ -0,0 +1,81 @@
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces"
+	"github.com/ledgerwatch/erigon-lib/txpool"
+
+	"github.com/ledgerwatch/erigon/core/types"
+)
+
+// maxBundleTxs is the maximum number of transactions in a bundle
+const maxBundleTxs = 100
+
+// SendBundleArgs are the arguments of eth_sendBundle, as in the Flashbots API
+type SendBundleArgs struct {
+	Txs               []hexutility.Bytes `json:"txs"`
+	BlockNumber       hexutil.Uint64     `json:"blockNumber"`
+	MinTimestamp      *uint64            `json:"minTimestamp,omitempty"`
+	MaxTimestamp      *uint64            `json:"maxTimestamp,omitempty"`
+	RevertingTxHashes []common.Hash      `json:"revertingTxHashes,omitempty"`
+}
+
+// SendBundleResult is the result of eth_sendBundle
+type SendBundleResult struct {
+	BundleHash common.Hash `json:"bundleHash"`
+}
+
+// SendBundle implements eth_sendBundle. The transactions of the bundle are only included together, in order, at the
+// top of the given block (the next one if 0) built by this node, and only if none of them fails or reverts, except for
+// the ones in revertingTxHashes. The bundle is dropped once the block is mined. Bundles are never announced to peers.
+func (api *APIImpl) SendBundle(ctx context.Context, args SendBundleArgs) (*SendBundleResult, error) {
+	if len(args.Txs) == 0 {
+		return nil, errors.New("bundle missing txs")
+	}
+	if len(args.Txs) > maxBundleTxs {
+		return nil, fmt.Errorf("too many bundle txs: %d, max: %d", len(args.Txs), maxBundleTxs)
+	}
+
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	cc, err := api.chainConfig(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &txpool.Bundle{BlockNumber: uint64(args.BlockNumber), RevertingTxHashes: args.RevertingTxHashes}
+	if args.MinTimestamp != nil {
+		bundle.MinTimestamp = *args.MinTimestamp
+	}
+	if args.MaxTimestamp != nil {
+		bundle.MaxTimestamp = *args.MaxTimestamp
+	}
+	for i, encodedTx := range args.Txs {
+		txn, err := types.DecodeWrappedTransaction(encodedTx)
+		if err != nil {
+			return nil, fmt.Errorf("bundle tx %d: %w", i, err)
+		}
+		if err := api.checkTransaction(txn); err != nil {
+			return nil, fmt.Errorf("bundle tx %d: %w", i, err)
+		}
+		if err := checkTxChainID(txn, cc); err != nil {
+			return nil, fmt.Errorf("bundle tx %d: %w", i, err)
+		}
+		bundle.Txs = append(bundle.Txs, encodedTx)
+	}
+
+	reply, err := api.txPool.AddBundle(ctx, txpool.BundleToProto(bundle))
+	if err != nil {
+		return nil, err
+	}
+	return &SendBundleResult{BundleHash: gointerfaces.ConvertH256ToHash(reply.BundleHash)}, nil
+}
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/ledgerwatch/erigon-lib/chain"
 	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
 	"github.com/ledgerwatch/erigon-lib/common/hexutility"
 	txPoolProto "github.com/ledgerwatch/erigon-lib/gointerfaces/txpool"
 
@@ -17,19 +19,28 @@ import (
 
 // SendRawTransaction implements eth_sendRawTransaction. Creates new message call transaction or a contract creation for previously-signed transactions.
 func (api *APIImpl) SendRawTransaction(ctx context.Context, encodedTx hexutility.Bytes) (common.Hash, error) {
+	return api.sendRawTransaction(ctx, encodedTx, nil)
+}
+
+// SendPrivateRawTransaction implements eth_sendPrivateRawTransaction. Same as eth_sendRawTransaction, but the transaction
+// is only used to build the blocks of this node and is never announced to peers. If expiryBlocks is set, the
+// transaction is dropped if it is not included in the next expiryBlocks blocks.
+func (api *APIImpl) SendPrivateRawTransaction(ctx context.Context, encodedTx hexutility.Bytes, expiryBlocks *hexutil.Uint64) (common.Hash, error) {
+	options := &txPoolProto.TxOptions{Private: true}
+	if expiryBlocks != nil {
+		options.PrivateExpiryBlocks = uint64(*expiryBlocks)
+	}
+	return api.sendRawTransaction(ctx, encodedTx, options)
+}
+
+func (api *APIImpl) sendRawTransaction(ctx context.Context, encodedTx hexutility.Bytes, options *txPoolProto.TxOptions) (common.Hash, error) {
 	txn, err := types.DecodeWrappedTransaction(encodedTx)
 	if err != nil {
 		return common.Hash{}, err
 	}
-
-	// If the transaction fee cap is already specified, ensure the
-	// fee of the given transaction is _reasonable_.
-	if err := checkTxFee(txn.GetPrice().ToBig(), txn.GetGas(), ethconfig.Defaults.RPCTxFeeCap); err != nil {
+	if err := api.checkTransaction(txn); err != nil {
 		return common.Hash{}, err
 	}
-	if !txn.Protected() && !api.AllowUnprotectedTxs {
-		return common.Hash{}, errors.New("only replay-protected (EIP-155) transactions allowed over RPC")
-	}
 
 	// this has been moved to prior to adding of transactions to capture the
 	// pre state of the db - which is used for logging in the messages below
@@ -45,16 +56,16 @@ func (api *APIImpl) SendRawTransaction(ctx context.Context, encodedTx hexutility
 		return common.Hash{}, err
 	}
 
-	if txn.Protected() {
-		txnChainId := txn.GetChainID()
-		chainId := cc.ChainID
-		if chainId.Cmp(txnChainId.ToBig()) != 0 {
-			return common.Hash{}, fmt.Errorf("invalid chain id, expected: %d got: %d", chainId, *txnChainId)
-		}
+	if err := checkTxChainID(txn, cc); err != nil {
+		return common.Hash{}, err
 	}
 
 	hash := txn.Hash()
-	res, err := api.txPool.Add(ctx, &txPoolProto.AddRequest{RlpTxs: [][]byte{encodedTx}})
+	req := &txPoolProto.AddRequest{RlpTxs: [][]byte{encodedTx}}
+	if options != nil {
+		req.Options = []*txPoolProto.TxOptions{options}
+	}
+	res, err := api.txPool.Add(ctx, req)
 	if err != nil {
 		return common.Hash{}, err
 	}
@@ -66,6 +77,30 @@ func (api *APIImpl) SendRawTransaction(ctx context.Context, encodedTx hexutility
 	return txn.Hash(), nil
 }
 
+// checkTransaction checks that the fee of the transaction is reasonable, and that it is replay-protected if required
+func (api *APIImpl) checkTransaction(txn types.Transaction) error {
+	// If the transaction fee cap is already specified, ensure the
+	// fee of the given transaction is _reasonable_.
+	if err := checkTxFee(txn.GetPrice().ToBig(), txn.GetGas(), ethconfig.Defaults.RPCTxFeeCap); err != nil {
+		return err
+	}
+	if !txn.Protected() && !api.AllowUnprotectedTxs {
+		return errors.New("only replay-protected (EIP-155) transactions allowed over RPC")
+	}
+	return nil
+}
+
+func checkTxChainID(txn types.Transaction, cc *chain.Config) error {
+	if txn.Protected() {
+		txnChainId := txn.GetChainID()
+		chainId := cc.ChainID
+		if chainId.Cmp(txnChainId.ToBig()) != 0 {
+			return fmt.Errorf("invalid chain id, expected: %d got: %d", chainId, *txnChainId)
+		}
+	}
+	return nil
+}
+
 // SendTransaction implements eth_sendTransaction. Creates new message call transaction or a contract creation if the data field contains code.
 func (api *APIImpl) SendTransaction(_ context.Context, txObject interface{}) (common.Hash, error) {
 	return common.Hash{0}, fmt.Errorf(NotImplemented, "eth_sendTransaction")
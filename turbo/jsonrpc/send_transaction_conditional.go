@@ -0,0 +1,143 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	txPoolProto "github.com/ledgerwatch/erigon-lib/gointerfaces/txpool"
+	"github.com/ledgerwatch/erigon-lib/txpool"
+
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
+)
+
+// maxKnownAccountsEntries is the maximum number of storage roots and slots a conditional transaction may expect
+const maxKnownAccountsEntries = 1000
+
+// TransactionConditions are the options of eth_sendRawTransactionConditional
+type TransactionConditions struct {
+	KnownAccounts  map[common.Address]KnownAccountStorage `json:"knownAccounts,omitempty"`
+	BlockNumberMin *hexutil.Uint64                        `json:"blockNumberMin,omitempty"`
+	BlockNumberMax *hexutil.Uint64                        `json:"blockNumberMax,omitempty"`
+	TimestampMin   *hexutil.Uint64                        `json:"timestampMin,omitempty"`
+	TimestampMax   *hexutil.Uint64                        `json:"timestampMax,omitempty"`
+}
+
+// KnownAccountStorage is either the storage root of an account, or the values of some of its slots
+type KnownAccountStorage struct {
+	StorageRoot *common.Hash
+	Slots       map[common.Hash]common.Hash
+}
+
+func (s *KnownAccountStorage) UnmarshalJSON(input []byte) error {
+	var root common.Hash
+	if err := json.Unmarshal(input, &root); err == nil {
+		s.StorageRoot = &root
+		return nil
+	}
+	return json.Unmarshal(input, &s.Slots)
+}
+
+func (s KnownAccountStorage) MarshalJSON() ([]byte, error) {
+	if s.StorageRoot != nil {
+		return json.Marshal(s.StorageRoot)
+	}
+	return json.Marshal(s.Slots)
+}
+
+func (c *TransactionConditions) toTxPool() *txpool.TxConditions {
+	out := &txpool.TxConditions{}
+	if c.BlockNumberMin != nil {
+		out.BlockNumberMin = uint64(*c.BlockNumberMin)
+	}
+	if c.BlockNumberMax != nil {
+		out.BlockNumberMax = uint64(*c.BlockNumberMax)
+	}
+	if c.TimestampMin != nil {
+		out.TimestampMin = uint64(*c.TimestampMin)
+	}
+	if c.TimestampMax != nil {
+		out.TimestampMax = uint64(*c.TimestampMax)
+	}
+	if len(c.KnownAccounts) > 0 {
+		out.KnownAccounts = make(map[common.Address]*txpool.KnownAccount, len(c.KnownAccounts))
+	}
+	for address, storage := range c.KnownAccounts {
+		out.KnownAccounts[address] = &txpool.KnownAccount{StorageRoot: storage.StorageRoot, Slots: storage.Slots}
+	}
+	return out
+}
+
+// SendRawTransactionConditional implements eth_sendRawTransactionConditional (ERC-4337). Same as eth_sendRawTransaction,
+// but the transaction is only included in blocks within the given bounds, and only while the known accounts have the
+// given storage. The conditions are checked against the latest state, and the transaction is dropped from the pool
+// as soon as they do not hold anymore.
+func (api *APIImpl) SendRawTransactionConditional(ctx context.Context, encodedTx hexutility.Bytes, options TransactionConditions) (common.Hash, error) {
+	entries := 0
+	for _, storage := range options.KnownAccounts {
+		if storage.StorageRoot != nil {
+			entries++
+		}
+		entries += len(storage.Slots)
+	}
+	if entries > maxKnownAccountsEntries {
+		return common.Hash{}, fmt.Errorf("too many knownAccounts entries: %d, max: %d", entries, maxKnownAccountsEntries)
+	}
+	if err := api.checkKnownAccounts(ctx, options.KnownAccounts); err != nil {
+		return common.Hash{}, err
+	}
+	return api.sendRawTransaction(ctx, encodedTx, &txPoolProto.TxOptions{Conditions: txpool.ConditionsToProto(options.toTxPool())})
+}
+
+// checkKnownAccounts checks the expected storage of the known accounts against the latest state
+func (api *APIImpl) checkKnownAccounts(ctx context.Context, knownAccounts map[common.Address]KnownAccountStorage) error {
+	if len(knownAccounts) == 0 {
+		return nil
+	}
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	reader, err := rpchelper.CreateStateReader(ctx, tx, latest, 0, api.filters, api.stateCache, api.historyV3(tx), "")
+	if err != nil {
+		return err
+	}
+	for address, storage := range knownAccounts {
+		if storage.StorageRoot != nil {
+			proof, err := api.GetProof(ctx, address, nil, latest)
+			if err != nil {
+				return fmt.Errorf("storage root of %x: %w", address, err)
+			}
+			if proof.StorageHash != *storage.StorageRoot {
+				return fmt.Errorf("storage root of %x mismatch: expected %x, actual %x", address, *storage.StorageRoot, proof.StorageHash)
+			}
+			continue
+		}
+		acc, err := reader.ReadAccountData(address)
+		if err != nil {
+			return err
+		}
+		for key, expected := range storage.Slots {
+			var value uint256.Int
+			if acc != nil {
+				key := key
+				enc, err := reader.ReadAccountStorage(address, acc.Incarnation, &key)
+				if err != nil {
+					return err
+				}
+				value.SetBytes(enc)
+			}
+			if common.Hash(value.Bytes32()) != expected {
+				return fmt.Errorf("storage slot %x of %x mismatch: expected %x, actual %x", key, address, expected, value.Bytes32())
+			}
+		}
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/holiman/uint256"
 	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
 	"github.com/ledgerwatch/erigon-lib/txpool/txpoolcfg"
 
 	"github.com/ledgerwatch/erigon-lib/gointerfaces/sentry"
@@ -166,6 +167,69 @@ func TestSendRawTransactionUnprotected(t *testing.T) {
 	}
 }
 
+func TestSendPrivateRawTransaction(t *testing.T) {
+	mockSentry, require := mock.MockWithTxPool(t), require.New(t)
+	logger := log.New()
+
+	oneBlockStep(mockSentry, require, t)
+
+	expectedValue := uint64(1234)
+	txn, err := types.SignTx(types.NewTransaction(0, common.Address{1}, uint256.NewInt(expectedValue), params.TxGas, uint256.NewInt(10*params.GWei), nil), *types.LatestSignerForChainID(mockSentry.ChainConfig.ChainID), mockSentry.Key)
+	require.NoError(err)
+
+	ctx, conn := rpcdaemontest.CreateTestGrpcConn(t, mockSentry)
+	txPool := txpool.NewTxpoolClient(conn)
+	api := jsonrpc.NewEthAPI(newBaseApiForTest(mockSentry), mockSentry.DB, nil, txPool, nil, 5000000, 100_000, false, 100_000, logger)
+
+	buf := bytes.NewBuffer(nil)
+	err = txn.MarshalBinary(buf)
+	require.NoError(err)
+
+	expiryBlocks := hexutil.Uint64(10)
+	txHash, err := api.SendPrivateRawTransaction(ctx, buf.Bytes(), &expiryBlocks)
+	require.NoError(err)
+	require.True(mockSentry.TxPool.IsPrivate(txHash[:]))
+
+	// the transaction is still served to the node itself
+	jsonTx, err := api.GetTransactionByHash(ctx, txHash)
+	require.NoError(err)
+	require.Equal(expectedValue, jsonTx.Value.Uint64())
+}
+
+func TestSendRawTransactionConditional(t *testing.T) {
+	mockSentry, require := mock.MockWithTxPool(t), require.New(t)
+	logger := log.New()
+
+	oneBlockStep(mockSentry, require, t)
+
+	txn, err := types.SignTx(types.NewTransaction(0, common.Address{1}, uint256.NewInt(1234), params.TxGas, uint256.NewInt(10*params.GWei), nil), *types.LatestSignerForChainID(mockSentry.ChainConfig.ChainID), mockSentry.Key)
+	require.NoError(err)
+
+	ctx, conn := rpcdaemontest.CreateTestGrpcConn(t, mockSentry)
+	txPool := txpool.NewTxpoolClient(conn)
+	api := jsonrpc.NewEthAPI(newBaseApiForTest(mockSentry), mockSentry.DB, nil, txPool, nil, 5000000, 100_000, false, 100_000, logger)
+
+	buf := bytes.NewBuffer(nil)
+	err = txn.MarshalBinary(buf)
+	require.NoError(err)
+
+	// the slot is empty in the latest state
+	blockNumberMax := hexutil.Uint64(100)
+	options := jsonrpc.TransactionConditions{
+		KnownAccounts:  map[common.Address]jsonrpc.KnownAccountStorage{{2}: {Slots: map[common.Hash]common.Hash{{1}: {1}}}},
+		BlockNumberMax: &blockNumberMax,
+	}
+	_, err = api.SendRawTransactionConditional(ctx, buf.Bytes(), options)
+	require.Error(err)
+
+	options.KnownAccounts[common.Address{2}] = jsonrpc.KnownAccountStorage{Slots: map[common.Hash]common.Hash{{1}: {}}}
+	txHash, err := api.SendRawTransactionConditional(ctx, buf.Bytes(), options)
+	require.NoError(err)
+	conditions := mockSentry.TxPool.TxConditions(txHash)
+	require.NotNil(conditions)
+	require.Equal(uint64(blockNumberMax), conditions.BlockNumberMax)
+}
+
 func transaction(nonce uint64, gaslimit uint64, key *ecdsa.PrivateKey) types.Transaction {
 	return pricedTransaction(nonce, gaslimit, u256.Num1, key)
 }
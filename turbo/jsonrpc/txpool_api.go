@@ -17,6 +17,8 @@ import (
 // NetAPI the interface for the net_ RPC commands
 type TxPoolAPI interface {
 	Content(ctx context.Context) (map[string]map[string]map[string]*RPCTransaction, error)
+	GetTransactionStatus(ctx context.Context, hash libcommon.Hash) (*TransactionStatus, error)
+	LocalTransactions(ctx context.Context) ([]*LocalTransaction, error)
 }
 
 // TxPoolAPIImpl data structure to store things needed for net_ commands
@@ -129,6 +131,87 @@ func (api *TxPoolAPIImpl) Status(ctx context.Context) (map[string]hexutil.Uint,
 	}, nil
 }
 
+// TransactionStatus is the result of txpool_getTransactionStatus. Status is one of pending, baseFee, queued,
+// discarded and unknown. Position is the number of better transactions in the same sub-pool.
+type TransactionStatus struct {
+	Status        string        `json:"status"`
+	Position      *hexutil.Uint `json:"position,omitempty"`
+	DiscardReason string        `json:"discardReason,omitempty"`
+}
+
+// GetTransactionStatus tells where a transaction is in the pool, or why the pool discarded it. The discard reasons
+// are only remembered for the latest discarded transactions.
+func (api *TxPoolAPIImpl) GetTransactionStatus(ctx context.Context, hash libcommon.Hash) (*TransactionStatus, error) {
+	reply, err := api.pool.TransactionStatus(ctx, &proto_txpool.TransactionStatusRequest{Hash: gointerfaces.ConvertHashToH256(hash)})
+	if err != nil {
+		return nil, err
+	}
+	status := &TransactionStatus{Status: "unknown"}
+	position := hexutil.Uint(reply.Position)
+	switch reply.Status {
+	case proto_txpool.TransactionStatusReply_PENDING:
+		status.Status, status.Position = "pending", &position
+	case proto_txpool.TransactionStatusReply_BASE_FEE:
+		status.Status, status.Position = "baseFee", &position
+	case proto_txpool.TransactionStatusReply_QUEUED:
+		status.Status, status.Position = "queued", &position
+	case proto_txpool.TransactionStatusReply_DISCARDED:
+		status.Status, status.DiscardReason = "discarded", reply.DiscardReason
+	}
+	return status, nil
+}
+
+// LocalTransaction is an item of txpool_localTransactions. Status is one of pending, baseFee, queued, mined (in a block
+// which is not finalized yet) and dropped (not in the pool anymore, to be added again). LastBroadcast is a unix time.
+type LocalTransaction struct {
+	Hash          libcommon.Hash    `json:"hash"`
+	Sender        libcommon.Address `json:"sender"`
+	Nonce         hexutil.Uint64    `json:"nonce"`
+	Status        string            `json:"status"`
+	MinedBlock    *hexutil.Uint64   `json:"minedBlock,omitempty"`
+	DiscardReason string            `json:"discardReason,omitempty"`
+	Broadcasts    hexutil.Uint      `json:"broadcasts"`
+	LastBroadcast *hexutil.Uint64   `json:"lastBroadcast,omitempty"`
+}
+
+// LocalTransactions returns the local transactions journaled by the pool, by sender and nonce. They are kept until
+// they are mined in a finalized block, and rebroadcast while they are pending.
+func (api *TxPoolAPIImpl) LocalTransactions(ctx context.Context) ([]*LocalTransaction, error) {
+	reply, err := api.pool.LocalTransactions(ctx, &proto_txpool.LocalTransactionsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	localTxs := make([]*LocalTransaction, len(reply.Txs))
+	for i, tx := range reply.Txs {
+		localTx := &LocalTransaction{
+			Hash:          gointerfaces.ConvertH256ToHash(tx.Hash),
+			Sender:        gointerfaces.ConvertH160toAddress(tx.Sender),
+			Nonce:         hexutil.Uint64(tx.Nonce),
+			DiscardReason: tx.DiscardReason,
+			Broadcasts:    hexutil.Uint(tx.Broadcasts),
+		}
+		switch tx.Status {
+		case proto_txpool.LocalTransactionsReply_PENDING:
+			localTx.Status = "pending"
+		case proto_txpool.LocalTransactionsReply_BASE_FEE:
+			localTx.Status = "baseFee"
+		case proto_txpool.LocalTransactionsReply_QUEUED:
+			localTx.Status = "queued"
+		case proto_txpool.LocalTransactionsReply_MINED:
+			minedBlock := hexutil.Uint64(tx.MinedBlock)
+			localTx.Status, localTx.MinedBlock = "mined", &minedBlock
+		default:
+			localTx.Status = "dropped"
+		}
+		if tx.LastBroadcast != 0 {
+			lastBroadcast := hexutil.Uint64(tx.LastBroadcast)
+			localTx.LastBroadcast = &lastBroadcast
+		}
+		localTxs[i] = localTx
+	}
+	return localTxs, nil
+}
+
 /*
 
 // Inspect retrieves the content of the transaction pool and flattens it into an
@@ -63,4 +63,14 @@ func TestTxPoolContent(t *testing.T) {
 	require.Len(status, 3)
 	require.Equal(status["pending"], hexutil.Uint(1))
 	require.Equal(status["queued"], hexutil.Uint(0))
+
+	txStatus, err := api.GetTransactionStatus(ctx, txn.Hash())
+	require.NoError(err)
+	require.Equal("pending", txStatus.Status)
+	require.Equal(hexutil.Uint(0), *txStatus.Position)
+
+	txStatus, err = api.GetTransactionStatus(ctx, libcommon.Hash{1})
+	require.NoError(err)
+	require.Equal("unknown", txStatus.Status)
+	require.Nil(txStatus.Position)
 }
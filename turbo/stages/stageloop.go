@@ -497,7 +497,7 @@ func NewDefaultStages(ctx context.Context,
 			nil,
 			controlServer.ChainConfig,
 			controlServer.Engine,
-			&vm.Config{},
+			&vm.Config{AnalysisCache: vm.NewAnalysisCache(vm.DefaultAnalysisCacheSize)},
 			notifications.Accumulator,
 			cfg.StateStream,
 			/*stateStream=*/ false,
@@ -552,7 +552,7 @@ func NewPipelineStages(ctx context.Context,
 			nil,
 			controlServer.ChainConfig,
 			controlServer.Engine,
-			&vm.Config{},
+			&vm.Config{AnalysisCache: vm.NewAnalysisCache(vm.DefaultAnalysisCacheSize)},
 			notifications.Accumulator,
 			cfg.StateStream,
 			/*stateStream=*/ false,
@@ -591,7 +591,7 @@ func NewInMemoryExecution(ctx context.Context, db kv.RwDB, cfg *ethconfig.Config
 				nil,
 				controlServer.ChainConfig,
 				controlServer.Engine,
-				&vm.Config{},
+				&vm.Config{AnalysisCache: vm.NewAnalysisCache(vm.DefaultAnalysisCacheSize)},
 				notifications.Accumulator,
 				cfg.StateStream,
 				true,
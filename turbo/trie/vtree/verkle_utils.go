@@ -95,6 +95,13 @@ func GetTreeKeyCodeSize(address []byte) []byte {
 }
 
 func GetTreeKeyCodeChunk(address []byte, chunk *uint256.Int) []byte {
+	treeIndex, subIndex := GetTreeKeyCodeChunkIndices(chunk)
+	return GetTreeKey(address, treeIndex, subIndex)
+}
+
+// GetTreeKeyCodeChunkIndices returns the tree index and the sub index (position
+// within the stem) of the given code chunk, without computing the pedersen hash.
+func GetTreeKeyCodeChunkIndices(chunk *uint256.Int) (*uint256.Int, byte) {
 	chunkOffset := new(uint256.Int).Add(CodeOffset, chunk)
 	treeIndex := new(uint256.Int).Div(chunkOffset, VerkleNodeWidth)
 	subIndexMod := new(uint256.Int).Mod(chunkOffset, VerkleNodeWidth).Bytes()
@@ -102,10 +109,17 @@ func GetTreeKeyCodeChunk(address []byte, chunk *uint256.Int) []byte {
 	if len(subIndexMod) != 0 {
 		subIndex = subIndexMod[0]
 	}
-	return GetTreeKey(address, treeIndex, subIndex)
+	return treeIndex, subIndex
 }
 
 func GetTreeKeyStorageSlot(address []byte, storageKey *uint256.Int) []byte {
+	treeIndex, subIndex := GetTreeKeyStorageSlotTreeIndexes(storageKey)
+	return GetTreeKey(address, treeIndex, subIndex)
+}
+
+// GetTreeKeyStorageSlotTreeIndexes returns the tree index and the sub index (position
+// within the stem) of the given storage slot, without computing the pedersen hash.
+func GetTreeKeyStorageSlotTreeIndexes(storageKey *uint256.Int) (*uint256.Int, byte) {
 	pos := storageKey.Clone()
 	if storageKey.Cmp(codeStorageDelta) < 0 {
 		pos.Add(HeaderStorageOffset, storageKey)
@@ -124,7 +138,7 @@ func GetTreeKeyStorageSlot(address []byte, storageKey *uint256.Int) []byte {
 		// significant byte.
 		subIndex = subIndexMod[0] & 0xFF
 	}
-	return GetTreeKey(address, treeIndex, subIndex)
+	return treeIndex, subIndex
 }
 
 func PointToHash(evaluated *verkle.Point, suffix byte) []byte {